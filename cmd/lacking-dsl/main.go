@@ -0,0 +1,135 @@
+// Command lacking-dsl builds the assets of a project that are declared
+// through the dsl package.
+//
+// Usage:
+//
+//	lacking-dsl -pkg ./resources -- -store ./assets [-dry-run] [-force] [-parallelism N] [-json] [pattern ...]
+//
+// The -pkg flag specifies the package that contains the asset declarations.
+// The command generates a temporary main package in the current Go module
+// that imports the declarations and runs it through "go run". All arguments
+// after "--" are passed to the generated program, which accepts the flags
+// of the dslcli package.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/mokiat/lacking/game/asset/dsl/dslcli"
+)
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Code generated by lacking-dsl. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/mokiat/lacking/game/asset/dsl/dslcli"
+
+	_ "{{ . }}"
+)
+
+func main() {
+	dslcli.Main()
+}
+`))
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("lacking-dsl", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s -pkg <package> -- [dsl flags] [pattern ...]\n\n", flags.Name())
+		flags.PrintDefaults()
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "DSL flags:")
+		dslcli.Run([]string{"-help"}, os.Stdout, flags.Output())
+	}
+	pkg := flags.String("pkg", "", "package that contains the asset declarations (required)")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return dslcli.ExitCodeSuccess
+		}
+		return dslcli.ExitCodeUsage
+	}
+	if *pkg == "" {
+		fmt.Fprintln(os.Stderr, "error: the -pkg flag is required")
+		flags.Usage()
+		return dslcli.ExitCodeUsage
+	}
+
+	importPath, err := resolveImportPath(*pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return dslcli.ExitCodeUsage
+	}
+	moduleDir, err := resolveModuleDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return dslcli.ExitCodeUsage
+	}
+
+	// The program needs to be within the module so that the declarations
+	// package and its dependencies can be resolved.
+	programDir, err := os.MkdirTemp(moduleDir, ".lacking-dsl-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to create program dir: %v\n", err)
+		return dslcli.ExitCodeFailure
+	}
+	defer os.RemoveAll(programDir)
+
+	var source bytes.Buffer
+	if err := mainTemplate.Execute(&source, importPath); err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to generate program: %v\n", err)
+		return dslcli.ExitCodeFailure
+	}
+	if err := os.WriteFile(filepath.Join(programDir, "main.go"), source.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to write program: %v\n", err)
+		return dslcli.ExitCodeFailure
+	}
+
+	cmdArgs := append([]string{"run", "./" + filepath.Base(programDir)}, flags.Args()...)
+	cmd := exec.Command("go", cmdArgs...)
+	cmd.Dir = moduleDir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "error: failed to run program: %v\n", err)
+		return dslcli.ExitCodeFailure
+	}
+	return dslcli.ExitCodeSuccess
+}
+
+func resolveImportPath(pkg string) (string, error) {
+	output, err := exec.Command("go", "list", "-f", "{{.ImportPath}}", pkg).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve package %q: %w", pkg, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func resolveModuleDir() (string, error) {
+	output, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve module: %w", err)
+	}
+	goMod := strings.TrimSpace(string(output))
+	if goMod == "" || goMod == os.DevNull {
+		return "", errors.New("the command needs to be run within a Go module")
+	}
+	return filepath.Dir(goMod), nil
+}
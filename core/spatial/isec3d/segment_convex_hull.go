@@ -0,0 +1,75 @@
+package isec3d
+
+import (
+	"math"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/core/spatial/shape3d"
+)
+
+// CheckSegmentConvexHull reports whether the directed segment enters the
+// convex hull.
+//
+// The test is oriented and face-culled in the same way as [CheckSegmentBox]:
+// a segment that starts inside the hull, or that reaches it only through a
+// back-facing face, is not considered to intersect it.
+func CheckSegmentConvexHull(segment shape3d.Segment, hull shape3d.ConvexHull) bool {
+	_, _, ok := clipSegmentConvexHull(segment, hull)
+	return ok
+}
+
+// ResolveSegmentConvexHull yields the contact at which the directed segment
+// enters the convex hull, if it enters it at all.
+//
+// The contact follows the entry-point convention of [ResolveSegmentBox]:
+// TargetPoint is the entry point, TargetNormal is the outward normal of the
+// entered face and Depth is the fraction of the segment lying beyond the entry
+// point.
+func ResolveSegmentConvexHull(segment shape3d.Segment, hull shape3d.ConvexHull, yield shape3d.ContactCallback) {
+	tClose, faceIndex, ok := clipSegmentConvexHull(segment, hull)
+	if !ok {
+		return
+	}
+	yield(shape3d.Contact{
+		TargetPoint:  dprec.Vec3Lerp(segment.A, segment.B, tClose),
+		TargetNormal: hull.Rotation.Apply(hull.Faces[faceIndex].Normal),
+		Depth:        1.0 - tClose,
+	})
+}
+
+// clipSegmentConvexHull clips the segment against the bounding planes of the
+// hull and returns the segment parameter at which it enters the hull, along
+// with the index of the entered face.
+func clipSegmentConvexHull(segment shape3d.Segment, hull shape3d.ConvexHull) (float64, int, bool) {
+	inverseRotation := hull.Rotation.Inverse()
+	start := inverseRotation.Apply(dprec.Vec3Diff(segment.A, hull.Center))
+	delta := inverseRotation.Apply(dprec.Vec3Diff(segment.B, segment.A))
+
+	tClose := -math.MaxFloat64
+	tFar := math.MaxFloat64
+	faceIndex := -1
+	for i, face := range hull.Faces {
+		distance := face.SignedDistance(start)
+		speed := dprec.Vec3Dot(face.Normal, delta)
+		if speed == 0.0 {
+			if distance > 0.0 {
+				return 0.0, 0, false // parallel to and outside of this face
+			}
+			continue
+		}
+		t := -distance / speed
+		if speed < 0.0 {
+			if t > tClose {
+				tClose = t
+				faceIndex = i
+			}
+		} else {
+			tFar = min(tFar, t)
+		}
+	}
+
+	if (faceIndex < 0) || (tClose > tFar) || (tClose < 0.0) || (tClose > 1.0) {
+		return 0.0, 0, false
+	}
+	return tClose, faceIndex, true
+}
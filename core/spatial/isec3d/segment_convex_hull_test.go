@@ -0,0 +1,129 @@
+package isec3d_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/testing/dprectest"
+	"github.com/mokiat/lacking/core/spatial/isec3d"
+	"github.com/mokiat/lacking/core/spatial/shape3d"
+)
+
+var _ = Describe("SegmentConvexHull", func() {
+	// A cube of half-extent 1 centered at (0,0,5), described as a hull.
+	var hull shape3d.ConvexHull
+
+	BeforeEach(func() {
+		hull = shape3d.NewConvexHull(
+			dprec.NewVec3(0.0, 0.0, 5.0),
+			shape3d.IdentityRotation(),
+			[]dprec.Vec3{
+				dprec.NewVec3(-1.0, -1.0, -1.0),
+				dprec.NewVec3(1.0, -1.0, -1.0),
+				dprec.NewVec3(-1.0, 1.0, -1.0),
+				dprec.NewVec3(1.0, 1.0, -1.0),
+				dprec.NewVec3(-1.0, -1.0, 1.0),
+				dprec.NewVec3(1.0, -1.0, 1.0),
+				dprec.NewVec3(-1.0, 1.0, 1.0),
+				dprec.NewVec3(1.0, 1.0, 1.0),
+			},
+			[]shape3d.Surface{
+				{Normal: dprec.NewVec3(1.0, 0.0, 0.0), Distance: 1.0},
+				{Normal: dprec.NewVec3(-1.0, 0.0, 0.0), Distance: 1.0},
+				{Normal: dprec.NewVec3(0.0, 1.0, 0.0), Distance: 1.0},
+				{Normal: dprec.NewVec3(0.0, -1.0, 0.0), Distance: 1.0},
+				{Normal: dprec.NewVec3(0.0, 0.0, 1.0), Distance: 1.0},
+				{Normal: dprec.NewVec3(0.0, 0.0, -1.0), Distance: 1.0},
+			},
+		)
+	})
+
+	Describe("CheckSegmentConvexHull", func() {
+		It("returns true for a segment entering the hull", func() {
+			seg := shape3d.Segment{
+				A: dprec.NewVec3(-3.0, 0.0, 5.0),
+				B: dprec.NewVec3(3.0, 0.0, 5.0),
+			}
+			Expect(isec3d.CheckSegmentConvexHull(seg, hull)).To(BeTrue())
+		})
+
+		It("returns false for a segment that starts inside the hull", func() {
+			seg := shape3d.Segment{
+				A: dprec.NewVec3(0.0, 0.0, 5.0),
+				B: dprec.NewVec3(3.0, 0.0, 5.0),
+			}
+			Expect(isec3d.CheckSegmentConvexHull(seg, hull)).To(BeFalse())
+		})
+
+		It("returns false for a segment that misses the hull", func() {
+			seg := shape3d.Segment{
+				A: dprec.NewVec3(-3.0, 2.0, 5.0),
+				B: dprec.NewVec3(3.0, 2.0, 5.0),
+			}
+			Expect(isec3d.CheckSegmentConvexHull(seg, hull)).To(BeFalse())
+		})
+
+		It("returns false when the hull lies beyond the segment's extent", func() {
+			seg := shape3d.Segment{
+				A: dprec.NewVec3(-3.0, 0.0, 5.0),
+				B: dprec.NewVec3(-2.0, 0.0, 5.0),
+			}
+			Expect(isec3d.CheckSegmentConvexHull(seg, hull)).To(BeFalse())
+		})
+	})
+
+	Describe("ResolveSegmentConvexHull", func() {
+		var (
+			contact shape3d.Contact
+			found   bool
+		)
+
+		collect := func(c shape3d.Contact) {
+			contact = c
+			found = true
+		}
+
+		BeforeEach(func() {
+			found = false
+		})
+
+		It("yields the entry point, face normal and depth", func() {
+			seg := shape3d.Segment{
+				A: dprec.NewVec3(0.0, 0.0, 9.0),
+				B: dprec.NewVec3(0.0, 0.0, 1.0),
+			}
+			isec3d.ResolveSegmentConvexHull(seg, hull, collect)
+			Expect(found).To(BeTrue())
+			Expect(contact.TargetPoint).To(dprectest.HaveVec3Coords(0.0, 0.0, 6.0))
+			Expect(contact.TargetNormal).To(dprectest.HaveVec3Coords(0.0, 0.0, 1.0))
+			Expect(contact.Depth).To(BeNumerically("~", 0.625, 1e-6))
+		})
+
+		It("yields nothing for a segment that misses the hull", func() {
+			seg := shape3d.Segment{
+				A: dprec.NewVec3(2.0, 0.0, 9.0),
+				B: dprec.NewVec3(2.0, 0.0, 1.0),
+			}
+			isec3d.ResolveSegmentConvexHull(seg, hull, collect)
+			Expect(found).To(BeFalse())
+		})
+
+		Context("with a rotated hull", func() {
+			BeforeEach(func() {
+				hull.Rotation = shape3d.RotationFromQuat(dprec.RotationQuat(dprec.Degrees(90.0), dprec.BasisXVec3()))
+			})
+
+			It("yields the normal in world space", func() {
+				seg := shape3d.Segment{
+					A: dprec.NewVec3(0.0, 0.0, 9.0),
+					B: dprec.NewVec3(0.0, 0.0, 1.0),
+				}
+				isec3d.ResolveSegmentConvexHull(seg, hull, collect)
+				Expect(found).To(BeTrue())
+				Expect(contact.TargetPoint).To(dprectest.HaveVec3Coords(0.0, 0.0, 6.0))
+				Expect(contact.TargetNormal).To(dprectest.HaveVec3Coords(0.0, 0.0, 1.0))
+			})
+		})
+	})
+})
@@ -213,6 +213,28 @@ func (s *Scene[O, S, M]) AttachBox(objID ObjectID, info BoxInfo[S]) ShapeID {
 	}, info.UserData)
 }
 
+// AttachConvexHull creates a convex hull shape and attaches it to the object
+// to be used for intersection tests.
+func (s *Scene[O, S, M]) AttachConvexHull(objID ObjectID, info ConvexHullInfo[S]) ShapeID {
+	hull := info.ConvexHull
+	transform := shape3d.Transform{
+		Translation: hull.Center,
+		Rotation:    hull.Rotation,
+	}
+	bSphere := hull.BoundingSphere()
+
+	return s.attachShape(int32(objID), info.Filtering, shapeRepresentation{
+		lsBSphere:   bSphere,
+		wsBSphere:   bSphere,
+		lsTransform: transform,
+		wsTransform: transform,
+		kind:        shapeKindConvexHull,
+		points:      hull.Points,
+		faces:       hull.Faces,
+		skinRadius:  0.0,
+	}, info.UserData)
+}
+
 // DeleteShape deletes a shape from an object. The object is not
 // deleted and continues to exist in the scene.
 func (s *Scene[O, S, M]) DeleteShape(shapeID ShapeID) {
@@ -296,6 +318,37 @@ func (s *Scene[O, S, M]) BoxIter(filter Filter) iter.Seq[shape3d.Box] {
 	}
 }
 
+// EachConvexHull iterates over all convex hull shapes in the scene that match
+// the filter and yields them to the provided callback.
+func (s *Scene[O, S, M]) EachConvexHull(filter Filter, yield func(shape3d.ConvexHull) bool) {
+	if filter.SkipDynamic {
+		return
+	}
+	for index := range s.shapes {
+		shape := &s.shapes[index]
+		if shape.spatialID == query3d.InvalidTreeItemID {
+			continue
+		}
+		if shape.kind != shapeKindConvexHull {
+			continue
+		}
+		if !shape.matchesFilter(filter) {
+			continue
+		}
+		if !yield(shape.toConvexHull()) {
+			return
+		}
+	}
+}
+
+// ConvexHullIter returns an iterator over all convex hull shapes in the scene
+// that match the filter.
+func (s *Scene[O, S, M]) ConvexHullIter(filter Filter) iter.Seq[shape3d.ConvexHull] {
+	return func(yield func(shape3d.ConvexHull) bool) {
+		s.EachConvexHull(filter, yield)
+	}
+}
+
 // CreateMesh creates a new static mesh in the scene.
 //
 // Unlike shapes, a mesh is not attached to an object. It is positioned
@@ -601,6 +654,9 @@ func (s *Scene[O, S, M]) collectSegmentShape(segment shape3d.Segment, filter Fil
 		case shapeKindBox:
 			box := shape.toBox()
 			isec3d.ResolveSegmentBox(segment, box, onContact)
+		case shapeKindConvexHull:
+			hull := shape.toConvexHull()
+			isec3d.ResolveSegmentConvexHull(segment, hull, onContact)
 		}
 	}
 }
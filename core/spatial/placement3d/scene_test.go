@@ -29,6 +29,29 @@ func boxAt(x, y, z, half float64) shape3d.Box {
 	)
 }
 
+// tetrahedronHullAt builds a convex hull shaped as a tetrahedron with a
+// right-angled corner at the given coordinates and legs of the given length
+// along the positive X, Y and Z axes.
+func tetrahedronHullAt(x, y, z, size float64) shape3d.ConvexHull {
+	slantNormal := dprec.UnitVec3(dprec.NewVec3(1.0, 1.0, 1.0))
+	return shape3d.NewConvexHull(
+		dprec.NewVec3(x, y, z),
+		shape3d.IdentityRotation(),
+		[]dprec.Vec3{
+			dprec.NewVec3(0.0, 0.0, 0.0),
+			dprec.NewVec3(size, 0.0, 0.0),
+			dprec.NewVec3(0.0, size, 0.0),
+			dprec.NewVec3(0.0, 0.0, size),
+		},
+		[]shape3d.Surface{
+			{Normal: dprec.NewVec3(-1.0, 0.0, 0.0), Distance: 0.0},
+			{Normal: dprec.NewVec3(0.0, -1.0, 0.0), Distance: 0.0},
+			{Normal: dprec.NewVec3(0.0, 0.0, -1.0), Distance: 0.0},
+			{Normal: slantNormal, Distance: size * slantNormal.X},
+		},
+	)
+}
+
 // planeMesh builds a mesh made of two triangles forming a quad in the XZ plane
 // (at y == 0), centered at the given point and spanning halfSize in each of
 // the X and Z directions.
@@ -139,6 +162,24 @@ var _ = Describe("Scene", func() {
 			Expect(count).To(Equal(1))
 		})
 
+		It("yields attached convex hulls in world space", func() {
+			scene.SetObjectTransform(objID, shape3d.TranslationTransform(
+				dprec.NewVec3(10.0, 0.0, 0.0),
+			))
+			scene.AttachConvexHull(objID, placement3d.ConvexHullInfo[string]{
+				ConvexHull: tetrahedronHullAt(1.0, 0.0, 0.0, 2.0),
+			})
+
+			var found []shape3d.ConvexHull
+			scene.EachConvexHull(placement3d.Filter{}, func(h shape3d.ConvexHull) bool {
+				found = append(found, h)
+				return true
+			})
+			Expect(found).To(HaveLen(1))
+			Expect(found[0].Center).To(dprectest.HaveVec3Coords(11.0, 0.0, 0.0))
+			Expect(found[0].Points).To(HaveLen(4))
+		})
+
 		It("exposes a sphere iterator", func() {
 			scene.AttachSphere(objID, placement3d.SphereInfo[string]{
 				Sphere: sphereAt(0.0, 0.0, 0.0, 1.0),
@@ -676,6 +717,24 @@ var _ = Describe("Scene", func() {
 			Expect(scene.GetShapeObject(contact.TargetShapeID)).To(Equal(objID))
 		})
 
+		It("finds a convex hull crossed by the segment", func() {
+			objID := scene.CreateObject(placement3d.ObjectInfo[string]{})
+			scene.AttachConvexHull(objID, placement3d.ConvexHullInfo[string]{
+				ConvexHull: tetrahedronHullAt(0.0, 0.0, 0.0, 2.0),
+			})
+
+			contact, ok := scene.CheckSegmentIntersection(
+				shape3d.NewSegment(
+					dprec.NewVec3(0.5, 0.5, 5.0),
+					dprec.NewVec3(0.5, 0.5, -5.0),
+				),
+				placement3d.Filter{},
+			)
+			Expect(ok).To(BeTrue())
+			Expect(scene.GetShapeObject(contact.TargetShapeID)).To(Equal(objID))
+			Expect(contact.TargetPoint).To(dprectest.HaveVec3Coords(0.5, 0.5, 1.0))
+		})
+
 		It("finds a mesh crossed by the segment", func() {
 			meshID := scene.CreateMesh(placement3d.MeshInfo[string]{
 				Mesh: planeMesh(0.0, 0.0, 0.0, 5.0),
@@ -39,6 +39,20 @@ type BoxInfo[S any] struct {
 	Box shape3d.Box
 }
 
+// ConvexHullInfo contains the information needed to create a convex hull
+// shape.
+type ConvexHullInfo[S any] struct {
+
+	// Filtering holds the collision-filtering metadata for the shape.
+	Filtering FilterInfo
+
+	// UserData allows one to attach custom user data to the shape.
+	UserData S
+
+	// ConvexHull contains the convex hull information.
+	ConvexHull shape3d.ConvexHull
+}
+
 type shape[S any] struct {
 	objectIndex    int32
 	nextShapeIndex int32
@@ -65,6 +79,7 @@ type shapeRepresentation struct {
 
 	kind       shapeKind
 	points     []dprec.Vec3
+	faces      []shape3d.Surface
 	skinRadius float64
 }
 
@@ -113,6 +128,15 @@ func (s *shapeRepresentation) toBox() shape3d.Box {
 	}
 }
 
+func (s *shapeRepresentation) toConvexHull() shape3d.ConvexHull {
+	return shape3d.ConvexHull{
+		Center:   s.wsTransform.Translation,
+		Rotation: s.wsTransform.Rotation,
+		Points:   s.points,
+		Faces:    s.faces,
+	}
+}
+
 type shapeKind uint32
 
 const (
@@ -0,0 +1,75 @@
+package shape3d
+
+import "github.com/mokiat/gomath/dprec"
+
+// ConvexHull represents a three-dimensional, arbitrarily oriented convex
+// polyhedron.
+//
+// The polyhedron is described both by its vertices, which are needed for
+// support mapping, and by its bounding planes, which are needed for
+// containment and segment tests. The two are expected to describe the same
+// polyhedron.
+type ConvexHull struct {
+	// Center specifies the origin of the local space of the hull.
+	Center dprec.Vec3
+	// Rotation specifies the orientation of the hull.
+	Rotation Rotation
+	// Points holds the vertices of the hull in local space.
+	Points []dprec.Vec3
+	// Faces holds the planes that bound the hull in local space, with normals
+	// that face outward.
+	Faces []Surface
+}
+
+// NewConvexHull creates a [ConvexHull] with the given center, orientation,
+// local vertices and local bounding planes. The slices are retained rather
+// than copied, so they should not be modified after the call.
+func NewConvexHull(center dprec.Vec3, rotation Rotation, points []dprec.Vec3, faces []Surface) ConvexHull {
+	return ConvexHull{
+		Center:   center,
+		Rotation: rotation,
+		Points:   points,
+		Faces:    faces,
+	}
+}
+
+// TransformedConvexHull returns a new [ConvexHull] that is the result of
+// applying the specified transform to the given hull. The center is moved by
+// the transform and the hull's orientation is composed with the transform's
+// rotation, while the local vertices and planes are shared with the original
+// hull, since a rigid-body transform preserves their relative placement.
+func TransformedConvexHull(hull ConvexHull, transform Transform) ConvexHull {
+	return ConvexHull{
+		Center:   transform.Apply(hull.Center),
+		Rotation: ChainedRotation(transform.Rotation, hull.Rotation),
+		Points:   hull.Points,
+		Faces:    hull.Faces,
+	}
+}
+
+// ContainsPoint returns whether the specified point lies within the hull.
+func (h ConvexHull) ContainsPoint(point dprec.Vec3) bool {
+	localPoint := h.Rotation.Inverse().Apply(dprec.Vec3Diff(point, h.Center))
+	for _, face := range h.Faces {
+		if face.SignedDistance(localPoint) > 0.0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BoundingSphere returns a [Sphere] that fully encompasses the hull.
+//
+// The sphere is centered at the center of the hull and its radius is the
+// distance to the farthest vertex, so it is only the smallest possible
+// bounding sphere when the vertices are arranged around the center.
+func (h ConvexHull) BoundingSphere() Sphere {
+	var sqrRadius float64
+	for _, point := range h.Points {
+		sqrRadius = max(sqrRadius, point.SqrLength())
+	}
+	return Sphere{
+		Center: h.Center,
+		Radius: dprec.Sqrt(sqrRadius),
+	}
+}
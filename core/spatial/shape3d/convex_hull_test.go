@@ -0,0 +1,107 @@
+package shape3d_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/testing/dprectest"
+	"github.com/mokiat/lacking/core/spatial/shape3d"
+)
+
+var _ = Describe("ConvexHull", func() {
+	// A tetrahedron with a right-angled corner at the local origin and legs of
+	// length 2 along the local X, Y and Z axes, placed at (3,4,5).
+	var hull shape3d.ConvexHull
+
+	BeforeEach(func() {
+		slantNormal := dprec.UnitVec3(dprec.NewVec3(1.0, 1.0, 1.0))
+		hull = shape3d.NewConvexHull(
+			dprec.NewVec3(3.0, 4.0, 5.0),
+			shape3d.IdentityRotation(),
+			[]dprec.Vec3{
+				dprec.NewVec3(0.0, 0.0, 0.0),
+				dprec.NewVec3(2.0, 0.0, 0.0),
+				dprec.NewVec3(0.0, 2.0, 0.0),
+				dprec.NewVec3(0.0, 0.0, 2.0),
+			},
+			[]shape3d.Surface{
+				{Normal: dprec.NewVec3(-1.0, 0.0, 0.0), Distance: 0.0},
+				{Normal: dprec.NewVec3(0.0, -1.0, 0.0), Distance: 0.0},
+				{Normal: dprec.NewVec3(0.0, 0.0, -1.0), Distance: 0.0},
+				{Normal: slantNormal, Distance: dprec.Vec3Dot(slantNormal, dprec.NewVec3(2.0, 0.0, 0.0))},
+			},
+		)
+	})
+
+	Describe("TransformedConvexHull", func() {
+		It("moves the center, composes the rotation and shares the local shape", func() {
+			transform := shape3d.TRTransform(
+				dprec.NewVec3(10.0, 20.0, 30.0),
+				shape3d.RotationFromQuat(dprec.RotationQuat(dprec.Degrees(90.0), dprec.BasisZVec3())),
+			)
+			result := shape3d.TransformedConvexHull(hull, transform)
+			// Center (3,4,5) rotated by 90deg around Z becomes (-4,3,5), then translated to (6,23,35).
+			Expect(result.Center).To(dprectest.HaveVec3Coords(6.0, 23.0, 35.0))
+			Expect(result.Rotation.BasisX).To(dprectest.HaveVec3Coords(0.0, 1.0, 0.0))
+			Expect(result.Rotation.BasisY).To(dprectest.HaveVec3Coords(-1.0, 0.0, 0.0))
+			Expect(result.Rotation.BasisZ).To(dprectest.HaveVec3Coords(0.0, 0.0, 1.0))
+			Expect(result.Points).To(HaveLen(4))
+			Expect(result.Faces).To(HaveLen(4))
+		})
+
+		It("does not modify the original hull", func() {
+			shape3d.TransformedConvexHull(hull, shape3d.TranslationTransform(dprec.NewVec3(5.0, 5.0, 5.0)))
+			Expect(hull.Center).To(dprectest.HaveVec3Coords(3.0, 4.0, 5.0))
+			Expect(hull.Points[1]).To(dprectest.HaveVec3Coords(2.0, 0.0, 0.0))
+		})
+	})
+
+	Describe("ContainsPoint", func() {
+		It("returns true for a point strictly inside", func() {
+			Expect(hull.ContainsPoint(dprec.NewVec3(3.5, 4.5, 5.5))).To(BeTrue())
+		})
+
+		It("returns true for a vertex", func() {
+			Expect(hull.ContainsPoint(dprec.NewVec3(3.0, 4.0, 5.0))).To(BeTrue())
+		})
+
+		It("returns false for a point beyond the slanted face", func() {
+			Expect(hull.ContainsPoint(dprec.NewVec3(4.0, 5.0, 6.0))).To(BeFalse())
+		})
+
+		It("returns false for a point behind an axis face", func() {
+			Expect(hull.ContainsPoint(dprec.NewVec3(2.9, 4.5, 5.5))).To(BeFalse())
+		})
+
+		Context("with 90-degree CCW rotation about the Z axis", func() {
+			var rotated shape3d.ConvexHull
+
+			BeforeEach(func() {
+				rotated = hull
+				rotated.Rotation = shape3d.RotationFromQuat(dprec.RotationQuat(dprec.Degrees(90.0), dprec.BasisZVec3()))
+			})
+
+			It("contains a point along the rotated X leg", func() {
+				// The local X leg now points along world Y.
+				Expect(rotated.ContainsPoint(dprec.NewVec3(2.9, 5.5, 5.1))).To(BeTrue())
+			})
+
+			It("rejects a point along the unrotated X leg", func() {
+				Expect(rotated.ContainsPoint(dprec.NewVec3(4.5, 4.1, 5.1))).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("BoundingSphere", func() {
+		It("is centered at the center of the hull", func() {
+			bs := hull.BoundingSphere()
+			Expect(bs.Center).To(dprectest.HaveVec3Coords(3.0, 4.0, 5.0))
+		})
+
+		It("reaches the farthest vertex", func() {
+			bs := hull.BoundingSphere()
+			Expect(bs.Radius).To(BeNumerically("~", 2.0, 1e-6))
+		})
+	})
+})
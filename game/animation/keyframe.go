@@ -1,11 +1,42 @@
 package animation
 
-import "github.com/mokiat/gomath/dprec"
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/util/easing"
+)
+
+const (
+	// InterpolationLinear specifies that values are linearly interpolated
+	// towards the next keyframe.
+	InterpolationLinear Interpolation = iota
+
+	// InterpolationStep specifies that the value of a keyframe is held until
+	// the next keyframe.
+	InterpolationStep
+
+	// InterpolationCubicSpline specifies that values follow a cubic Hermite
+	// spline towards the next keyframe, as defined by the out tangent of the
+	// keyframe and the in tangent of the next keyframe.
+	InterpolationCubicSpline
+)
+
+// Interpolation specifies how values are interpolated between keyframes.
+type Interpolation uint8
 
 // Keyframe represents a single keyframe in an animation.
 type Keyframe[T any] struct {
 	Timestamp float64
 	Value     T
+
+	// Interpolation specifies how values are interpolated between this
+	// keyframe and the next one.
+	Interpolation Interpolation
+
+	// InTangent and OutTangent are the tangents (per second) with which the
+	// spline arrives at and leaves this keyframe. They are only used for
+	// cubic spline interpolation.
+	InTangent  T
+	OutTangent T
 }
 
 // KeyframeList is a list of keyframes.
@@ -13,6 +44,10 @@ type KeyframeList[T any] []Keyframe[T]
 
 // Keyframe returns the keyframes that are closest to the specified timestamp
 // and the interpolation factor between them.
+//
+// Two keyframes that share a timestamp form an instantaneous jump, in which
+// case the factor is either zero or one, depending on whether the timestamp
+// has reached them.
 func (l KeyframeList[T]) Keyframe(timestamp float64) (Keyframe[T], Keyframe[T], float64) {
 	leftIndex := 0
 	rightIndex := len(l) - 1
@@ -26,40 +61,164 @@ func (l KeyframeList[T]) Keyframe(timestamp float64) (Keyframe[T], Keyframe[T],
 			rightIndex = middleIndex
 		}
 	}
+	if leftIndex == rightIndex {
+		// An exact match on a jump resolves to the last of the keyframes
+		// that share the timestamp.
+		for rightIndex+1 < len(l) && l[rightIndex+1].Timestamp <= timestamp {
+			rightIndex++
+		}
+		return l[rightIndex], l[rightIndex], 0
+	}
 	left := l[leftIndex]
 	right := l[rightIndex]
-	if leftIndex == rightIndex {
-		return left, right, 0
+	if right.Timestamp <= left.Timestamp {
+		if timestamp < right.Timestamp {
+			return left, right, 0
+		}
+		return left, right, 1
 	}
 	t := dprec.Clamp((timestamp-left.Timestamp)/(right.Timestamp-left.Timestamp), 0.0, 1.0)
 	return left, right, t
 }
 
 // KeyframeSet represents a set of keyframes for an animation, including
-// translations, rotations, and scales.
+// translations, rotations, scales and morph target weights.
 type KeyframeSet struct {
 	TranslationKeyframes KeyframeList[dprec.Vec3]
 	RotationKeyframes    KeyframeList[dprec.Quat]
 	ScaleKeyframes       KeyframeList[dprec.Vec3]
+	MorphWeightKeyframes KeyframeList[[]float64]
 }
 
 // Translation returns the translation at the specified timestamp by
 // interpolating between the two closest keyframes.
 func (s KeyframeSet) Translation(timestamp float64) dprec.Vec3 {
-	left, right, t := s.TranslationKeyframes.Keyframe(timestamp)
-	return dprec.Vec3Lerp(left.Value, right.Value, t)
+	return InterpolateVec3(s.TranslationKeyframes, timestamp)
 }
 
 // Rotation returns the rotation at the specified timestamp by interpolating
-// between the two closest keyframes using spherical linear interpolation.
+// between the two closest keyframes.
 func (s KeyframeSet) Rotation(timestamp float64) dprec.Quat {
-	left, right, t := s.RotationKeyframes.Keyframe(timestamp)
-	return dprec.QuatSlerp(left.Value, right.Value, t)
+	return InterpolateQuat(s.RotationKeyframes, timestamp)
 }
 
 // Scale returns the scale at the specified timestamp by interpolating between
 // the two closest keyframes.
 func (s KeyframeSet) Scale(timestamp float64) dprec.Vec3 {
-	left, right, t := s.ScaleKeyframes.Keyframe(timestamp)
-	return dprec.Vec3Lerp(left.Value, right.Value, t)
+	return InterpolateVec3(s.ScaleKeyframes, timestamp)
+}
+
+// MorphWeights returns the morph target weights at the specified timestamp
+// by interpolating between the two closest keyframes. The weights are
+// written to the target slice, whose capacity is reused when sufficient.
+func (s KeyframeSet) MorphWeights(target []float64, timestamp float64) []float64 {
+	return InterpolateWeights(target, s.MorphWeightKeyframes, timestamp)
+}
+
+// InterpolateVec3 returns the value of the vector keyframes at the specified
+// timestamp, taking the interpolation mode of the keyframes into account.
+func InterpolateVec3(keyframes KeyframeList[dprec.Vec3], timestamp float64) dprec.Vec3 {
+	left, right, t := keyframes.Keyframe(timestamp)
+	switch left.Interpolation {
+	case InterpolationStep:
+		return stepValue(left, right, t)
+	case InterpolationCubicSpline:
+		duration := right.Timestamp - left.Timestamp
+		if duration <= 0.0 {
+			return stepValue(left, right, t)
+		}
+		return easing.HermiteSpline3D(left.Value, left.OutTangent, right.Value, right.InTangent, t*duration, duration)
+	default:
+		return dprec.Vec3Lerp(left.Value, right.Value, t)
+	}
+}
+
+// InterpolateQuat returns the value of the rotation keyframes at the
+// specified timestamp, taking the interpolation mode of the keyframes into
+// account.
+//
+// Linear interpolation uses spherical linear interpolation, while cubic
+// spline interpolation is performed per component and normalized.
+func InterpolateQuat(keyframes KeyframeList[dprec.Quat], timestamp float64) dprec.Quat {
+	left, right, t := keyframes.Keyframe(timestamp)
+	switch left.Interpolation {
+	case InterpolationStep:
+		return stepValue(left, right, t)
+	case InterpolationCubicSpline:
+		duration := right.Timestamp - left.Timestamp
+		if duration <= 0.0 {
+			return stepValue(left, right, t)
+		}
+		coefP0, coefV0, coefP1, coefV1 := easing.HermiteSplineCoefficients(t*duration, duration)
+		p0, v0, p1, v1 := left.Value, left.OutTangent, right.Value, right.InTangent
+		return dprec.UnitQuat(dprec.NewQuat(
+			coefP0*p0.W+coefV0*v0.W+coefP1*p1.W+coefV1*v1.W,
+			coefP0*p0.X+coefV0*v0.X+coefP1*p1.X+coefV1*v1.X,
+			coefP0*p0.Y+coefV0*v0.Y+coefP1*p1.Y+coefV1*v1.Y,
+			coefP0*p0.Z+coefV0*v0.Z+coefP1*p1.Z+coefV1*v1.Z,
+		))
+	default:
+		return dprec.QuatSlerp(left.Value, right.Value, t)
+	}
+}
+
+// InterpolateWeights returns the value of the morph weight keyframes at the
+// specified timestamp, taking the interpolation mode of the keyframes into
+// account. Each weight is interpolated independently.
+//
+// The result is written to the target slice, which is grown only when its
+// capacity is insufficient. The returned slice never aliases the values of
+// the keyframes.
+func InterpolateWeights(target []float64, keyframes KeyframeList[[]float64], timestamp float64) []float64 {
+	left, right, t := keyframes.Keyframe(timestamp)
+	result := resizeWeights(target, len(left.Value))
+	switch left.Interpolation {
+	case InterpolationStep:
+		copyWeights(result, stepValue(left, right, t))
+	case InterpolationCubicSpline:
+		duration := right.Timestamp - left.Timestamp
+		if duration <= 0.0 {
+			copyWeights(result, stepValue(left, right, t))
+			break
+		}
+		coefP0, coefV0, coefP1, coefV1 := easing.HermiteSplineCoefficients(t*duration, duration)
+		for i := range result {
+			p0, p1 := left.Value[i], weightAt(right.Value, i)
+			v0, v1 := weightAt(left.OutTangent, i), weightAt(right.InTangent, i)
+			result[i] = coefP0*p0 + coefV0*v0 + coefP1*p1 + coefV1*v1
+		}
+	default:
+		for i := range result {
+			result[i] = dprec.Mix(left.Value[i], weightAt(right.Value, i), t)
+		}
+	}
+	return result
+}
+
+func stepValue[T any](left, right Keyframe[T], t float64) T {
+	if t < 1.0 {
+		return left.Value
+	}
+	return right.Value
+}
+
+func weightAt(weights []float64, index int) float64 {
+	if index < len(weights) {
+		return weights[index]
+	}
+	return 0.0
+}
+
+func resizeWeights(weights []float64, length int) []float64 {
+	if cap(weights) < length {
+		return make([]float64, length)
+	}
+	return weights[:length]
+}
+
+// copyWeights copies the source weights to the target, padding the target
+// with zeros when the source has fewer weights.
+func copyWeights(target, source []float64) {
+	count := copy(target, source)
+	clear(target[count:])
 }
@@ -0,0 +1,345 @@
+package animation_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/testing/dprectest"
+	"github.com/mokiat/lacking/game/animation"
+)
+
+var _ = Describe("Keyframe", func() {
+	vec3Keyframe := func(timestamp float64, value dprec.Vec3, interpolation animation.Interpolation) animation.Keyframe[dprec.Vec3] {
+		return animation.Keyframe[dprec.Vec3]{
+			Timestamp:     timestamp,
+			Value:         value,
+			Interpolation: interpolation,
+		}
+	}
+
+	quatKeyframe := func(timestamp float64, value dprec.Quat, interpolation animation.Interpolation) animation.Keyframe[dprec.Quat] {
+		return animation.Keyframe[dprec.Quat]{
+			Timestamp:     timestamp,
+			Value:         value,
+			Interpolation: interpolation,
+		}
+	}
+
+	Describe("InterpolateVec3", func() {
+		var (
+			first  = dprec.NewVec3(0.0, 0.0, 0.0)
+			second = dprec.NewVec3(2.0, 4.0, -6.0)
+			third  = dprec.NewVec3(10.0, 10.0, 10.0)
+			fourth = dprec.NewVec3(-2.0, -2.0, -2.0)
+		)
+
+		jumpKeyframes := func(interpolation animation.Interpolation) animation.KeyframeList[dprec.Vec3] {
+			return animation.KeyframeList[dprec.Vec3]{
+				vec3Keyframe(0.0, first, interpolation),
+				vec3Keyframe(1.0, second, interpolation),
+				vec3Keyframe(1.0, third, interpolation),
+				vec3Keyframe(2.0, fourth, interpolation),
+			}
+		}
+
+		DescribeTable("step interpolation",
+			func(timestamp float64, expected dprec.Vec3) {
+				keyframes := animation.KeyframeList[dprec.Vec3]{
+					vec3Keyframe(0.0, first, animation.InterpolationStep),
+					vec3Keyframe(1.0, second, animation.InterpolationStep),
+					vec3Keyframe(2.0, third, animation.InterpolationStep),
+				}
+				value := animation.InterpolateVec3(keyframes, timestamp)
+				Expect(value).To(dprectest.HaveVec3Coords(expected.X, expected.Y, expected.Z))
+			},
+			Entry("before the first keyframe", -1.0, first),
+			Entry("at the first keyframe", 0.0, first),
+			Entry("just before a boundary", 0.999, first),
+			Entry("at a boundary", 1.0, second),
+			Entry("just after a boundary", 1.001, second),
+			Entry("just before the last keyframe", 1.999, second),
+			Entry("at the last keyframe", 2.0, third),
+			Entry("after the last keyframe", 3.0, third),
+		)
+
+		DescribeTable("linear interpolation",
+			func(timestamp float64, expected dprec.Vec3) {
+				keyframes := animation.KeyframeList[dprec.Vec3]{
+					vec3Keyframe(0.0, first, animation.InterpolationLinear),
+					vec3Keyframe(2.0, second, animation.InterpolationLinear),
+				}
+				value := animation.InterpolateVec3(keyframes, timestamp)
+				Expect(value).To(dprectest.HaveVec3Coords(expected.X, expected.Y, expected.Z))
+			},
+			Entry("before the first keyframe", -1.0, first),
+			Entry("at the first keyframe", 0.0, first),
+			Entry("at a quarter", 0.5, dprec.NewVec3(0.5, 1.0, -1.5)),
+			Entry("in the middle", 1.0, dprec.NewVec3(1.0, 2.0, -3.0)),
+			Entry("at the last keyframe", 2.0, second),
+			Entry("after the last keyframe", 3.0, second),
+		)
+
+		Describe("cubic spline interpolation", func() {
+			const duration = 2.0
+
+			var (
+				outTangent = dprec.NewVec3(3.0, -1.0, 0.5)
+				inTangent  = dprec.NewVec3(-2.0, 0.0, 4.0)
+				keyframes  animation.KeyframeList[dprec.Vec3]
+			)
+
+			BeforeEach(func() {
+				keyframes = animation.KeyframeList[dprec.Vec3]{
+					{
+						Timestamp:     0.0,
+						Value:         first,
+						Interpolation: animation.InterpolationCubicSpline,
+						OutTangent:    outTangent,
+					},
+					{
+						Timestamp:     duration,
+						Value:         second,
+						Interpolation: animation.InterpolationCubicSpline,
+						InTangent:     inTangent,
+					},
+				}
+			})
+
+			It("passes through the start keyframe", func() {
+				value := animation.InterpolateVec3(keyframes, 0.0)
+				Expect(value).To(dprectest.HaveVec3Coords(first.X, first.Y, first.Z))
+			})
+
+			It("passes through the end keyframe", func() {
+				value := animation.InterpolateVec3(keyframes, duration)
+				Expect(value).To(dprectest.HaveVec3Coords(second.X, second.Y, second.Z))
+			})
+
+			It("leaves the start keyframe with the out tangent", func() {
+				const delta = 1.0e-6
+				start := animation.InterpolateVec3(keyframes, 0.0)
+				next := animation.InterpolateVec3(keyframes, delta)
+				velocity := dprec.Vec3Quot(dprec.Vec3Diff(next, start), delta)
+				Expect(velocity.X).To(BeNumerically("~", outTangent.X, 1.0e-4))
+				Expect(velocity.Y).To(BeNumerically("~", outTangent.Y, 1.0e-4))
+				Expect(velocity.Z).To(BeNumerically("~", outTangent.Z, 1.0e-4))
+			})
+
+			It("arrives at the end keyframe with the in tangent", func() {
+				const delta = 1.0e-6
+				previous := animation.InterpolateVec3(keyframes, duration-delta)
+				end := animation.InterpolateVec3(keyframes, duration)
+				velocity := dprec.Vec3Quot(dprec.Vec3Diff(end, previous), delta)
+				Expect(velocity.X).To(BeNumerically("~", inTangent.X, 1.0e-4))
+				Expect(velocity.Y).To(BeNumerically("~", inTangent.Y, 1.0e-4))
+				Expect(velocity.Z).To(BeNumerically("~", inTangent.Z, 1.0e-4))
+			})
+
+			It("matches linear interpolation when the tangents follow the segment", func() {
+				slope := dprec.Vec3Quot(dprec.Vec3Diff(second, first), duration)
+				keyframes[0].OutTangent = slope
+				keyframes[1].InTangent = slope
+				for _, timestamp := range []float64{0.25, 0.5, 1.0, 1.75} {
+					expected := dprec.Vec3Lerp(first, second, timestamp/duration)
+					value := animation.InterpolateVec3(keyframes, timestamp)
+					Expect(value).To(dprectest.HaveVec3Coords(expected.X, expected.Y, expected.Z))
+				}
+			})
+		})
+
+		DescribeTable("zero-duration segments",
+			func(interpolation animation.Interpolation, timestamp float64, expected dprec.Vec3) {
+				value := animation.InterpolateVec3(jumpKeyframes(interpolation), timestamp)
+				Expect(value).To(dprectest.HaveVec3Coords(expected.X, expected.Y, expected.Z))
+			},
+			Entry("step before the jump", animation.InterpolationStep, 0.999, first),
+			Entry("step at the jump", animation.InterpolationStep, 1.0, third),
+			Entry("step after the jump", animation.InterpolationStep, 1.001, third),
+			Entry("linear just before the jump", animation.InterpolationLinear, 0.5, dprec.NewVec3(1.0, 2.0, -3.0)),
+			Entry("linear at the jump", animation.InterpolationLinear, 1.0, third),
+			Entry("linear after the jump", animation.InterpolationLinear, 1.5, dprec.NewVec3(4.0, 4.0, 4.0)),
+			Entry("cubic at the jump", animation.InterpolationCubicSpline, 1.0, third),
+			Entry("cubic at the end", animation.InterpolationCubicSpline, 2.0, fourth),
+		)
+
+		DescribeTable("a jump as the only segment",
+			func(interpolation animation.Interpolation, timestamp float64, expected dprec.Vec3) {
+				keyframes := animation.KeyframeList[dprec.Vec3]{
+					vec3Keyframe(1.0, first, interpolation),
+					vec3Keyframe(1.0, second, interpolation),
+				}
+				value := animation.InterpolateVec3(keyframes, timestamp)
+				Expect(value).To(dprectest.HaveVec3Coords(expected.X, expected.Y, expected.Z))
+			},
+			Entry("step before the jump", animation.InterpolationStep, 0.0, first),
+			Entry("step at the jump", animation.InterpolationStep, 1.0, second),
+			Entry("linear before the jump", animation.InterpolationLinear, 0.0, first),
+			Entry("linear at the jump", animation.InterpolationLinear, 1.0, second),
+			Entry("linear after the jump", animation.InterpolationLinear, 2.0, second),
+			Entry("cubic before the jump", animation.InterpolationCubicSpline, 0.0, first),
+			Entry("cubic at the jump", animation.InterpolationCubicSpline, 1.0, second),
+		)
+	})
+
+	Describe("InterpolateQuat", func() {
+		var (
+			identity   = dprec.IdentityQuat()
+			quarterY   = dprec.RotationQuat(dprec.Degrees(90.0), dprec.BasisYVec3())
+			eighthY    = dprec.RotationQuat(dprec.Degrees(45.0), dprec.BasisYVec3())
+			quarterX   = dprec.RotationQuat(dprec.Degrees(90.0), dprec.BasisXVec3())
+			expectQuat = func(actual, expected dprec.Quat) {
+				if dprec.QuatDot(actual, expected) < 0.0 {
+					expected = dprec.NewQuat(-expected.W, -expected.X, -expected.Y, -expected.Z)
+				}
+				Expect(actual).To(dprectest.HaveQuatCoords(expected.W, expected.X, expected.Y, expected.Z))
+			}
+		)
+
+		DescribeTable("step interpolation",
+			func(timestamp float64, expected dprec.Quat) {
+				keyframes := animation.KeyframeList[dprec.Quat]{
+					quatKeyframe(0.0, identity, animation.InterpolationStep),
+					quatKeyframe(1.0, quarterY, animation.InterpolationStep),
+					quatKeyframe(2.0, quarterX, animation.InterpolationStep),
+				}
+				expectQuat(animation.InterpolateQuat(keyframes, timestamp), expected)
+			},
+			Entry("at the first keyframe", 0.0, identity),
+			Entry("just before a boundary", 0.999, identity),
+			Entry("at a boundary", 1.0, quarterY),
+			Entry("just after a boundary", 1.001, quarterY),
+			Entry("at the last keyframe", 2.0, quarterX),
+		)
+
+		It("uses spherical linear interpolation", func() {
+			keyframes := animation.KeyframeList[dprec.Quat]{
+				quatKeyframe(0.0, identity, animation.InterpolationLinear),
+				quatKeyframe(2.0, quarterY, animation.InterpolationLinear),
+			}
+			expectQuat(animation.InterpolateQuat(keyframes, 1.0), eighthY)
+		})
+
+		Describe("cubic spline interpolation", func() {
+			var keyframes animation.KeyframeList[dprec.Quat]
+
+			BeforeEach(func() {
+				keyframes = animation.KeyframeList[dprec.Quat]{
+					{
+						Timestamp:     0.0,
+						Value:         identity,
+						Interpolation: animation.InterpolationCubicSpline,
+						OutTangent:    dprec.NewQuat(0.0, 0.0, 0.5, 0.0),
+					},
+					{
+						Timestamp:     2.0,
+						Value:         quarterY,
+						Interpolation: animation.InterpolationCubicSpline,
+						InTangent:     dprec.NewQuat(0.0, 0.0, 0.1, 0.0),
+					},
+				}
+			})
+
+			It("passes through the start keyframe", func() {
+				expectQuat(animation.InterpolateQuat(keyframes, 0.0), identity)
+			})
+
+			It("passes through the end keyframe", func() {
+				expectQuat(animation.InterpolateQuat(keyframes, 2.0), quarterY)
+			})
+
+			It("produces unit quaternions in between", func() {
+				for _, timestamp := range []float64{0.25, 0.5, 1.0, 1.5, 1.75} {
+					value := animation.InterpolateQuat(keyframes, timestamp)
+					Expect(value.Norm()).To(BeNumerically("~", 1.0, 1.0e-9))
+				}
+			})
+
+			It("resolves a zero-duration segment as a jump", func() {
+				keyframes[1].Timestamp = keyframes[0].Timestamp
+				expectQuat(animation.InterpolateQuat(keyframes, -1.0), identity)
+				expectQuat(animation.InterpolateQuat(keyframes, 0.0), quarterY)
+			})
+		})
+
+		DescribeTable("zero-duration segments",
+			func(interpolation animation.Interpolation, timestamp float64, expected dprec.Quat) {
+				keyframes := animation.KeyframeList[dprec.Quat]{
+					quatKeyframe(0.0, identity, interpolation),
+					quatKeyframe(1.0, quarterY, interpolation),
+					quatKeyframe(1.0, quarterX, interpolation),
+					quatKeyframe(2.0, identity, interpolation),
+				}
+				expectQuat(animation.InterpolateQuat(keyframes, timestamp), expected)
+			},
+			Entry("step before the jump", animation.InterpolationStep, 0.999, identity),
+			Entry("step at the jump", animation.InterpolationStep, 1.0, quarterX),
+			Entry("linear at the jump", animation.InterpolationLinear, 1.0, quarterX),
+			Entry("cubic at the jump", animation.InterpolationCubicSpline, 1.0, quarterX),
+		)
+	})
+
+	Describe("InterpolateWeights", func() {
+		weightsKeyframe := func(timestamp float64, value []float64, interpolation animation.Interpolation) animation.Keyframe[[]float64] {
+			return animation.Keyframe[[]float64]{
+				Timestamp:     timestamp,
+				Value:         value,
+				Interpolation: interpolation,
+			}
+		}
+
+		DescribeTable("interpolation",
+			func(interpolation animation.Interpolation, timestamp float64, expected []float64) {
+				keyframes := animation.KeyframeList[[]float64]{
+					weightsKeyframe(0.0, []float64{0.0, 1.0}, interpolation),
+					weightsKeyframe(2.0, []float64{1.0, 0.0}, interpolation),
+					weightsKeyframe(2.0, []float64{0.5, 0.5}, interpolation),
+					weightsKeyframe(3.0, []float64{0.0, 0.0}, interpolation),
+				}
+				weights := animation.InterpolateWeights(nil, keyframes, timestamp)
+				Expect(weights).To(HaveLen(len(expected)))
+				for i := range expected {
+					Expect(weights[i]).To(BeNumerically("~", expected[i], 1e-9))
+				}
+			},
+			Entry("step before a boundary", animation.InterpolationStep, 1.5, []float64{0.0, 1.0}),
+			Entry("step after the jump", animation.InterpolationStep, 2.5, []float64{0.5, 0.5}),
+			Entry("linear in the middle", animation.InterpolationLinear, 1.0, []float64{0.5, 0.5}),
+			Entry("linear at the jump", animation.InterpolationLinear, 2.0, []float64{0.5, 0.5}),
+			Entry("cubic in the middle", animation.InterpolationCubicSpline, 1.0, []float64{0.5, 0.5}),
+			Entry("cubic at the jump", animation.InterpolationCubicSpline, 2.0, []float64{0.5, 0.5}),
+		)
+
+		It("writes into the provided buffer", func() {
+			keyframes := animation.KeyframeList[[]float64]{
+				weightsKeyframe(0.0, []float64{0.0, 1.0}, animation.InterpolationLinear),
+				weightsKeyframe(1.0, []float64{1.0, 0.0}, animation.InterpolationLinear),
+			}
+			buffer := make([]float64, 0, 4)
+			weights := animation.InterpolateWeights(buffer, keyframes, 0.5)
+			Expect(weights).To(Equal([]float64{0.5, 0.5}))
+			Expect(&weights[0]).To(BeIdenticalTo(&buffer[:1][0]))
+		})
+
+		It("does not return the values of the keyframes", func() {
+			value := []float64{0.25, 0.75}
+			keyframes := animation.KeyframeList[[]float64]{
+				weightsKeyframe(0.0, value, animation.InterpolationStep),
+				weightsKeyframe(1.0, []float64{1.0, 0.0}, animation.InterpolationStep),
+			}
+			weights := animation.InterpolateWeights(nil, keyframes, 0.5)
+			weights[0] = 10.0
+			Expect(value).To(Equal([]float64{0.25, 0.75}))
+		})
+
+		It("pads missing weights of the next keyframe with zeros", func() {
+			keyframes := animation.KeyframeList[[]float64]{
+				weightsKeyframe(0.0, []float64{1.0, 1.0}, animation.InterpolationStep),
+				weightsKeyframe(1.0, []float64{0.5}, animation.InterpolationStep),
+			}
+			buffer := []float64{7.0, 7.0}
+			weights := animation.InterpolateWeights(buffer, keyframes, 1.5)
+			Expect(weights).To(Equal([]float64{0.5, 0.0}))
+		})
+	})
+})
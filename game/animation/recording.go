@@ -172,6 +172,9 @@ func (r *Recording) BoneTransformAt(bone string, timestamp float64) NodeTransfor
 	if len(binding.ScaleKeyframes) > 0 {
 		result.Scale = opt.V(binding.Scale(timestamp))
 	}
+	if len(binding.MorphWeightKeyframes) > 0 {
+		result.MorphWeights = opt.V(binding.MorphWeights(nil, timestamp))
+	}
 	return result
 }
 
@@ -16,6 +16,10 @@ type NodeTransform struct {
 
 	// Scale, if specified, indicates the scale of the node.
 	Scale opt.T[dprec.Vec3]
+
+	// MorphWeights, if specified, indicates the morph target weights of the
+	// mesh attached to the node.
+	MorphWeights opt.T[[]float64]
 }
 
 // InverseNodeTransform returns the inverse of a node transform.
@@ -31,6 +35,13 @@ func InverseNodeTransform(transform NodeTransform) NodeTransform {
 		scale := transform.Scale.Value
 		result.Scale = opt.V(dprec.NewVec3(1.0/scale.X, 1.0/scale.Y, 1.0/scale.Z))
 	}
+	if transform.MorphWeights.Specified {
+		weights := make([]float64, len(transform.MorphWeights.Value))
+		for i, weight := range transform.MorphWeights.Value {
+			weights[i] = -weight
+		}
+		result.MorphWeights = opt.V(weights)
+	}
 	return result
 }
 
@@ -39,27 +50,30 @@ func InverseNodeTransform(transform NodeTransform) NodeTransform {
 // factor of 1.0 means that the second transformation is used.
 func BlendNodeTransforms(first, second NodeTransform, factor float64) NodeTransform {
 	return NodeTransform{
-		Translation: combineLinear(first.Translation, second.Translation, factor),
-		Rotation:    combineSpherical(first.Rotation, second.Rotation, factor),
-		Scale:       combineLinear(first.Scale, second.Scale, factor),
+		Translation:  combineLinear(first.Translation, second.Translation, factor),
+		Rotation:     combineSpherical(first.Rotation, second.Rotation, factor),
+		Scale:        combineLinear(first.Scale, second.Scale, factor),
+		MorphWeights: combineWeights(first.MorphWeights, second.MorphWeights, factor),
 	}
 }
 
 // AddNodeTransforms combines two transforms into a single one.
 func AddNodeTransforms(first, second NodeTransform) NodeTransform {
 	return NodeTransform{
-		Translation: addLinear(first.Translation, second.Translation),
-		Rotation:    addSpherical(first.Rotation, second.Rotation),
-		Scale:       addLinear(first.Scale, second.Scale),
+		Translation:  addLinear(first.Translation, second.Translation),
+		Rotation:     addSpherical(first.Rotation, second.Rotation),
+		Scale:        addLinear(first.Scale, second.Scale),
+		MorphWeights: addWeights(first.MorphWeights, second.MorphWeights),
 	}
 }
 
 // DiffNodeTransforms combines two transforms into a single one.
 func DiffNodeTransforms(first, second NodeTransform) NodeTransform {
 	return NodeTransform{
-		Translation: diffLinear(first.Translation, second.Translation),
-		Rotation:    diffSpherical(first.Rotation, second.Rotation),
-		Scale:       diffLinear(first.Scale, second.Scale),
+		Translation:  diffLinear(first.Translation, second.Translation),
+		Rotation:     diffSpherical(first.Rotation, second.Rotation),
+		Scale:        diffLinear(first.Scale, second.Scale),
+		MorphWeights: diffWeights(first.MorphWeights, second.MorphWeights),
 	}
 }
 
@@ -67,9 +81,10 @@ func DiffNodeTransforms(first, second NodeTransform) NodeTransform {
 // by using the first transform that has a value.
 func FirstNodeTransform(first, second NodeTransform) NodeTransform {
 	return NodeTransform{
-		Translation: firstLinear(first.Translation, second.Translation),
-		Rotation:    firstSpherical(first.Rotation, second.Rotation),
-		Scale:       firstLinear(first.Scale, second.Scale),
+		Translation:  firstLinear(first.Translation, second.Translation),
+		Rotation:     firstSpherical(first.Rotation, second.Rotation),
+		Scale:        firstLinear(first.Scale, second.Scale),
+		MorphWeights: firstWeights(first.MorphWeights, second.MorphWeights),
 	}
 }
 
@@ -164,3 +179,61 @@ func firstSpherical(first, second opt.T[dprec.Quat]) opt.T[dprec.Quat] {
 		return opt.Unspecified[dprec.Quat]()
 	}
 }
+
+func combineWeights(first, second opt.T[[]float64], amount float64) opt.T[[]float64] {
+	switch {
+	case first.Specified && second.Specified:
+		result := make([]float64, max(len(first.Value), len(second.Value)))
+		for i := range result {
+			result[i] = dprec.Mix(weightAt(first.Value, i), weightAt(second.Value, i), amount)
+		}
+		return opt.V(result)
+	case first.Specified:
+		return first
+	case second.Specified:
+		return second
+	default:
+		return opt.Unspecified[[]float64]()
+	}
+}
+
+func addWeights(first, second opt.T[[]float64]) opt.T[[]float64] {
+	switch {
+	case first.Specified && second.Specified:
+		result := make([]float64, max(len(first.Value), len(second.Value)))
+		for i := range result {
+			result[i] = weightAt(first.Value, i) + weightAt(second.Value, i)
+		}
+		return opt.V(result)
+	case first.Specified:
+		return opt.V(first.Value)
+	case second.Specified:
+		return opt.V(second.Value)
+	default:
+		return opt.Unspecified[[]float64]()
+	}
+}
+
+func diffWeights(first, second opt.T[[]float64]) opt.T[[]float64] {
+	switch {
+	case first.Specified && second.Specified:
+		result := make([]float64, max(len(first.Value), len(second.Value)))
+		for i := range result {
+			result[i] = weightAt(first.Value, i) - weightAt(second.Value, i)
+		}
+		return opt.V(result)
+	default:
+		return opt.Unspecified[[]float64]()
+	}
+}
+
+func firstWeights(first, second opt.T[[]float64]) opt.T[[]float64] {
+	switch {
+	case first.Specified:
+		return opt.V(first.Value)
+	case second.Specified:
+		return opt.V(second.Value)
+	default:
+		return opt.Unspecified[[]float64]()
+	}
+}
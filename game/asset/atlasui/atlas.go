@@ -0,0 +1,117 @@
+package atlasui
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/mokiat/lacking/core/resource"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/storage/chunked"
+	"github.com/mokiat/lacking/ui"
+	"github.com/mokiat/lacking/util/bcn"
+)
+
+// OpenAtlas opens the atlas at the specified URI location and creates a
+// ui.Atlas from it in the specified ui.Context.
+//
+// The Atlas will be destroyed once the Context is destroyed.
+func OpenAtlas(ctx *ui.Context, locator resource.Locator, uri string) (*ui.Atlas, error) {
+	in, err := locator.Open(uri)
+	if err != nil {
+		return nil, fmt.Errorf("error opening resource: %w", err)
+	}
+	defer in.Close()
+
+	info, err := ReadAtlas(in)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.CreateAtlas(info)
+}
+
+// ReadAtlas decodes an atlas from the specified reader into the information
+// that is needed to create a ui.Atlas.
+func ReadAtlas(in io.Reader) (ui.AtlasInfo, error) {
+	var dtoAtlas dto.Atlas
+	if err := chunked.Decode(in, &dtoAtlas); err != nil {
+		return ui.AtlasInfo{}, fmt.Errorf("error decoding atlas: %w", err)
+	}
+	if dtoAtlas.ShadingChunk == nil || dtoAtlas.AtlasChunk == nil {
+		return ui.AtlasInfo{}, fmt.Errorf("resource is not an atlas")
+	}
+
+	pages := make([]image.Image, len(dtoAtlas.ShadingChunk.Textures))
+	pageIndices := make(map[uint32]int)
+	for i, texture := range dtoAtlas.ShadingChunk.Textures {
+		page, err := readPage(texture)
+		if err != nil {
+			return ui.AtlasInfo{}, fmt.Errorf("error reading atlas page %d: %w", texture.ID, err)
+		}
+		pages[i] = page
+		pageIndices[texture.ID] = i
+	}
+
+	regions := make([]ui.AtlasRegionInfo, len(dtoAtlas.AtlasChunk.Regions))
+	for i, dtoRegion := range dtoAtlas.AtlasChunk.Regions {
+		page, ok := pageIndices[dtoRegion.TextureID]
+		if !ok {
+			return ui.AtlasInfo{}, fmt.Errorf("atlas region %q references missing page %d", dtoRegion.Name, dtoRegion.TextureID)
+		}
+		regions[i] = ui.AtlasRegionInfo{
+			Name:  dtoRegion.Name,
+			Page:  page,
+			Size:  ui.NewSize(int(dtoRegion.Width), int(dtoRegion.Height)),
+			MinUV: dtoRegion.MinUV,
+			MaxUV: dtoRegion.MaxUV,
+		}
+	}
+	return ui.AtlasInfo{
+		Pages:   pages,
+		Regions: regions,
+	}, nil
+}
+
+// readPage converts the first mipmap of an atlas texture to an image. The
+// rows of the image keep the bottom to top order of the texture.
+func readPage(texture dto.Texture) (*image.NRGBA, error) {
+	if !texture.Flags.Has(dto.TextureFlag2D) || len(texture.MipmapLayers) == 0 {
+		return nil, fmt.Errorf("texture is not a 2D texture")
+	}
+	mipmap := texture.MipmapLayers[0]
+	if len(mipmap.Layers) == 0 {
+		return nil, fmt.Errorf("texture has no data")
+	}
+	width, height := int(mipmap.Width), int(mipmap.Height)
+	data, err := pageRGBA8(texture.Format, mipmap.Layers[0].Data, width, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 4*width*height {
+		return nil, fmt.Errorf("texture data size %d does not match dimensions %dx%d", len(data), width, height)
+	}
+	return &image.NRGBA{
+		Pix:    data,
+		Stride: 4 * width,
+		Rect:   image.Rect(0, 0, width, height),
+	}, nil
+}
+
+// pageRGBA8 converts the texel data of an atlas page to RGBA8, which is the
+// only format that is used by the UI.
+func pageRGBA8(format dto.TexelFormat, data []byte, width, height int) ([]byte, error) {
+	var blockFormat bcn.Format
+	switch format {
+	case dto.TexelFormatRGBA8:
+		return data, nil
+	case dto.TexelFormatBC1:
+		blockFormat = bcn.FormatBC1
+	case dto.TexelFormatBC3:
+		blockFormat = bcn.FormatBC3
+	case dto.TexelFormatBC7:
+		blockFormat = bcn.FormatBC7
+	default:
+		return nil, fmt.Errorf("unsupported texel format %d", format)
+	}
+	return bcn.Decode(blockFormat, data, width, height), nil
+}
@@ -0,0 +1,149 @@
+package atlasui_test
+
+import (
+	"image"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/core/resource"
+	"github.com/mokiat/lacking/game/asset/atlasui"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/storage/chunked"
+	"github.com/mokiat/lacking/ui"
+)
+
+var _ = Describe("ReadAtlas", func() {
+	var (
+		store    resource.Store
+		dtoAtlas dto.Atlas
+	)
+
+	pageTexture := func(id uint32, width, height int, data []byte) dto.Texture {
+		return dto.Texture{
+			ID:     id,
+			Format: dto.TexelFormatRGBA8,
+			Flags:  dto.TextureFlag2D,
+			MipmapLayers: []dto.MipmapLayer{
+				{
+					Width:  uint32(width),
+					Height: uint32(height),
+					Depth:  1,
+					Layers: []dto.TextureLayer{
+						{Data: data},
+					},
+				},
+			},
+		}
+	}
+
+	readAtlas := func() (ui.AtlasInfo, error) {
+		Expect(chunked.NewAsset(store, "atlas.dat").Write(dtoAtlas)).To(Succeed())
+		in, err := store.Open("atlas.dat")
+		Expect(err).ToNot(HaveOccurred())
+		defer in.Close()
+		return atlasui.ReadAtlas(in)
+	}
+
+	BeforeEach(func() {
+		store = resource.NewMemStore()
+		dtoAtlas = dto.Atlas{
+			ShadingChunkHolder: dto.ShadingChunkHolder{
+				ShadingChunk: &dto.ShadingChunk{
+					Textures: []dto.Texture{
+						pageTexture(7, 2, 1, []byte{
+							0xFF, 0x00, 0x00, 0xFF,
+							0x00, 0xFF, 0x00, 0x80,
+						}),
+						pageTexture(3, 1, 1, []byte{
+							0x00, 0x00, 0xFF, 0xFF,
+						}),
+					},
+				},
+			},
+			AtlasChunkHolder: dto.AtlasChunkHolder{
+				AtlasChunk: &dto.AtlasChunk{
+					Regions: []dto.AtlasRegion{
+						{
+							Name:      "red",
+							TextureID: 7,
+							Width:     1,
+							Height:    1,
+							MinUV:     sprec.NewVec2(0.0, 0.0),
+							MaxUV:     sprec.NewVec2(0.5, 1.0),
+						},
+						{
+							Name:      "blue",
+							TextureID: 3,
+							Width:     1,
+							Height:    1,
+							MinUV:     sprec.NewVec2(0.0, 0.0),
+							MaxUV:     sprec.NewVec2(1.0, 1.0),
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("converts the textures to pages", func() {
+		info, err := readAtlas()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Pages).To(HaveLen(2))
+
+		first, ok := info.Pages[0].(*image.NRGBA)
+		Expect(ok).To(BeTrue())
+		Expect(first.Rect).To(Equal(image.Rect(0, 0, 2, 1)))
+		Expect(first.Pix).To(Equal([]byte{
+			0xFF, 0x00, 0x00, 0xFF,
+			0x00, 0xFF, 0x00, 0x80,
+		}))
+		Expect(info.Pages[1].Bounds()).To(Equal(image.Rect(0, 0, 1, 1)))
+	})
+
+	It("maps the regions to the pages of their textures", func() {
+		info, err := readAtlas()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Regions).To(Equal([]ui.AtlasRegionInfo{
+			{
+				Name:  "red",
+				Page:  0,
+				Size:  ui.NewSize(1, 1),
+				MinUV: sprec.NewVec2(0.0, 0.0),
+				MaxUV: sprec.NewVec2(0.5, 1.0),
+			},
+			{
+				Name:  "blue",
+				Page:  1,
+				Size:  ui.NewSize(1, 1),
+				MinUV: sprec.NewVec2(0.0, 0.0),
+				MaxUV: sprec.NewVec2(1.0, 1.0),
+			},
+		}))
+	})
+
+	It("returns an error when the asset is not an atlas", func() {
+		dtoAtlas.AtlasChunk = nil
+		_, err := readAtlas()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when a region references a missing page", func() {
+		dtoAtlas.AtlasChunk.Regions[1].TextureID = 4
+		_, err := readAtlas()
+		Expect(err).To(MatchError(ContainSubstring(`atlas region "blue" references missing page 4`)))
+	})
+
+	It("returns an error when the page data does not match its size", func() {
+		dtoAtlas.ShadingChunk.Textures[1].MipmapLayers[0].Width = 2
+		_, err := readAtlas()
+		Expect(err).To(MatchError(ContainSubstring("error reading atlas page 3")))
+	})
+
+	It("returns an error when a page is not a 2D texture", func() {
+		dtoAtlas.ShadingChunk.Textures[0].Flags = dto.TextureFlagCubeMap
+		_, err := readAtlas()
+		Expect(err).To(MatchError(ContainSubstring("error reading atlas page 7")))
+	})
+})
@@ -0,0 +1,3 @@
+// Package atlasui adapts texture atlases that are produced by the asset
+// pipeline for use by the ui package.
+package atlasui
@@ -0,0 +1,13 @@
+package atlasui_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAtlasUI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AtlasUI Suite")
+}
@@ -45,22 +45,41 @@ func (c *AnimationConverter) CreateAnimationChunk(src AnimationSource) (*dto.Ani
 			translationKeyframes := make([]dto.AnimationKeyframe[dprec.Vec3], len(binding.TranslationKeyframes()))
 			for j, keyframe := range binding.TranslationKeyframes() {
 				translationKeyframes[j] = dto.AnimationKeyframe[dprec.Vec3]{
-					Timestamp: keyframe.Timestamp,
-					Value:     keyframe.Value,
+					Timestamp:     keyframe.Timestamp,
+					Value:         keyframe.Value,
+					Interpolation: convertInterpolation(keyframe.Interpolation),
+					InTangent:     keyframe.InTangent,
+					OutTangent:    keyframe.OutTangent,
 				}
 			}
 			rotationKeyframes := make([]dto.AnimationKeyframe[dprec.Quat], len(binding.RotationKeyframes()))
 			for j, keyframe := range binding.RotationKeyframes() {
 				rotationKeyframes[j] = dto.AnimationKeyframe[dprec.Quat]{
-					Timestamp: keyframe.Timestamp,
-					Value:     keyframe.Value,
+					Timestamp:     keyframe.Timestamp,
+					Value:         keyframe.Value,
+					Interpolation: convertInterpolation(keyframe.Interpolation),
+					InTangent:     keyframe.InTangent,
+					OutTangent:    keyframe.OutTangent,
 				}
 			}
 			scaleKeyframes := make([]dto.AnimationKeyframe[dprec.Vec3], len(binding.ScaleKeyframes()))
 			for j, keyframe := range binding.ScaleKeyframes() {
 				scaleKeyframes[j] = dto.AnimationKeyframe[dprec.Vec3]{
-					Timestamp: keyframe.Timestamp,
-					Value:     keyframe.Value,
+					Timestamp:     keyframe.Timestamp,
+					Value:         keyframe.Value,
+					Interpolation: convertInterpolation(keyframe.Interpolation),
+					InTangent:     keyframe.InTangent,
+					OutTangent:    keyframe.OutTangent,
+				}
+			}
+			morphWeightKeyframes := make([]dto.AnimationKeyframe[[]float64], len(binding.MorphWeightKeyframes()))
+			for j, keyframe := range binding.MorphWeightKeyframes() {
+				morphWeightKeyframes[j] = dto.AnimationKeyframe[[]float64]{
+					Timestamp:     keyframe.Timestamp,
+					Value:         keyframe.Value,
+					Interpolation: convertInterpolation(keyframe.Interpolation),
+					InTangent:     keyframe.InTangent,
+					OutTangent:    keyframe.OutTangent,
 				}
 			}
 			dtoAnimation.Bindings[i] = dto.AnimationBinding{
@@ -68,6 +87,7 @@ func (c *AnimationConverter) CreateAnimationChunk(src AnimationSource) (*dto.Ani
 				TranslationKeyframes: translationKeyframes,
 				RotationKeyframes:    rotationKeyframes,
 				ScaleKeyframes:       scaleKeyframes,
+				MorphWeightKeyframes: morphWeightKeyframes,
 			}
 		}
 		dtoAnimations[i] = dtoAnimation
@@ -76,3 +96,14 @@ func (c *AnimationConverter) CreateAnimationChunk(src AnimationSource) (*dto.Ani
 		Animations: dtoAnimations,
 	}, nil
 }
+
+func convertInterpolation(interpolation mdl.Interpolation) dto.AnimationInterpolation {
+	switch interpolation {
+	case mdl.InterpolationStep:
+		return dto.AnimationInterpolationStep
+	case mdl.InterpolationCubicSpline:
+		return dto.AnimationInterpolationCubicSpline
+	default:
+		return dto.AnimationInterpolationLinear
+	}
+}
@@ -0,0 +1,56 @@
+package conv
+
+import (
+	"fmt"
+
+	"github.com/mokiat/gog/ds"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/mokiat/lacking/storage/chunked"
+)
+
+type AtlasSource interface {
+	AllAtlasRegions() []mdl.AtlasRegion
+}
+
+func NewAtlasConverter() *AtlasConverter {
+	return &AtlasConverter{}
+}
+
+type AtlasConverter struct{}
+
+func (c *AtlasConverter) Convert(target *ds.List[chunked.Chunk], asset any) error {
+	src, ok := asset.(AtlasSource)
+	if !ok {
+		return nil
+	}
+	chunk, err := c.CreateAtlasChunk(src)
+	if err != nil {
+		return err
+	}
+	target.Add(chunked.FromValue(dto.AtlasChunkID, chunk))
+	return nil
+}
+
+func (c *AtlasConverter) CreateAtlasChunk(src AtlasSource) (*dto.AtlasChunk, error) {
+	allRegions := src.AllAtlasRegions()
+	dtoRegions := make([]dto.AtlasRegion, len(allRegions))
+	for i, region := range allRegions {
+		if region.Page == nil {
+			return nil, fmt.Errorf("atlas region %q is missing a page", region.Name)
+		}
+		dtoRegions[i] = dto.AtlasRegion{
+			Name:      region.Name,
+			TextureID: region.Page.ID(),
+			X:         uint32(region.X),
+			Y:         uint32(region.Y),
+			Width:     uint32(region.Width),
+			Height:    uint32(region.Height),
+			MinUV:     region.MinUV,
+			MaxUV:     region.MaxUV,
+		}
+	}
+	return &dto.AtlasChunk{
+		Regions: dtoRegions,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package conv
+
+import (
+	"fmt"
+
+	"github.com/mokiat/gog/ds"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/mokiat/lacking/storage/chunked"
+)
+
+type CameraSource interface {
+	AllCameraPlacements() []mdl.Placed[*mdl.Camera]
+}
+
+func NewCameraConverter() *CameraConverter {
+	return &CameraConverter{}
+}
+
+type CameraConverter struct{}
+
+func (c *CameraConverter) Convert(target *ds.List[chunked.Chunk], asset any) error {
+	src, ok := asset.(CameraSource)
+	if !ok {
+		return nil
+	}
+	chunk, err := c.CreateCameraChunk(src)
+	if err != nil {
+		return err
+	}
+	target.Add(chunked.FromValue(dto.CameraChunkID, chunk))
+	return nil
+}
+
+func (c *CameraConverter) CreateCameraChunk(src CameraSource) (*dto.CameraChunk, error) {
+	allCameraPlacements := src.AllCameraPlacements()
+	dtoCameras := make([]dto.Camera, len(allCameraPlacements))
+	for i, placement := range allCameraPlacements {
+		camera := placement.Value
+		projection, err := c.convertProjection(camera.Projection())
+		if err != nil {
+			return nil, fmt.Errorf("error converting camera %q: %w", camera.Name(), err)
+		}
+		dtoCameras[i] = dto.Camera{
+			ID:                 camera.ID(),
+			NodeID:             placement.Node.ID(),
+			Name:               camera.Name(),
+			Projection:         projection,
+			FoVAngle:           camera.FoV(),
+			AspectRatio:        camera.AspectRatio(),
+			OrthographicHeight: camera.OrthographicHeight(),
+			Near:               camera.Near(),
+			Far:                camera.Far(),
+		}
+	}
+	return &dto.CameraChunk{
+		Cameras: dtoCameras,
+	}, nil
+}
+
+func (c *CameraConverter) convertProjection(projection mdl.CameraProjection) (dto.CameraProjection, error) {
+	switch projection {
+	case mdl.CameraProjectionPerspective:
+		return dto.CameraProjectionPerspective, nil
+	case mdl.CameraProjectionOrthographic:
+		return dto.CameraProjectionOrthographic, nil
+	default:
+		return 0, fmt.Errorf("unsupported camera projection: %d", projection)
+	}
+}
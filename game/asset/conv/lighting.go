@@ -67,13 +67,19 @@ func (c *LightingConverter) CreateLightingChunk(src LightingSource) (*dto.Lighti
 }
 
 func (c *LightingConverter) convertAmbientLight(node *mdl.Node, light *mdl.AmbientLight) dto.AmbientLight {
-	return dto.AmbientLight{
+	result := dto.AmbientLight{
 		ID:                  light.ID(),
 		NodeID:              node.ID(),
 		ReflectionTextureID: light.ReflectionTexture().ID(),
 		RefractionTextureID: light.RefractionTexture().ID(),
+		Mode:                dto.AmbientLightModeTexture,
 		CastShadow:          light.CastShadow(),
 	}
+	if coefficients, ok := light.IrradianceSH9(); ok {
+		result.Mode = dto.AmbientLightModeSH9
+		result.IrradianceSH9 = coefficients
+	}
+	return result
 }
 
 func (c *LightingConverter) convertPointLight(node *mdl.Node, light *mdl.PointLight) dto.PointLight {
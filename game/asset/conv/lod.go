@@ -0,0 +1,54 @@
+package conv
+
+import (
+	"github.com/mokiat/gog"
+	"github.com/mokiat/gog/ds"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/mokiat/lacking/storage/chunked"
+)
+
+type LODSource interface {
+	AllMeshDefinitions() []*mdl.MeshDefinition
+}
+
+func NewLODConverter() *LODConverter {
+	return &LODConverter{}
+}
+
+type LODConverter struct{}
+
+func (c *LODConverter) Convert(target *ds.List[chunked.Chunk], asset any) error {
+	src, ok := asset.(LODSource)
+	if !ok {
+		return nil
+	}
+	chunk, err := c.CreateLODChunk(src)
+	if err != nil {
+		return err
+	}
+	target.Add(chunked.FromValue(dto.LODChunkID, chunk))
+	return nil
+}
+
+func (c *LODConverter) CreateLODChunk(src LODSource) (*dto.LODChunk, error) {
+	var dtoLODSets []dto.LODSet
+	for _, definition := range src.AllMeshDefinitions() {
+		if len(definition.LODs()) == 0 {
+			continue
+		}
+		dtoLODSets = append(dtoLODSets, dto.LODSet{
+			MeshDefinitionID: definition.ID(),
+			Levels: gog.Map(definition.LODs(), func(lod mdl.MeshLOD) dto.LODLevel {
+				return dto.LODLevel{
+					GeometryID:       lod.Geometry.ID(),
+					MaterialBindings: convertMaterialBindings(definition, lod.Geometry),
+					ScreenSize:       lod.ScreenSize,
+				}
+			}),
+		})
+	}
+	return &dto.LODChunk{
+		LODSets: dtoLODSets,
+	}, nil
+}
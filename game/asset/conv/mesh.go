@@ -2,6 +2,7 @@ package conv
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/mokiat/gblob"
 	"github.com/mokiat/gog"
@@ -104,119 +105,49 @@ func (c *MeshConverter) convertGeometry(geometry *mdl.Geometry) (dto.Geometry, e
 		sizeUnsignedByte  = 1
 		sizeUnsignedShort = 2
 		sizeUnsignedInt   = 4
-		sizeHalfFloat     = 2
-		sizeFloat         = 4
 	)
 
-	var (
-		stride              uint32
-		coordBufferIndex    int32
-		coordOffset         uint32
-		normalBufferIndex   int32
-		normalOffset        uint32
-		tangentBufferIndex  int32
-		tangentOffset       uint32
-		texCoordBufferIndex int32
-		texCoordOffset      uint32
-		colorBufferIndex    int32
-		colorOffset         uint32
-		weightsBufferIndex  int32
-		weightsOffset       uint32
-		jointsBufferIndex   int32
-		jointsOffset        uint32
-	)
-
-	layout := geometry.Format()
-	if layout&mdl.VertexFormatCoord != 0 {
-		coordBufferIndex = 0
-		coordOffset = stride
-		stride += 3 * sizeFloat
-	} else {
-		coordBufferIndex = dto.UnspecifiedBufferIndex
-	}
-	if layout&mdl.VertexFormatNormal != 0 {
-		normalBufferIndex = 0
-		normalOffset = stride
-		stride += 3 * sizeHalfFloat
-		stride += sizeHalfFloat // due to alignment requirements
-	} else {
-		normalBufferIndex = dto.UnspecifiedBufferIndex
-	}
-	if layout&mdl.VertexFormatTangent != 0 {
-		tangentBufferIndex = 0
-		tangentOffset = stride
-		stride += 3 * sizeHalfFloat
-		stride += sizeHalfFloat // due to alignment requirements
-	} else {
-		tangentBufferIndex = dto.UnspecifiedBufferIndex
-	}
-	if layout&mdl.VertexFormatTexCoord != 0 {
-		texCoordBufferIndex = 0
-		texCoordOffset = stride
-		stride += 2 * sizeHalfFloat
-	} else {
-		texCoordBufferIndex = dto.UnspecifiedBufferIndex
-	}
-	if layout&mdl.VertexFormatColor != 0 {
-		colorBufferIndex = 0
-		colorOffset = stride
-		stride += 4 * sizeUnsignedByte
-	} else {
-		colorBufferIndex = dto.UnspecifiedBufferIndex
-	}
-	if layout&mdl.VertexFormatWeights != 0 {
-		weightsBufferIndex = 0
-		weightsOffset = stride
-		stride += 4 * sizeUnsignedByte
-	} else {
-		weightsBufferIndex = dto.UnspecifiedBufferIndex
-	}
-	if layout&mdl.VertexFormatJoints != 0 {
-		jointsBufferIndex = 0
-		jointsOffset = stride
-		stride += 4 * sizeUnsignedByte
-	} else {
-		jointsBufferIndex = dto.UnspecifiedBufferIndex
-	}
+	layout := newGeometryLayout(geometry)
+	stride := layout.stride
 
 	vertexData := gblob.LittleEndianBlock(make([]byte, len(geometry.Vertices())*int(stride)))
-	if layout&mdl.VertexFormatCoord != 0 {
-		offset := int(coordOffset)
+	if layout.coord.isSpecified() {
+		offset := int(layout.coord.offset)
 		for _, vertex := range geometry.Vertices() {
-			vertexData.SetFloat32(offset+0*sizeFloat, vertex.Coord.X)
-			vertexData.SetFloat32(offset+1*sizeFloat, vertex.Coord.Y)
-			vertexData.SetFloat32(offset+2*sizeFloat, vertex.Coord.Z)
+			writeVertexAttribute(vertexData, offset, layout.coord.format, vertex.Coord.X, vertex.Coord.Y, vertex.Coord.Z)
 			offset += int(stride)
 		}
 	}
-	if layout&mdl.VertexFormatNormal != 0 {
-		offset := int(normalOffset)
+	if layout.normal.isSpecified() {
+		offset := int(layout.normal.offset)
 		for _, vertex := range geometry.Vertices() {
-			vertexData.SetUint16(offset+0*sizeHalfFloat, float16.Fromfloat32(vertex.Normal.X).Bits())
-			vertexData.SetUint16(offset+1*sizeHalfFloat, float16.Fromfloat32(vertex.Normal.Y).Bits())
-			vertexData.SetUint16(offset+2*sizeHalfFloat, float16.Fromfloat32(vertex.Normal.Z).Bits())
+			writeVertexAttribute(vertexData, offset, layout.normal.format, vertex.Normal.X, vertex.Normal.Y, vertex.Normal.Z)
 			offset += int(stride)
 		}
 	}
-	if layout&mdl.VertexFormatTangent != 0 {
-		offset := int(tangentOffset)
+	if layout.tangent.isSpecified() {
+		offset := int(layout.tangent.offset)
 		for _, vertex := range geometry.Vertices() {
-			vertexData.SetUint16(offset+0*sizeHalfFloat, float16.Fromfloat32(vertex.Tangent.X).Bits())
-			vertexData.SetUint16(offset+1*sizeHalfFloat, float16.Fromfloat32(vertex.Tangent.Y).Bits())
-			vertexData.SetUint16(offset+2*sizeHalfFloat, float16.Fromfloat32(vertex.Tangent.Z).Bits())
+			writeVertexAttribute(vertexData, offset, layout.tangent.format, vertex.Tangent.X, vertex.Tangent.Y, vertex.Tangent.Z, vertex.Tangent.W)
 			offset += int(stride)
 		}
 	}
-	if layout&mdl.VertexFormatTexCoord != 0 {
-		offset := int(texCoordOffset)
+	if layout.texCoord.isSpecified() {
+		offset := int(layout.texCoord.offset)
 		for _, vertex := range geometry.Vertices() {
-			vertexData.SetUint16(offset+0*sizeHalfFloat, float16.Fromfloat32(vertex.TexCoord.X).Bits())
-			vertexData.SetUint16(offset+1*sizeHalfFloat, float16.Fromfloat32(vertex.TexCoord.Y).Bits())
+			writeVertexAttribute(vertexData, offset, layout.texCoord.format, vertex.TexCoord.X, vertex.TexCoord.Y)
 			offset += int(stride)
 		}
 	}
-	if layout&mdl.VertexFormatColor != 0 {
-		offset := int(colorOffset)
+	if layout.texCoord1.isSpecified() {
+		offset := int(layout.texCoord1.offset)
+		for _, vertex := range geometry.Vertices() {
+			writeVertexAttribute(vertexData, offset, layout.texCoord1.format, vertex.TexCoord1.X, vertex.TexCoord1.Y)
+			offset += int(stride)
+		}
+	}
+	if layout.color.isSpecified() {
+		offset := int(layout.color.offset)
 		for _, vertex := range geometry.Vertices() {
 			vertexData.SetUint8(offset+0*sizeUnsignedByte, uint8(vertex.Color.X*255.0))
 			vertexData.SetUint8(offset+1*sizeUnsignedByte, uint8(vertex.Color.Y*255.0))
@@ -225,8 +156,8 @@ func (c *MeshConverter) convertGeometry(geometry *mdl.Geometry) (dto.Geometry, e
 			offset += int(stride)
 		}
 	}
-	if layout&mdl.VertexFormatWeights != 0 {
-		offset := int(weightsOffset)
+	if layout.weights.isSpecified() {
+		offset := int(layout.weights.offset)
 		for _, vertex := range geometry.Vertices() {
 			vertexData.SetUint8(offset+0*sizeUnsignedByte, uint8(vertex.Weights.X*255.0))
 			vertexData.SetUint8(offset+1*sizeUnsignedByte, uint8(vertex.Weights.Y*255.0))
@@ -235,8 +166,8 @@ func (c *MeshConverter) convertGeometry(geometry *mdl.Geometry) (dto.Geometry, e
 			offset += int(stride)
 		}
 	}
-	if layout&mdl.VertexFormatJoints != 0 {
-		offset := int(jointsOffset)
+	if layout.joints.isSpecified() {
+		offset := int(layout.joints.offset)
 		for _, vertex := range geometry.Vertices() {
 			vertexData.SetUint8(offset+0*sizeUnsignedByte, uint8(vertex.Joints[0]))
 			vertexData.SetUint8(offset+1*sizeUnsignedByte, uint8(vertex.Joints[1]))
@@ -251,7 +182,7 @@ func (c *MeshConverter) convertGeometry(geometry *mdl.Geometry) (dto.Geometry, e
 		indexData   gblob.LittleEndianBlock
 		indexSize   int
 	)
-	if len(geometry.Vertices()) >= 0xFFFF {
+	if geometryNeedsUint32Indices(geometry) {
 		indexSize = sizeUnsignedInt
 		indexLayout = dto.IndexLayoutUint32
 		indexData = gblob.LittleEndianBlock(make([]byte, len(geometry.Indices())*sizeUnsignedInt))
@@ -278,10 +209,16 @@ func (c *MeshConverter) convertGeometry(geometry *mdl.Geometry) (dto.Geometry, e
 	}
 
 	var boundingSphereRadius float64
-	for _, vertex := range geometry.Vertices() {
+	for i, vertex := range geometry.Vertices() {
+		// Morph targets can move vertices, so the bounding sphere needs to
+		// account for all of them being applied at full weight.
+		var morphDistance float64
+		for _, target := range geometry.MorphTargets() {
+			morphDistance += float64(target.Delta(i).Coord.Length())
+		}
 		boundingSphereRadius = max(
 			boundingSphereRadius,
-			float64(vertex.Coord.Length()),
+			float64(vertex.Coord.Length())+morphDistance,
 		)
 	}
 
@@ -294,41 +231,14 @@ func (c *MeshConverter) convertGeometry(geometry *mdl.Geometry) (dto.Geometry, e
 			},
 		},
 		VertexLayout: dto.VertexLayout{
-			Coord: dto.VertexAttribute{
-				BufferIndex: coordBufferIndex,
-				ByteOffset:  coordOffset,
-				Format:      dto.VertexAttributeFormatRGB32F,
-			},
-			Normal: dto.VertexAttribute{
-				BufferIndex: normalBufferIndex,
-				ByteOffset:  normalOffset,
-				Format:      dto.VertexAttributeFormatRGB16F,
-			},
-			Tangent: dto.VertexAttribute{
-				BufferIndex: tangentBufferIndex,
-				ByteOffset:  tangentOffset,
-				Format:      dto.VertexAttributeFormatRGB16F,
-			},
-			TexCoord: dto.VertexAttribute{
-				BufferIndex: texCoordBufferIndex,
-				ByteOffset:  texCoordOffset,
-				Format:      dto.VertexAttributeFormatRG16F,
-			},
-			Color: dto.VertexAttribute{
-				BufferIndex: colorBufferIndex,
-				ByteOffset:  colorOffset,
-				Format:      dto.VertexAttributeFormatRGBA8UN,
-			},
-			Weights: dto.VertexAttribute{
-				BufferIndex: weightsBufferIndex,
-				ByteOffset:  weightsOffset,
-				Format:      dto.VertexAttributeFormatRGBA8UN,
-			},
-			Joints: dto.VertexAttribute{
-				BufferIndex: jointsBufferIndex,
-				ByteOffset:  jointsOffset,
-				Format:      dto.VertexAttributeFormatRGBA8IU,
-			},
+			Coord:     layout.coord.dto(),
+			Normal:    layout.normal.dto(),
+			Tangent:   layout.tangent.dto(),
+			TexCoord:  layout.texCoord.dto(),
+			Color:     layout.color.dto(),
+			Weights:   layout.weights.dto(),
+			Joints:    layout.joints.dto(),
+			TexCoord1: layout.texCoord1.dto(),
 		},
 		IndexBuffer: dto.IndexBuffer{
 			IndexLayout: indexLayout,
@@ -342,9 +252,145 @@ func (c *MeshConverter) convertGeometry(geometry *mdl.Geometry) (dto.Geometry, e
 	}, nil
 }
 
+// GeometrySize returns the number of bytes that the vertex and index data of
+// the specified geometry occupy once converted.
+func GeometrySize(geometry *mdl.Geometry) int {
+	layout := newGeometryLayout(geometry)
+	indexSize := 2
+	if geometryNeedsUint32Indices(geometry) {
+		indexSize = 4
+	}
+	return len(geometry.Vertices())*int(layout.stride) + len(geometry.Indices())*indexSize
+}
+
+func geometryNeedsUint32Indices(geometry *mdl.Geometry) bool {
+	return len(geometry.Vertices()) >= 0xFFFF
+}
+
+// geometryLayout describes how the attributes of the vertices of a geometry
+// are positioned within the single vertex buffer.
+type geometryLayout struct {
+	stride    uint32
+	coord     geometryAttribute
+	normal    geometryAttribute
+	tangent   geometryAttribute
+	texCoord  geometryAttribute
+	texCoord1 geometryAttribute
+	color     geometryAttribute
+	weights   geometryAttribute
+	joints    geometryAttribute
+}
+
+type geometryAttribute struct {
+	bufferIndex int32
+	offset      uint32
+	format      dto.VertexAttributeFormat
+}
+
+func (a geometryAttribute) isSpecified() bool {
+	return a.bufferIndex != dto.UnspecifiedBufferIndex
+}
+
+func (a geometryAttribute) dto() dto.VertexAttribute {
+	return dto.VertexAttribute{
+		BufferIndex: a.bufferIndex,
+		ByteOffset:  a.offset,
+		Format:      a.format,
+	}
+}
+
+func newGeometryLayout(geometry *mdl.Geometry) geometryLayout {
+	var result geometryLayout
+	vertexFormat := geometry.Format()
+
+	addAttribute := func(attribute mdl.VertexFormat, defaultFormat dto.VertexAttributeFormat) geometryAttribute {
+		if vertexFormat&attribute == 0 {
+			return geometryAttribute{
+				bufferIndex: dto.UnspecifiedBufferIndex,
+			}
+		}
+		format, ok := geometry.AttributeFormat(attribute)
+		if !ok {
+			format = defaultFormat
+		}
+		attrib := geometryAttribute{
+			bufferIndex: 0,
+			offset:      result.stride,
+			format:      format,
+		}
+		result.stride += vertexAttributeSize(format)
+		return attrib
+	}
+
+	result.coord = addAttribute(mdl.VertexFormatCoord, dto.VertexAttributeFormatRGB32F)
+	result.normal = addAttribute(mdl.VertexFormatNormal, dto.VertexAttributeFormatRGB16F)
+	result.tangent = addAttribute(mdl.VertexFormatTangent, dto.VertexAttributeFormatRGBA16F)
+	result.texCoord = addAttribute(mdl.VertexFormatTexCoord, dto.VertexAttributeFormatRG16F)
+	result.texCoord1 = addAttribute(mdl.VertexFormatTexCoord1, dto.VertexAttributeFormatRG16F)
+	result.color = addAttribute(mdl.VertexFormatColor, dto.VertexAttributeFormatRGBA8UN)
+	result.weights = addAttribute(mdl.VertexFormatWeights, dto.VertexAttributeFormatRGBA8UN)
+	result.joints = addAttribute(mdl.VertexFormatJoints, dto.VertexAttributeFormatRGBA8IU)
+	return result
+}
+
+// vertexAttributeSize returns the number of bytes that an attribute of the
+// specified format occupies, including padding due to alignment requirements.
+func vertexAttributeSize(format dto.VertexAttributeFormat) uint32 {
+	var size uint32
+	switch format {
+	case dto.VertexAttributeFormatRGB32F:
+		size = 3 * 4
+	case dto.VertexAttributeFormatRG32F:
+		size = 2 * 4
+	case dto.VertexAttributeFormatRGBA16F:
+		size = 4 * 2
+	case dto.VertexAttributeFormatRGB16F, dto.VertexAttributeFormatRGB16SN:
+		size = 3 * 2
+	case dto.VertexAttributeFormatRG16F, dto.VertexAttributeFormatRG16SN, dto.VertexAttributeFormatRG16UN:
+		size = 2 * 2
+	case dto.VertexAttributeFormatRGB8SN:
+		size = 3 * 1
+	case dto.VertexAttributeFormatRGBA8SN, dto.VertexAttributeFormatRGBA8UN, dto.VertexAttributeFormatRGBA8IU:
+		size = 4 * 1
+	default:
+		panic(fmt.Errorf("unsupported vertex attribute format %d", format))
+	}
+	return (size + 3) &^ 3
+}
+
+// writeVertexAttribute writes the specified float components at the specified
+// offset, encoded in the specified format.
+func writeVertexAttribute(data gblob.LittleEndianBlock, offset int, format dto.VertexAttributeFormat, values ...float32) {
+	for i, value := range values {
+		switch format {
+		case dto.VertexAttributeFormatRGB32F, dto.VertexAttributeFormatRG32F:
+			data.SetFloat32(offset+i*4, value)
+		case dto.VertexAttributeFormatRGBA16F, dto.VertexAttributeFormatRGB16F, dto.VertexAttributeFormatRG16F:
+			data.SetUint16(offset+i*2, float16.Fromfloat32(value).Bits())
+		case dto.VertexAttributeFormatRGB16SN, dto.VertexAttributeFormatRG16SN:
+			data.SetUint16(offset+i*2, uint16(int16(math.Round(float64(min(max(value, -1.0), 1.0))*math.MaxInt16))))
+		case dto.VertexAttributeFormatRG16UN:
+			data.SetUint16(offset+i*2, uint16(math.Round(float64(min(max(value, 0.0), 1.0))*math.MaxUint16)))
+		case dto.VertexAttributeFormatRGBA8SN, dto.VertexAttributeFormatRGB8SN:
+			data.SetUint8(offset+i, uint8(int8(math.Round(float64(min(max(value, -1.0), 1.0))*math.MaxInt8))))
+		default:
+			panic(fmt.Errorf("unsupported vertex attribute format %d", format))
+		}
+	}
+}
+
 func (c *MeshConverter) convertMeshDefinition(definition *mdl.MeshDefinition) (dto.MeshDefinition, error) {
 	geometry := definition.Geometry()
+	return dto.MeshDefinition{
+		ID:               definition.ID(),
+		GeometryID:       geometry.ID(),
+		MaterialBindings: convertMaterialBindings(definition, geometry),
+	}, nil
+}
 
+// convertMaterialBindings binds the fragments of the specified geometry to
+// the materials of the mesh definition, matching them by name.
+func convertMaterialBindings(definition *mdl.MeshDefinition, geometry *mdl.Geometry) []dto.MaterialBinding {
 	var materialBindings []dto.MaterialBinding
 	for i, fragment := range geometry.Fragments() {
 		material, ok := definition.MaterialBindings()[fragment.Name()]
@@ -356,12 +402,7 @@ func (c *MeshConverter) convertMeshDefinition(definition *mdl.MeshDefinition) (d
 			MaterialID:    material.ID(),
 		})
 	}
-
-	return dto.MeshDefinition{
-		ID:               definition.ID(),
-		GeometryID:       geometry.ID(),
-		MaterialBindings: materialBindings,
-	}, nil
+	return materialBindings
 }
 
 func (c *MeshConverter) convertMesh(node *mdl.Node, mesh *mdl.Mesh) (dto.Mesh, error) {
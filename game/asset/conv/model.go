@@ -13,10 +13,14 @@ func NewModelConverter() *ModelConverter {
 	return &ModelConverter{
 		converters: []Converter{
 			NewAnimationConverter(),
+			NewAtlasConverter(),
 			NewBackgroundConverter(),
+			NewCameraConverter(),
 			NewHierarchyConverter(),
 			NewLightingConverter(),
+			NewLODConverter(),
 			NewMeshConverter(),
+			NewMorphConverter(),
 			NewPhysicsConverter(),
 			NewShadingConverter(),
 		},
@@ -0,0 +1,90 @@
+package conv
+
+import (
+	"github.com/mokiat/gblob"
+	"github.com/mokiat/gog"
+	"github.com/mokiat/gog/ds"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/mokiat/lacking/storage/chunked"
+)
+
+type MorphSource interface {
+	AllGeometries() []*mdl.Geometry
+}
+
+func NewMorphConverter() *MorphConverter {
+	return &MorphConverter{}
+}
+
+type MorphConverter struct{}
+
+func (c *MorphConverter) Convert(target *ds.List[chunked.Chunk], asset any) error {
+	src, ok := asset.(MorphSource)
+	if !ok {
+		return nil
+	}
+	chunk, err := c.CreateMorphChunk(src)
+	if err != nil {
+		return err
+	}
+	target.Add(chunked.FromValue(dto.MorphChunkID, chunk))
+	return nil
+}
+
+func (c *MorphConverter) CreateMorphChunk(src MorphSource) (*dto.MorphChunk, error) {
+	var dtoMorphSets []dto.MorphSet
+	for _, geometry := range src.AllGeometries() {
+		if len(geometry.MorphTargets()) == 0 {
+			continue
+		}
+		dtoMorphSets = append(dtoMorphSets, c.convertMorphSet(geometry))
+	}
+	return &dto.MorphChunk{
+		MorphSets: dtoMorphSets,
+	}, nil
+}
+
+func (c *MorphConverter) convertMorphSet(geometry *mdl.Geometry) dto.MorphSet {
+	const (
+		sizeFloat = 4
+		sizeTexel = 4 * sizeFloat
+	)
+
+	texelsPerVertex := 1
+	hasNormals := geometry.Format()&mdl.VertexFormatNormal != 0
+	if hasNormals {
+		texelsPerVertex = 2
+	}
+
+	targets := geometry.MorphTargets()
+	vertexCount := len(geometry.Vertices())
+	deltaData := gblob.LittleEndianBlock(make([]byte, len(targets)*vertexCount*texelsPerVertex*sizeTexel))
+	for t, target := range targets {
+		for i := range vertexCount {
+			delta := target.Delta(i)
+			offset := (t*vertexCount + i) * texelsPerVertex * sizeTexel
+			deltaData.SetFloat32(offset+0*sizeFloat, delta.Coord.X)
+			deltaData.SetFloat32(offset+1*sizeFloat, delta.Coord.Y)
+			deltaData.SetFloat32(offset+2*sizeFloat, delta.Coord.Z)
+			if hasNormals {
+				offset += sizeTexel
+				deltaData.SetFloat32(offset+0*sizeFloat, delta.Normal.X)
+				deltaData.SetFloat32(offset+1*sizeFloat, delta.Normal.Y)
+				deltaData.SetFloat32(offset+2*sizeFloat, delta.Normal.Z)
+			}
+		}
+	}
+
+	return dto.MorphSet{
+		GeometryID: geometry.ID(),
+		Targets: gog.Map(targets, func(target *mdl.MorphTarget) dto.MorphTarget {
+			return dto.MorphTarget{
+				Name:          target.Name(),
+				DefaultWeight: float32(target.DefaultWeight()),
+			}
+		}),
+		HasNormalDeltas: hasNormals,
+		Deltas:          deltaData,
+	}
+}
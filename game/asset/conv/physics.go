@@ -91,22 +91,31 @@ func (c *PhysicsConverter) convertBodyDefinition(definition *mdl.BodyDefinition)
 				Radius:      sphere.Radius(),
 			}
 		}),
+		CollisionConvexHulls: gog.Map(definition.CollisionConvexHulls(), func(hull *mdl.CollisionConvexHull) dto.CollisionConvexHull {
+			return dto.CollisionConvexHull{
+				Translation: hull.Translation(),
+				Rotation:    hull.Rotation(),
+				Triangles:   gog.Map(hull.Triangles(), c.convertCollisionTriangle),
+			}
+		}),
 		CollisionMeshes: gog.Map(definition.CollisionMeshes(), func(mesh *mdl.CollisionMesh) dto.CollisionMesh {
 			return dto.CollisionMesh{
 				Translation: mesh.Translation(),
 				Rotation:    mesh.Rotation(),
-				Triangles: gog.Map(mesh.Triangles(), func(triangle mdl.CollisionTriangle) dto.CollisionTriangle {
-					return dto.CollisionTriangle{
-						A: triangle.A,
-						B: triangle.B,
-						C: triangle.C,
-					}
-				}),
+				Triangles:   gog.Map(mesh.Triangles(), c.convertCollisionTriangle),
 			}
 		}),
 	}
 }
 
+func (c *PhysicsConverter) convertCollisionTriangle(triangle mdl.CollisionTriangle) dto.CollisionTriangle {
+	return dto.CollisionTriangle{
+		A: triangle.A,
+		B: triangle.B,
+		C: triangle.C,
+	}
+}
+
 func (c *PhysicsConverter) convertBody(node *mdl.Node, body *mdl.Body) dto.Body {
 	return dto.Body{
 		ID:               body.ID(),
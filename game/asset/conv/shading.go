@@ -150,6 +150,8 @@ func (c *ShadingConverter) isLikelyLinearSpace(format mdl.TextureFormat) bool {
 	linearFormats := []mdl.TextureFormat{
 		mdl.TextureFormatRGBA16F,
 		mdl.TextureFormatRGBA32F,
+		mdl.TextureFormatBC4,
+		mdl.TextureFormatBC5,
 	}
 	return slices.Contains(linearFormats, format)
 }
@@ -265,13 +267,14 @@ func (c *ShadingConverter) convertProperties(properties map[string]any) ([]dto.P
 
 func (c *ShadingConverter) convertMaterialPass(pass *mdl.MaterialPass) (dto.MaterialPass, error) {
 	return dto.MaterialPass{
-		Layer:           int32(pass.Layer()),
-		Culling:         pass.Culling(),
-		FrontFace:       pass.FrontFace(),
-		DepthTest:       pass.DepthTest(),
-		DepthWrite:      pass.DepthWrite(),
-		DepthComparison: pass.DepthComparison(),
-		Blending:        pass.Blending(),
-		ShaderID:        pass.Shader().ID(),
+		Layer:            int32(pass.Layer()),
+		Culling:          pass.Culling(),
+		FrontFace:        pass.FrontFace(),
+		DepthTest:        pass.DepthTest(),
+		DepthWrite:       pass.DepthWrite(),
+		DepthComparison:  pass.DepthComparison(),
+		Blending:         pass.Blending(),
+		ShaderID:         pass.Shader().ID(),
+		AdditiveBlending: pass.AdditiveBlending(),
 	}, nil
 }
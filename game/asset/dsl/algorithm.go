@@ -6,6 +6,7 @@ import (
 	"io"
 	"log/slog"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/mokiat/gog/ds"
@@ -15,20 +16,82 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-// Run runs the DSL algorithm on the provided registry. If modelNames
-// is not empty, only the models with the provided names will be processed.
+// RunOptions configures how the DSL algorithm is run.
+type RunOptions struct {
+
+	// PathFilter determines which assets should be processed. If not
+	// specified, all declared assets are processed.
+	PathFilter filter.Func[string]
+
+	// DryRun specifies that assets should only be checked and reported but
+	// not rebuilt.
+	DryRun bool
+
+	// Force specifies that assets should be rebuilt even if their digests
+	// have not changed.
+	Force bool
+
+	// Parallelism is the maximum number of assets that are processed at the
+	// same time. If zero, the number of CPUs is used.
+	Parallelism int
+
+	// PrunePrefix, if specified, enables the removal of stale files from the
+	// store once all assets have been processed. See PruneOptions.Prefix for
+	// more information.
+	//
+	// The removal honours DryRun but not PathFilter, since stale files are
+	// by definition not declared.
+	PrunePrefix string
+}
+
+// Run runs the DSL algorithm on the provided registry. Only assets whose
+// paths pass the specified filter will be processed.
 func Run(store resource.Store, pathFilter filter.Func[string]) error {
+	_, err := RunWithOptions(store, RunOptions{
+		PathFilter: pathFilter,
+	})
+	return err
+}
+
+// RunWithOptions runs the DSL algorithm on the provided registry, using the
+// specified options.
+//
+// All matching assets are processed, even if some of them fail. The returned
+// report contains the outcome for each asset and the returned error joins
+// the errors of all failed assets.
+func RunWithOptions(store resource.Store, options RunOptions) (*Report, error) {
+	startTime := time.Now()
+
+	pathFilter := options.PathFilter
+	if pathFilter == nil {
+		pathFilter = filter.True[string]()
+	}
+	parallelism := options.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
 	var g errgroup.Group
-	g.SetLimit(runtime.NumCPU())
+	g.SetLimit(parallelism)
+
+	var (
+		reportMU sync.Mutex
+		report   = &Report{
+			DryRun: options.DryRun,
+		}
+	)
+	appendAssetReport := func(assetReport AssetReport) {
+		reportMU.Lock()
+		defer reportMU.Unlock()
+		report.Assets = append(report.Assets, assetReport)
+	}
 
 	for path, rawProvider := range rawResourceProviders {
 		if !pathFilter(path) {
 			continue // skip this one
 		}
 		g.Go(func() error {
-			if err := processRawAsset(store, path, rawProvider); err != nil {
-				return fmt.Errorf("error processing asset %q: %w", path, err)
-			}
+			appendAssetReport(processRawAsset(store, path, rawProvider, options))
 			return nil
 		})
 	}
@@ -38,115 +101,162 @@ func Run(store resource.Store, pathFilter filter.Func[string]) error {
 			continue // skip this one
 		}
 		g.Go(func() error {
-			if err := processAsset(store, path, modelProvider); err != nil {
-				return fmt.Errorf("error processing asset %q: %w", path, err)
-			}
+			appendAssetReport(processAsset(store, path, modelProvider, options))
 			return nil
 		})
 	}
 
-	return g.Wait()
-}
-
-func processAsset(store resource.Store, path string, provider Provider[any]) error {
-	startTime := time.Now()
+	g.Wait()
+	report.sort()
 
-	currentSourceDigest, err := StringDigest(provider)
-	if err != nil {
-		return fmt.Errorf("error calculating new digest: %w", err)
+	var errs []error
+	for _, assetReport := range report.Assets {
+		if assetReport.Status == AssetStatusFailed {
+			errs = append(errs, fmt.Errorf("error processing asset %q: %s", assetReport.Path, assetReport.Error))
+		}
 	}
 
-	previousSourceDigest, err := openSourceDigest(store, path)
-	if err != nil {
-		return fmt.Errorf("error retrieving old digest: %w", err)
+	if options.PrunePrefix != "" {
+		pruned, err := Prune(store, PruneOptions{
+			Prefix: options.PrunePrefix,
+			DryRun: options.DryRun,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error pruning stale files: %w", err))
+		}
+		report.Pruned = pruned
 	}
 
-	if previousSourceDigest == currentSourceDigest {
-		logger.Info("Asset skipped",
-			slog.String("path", path),
-			slog.String("duration", time.Since(startTime).String()),
-		)
-		return nil
-	}
+	report.Duration = time.Since(startTime)
+	return report, errors.Join(errs...)
+}
 
-	resource, err := provider.Get()
-	if err != nil {
-		return fmt.Errorf("provider failed to produce asset: %w", err)
-	}
+func processAsset(store resource.Store, path string, provider Provider[any], options RunOptions) AssetReport {
+	return processEntry(store, path, AssetKindResource, provider, options, func() (slog.Attr, error) {
+		resource, err := provider.Get()
+		if err != nil {
+			return slog.Attr{}, fmt.Errorf("provider failed to produce asset: %w", err)
+		}
 
-	chunkList := ds.PreallocatedList[chunked.Chunk](1)
-	for _, converter := range registeredConverters {
-		if err := converter.Convert(chunkList, resource); err != nil {
-			return fmt.Errorf("converter %T failed to convert resource: %w", converter, err)
+		chunkList := ds.PreallocatedList[chunked.Chunk](1)
+		for _, converter := range registeredConverters {
+			if err := converter.Convert(chunkList, resource); err != nil {
+				return slog.Attr{}, fmt.Errorf("converter %T failed to convert resource: %w", converter, err)
+			}
 		}
-	}
-	chunks := chunked.ChunkList(chunkList.Unbox())
+		chunks := chunked.ChunkList(chunkList.Unbox())
 
-	asset := chunked.NewAsset(store, path)
-	if err := asset.Write(chunks); err != nil {
-		return fmt.Errorf("error writing chunks: %w", err)
-	}
+		asset := chunked.NewAsset(store, path)
+		if err := asset.Write(chunks); err != nil {
+			return slog.Attr{}, fmt.Errorf("error writing chunks: %w", err)
+		}
+		return slog.Int("chunks", len(chunks)), nil
+	})
+}
 
-	if err := saveSourceDigest(store, path, currentSourceDigest); err != nil {
-		return fmt.Errorf("error saving source digest: %w", err)
-	}
+func processRawAsset(store resource.Store, path string, provider Provider[io.ReadCloser], options RunOptions) AssetReport {
+	return processEntry(store, path, AssetKindRaw, provider, options, func() (slog.Attr, error) {
+		in, err := provider.Get()
+		if err != nil {
+			return slog.Attr{}, fmt.Errorf("provider failed to produce asset: %w", err)
+		}
+		defer in.Close()
 
-	logger.Info("Asset updated",
-		slog.String("path", path),
-		slog.Int("chunks", len(chunks)),
-		slog.String("duration", time.Since(startTime).String()),
-	)
-	return nil
+		out, err := store.Create(path)
+		if err != nil {
+			return slog.Attr{}, fmt.Errorf("error creating asset file: %w", err)
+		}
+		defer out.Close()
+
+		size, err := io.Copy(out, in)
+		if err != nil {
+			return slog.Attr{}, fmt.Errorf("error copying raw asset data: %w", err)
+		}
+		return slog.Int("size", int(size)), nil
+	})
 }
 
-func processRawAsset(store resource.Store, path string, provider Provider[io.ReadCloser]) error {
+// processEntry checks whether the asset at the specified path needs to be
+// rebuilt and if so uses the build function to do so.
+func processEntry(store resource.Store, path string, kind AssetKind, source Digestable, options RunOptions, build func() (slog.Attr, error)) AssetReport {
 	startTime := time.Now()
 
-	currentSourceDigest, err := StringDigest(provider)
+	report := AssetReport{
+		Path: path,
+		Kind: kind,
+	}
+	fail := func(err error) AssetReport {
+		logger.Error("Asset failed",
+			slog.String("path", path),
+			slog.String("error", err.Error()),
+		)
+		report.Status = AssetStatusFailed
+		report.Error = err.Error()
+		report.Duration = time.Since(startTime)
+		return report
+	}
+
+	currentSourceDigest, err := StringDigest(source)
 	if err != nil {
-		return fmt.Errorf("error calculating new digest: %w", err)
+		return fail(fmt.Errorf("error calculating new digest: %w", err))
 	}
+	report.Digest = currentSourceDigest
 
 	previousSourceDigest, err := openSourceDigest(store, path)
 	if err != nil {
-		return fmt.Errorf("error retrieving old digest: %w", err)
+		return fail(fmt.Errorf("error retrieving old digest: %w", err))
+	}
+	report.PreviousDigest = previousSourceDigest
+
+	switch {
+	case options.Force:
+		report.Reason = AssetReasonForced
+	case previousSourceDigest == "":
+		report.Reason = AssetReasonMissingDigest
+	case previousSourceDigest != currentSourceDigest:
+		report.Reason = AssetReasonDigestChanged
+	default:
+		report.Reason = AssetReasonUpToDate
 	}
 
-	if previousSourceDigest == currentSourceDigest {
+	if report.Reason == AssetReasonUpToDate {
 		logger.Info("Asset skipped",
 			slog.String("path", path),
 			slog.String("duration", time.Since(startTime).String()),
 		)
-		return nil
+		report.Status = AssetStatusSkipped
+		report.Duration = time.Since(startTime)
+		return report
 	}
 
-	in, err := provider.Get()
-	if err != nil {
-		return fmt.Errorf("provider failed to produce asset: %w", err)
-	}
-	defer in.Close()
-
-	out, err := store.Create(path)
-	if err != nil {
-		return fmt.Errorf("error creating asset file: %w", err)
+	if options.DryRun {
+		logger.Info("Asset outdated",
+			slog.String("path", path),
+			slog.String("reason", string(report.Reason)),
+		)
+		report.Status = AssetStatusOutdated
+		report.Duration = time.Since(startTime)
+		return report
 	}
-	defer out.Close()
 
-	size, err := io.Copy(out, in)
+	attr, err := build()
+	report.Providers = collectProviderReports(source)
 	if err != nil {
-		return fmt.Errorf("error copying raw asset data: %w", err)
+		return fail(err)
 	}
 
 	if err := saveSourceDigest(store, path, currentSourceDigest); err != nil {
-		return fmt.Errorf("error saving source digest: %w", err)
+		return fail(fmt.Errorf("error saving source digest: %w", err))
 	}
 
 	logger.Info("Asset updated",
 		slog.String("path", path),
-		slog.Int("size", int(size)),
+		attr,
 		slog.String("duration", time.Since(startTime).String()),
 	)
-	return nil
+	report.Status = AssetStatusUpdated
+	report.Duration = time.Since(startTime)
+	return report
 }
 
 func openSourceDigest(store resource.Store, path string) (string, error) {
@@ -180,6 +290,8 @@ func saveSourceDigest(store resource.Store, path string, digest string) error {
 	return nil
 }
 
+const digestSuffix = ".srcsha"
+
 func digestPath(path string) string {
-	return fmt.Sprintf("%s.srcsha", path)
+	return path + digestSuffix
 }
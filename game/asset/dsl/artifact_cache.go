@@ -0,0 +1,312 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const artifactFileExtension = ".artifact"
+
+// artifactFormatVersion is mixed into the key of every cached artifact. It
+// needs to be incremented whenever the binary encoding of a cached value type
+// or the algorithm of a cached provider changes in a way that is not
+// reflected in the provider digests, so that stale artifacts are not loaded.
+const artifactFormatVersion = 2
+
+var (
+	artifactCacheMU sync.Mutex
+	artifactCache   *ArtifactCache
+)
+
+// SetArtifactCache configures the cache that is used by CachedProvider to
+// persist intermediate values between runs. Passing nil disables caching,
+// which is the default.
+func SetArtifactCache(cache *ArtifactCache) {
+	artifactCacheMU.Lock()
+	defer artifactCacheMU.Unlock()
+	artifactCache = cache
+}
+
+func currentArtifactCache() *ArtifactCache {
+	artifactCacheMU.Lock()
+	defer artifactCacheMU.Unlock()
+	return artifactCache
+}
+
+// CachedProvider returns a provider that stores the values produced by the
+// delegate provider in the configured ArtifactCache (see SetArtifactCache),
+// keyed by the delegate's digest. Subsequent runs that request a value with
+// the same digest load it from the cache instead of computing it again.
+//
+// The value type needs to be serializable through encoding/gob, for example
+// by implementing encoding.BinaryMarshaler and encoding.BinaryUnmarshaler.
+//
+// If no cache is configured, the delegate is used directly.
+func CachedProvider[T any](delegate Provider[T]) Provider[T] {
+	result := &describedProvider[T]{
+		info: ProviderInfo{
+			Name:   "artifact-cache",
+			Inputs: []Digestable{delegate},
+		},
+	}
+	result.delegate = OnceProvider(FuncProvider(
+		// get function
+		func() (T, error) {
+			cache := currentArtifactCache()
+			if cache == nil {
+				return delegate.Get()
+			}
+
+			digest, err := delegate.Digest()
+			if err != nil {
+				var zero T
+				return zero, fmt.Errorf("error calculating artifact digest: %w", err)
+			}
+			key, err := artifactKey[T](digest)
+			if err != nil {
+				var zero T
+				return zero, fmt.Errorf("error calculating artifact key: %w", err)
+			}
+
+			var value T
+			if ok, err := cache.load(key, &value); err != nil {
+				logger.Warn("Failed to load cached artifact",
+					slog.String("key", key),
+					slog.String("error", err.Error()),
+				)
+			} else if ok {
+				result.markCacheHit()
+				return value, nil
+			}
+
+			value, err = delegate.Get()
+			if err != nil {
+				return value, err
+			}
+			if err := cache.store(key, value); err != nil {
+				logger.Warn("Failed to store cached artifact",
+					slog.String("key", key),
+					slog.String("error", err.Error()),
+				)
+			}
+			return value, nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return delegate.Digest()
+		},
+	))
+	return result
+}
+
+// artifactKey returns the key under which a value of type T with the
+// specified provider digest is stored.
+func artifactKey[T any](digest []byte) (string, error) {
+	var zero T
+	keyDigest, err := CreateDigest("artifact",
+		artifactFormatVersion,
+		fmt.Sprintf("%T", zero),
+		hex.EncodeToString(digest),
+	)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(keyDigest), nil
+}
+
+// NewArtifactCache creates a new ArtifactCache that stores artifacts in the
+// specified directory. If maxSize is positive, the least recently used
+// artifacts are removed once the total size of the cache exceeds it.
+func NewArtifactCache(dir string, maxSize int64) (*ArtifactCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating artifact cache directory %q: %w", dir, err)
+	}
+	return &ArtifactCache{
+		dir:     dir,
+		maxSize: maxSize,
+	}, nil
+}
+
+// ArtifactCache is an on-disk cache of intermediate values produced by
+// expensive providers.
+type ArtifactCache struct {
+	mu      sync.Mutex
+	dir     string
+	maxSize int64
+}
+
+// Dir returns the directory in which artifacts are stored.
+func (c *ArtifactCache) Dir() string {
+	return c.dir
+}
+
+// MaxSize returns the size in bytes above which artifacts are evicted. A
+// value of zero or less means that there is no limit.
+func (c *ArtifactCache) MaxSize() int64 {
+	return c.maxSize
+}
+
+// Size returns the total size in bytes of all stored artifacts.
+func (c *ArtifactCache) Size() (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.entries()
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, entry := range entries {
+		size += entry.size
+	}
+	return size, nil
+}
+
+// Clear removes all stored artifacts.
+func (c *ArtifactCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, entry := range entries {
+		if err := os.Remove(entry.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("error removing artifact %q: %w", entry.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *ArtifactCache) load(key string, target any) (bool, error) {
+	path := c.artifactPath(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading artifact: %w", err)
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(target); err != nil {
+		// A corrupt artifact should be recomputed.
+		os.Remove(path)
+		return false, fmt.Errorf("error decoding artifact: %w", err)
+	}
+
+	// Mark as recently used.
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return true, fmt.Errorf("error touching artifact: %w", err)
+	}
+	return true, nil
+}
+
+func (c *ArtifactCache) store(key string, value any) error {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return fmt.Errorf("error encoding artifact: %w", err)
+	}
+
+	// Write to a temporary file first, so that concurrent runs never observe
+	// a partially written artifact.
+	tempFile, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating artifact file: %w", err)
+	}
+	tempPath := tempFile.Name()
+	if _, err := tempFile.Write(buffer.Bytes()); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("error writing artifact file: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("error closing artifact file: %w", err)
+	}
+	if err := os.Rename(tempPath, c.artifactPath(key)); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("error renaming artifact file: %w", err)
+	}
+	return c.evict()
+}
+
+func (c *ArtifactCache) evict() error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	var size int64
+	for _, entry := range entries {
+		size += entry.size
+	}
+	slices.SortFunc(entries, func(a, b artifactEntry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	for _, entry := range entries {
+		if size <= c.maxSize {
+			break
+		}
+		if err := os.Remove(entry.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error evicting artifact %q: %w", entry.path, err)
+		}
+		size -= entry.size
+	}
+	return nil
+}
+
+func (c *ArtifactCache) entries() ([]artifactEntry, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading artifact cache directory %q: %w", c.dir, err)
+	}
+	result := make([]artifactEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), artifactFileExtension) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // removed concurrently
+			}
+			return nil, fmt.Errorf("error inspecting artifact %q: %w", dirEntry.Name(), err)
+		}
+		result = append(result, artifactEntry{
+			path:    filepath.Join(c.dir, dirEntry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	return result, nil
+}
+
+func (c *ArtifactCache) artifactPath(key string) string {
+	return filepath.Join(c.dir, key+artifactFileExtension)
+}
+
+type artifactEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
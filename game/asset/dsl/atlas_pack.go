@@ -0,0 +1,332 @@
+package dsl
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+const (
+	// defaultAtlasMaxSize is the default maximum width and height of an
+	// atlas page.
+	defaultAtlasMaxSize = 2048
+
+	// minAtlasPageSize is the minimum width and height of an atlas page,
+	// which keeps pages compatible with block-compressed formats.
+	minAtlasPageSize = 4
+)
+
+type atlasImage struct {
+	name  string
+	image *mdl.Image
+}
+
+type atlasConfig struct {
+	textureConfig
+	images    []atlasImage
+	padding   int
+	extrusion int
+	maxSize   int
+}
+
+func (c *atlasConfig) AddAtlasImage(name string, image *mdl.Image) {
+	c.images = append(c.images, atlasImage{
+		name:  name,
+		image: image,
+	})
+}
+
+func (c *atlasConfig) SetPadding(padding int) {
+	c.padding = padding
+}
+
+func (c *atlasConfig) SetExtrusion(extrusion int) {
+	c.extrusion = extrusion
+}
+
+func (c *atlasConfig) SetMaxSize(size int) {
+	c.maxSize = size
+}
+
+// atlasPlacement represents the location of an image within an atlas page.
+type atlasPlacement struct {
+	image atlasImage
+	page  int
+	rect  atlasRect
+}
+
+// packAtlas distributes the images of the configuration across as few pages
+// as possible and returns the resulting atlas.
+//
+// Each image is surrounded by extruded copies of its edge texels, which
+// prevents neighbouring images from bleeding in when filtering, and by
+// transparent padding. The size of each page is the smallest power of two
+// that fits its images, unless that exceeds the maximum size.
+func packAtlas(cfg atlasConfig) (*mdl.Atlas, error) {
+	if cfg.padding < 0 {
+		return nil, fmt.Errorf("padding %d is negative", cfg.padding)
+	}
+	if cfg.extrusion < 0 {
+		return nil, fmt.Errorf("extrusion %d is negative", cfg.extrusion)
+	}
+	if cfg.maxSize < minAtlasPageSize {
+		return nil, fmt.Errorf("max size %d is smaller than %d", cfg.maxSize, minAtlasPageSize)
+	}
+
+	names := make(map[string]struct{}, len(cfg.images))
+	for _, image := range cfg.images {
+		if _, ok := names[image.name]; ok {
+			return nil, fmt.Errorf("duplicate atlas image %q", image.name)
+		}
+		names[image.name] = struct{}{}
+	}
+
+	// Placing large images first leads to tighter packing. The name is used
+	// as a final criterion so that the result does not depend on the order
+	// in which images were added.
+	pending := slices.Clone(cfg.images)
+	slices.SortFunc(pending, func(a, b atlasImage) int {
+		aWidth, aHeight := a.image.Width(), a.image.Height()
+		bWidth, bHeight := b.image.Width(), b.image.Height()
+		return cmp.Or(
+			cmp.Compare(max(bWidth, bHeight), max(aWidth, aHeight)),
+			cmp.Compare(bWidth*bHeight, aWidth*aHeight),
+			cmp.Compare(a.name, b.name),
+		)
+	})
+
+	// The padding is only added to the right and bottom of each image, so
+	// the page is allowed to overflow by that amount.
+	border := cfg.extrusion
+	footprint := func(image *mdl.Image) (int, int) {
+		return image.Width() + 2*border + cfg.padding, image.Height() + 2*border + cfg.padding
+	}
+	for _, image := range pending {
+		if image.image.Width() == 0 || image.image.Height() == 0 {
+			return nil, fmt.Errorf("atlas image %q is empty", image.name)
+		}
+		width, height := footprint(image.image)
+		if width-cfg.padding > cfg.maxSize || height-cfg.padding > cfg.maxSize {
+			return nil, fmt.Errorf("atlas image %q (%dx%d) does not fit in max size %d", image.name, image.image.Width(), image.image.Height(), cfg.maxSize)
+		}
+	}
+
+	var (
+		placements []atlasPlacement
+		pageSizes  [][2]int
+	)
+	for len(pending) > 0 {
+		page := len(pageSizes)
+		bin := newMaxRectsBin(cfg.maxSize+cfg.padding, cfg.maxSize+cfg.padding)
+
+		var remaining []atlasImage
+		usedWidth, usedHeight := 0, 0
+		for _, image := range pending {
+			width, height := footprint(image.image)
+			rect, ok := bin.Insert(width, height)
+			if !ok {
+				remaining = append(remaining, image)
+				continue
+			}
+			usedWidth = max(usedWidth, rect.x+rect.width-cfg.padding)
+			usedHeight = max(usedHeight, rect.y+rect.height-cfg.padding)
+			placements = append(placements, atlasPlacement{
+				image: image,
+				page:  page,
+				rect: atlasRect{
+					x:      rect.x + border,
+					y:      rect.y + border,
+					width:  image.image.Width(),
+					height: image.image.Height(),
+				},
+			})
+		}
+		pageSizes = append(pageSizes, [2]int{
+			min(atlasPageSize(usedWidth), cfg.maxSize),
+			min(atlasPageSize(usedHeight), cfg.maxSize),
+		})
+		pending = remaining
+	}
+
+	pageImages := make([]*mdl.Image, len(pageSizes))
+	for i, size := range pageSizes {
+		pageImages[i] = mdl.NewImage(size[0], size[1])
+	}
+	for _, placement := range placements {
+		blitExtruded(pageImages[placement.page], placement.image.image, placement.rect.x, placement.rect.y, border)
+	}
+
+	atlas := mdl.NewAtlas()
+	pages := make([]*mdl.Texture, len(pageImages))
+	for i, pageImage := range pageImages {
+		pageImage.SetName(fmt.Sprintf("atlas-page-%d", i))
+		pages[i] = create2DTexture(pageImage, cfg.textureConfig)
+		atlas.AddPage(pages[i])
+	}
+	for _, placement := range placements {
+		pageWidth := float64(pageSizes[placement.page][0])
+		pageHeight := float64(pageSizes[placement.page][1])
+		rect := placement.rect
+		atlas.AddRegion(mdl.AtlasRegion{
+			Name:   placement.image.name,
+			Page:   pages[placement.page],
+			X:      rect.x,
+			Y:      rect.y,
+			Width:  rect.width,
+			Height: rect.height,
+			// Texture rows go from the bottom to the top, whereas image rows
+			// go from the top to the bottom.
+			MinUV: sprec.NewVec2(
+				float32(float64(rect.x)/pageWidth),
+				float32(1.0-float64(rect.y+rect.height)/pageHeight),
+			),
+			MaxUV: sprec.NewVec2(
+				float32(float64(rect.x+rect.width)/pageWidth),
+				float32(1.0-float64(rect.y)/pageHeight),
+			),
+		})
+	}
+	return atlas, nil
+}
+
+// atlasPageSize returns the smallest power of two that is large enough to
+// hold the specified number of texels.
+func atlasPageSize(used int) int {
+	size := minAtlasPageSize
+	for size < used {
+		size *= 2
+	}
+	return size
+}
+
+// blitExtruded copies the source image into the target at the specified
+// position and repeats the edge texels of the source outwards by the
+// specified number of texels.
+func blitExtruded(target, source *mdl.Image, x, y, extrusion int) {
+	width, height := source.Width(), source.Height()
+	for dy := -extrusion; dy < height+extrusion; dy++ {
+		for dx := -extrusion; dx < width+extrusion; dx++ {
+			texel := source.Texel(min(max(dx, 0), width-1), min(max(dy, 0), height-1))
+			target.SetTexel(x+dx, y+dy, texel)
+		}
+	}
+}
+
+type atlasRect struct {
+	x      int
+	y      int
+	width  int
+	height int
+}
+
+func (r atlasRect) contains(other atlasRect) bool {
+	return other.x >= r.x && other.y >= r.y &&
+		other.x+other.width <= r.x+r.width &&
+		other.y+other.height <= r.y+r.height
+}
+
+func (r atlasRect) intersects(other atlasRect) bool {
+	return other.x < r.x+r.width && other.x+other.width > r.x &&
+		other.y < r.y+r.height && other.y+other.height > r.y
+}
+
+func newMaxRectsBin(width, height int) *maxRectsBin {
+	return &maxRectsBin{
+		freeRects: []atlasRect{
+			{x: 0, y: 0, width: width, height: height},
+		},
+	}
+}
+
+// maxRectsBin implements the MaxRects bin packing algorithm (Jukka Jylänki),
+// which tracks the maximal free rectangles of a bin, using the best short
+// side fit heuristic.
+type maxRectsBin struct {
+	freeRects []atlasRect
+}
+
+// Insert finds a location for a rectangle with the specified size and marks
+// it as used. It returns false if there is no room left for the rectangle.
+func (b *maxRectsBin) Insert(width, height int) (atlasRect, bool) {
+	var (
+		best          atlasRect
+		bestShortSide = math.MaxInt
+		bestLongSide  = math.MaxInt
+		found         bool
+	)
+	for _, free := range b.freeRects {
+		if width > free.width || height > free.height {
+			continue
+		}
+		leftoverX := free.width - width
+		leftoverY := free.height - height
+		shortSide, longSide := min(leftoverX, leftoverY), max(leftoverX, leftoverY)
+		if shortSide < bestShortSide || (shortSide == bestShortSide && longSide < bestLongSide) {
+			best = atlasRect{x: free.x, y: free.y, width: width, height: height}
+			bestShortSide = shortSide
+			bestLongSide = longSide
+			found = true
+		}
+	}
+	if !found {
+		return atlasRect{}, false
+	}
+	b.place(best)
+	return best, true
+}
+
+func (b *maxRectsBin) place(used atlasRect) {
+	var freeRects []atlasRect
+	for _, free := range b.freeRects {
+		if !free.intersects(used) {
+			freeRects = append(freeRects, free)
+			continue
+		}
+		// Split the free rectangle into the maximal rectangles that remain
+		// on each side of the used one.
+		if used.x > free.x {
+			freeRects = append(freeRects, atlasRect{
+				x: free.x, y: free.y, width: used.x - free.x, height: free.height,
+			})
+		}
+		if used.x+used.width < free.x+free.width {
+			freeRects = append(freeRects, atlasRect{
+				x: used.x + used.width, y: free.y, width: free.x + free.width - used.x - used.width, height: free.height,
+			})
+		}
+		if used.y > free.y {
+			freeRects = append(freeRects, atlasRect{
+				x: free.x, y: free.y, width: free.width, height: used.y - free.y,
+			})
+		}
+		if used.y+used.height < free.y+free.height {
+			freeRects = append(freeRects, atlasRect{
+				x: free.x, y: used.y + used.height, width: free.width, height: free.y + free.height - used.y - used.height,
+			})
+		}
+	}
+
+	// Remove rectangles that are fully contained in others, since they can
+	// never lead to a better placement.
+	b.freeRects = b.freeRects[:0]
+	for i, rect := range freeRects {
+		redundant := false
+		for j, other := range freeRects {
+			if i == j || !other.contains(rect) {
+				continue
+			}
+			// Of two identical rectangles only the first one is kept.
+			if rect != other || j < i {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			b.freeRects = append(b.freeRects, rect)
+		}
+	}
+}
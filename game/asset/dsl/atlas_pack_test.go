@@ -0,0 +1,221 @@
+package dsl_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("PackAtlas", func() {
+	type rect struct {
+		x, y, width, height int
+	}
+
+	intersects := func(a, b rect) bool {
+		return a.x < b.x+b.width && b.x < a.x+a.width &&
+			a.y < b.y+b.height && b.y < a.y+a.height
+	}
+
+	// createImage creates an image where every texel has a distinct color
+	// that survives the conversion to 8 bits.
+	createImage := func(index, width, height int) *mdl.Image {
+		image := mdl.NewImage(width, height)
+		for y := range height {
+			for x := range width {
+				image.SetTexel(x, y, mdl.Color{
+					R: (float64(index%256) + 0.5) / 255.0,
+					G: (float64(x%256) + 0.5) / 255.0,
+					B: (float64(y%256) + 0.5) / 255.0,
+					A: 1.0,
+				})
+			}
+		}
+		return image
+	}
+
+	rgba8 := func(color mdl.Color) [4]byte {
+		r, g, b, a := color.RGBA8()
+		return [4]byte{r, g, b, a}
+	}
+
+	pageSize := func(page *mdl.Texture) (int, int) {
+		layer := page.MipmapLayers()[0]
+		return layer.Width(), layer.Height()
+	}
+
+	// pageTexel returns the texel of the page at the specified texture
+	// coordinates, where rows go from the bottom to the top.
+	pageTexel := func(page *mdl.Texture, x, y int) [4]byte {
+		width, _ := pageSize(page)
+		data := page.MipmapLayers()[0].Layers()[0].Data()
+		offset := (y*width + x) * 4
+		return [4]byte(data[offset : offset+4])
+	}
+
+	It("places images without overlap", func() {
+		const (
+			padding   = 2
+			extrusion = 1
+			maxSize   = 128
+		)
+		random := rand.New(rand.NewPCG(5, 6))
+		var images []dsl.AtlasImage
+		for i := range 60 {
+			images = append(images, dsl.AtlasImage{
+				Name:  fmt.Sprintf("image-%d", i),
+				Image: createImage(i, 1+random.IntN(40), 1+random.IntN(40)),
+			})
+		}
+
+		atlas, err := dsl.PackAtlas(images, padding, extrusion, maxSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(atlas.Regions()).To(HaveLen(len(images)))
+
+		footprints := make(map[*mdl.Texture][]rect)
+		for i, image := range images {
+			region, ok := atlas.FindRegion(image.Name)
+			Expect(ok).To(BeTrue())
+			Expect(region.Width).To(Equal(image.Image.Width()))
+			Expect(region.Height).To(Equal(image.Image.Height()))
+
+			pageWidth, pageHeight := pageSize(region.Page)
+			Expect(pageWidth).To(BeNumerically("<=", maxSize))
+			Expect(pageHeight).To(BeNumerically("<=", maxSize))
+			Expect(region.X - extrusion).To(BeNumerically(">=", 0))
+			Expect(region.Y - extrusion).To(BeNumerically(">=", 0))
+			Expect(region.X + region.Width + extrusion).To(BeNumerically("<=", pageWidth))
+			Expect(region.Y + region.Height + extrusion).To(BeNumerically("<=", pageHeight))
+
+			footprint := rect{
+				x:      region.X - extrusion,
+				y:      region.Y - extrusion,
+				width:  region.Width + 2*extrusion + padding,
+				height: region.Height + 2*extrusion + padding,
+			}
+			for j, other := range footprints[region.Page] {
+				Expect(intersects(footprint, other)).To(BeFalse(), "image %d overlaps another one (%d) on its page", i, j)
+			}
+			footprints[region.Page] = append(footprints[region.Page], footprint)
+		}
+	})
+
+	It("surrounds images with extruded texels and transparent padding", func() {
+		const (
+			padding   = 3
+			extrusion = 2
+		)
+		images := []dsl.AtlasImage{
+			{Name: "first", Image: createImage(1, 5, 4)},
+			{Name: "second", Image: createImage(2, 3, 6)},
+			{Name: "third", Image: createImage(3, 2, 2)},
+		}
+		atlas, err := dsl.PackAtlas(images, padding, extrusion, 64)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(atlas.Pages()).To(HaveLen(1))
+		page := atlas.Pages()[0]
+		pageWidth, pageHeight := pageSize(page)
+
+		covered := make(map[[2]int]bool)
+		for _, image := range images {
+			region, ok := atlas.FindRegion(image.Name)
+			Expect(ok).To(BeTrue())
+			width, height := image.Image.Width(), image.Image.Height()
+			for dy := -extrusion; dy < height+extrusion; dy++ {
+				for dx := -extrusion; dx < width+extrusion; dx++ {
+					x, y := region.X+dx, region.Y+dy
+					covered[[2]int{x, y}] = true
+					expected := image.Image.Texel(min(max(dx, 0), width-1), min(max(dy, 0), height-1))
+					Expect(pageTexel(page, x, pageHeight-y-1)).To(Equal(rgba8(expected)))
+				}
+			}
+		}
+		for y := range pageHeight {
+			for x := range pageWidth {
+				if !covered[[2]int{x, y}] {
+					Expect(pageTexel(page, x, pageHeight-y-1)).To(Equal([4]byte{}))
+				}
+			}
+		}
+	})
+
+	It("overflows into multiple pages", func() {
+		var images []dsl.AtlasImage
+		for i := range 5 {
+			images = append(images, dsl.AtlasImage{
+				Name:  fmt.Sprintf("image-%d", i),
+				Image: createImage(i, 32, 32),
+			})
+		}
+		atlas, err := dsl.PackAtlas(images, 0, 0, 64)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(atlas.Pages()).To(HaveLen(2))
+
+		width, height := pageSize(atlas.Pages()[0])
+		Expect(width).To(Equal(64))
+		Expect(height).To(Equal(64))
+		width, height = pageSize(atlas.Pages()[1])
+		Expect(width).To(Equal(32))
+		Expect(height).To(Equal(32))
+
+		regionsPerPage := make(map[*mdl.Texture]int)
+		for _, region := range atlas.Regions() {
+			regionsPerPage[region.Page]++
+		}
+		Expect(regionsPerPage[atlas.Pages()[0]]).To(Equal(4))
+		Expect(regionsPerPage[atlas.Pages()[1]]).To(Equal(1))
+	})
+
+	It("matches tex coords with the texels of the images", func() {
+		images := []dsl.AtlasImage{
+			{Name: "wide", Image: createImage(1, 12, 3)},
+			{Name: "tall", Image: createImage(2, 4, 9)},
+			{Name: "small", Image: createImage(3, 2, 2)},
+		}
+		atlas, err := dsl.PackAtlas(images, 1, 1, 64)
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, image := range images {
+			region, ok := atlas.FindRegion(image.Name)
+			Expect(ok).To(BeTrue())
+			pageWidth, pageHeight := pageSize(region.Page)
+
+			// The pixel rect has its origin at the top-left corner of the page,
+			// whereas tex coords have theirs at the bottom-left corner.
+			Expect(region.MinUV.X * float32(pageWidth)).To(BeNumerically("~", region.X, 1e-3))
+			Expect(region.MaxUV.X * float32(pageWidth)).To(BeNumerically("~", region.X+region.Width, 1e-3))
+			Expect(region.MinUV.Y * float32(pageHeight)).To(BeNumerically("~", pageHeight-region.Y-region.Height, 1e-3))
+			Expect(region.MaxUV.Y * float32(pageHeight)).To(BeNumerically("~", pageHeight-region.Y, 1e-3))
+
+			// Sampling at the tex coords of a texel yields that texel.
+			width, height := image.Image.Width(), image.Image.Height()
+			for y := range height {
+				for x := range width {
+					u := region.MinUV.X + (float32(x)+0.5)/float32(width)*(region.MaxUV.X-region.MinUV.X)
+					v := region.MaxUV.Y - (float32(y)+0.5)/float32(height)*(region.MaxUV.Y-region.MinUV.Y)
+					texel := pageTexel(region.Page, int(u*float32(pageWidth)), int(v*float32(pageHeight)))
+					Expect(texel).To(Equal(rgba8(image.Image.Texel(x, y))))
+				}
+			}
+		}
+	})
+
+	It("rejects duplicate image names", func() {
+		_, err := dsl.PackAtlas([]dsl.AtlasImage{
+			{Name: "icon", Image: createImage(1, 4, 4)},
+			{Name: "icon", Image: createImage(2, 8, 8)},
+		}, 0, 0, 64)
+		Expect(err).To(MatchError(ContainSubstring(`duplicate atlas image "icon"`)))
+	})
+
+	It("rejects images that do not fit", func() {
+		_, err := dsl.PackAtlas([]dsl.AtlasImage{
+			{Name: "large", Image: createImage(1, 62, 62)},
+		}, 0, 2, 64)
+		Expect(err).To(MatchError(ContainSubstring("does not fit")))
+	})
+})
@@ -0,0 +1,392 @@
+package dsl
+
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+// decompositionSplitCandidates is the maximum number of split planes that
+// are evaluated along each axis when a part is split.
+const decompositionSplitCandidates = 8
+
+type convexDecompositionConfig struct {
+	maxHulls    int
+	vertexLimit int
+	resolution  int
+	concavity   float64
+}
+
+// decomposeConvex splits the volume enclosed by the specified triangles into
+// approximately convex parts and returns the convex hull of each part.
+//
+// The volume is voxelized and then recursively split with axis-aligned planes
+// at the position that minimizes the concavity of the two halves, where the
+// concavity of a part is the volume of its hull that is not occupied by the
+// part. Splitting stops once the concavity of every part is below the
+// configured portion of the total volume or the maximum number of hulls is
+// reached.
+//
+// The hulls of the parts pass through the centers of the boundary voxels, so
+// they can be smaller than the geometry by up to half a voxel.
+func decomposeConvex(triangles []mdl.CollisionTriangle, cfg convexDecompositionConfig) [][]mdl.CollisionTriangle {
+	var points []dprec.Vec3
+	for _, triangle := range triangles {
+		points = append(points, triangle.A, triangle.B, triangle.C)
+	}
+	exactHull, ok := computeConvexHull(points, cfg.vertexLimit)
+	if !ok {
+		return nil
+	}
+
+	grid := newVoxelGrid(triangles, cfg.resolution)
+	root := grid.solidPart()
+	totalVolume := root.volume(grid)
+	if totalVolume <= 0.0 {
+		return [][]mdl.CollisionTriangle{exactHull}
+	}
+	maxConcavity := cfg.concavity * totalVolume
+
+	// A mesh that is convex enough is better represented by its exact hull,
+	// since the voxel hulls are only accurate to the size of a voxel.
+	if convexHullVolume(exactHull)-totalVolume <= maxConcavity || cfg.maxHulls <= 1 {
+		return [][]mdl.CollisionTriangle{exactHull}
+	}
+
+	parts := []*voxelPart{root}
+	root.evaluate(grid, cfg.vertexLimit)
+	for len(parts) < cfg.maxHulls {
+		worstIndex := -1
+		for i, part := range parts {
+			if part.concavity <= maxConcavity || part.unsplittable {
+				continue
+			}
+			if worstIndex < 0 || part.concavity > parts[worstIndex].concavity {
+				worstIndex = i
+			}
+		}
+		if worstIndex < 0 {
+			break
+		}
+		worst := parts[worstIndex]
+		left, right, ok := worst.split(grid, cfg.vertexLimit)
+		if !ok {
+			worst.unsplittable = true
+			continue
+		}
+		parts[worstIndex] = left
+		parts = append(parts, right)
+	}
+
+	result := make([][]mdl.CollisionTriangle, 0, len(parts))
+	for _, part := range parts {
+		if part.hull != nil {
+			result = append(result, part.hull)
+		}
+	}
+	return result
+}
+
+type voxelCoord struct {
+	X int
+	Y int
+	Z int
+}
+
+func newVoxelGrid(triangles []mdl.CollisionTriangle, resolution int) *voxelGrid {
+	minBound := dprec.NewVec3(dprec.Inf(1), dprec.Inf(1), dprec.Inf(1))
+	maxBound := dprec.NewVec3(dprec.Inf(-1), dprec.Inf(-1), dprec.Inf(-1))
+	for _, triangle := range triangles {
+		for _, point := range [3]dprec.Vec3{triangle.A, triangle.B, triangle.C} {
+			minBound = dprec.NewVec3(min(minBound.X, point.X), min(minBound.Y, point.Y), min(minBound.Z, point.Z))
+			maxBound = dprec.NewVec3(max(maxBound.X, point.X), max(maxBound.Y, point.Y), max(maxBound.Z, point.Z))
+		}
+	}
+	extent := dprec.Vec3Diff(maxBound, minBound)
+	voxelSize := max(extent.X, extent.Y, extent.Z) / float64(resolution)
+
+	// A border of empty voxels around the mesh allows the exterior to be
+	// flood-filled from a single corner.
+	origin := dprec.Vec3Diff(minBound, dprec.NewVec3(voxelSize, voxelSize, voxelSize))
+	grid := &voxelGrid{
+		origin:    origin,
+		voxelSize: voxelSize,
+		sizeX:     int(extent.X/voxelSize) + 3,
+		sizeY:     int(extent.Y/voxelSize) + 3,
+		sizeZ:     int(extent.Z/voxelSize) + 3,
+	}
+	grid.cells = make([]voxelState, grid.sizeX*grid.sizeY*grid.sizeZ)
+	for _, triangle := range triangles {
+		grid.rasterizeTriangle(triangle)
+	}
+	grid.fillInterior()
+	return grid
+}
+
+type voxelState uint8
+
+const (
+	voxelEmpty voxelState = iota
+	voxelSurface
+	voxelExterior
+)
+
+type voxelGrid struct {
+	origin    dprec.Vec3
+	voxelSize float64
+	sizeX     int
+	sizeY     int
+	sizeZ     int
+	cells     []voxelState
+}
+
+func (g *voxelGrid) contains(coord voxelCoord) bool {
+	return coord.X >= 0 && coord.X < g.sizeX &&
+		coord.Y >= 0 && coord.Y < g.sizeY &&
+		coord.Z >= 0 && coord.Z < g.sizeZ
+}
+
+func (g *voxelGrid) cell(coord voxelCoord) *voxelState {
+	return &g.cells[(coord.Z*g.sizeY+coord.Y)*g.sizeX+coord.X]
+}
+
+func (g *voxelGrid) coordOf(point dprec.Vec3) voxelCoord {
+	relative := dprec.Vec3Quot(dprec.Vec3Diff(point, g.origin), g.voxelSize)
+	return voxelCoord{
+		X: int(relative.X),
+		Y: int(relative.Y),
+		Z: int(relative.Z),
+	}
+}
+
+func (g *voxelGrid) voxelCenter(coord voxelCoord) dprec.Vec3 {
+	return dprec.Vec3Sum(g.origin, dprec.Vec3Prod(dprec.NewVec3(
+		float64(coord.X)+0.5,
+		float64(coord.Y)+0.5,
+		float64(coord.Z)+0.5,
+	), g.voxelSize))
+}
+
+func (g *voxelGrid) voxelVolume() float64 {
+	return g.voxelSize * g.voxelSize * g.voxelSize
+}
+
+// rasterizeTriangle marks the voxels that the triangle passes through by
+// sampling it at intervals of half a voxel.
+func (g *voxelGrid) rasterizeTriangle(triangle mdl.CollisionTriangle) {
+	longestEdge := max(
+		dprec.Vec3Diff(triangle.B, triangle.A).Length(),
+		dprec.Vec3Diff(triangle.C, triangle.B).Length(),
+		dprec.Vec3Diff(triangle.A, triangle.C).Length(),
+	)
+	steps := int(2.0*longestEdge/g.voxelSize) + 1
+	for i := 0; i <= steps; i++ {
+		for j := 0; j <= steps-i; j++ {
+			u := float64(i) / float64(steps)
+			v := float64(j) / float64(steps)
+			point := dprec.Vec3Sum(triangle.A, dprec.Vec3Sum(
+				dprec.Vec3Prod(dprec.Vec3Diff(triangle.B, triangle.A), u),
+				dprec.Vec3Prod(dprec.Vec3Diff(triangle.C, triangle.A), v),
+			))
+			if coord := g.coordOf(point); g.contains(coord) {
+				*g.cell(coord) = voxelSurface
+			}
+		}
+	}
+}
+
+// fillInterior marks all voxels that are reachable from the border without
+// crossing the surface as exterior. The remaining empty voxels are inside.
+func (g *voxelGrid) fillInterior() {
+	pending := []voxelCoord{{}}
+	*g.cell(voxelCoord{}) = voxelExterior
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, neighbour := range voxelNeighbours(current) {
+			if !g.contains(neighbour) {
+				continue
+			}
+			if cell := g.cell(neighbour); *cell == voxelEmpty {
+				*cell = voxelExterior
+				pending = append(pending, neighbour)
+			}
+		}
+	}
+}
+
+func (g *voxelGrid) solidPart() *voxelPart {
+	part := &voxelPart{
+		voxels: make(map[voxelCoord]struct{}),
+	}
+	for z := range g.sizeZ {
+		for y := range g.sizeY {
+			for x := range g.sizeX {
+				coord := voxelCoord{X: x, Y: y, Z: z}
+				if *g.cell(coord) != voxelExterior {
+					part.add(coord)
+				}
+			}
+		}
+	}
+	return part
+}
+
+func voxelNeighbours(coord voxelCoord) [6]voxelCoord {
+	return [6]voxelCoord{
+		{X: coord.X - 1, Y: coord.Y, Z: coord.Z},
+		{X: coord.X + 1, Y: coord.Y, Z: coord.Z},
+		{X: coord.X, Y: coord.Y - 1, Z: coord.Z},
+		{X: coord.X, Y: coord.Y + 1, Z: coord.Z},
+		{X: coord.X, Y: coord.Y, Z: coord.Z - 1},
+		{X: coord.X, Y: coord.Y, Z: coord.Z + 1},
+	}
+}
+
+type voxelPart struct {
+	voxels       map[voxelCoord]struct{}
+	minCoord     voxelCoord
+	maxCoord     voxelCoord
+	hull         []mdl.CollisionTriangle
+	concavity    float64
+	unsplittable bool
+}
+
+func (p *voxelPart) add(coord voxelCoord) {
+	if len(p.voxels) == 0 {
+		p.minCoord, p.maxCoord = coord, coord
+	} else {
+		p.minCoord = voxelCoord{X: min(p.minCoord.X, coord.X), Y: min(p.minCoord.Y, coord.Y), Z: min(p.minCoord.Z, coord.Z)}
+		p.maxCoord = voxelCoord{X: max(p.maxCoord.X, coord.X), Y: max(p.maxCoord.Y, coord.Y), Z: max(p.maxCoord.Z, coord.Z)}
+	}
+	p.voxels[coord] = struct{}{}
+}
+
+func (p *voxelPart) volume(grid *voxelGrid) float64 {
+	return float64(len(p.voxels)) * grid.voxelVolume()
+}
+
+// boundaryPoints returns the centers of the voxels of the part that have at
+// least one neighbour outside of the part. Inner voxels cannot contribute to
+// the hull.
+func (p *voxelPart) boundaryPoints(grid *voxelGrid) []dprec.Vec3 {
+	var points []dprec.Vec3
+	for coord := range p.voxels {
+		isBoundary := false
+		for _, neighbour := range voxelNeighbours(coord) {
+			if _, ok := p.voxels[neighbour]; !ok {
+				isBoundary = true
+				break
+			}
+		}
+		if !isBoundary {
+			continue
+		}
+		points = append(points, grid.voxelCenter(coord))
+	}
+	return points
+}
+
+// evaluate computes the hull and the concavity of the part, where the
+// concavity is the volume of the voxels that are enclosed by the hull but do
+// not belong to the part.
+func (p *voxelPart) evaluate(grid *voxelGrid, vertexLimit int) {
+	hull, ok := computeConvexHull(p.boundaryPoints(grid), vertexLimit)
+	if !ok {
+		p.hull = nil
+		p.concavity = 0.0
+		return
+	}
+	p.hull = hull
+
+	type plane struct {
+		normal   dprec.Vec3
+		distance float64
+	}
+	planes := make([]plane, 0, len(hull))
+	for _, triangle := range hull {
+		normal := dprec.UnitVec3(dprec.Vec3Cross(
+			dprec.Vec3Diff(triangle.B, triangle.A),
+			dprec.Vec3Diff(triangle.C, triangle.A),
+		))
+		planes = append(planes, plane{
+			normal:   normal,
+			distance: dprec.Vec3Dot(normal, triangle.A),
+		})
+	}
+	epsilon := hullEpsilonFactor * grid.voxelSize * float64(grid.sizeX+grid.sizeY+grid.sizeZ)
+
+	var missingCount int
+	for z := p.minCoord.Z; z <= p.maxCoord.Z; z++ {
+		for y := p.minCoord.Y; y <= p.maxCoord.Y; y++ {
+			for x := p.minCoord.X; x <= p.maxCoord.X; x++ {
+				coord := voxelCoord{X: x, Y: y, Z: z}
+				if _, ok := p.voxels[coord]; ok {
+					continue
+				}
+				center := grid.voxelCenter(coord)
+				inside := true
+				for _, plane := range planes {
+					if dprec.Vec3Dot(plane.normal, center)-plane.distance > epsilon {
+						inside = false
+						break
+					}
+				}
+				if inside {
+					missingCount++
+				}
+			}
+		}
+	}
+	p.concavity = float64(missingCount) * grid.voxelVolume()
+}
+
+// split divides the part with the axis-aligned plane that results in the
+// lowest combined concavity.
+func (p *voxelPart) split(grid *voxelGrid, vertexLimit int) (*voxelPart, *voxelPart, bool) {
+	var bestLeft, bestRight *voxelPart
+	bestConcavity := dprec.Inf(1)
+	for axis := range 3 {
+		low := voxelAxis(p.minCoord, axis)
+		high := voxelAxis(p.maxCoord, axis)
+		span := high - low
+		if span < 1 {
+			continue
+		}
+		step := max(span/decompositionSplitCandidates, 1)
+		for plane := low + step; plane <= high; plane += step {
+			left := &voxelPart{voxels: make(map[voxelCoord]struct{})}
+			right := &voxelPart{voxels: make(map[voxelCoord]struct{})}
+			for coord := range p.voxels {
+				if voxelAxis(coord, axis) < plane {
+					left.add(coord)
+				} else {
+					right.add(coord)
+				}
+			}
+			if len(left.voxels) == 0 || len(right.voxels) == 0 {
+				continue
+			}
+			left.evaluate(grid, vertexLimit)
+			right.evaluate(grid, vertexLimit)
+			if concavity := left.concavity + right.concavity; concavity < bestConcavity {
+				bestConcavity = concavity
+				bestLeft, bestRight = left, right
+			}
+		}
+	}
+	if bestLeft == nil {
+		return nil, nil, false
+	}
+	return bestLeft, bestRight, true
+}
+
+func voxelAxis(coord voxelCoord, axis int) int {
+	switch axis {
+	case 0:
+		return coord.X
+	case 1:
+		return coord.Y
+	default:
+		return coord.Z
+	}
+}
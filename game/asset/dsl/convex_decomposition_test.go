@@ -0,0 +1,147 @@
+package dsl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("DecomposeConvex", func() {
+	const (
+		maxHulls    = 16
+		vertexLimit = 64
+		resolution  = 32
+		concavity   = 0.01
+	)
+
+	quad := func(a, b, c, d dprec.Vec3) []mdl.CollisionTriangle {
+		return []mdl.CollisionTriangle{
+			{A: a, B: b, C: c},
+			{A: a, B: c, C: d},
+		}
+	}
+
+	// extrude creates a closed mesh from the specified counter-clockwise
+	// outline in the XY plane, which is extruded from zero to depth along Z.
+	// The caps are made of the specified rectangles, which cover the outline.
+	extrude := func(outline []dprec.Vec2, caps [][2]dprec.Vec2, depth float64) []mdl.CollisionTriangle {
+		var result []mdl.CollisionTriangle
+		for i, from := range outline {
+			to := outline[(i+1)%len(outline)]
+			result = append(result, quad(
+				dprec.NewVec3(from.X, from.Y, 0.0),
+				dprec.NewVec3(to.X, to.Y, 0.0),
+				dprec.NewVec3(to.X, to.Y, depth),
+				dprec.NewVec3(from.X, from.Y, depth),
+			)...)
+		}
+		for _, rect := range caps {
+			minBound, maxBound := rect[0], rect[1]
+			result = append(result, quad(
+				dprec.NewVec3(minBound.X, minBound.Y, depth),
+				dprec.NewVec3(maxBound.X, minBound.Y, depth),
+				dprec.NewVec3(maxBound.X, maxBound.Y, depth),
+				dprec.NewVec3(minBound.X, maxBound.Y, depth),
+			)...)
+			result = append(result, quad(
+				dprec.NewVec3(minBound.X, minBound.Y, 0.0),
+				dprec.NewVec3(minBound.X, maxBound.Y, 0.0),
+				dprec.NewVec3(maxBound.X, maxBound.Y, 0.0),
+				dprec.NewVec3(maxBound.X, minBound.Y, 0.0),
+			)...)
+		}
+		return result
+	}
+
+	totalVolume := func(hulls [][]mdl.CollisionTriangle) float64 {
+		var result float64
+		for _, hull := range hulls {
+			result += dsl.ConvexHullVolume(hull)
+		}
+		return result
+	}
+
+	It("keeps a single exact hull for a cube", func() {
+		cube := extrude(
+			[]dprec.Vec2{
+				dprec.NewVec2(0.0, 0.0),
+				dprec.NewVec2(2.0, 0.0),
+				dprec.NewVec2(2.0, 2.0),
+				dprec.NewVec2(0.0, 2.0),
+			},
+			[][2]dprec.Vec2{
+				{dprec.NewVec2(0.0, 0.0), dprec.NewVec2(2.0, 2.0)},
+			},
+			2.0,
+		)
+		hulls := dsl.DecomposeConvex(cube, maxHulls, vertexLimit, resolution, concavity)
+		Expect(hulls).To(HaveLen(1))
+		Expect(hulls[0]).To(HaveLen(12))
+		Expect(dsl.ConvexHullVolume(hulls[0])).To(BeNumerically("~", 8.0, 1e-9))
+	})
+
+	Describe("L-shape", func() {
+		var lShape []mdl.CollisionTriangle
+
+		BeforeEach(func() {
+			lShape = extrude(
+				[]dprec.Vec2{
+					dprec.NewVec2(0.0, 0.0),
+					dprec.NewVec2(2.0, 0.0),
+					dprec.NewVec2(2.0, 1.0),
+					dprec.NewVec2(1.0, 1.0),
+					dprec.NewVec2(1.0, 2.0),
+					dprec.NewVec2(0.0, 2.0),
+				},
+				[][2]dprec.Vec2{
+					{dprec.NewVec2(0.0, 0.0), dprec.NewVec2(2.0, 1.0)},
+					{dprec.NewVec2(0.0, 1.0), dprec.NewVec2(1.0, 2.0)},
+				},
+				1.0,
+			)
+		})
+
+		It("splits into multiple hulls", func() {
+			hulls := dsl.DecomposeConvex(lShape, maxHulls, vertexLimit, resolution, concavity)
+			Expect(len(hulls)).To(BeNumerically(">=", 2))
+			Expect(len(hulls)).To(BeNumerically("<=", maxHulls))
+			for _, hull := range hulls {
+				Expect(dsl.ConvexHullVolume(hull)).To(BeNumerically(">", 0.0))
+			}
+
+			// The hulls pass through the voxel centers, so they are somewhat
+			// smaller than the shape itself, but they do not cover the notch
+			// that the single hull would.
+			Expect(totalVolume(hulls)).To(BeNumerically("~", 3.0, 0.5))
+			Expect(totalVolume(hulls)).To(BeNumerically("<", 3.25))
+		})
+
+		It("respects the maximum number of hulls", func() {
+			hulls := dsl.DecomposeConvex(lShape, 1, vertexLimit, resolution, concavity)
+			Expect(hulls).To(HaveLen(1))
+			Expect(dsl.ConvexHullVolume(hulls[0])).To(BeNumerically("~", 3.5, 1e-9))
+		})
+
+		It("respects the vertex limit", func() {
+			const limit = 6
+			hulls := dsl.DecomposeConvex(lShape, maxHulls, limit, resolution, concavity)
+			Expect(hulls).ToNot(BeEmpty())
+			for _, hull := range hulls {
+				Expect(len(hullVertices(hull))).To(BeNumerically("<=", limit))
+			}
+		})
+	})
+
+	It("returns no hulls for flat geometry", func() {
+		flat := quad(
+			dprec.NewVec3(0.0, 0.0, 0.0),
+			dprec.NewVec3(1.0, 0.0, 0.0),
+			dprec.NewVec3(1.0, 1.0, 0.0),
+			dprec.NewVec3(0.0, 1.0, 0.0),
+		)
+		Expect(dsl.DecomposeConvex(flat, maxHulls, vertexLimit, resolution, concavity)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,296 @@
+package dsl
+
+import (
+	"slices"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+// hullEpsilonFactor scales the extent of a point cloud into the distance
+// below which a point is considered to lie on a hull face.
+const hullEpsilonFactor = 1e-7
+
+// computeConvexHull builds the convex hull of the specified points with an
+// incremental quickhull, where at each step the point that is farthest
+// outside of the current hull is added. The returned triangles are ordered
+// so that their normals face outward.
+//
+// At most vertexLimit points are added to the hull, in which case the result
+// is a hull of the most significant points, lying within the exact hull. A
+// vertexLimit of zero or less means no limit.
+//
+// The returned bool is false when the points do not span a volume, in which
+// case no hull can be constructed.
+func computeConvexHull(points []dprec.Vec3, vertexLimit int) ([]mdl.CollisionTriangle, bool) {
+	builder := newHullBuilder(points)
+	if !builder.initialize() {
+		return nil, false
+	}
+	for vertexLimit <= 0 || builder.vertexCount < vertexLimit {
+		if !builder.expand() {
+			break
+		}
+	}
+	return builder.triangles(), true
+}
+
+// convexHullVolume returns the volume enclosed by the specified outward
+// facing hull triangles.
+func convexHullVolume(triangles []mdl.CollisionTriangle) float64 {
+	var volume float64
+	for _, triangle := range triangles {
+		volume += dprec.Vec3Dot(triangle.A, dprec.Vec3Cross(triangle.B, triangle.C))
+	}
+	return volume / 6.0
+}
+
+type hullFace struct {
+	indices  [3]int
+	normal   dprec.Vec3
+	distance float64
+	outside  []int
+	removed  bool
+}
+
+func (f *hullFace) signedDistance(point dprec.Vec3) float64 {
+	return dprec.Vec3Dot(f.normal, point) - f.distance
+}
+
+type hullEdge struct {
+	from int
+	to   int
+}
+
+func newHullBuilder(points []dprec.Vec3) *hullBuilder {
+	unique := make([]dprec.Vec3, 0, len(points))
+	seen := make(map[dprec.Vec3]struct{}, len(points))
+	for _, point := range points {
+		if _, ok := seen[point]; !ok {
+			seen[point] = struct{}{}
+			unique = append(unique, point)
+		}
+	}
+	return &hullBuilder{
+		points: unique,
+	}
+}
+
+type hullBuilder struct {
+	points      []dprec.Vec3
+	faces       []*hullFace
+	epsilon     float64
+	vertexCount int
+}
+
+func (b *hullBuilder) initialize() bool {
+	if len(b.points) < 4 {
+		return false
+	}
+
+	// The first two points are the most distant pair of axis extremes.
+	var extremes [6]int
+	for i, point := range b.points {
+		if point.X < b.points[extremes[0]].X {
+			extremes[0] = i
+		}
+		if point.X > b.points[extremes[1]].X {
+			extremes[1] = i
+		}
+		if point.Y < b.points[extremes[2]].Y {
+			extremes[2] = i
+		}
+		if point.Y > b.points[extremes[3]].Y {
+			extremes[3] = i
+		}
+		if point.Z < b.points[extremes[4]].Z {
+			extremes[4] = i
+		}
+		if point.Z > b.points[extremes[5]].Z {
+			extremes[5] = i
+		}
+	}
+	var first, second int
+	var maxSqrDistance float64
+	for i := range extremes {
+		for j := i + 1; j < len(extremes); j++ {
+			sqrDistance := dprec.Vec3Diff(b.points[extremes[i]], b.points[extremes[j]]).SqrLength()
+			if sqrDistance > maxSqrDistance {
+				maxSqrDistance = sqrDistance
+				first, second = extremes[i], extremes[j]
+			}
+		}
+	}
+	b.epsilon = hullEpsilonFactor * dprec.Sqrt(maxSqrDistance)
+	if maxSqrDistance <= b.epsilon*b.epsilon {
+		return false
+	}
+
+	// The third point is the one farthest from the line of the first two.
+	axis := dprec.UnitVec3(dprec.Vec3Diff(b.points[second], b.points[first]))
+	third := -1
+	var maxLineDistance float64
+	for i, point := range b.points {
+		lineDistance := dprec.Vec3Cross(axis, dprec.Vec3Diff(point, b.points[first])).Length()
+		if lineDistance > maxLineDistance {
+			maxLineDistance = lineDistance
+			third = i
+		}
+	}
+	if third < 0 || maxLineDistance <= b.epsilon {
+		return false
+	}
+
+	// The fourth point is the one farthest from the plane of the first three.
+	normal := dprec.UnitVec3(dprec.Vec3Cross(
+		dprec.Vec3Diff(b.points[second], b.points[first]),
+		dprec.Vec3Diff(b.points[third], b.points[first]),
+	))
+	fourth := -1
+	var maxPlaneDistance float64
+	for i, point := range b.points {
+		planeDistance := dprec.Abs(dprec.Vec3Dot(normal, dprec.Vec3Diff(point, b.points[first])))
+		if planeDistance > maxPlaneDistance {
+			maxPlaneDistance = planeDistance
+			fourth = i
+		}
+	}
+	if fourth < 0 || maxPlaneDistance <= b.epsilon {
+		return false
+	}
+
+	corners := [4]int{first, second, third, fourth}
+	centroid := dprec.Vec3Quot(dprec.Vec3Sum(
+		dprec.Vec3Sum(b.points[first], b.points[second]),
+		dprec.Vec3Sum(b.points[third], b.points[fourth]),
+	), 4.0)
+	for i := range corners {
+		a, c, d := corners[(i+1)%4], corners[(i+2)%4], corners[(i+3)%4]
+		face := b.newFace(a, c, d)
+		if face.signedDistance(centroid) > 0.0 {
+			face = b.newFace(a, d, c)
+		}
+		b.faces = append(b.faces, face)
+	}
+	b.vertexCount = 4
+
+	for i := range b.points {
+		if slices.Contains(corners[:], i) {
+			continue
+		}
+		b.assignOutside(b.faces, i)
+	}
+	return true
+}
+
+// expand adds the point that lies farthest outside of the hull, if there is
+// any, and reports whether the hull has changed.
+func (b *hullBuilder) expand() bool {
+	apex := -1
+	var maxDistance float64
+	for _, face := range b.faces {
+		if face.removed {
+			continue
+		}
+		for _, index := range face.outside {
+			if distance := face.signedDistance(b.points[index]); distance > maxDistance {
+				maxDistance = distance
+				apex = index
+			}
+		}
+	}
+	if apex < 0 {
+		return false
+	}
+	apexPoint := b.points[apex]
+
+	var (
+		visibleFaces []*hullFace
+		orphans      []int
+	)
+	visibleEdges := make(map[hullEdge]struct{})
+	for _, face := range b.faces {
+		if face.removed || face.signedDistance(apexPoint) <= b.epsilon {
+			continue
+		}
+		visibleFaces = append(visibleFaces, face)
+		for i := range 3 {
+			visibleEdges[hullEdge{
+				from: face.indices[i],
+				to:   face.indices[(i+1)%3],
+			}] = struct{}{}
+		}
+	}
+
+	// The horizon consists of the visible edges whose twin belongs to a face
+	// that remains. Connecting them with the apex keeps the orientation.
+	var newFaces []*hullFace
+	for _, face := range visibleFaces {
+		face.removed = true
+		for i := range 3 {
+			from, to := face.indices[i], face.indices[(i+1)%3]
+			if _, ok := visibleEdges[hullEdge{from: to, to: from}]; ok {
+				continue
+			}
+			newFaces = append(newFaces, b.newFace(from, to, apex))
+		}
+		orphans = append(orphans, face.outside...)
+		face.outside = nil
+	}
+	b.faces = append(b.faces, newFaces...)
+	b.vertexCount++
+
+	for _, index := range orphans {
+		if index != apex {
+			b.assignOutside(newFaces, index)
+		}
+	}
+	b.compact()
+	return true
+}
+
+func (b *hullBuilder) newFace(a, c, d int) *hullFace {
+	pointA := b.points[a]
+	normal := dprec.Vec3Cross(
+		dprec.Vec3Diff(b.points[c], pointA),
+		dprec.Vec3Diff(b.points[d], pointA),
+	)
+	if normal.Length() > 0.0 {
+		normal = dprec.UnitVec3(normal)
+	}
+	return &hullFace{
+		indices:  [3]int{a, c, d},
+		normal:   normal,
+		distance: dprec.Vec3Dot(normal, pointA),
+	}
+}
+
+// assignOutside attaches the point to the first of the faces that it lies
+// outside of. Points that are not outside of any face are inside the hull
+// and are dropped.
+func (b *hullBuilder) assignOutside(faces []*hullFace, index int) {
+	for _, face := range faces {
+		if face.signedDistance(b.points[index]) > b.epsilon {
+			face.outside = append(face.outside, index)
+			return
+		}
+	}
+}
+
+func (b *hullBuilder) compact() {
+	b.faces = slices.DeleteFunc(b.faces, func(face *hullFace) bool {
+		return face.removed
+	})
+}
+
+func (b *hullBuilder) triangles() []mdl.CollisionTriangle {
+	result := make([]mdl.CollisionTriangle, len(b.faces))
+	for i, face := range b.faces {
+		result[i] = mdl.CollisionTriangle{
+			A: b.points[face.indices[0]],
+			B: b.points[face.indices[1]],
+			C: b.points[face.indices[2]],
+		}
+	}
+	return result
+}
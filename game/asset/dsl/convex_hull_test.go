@@ -0,0 +1,140 @@
+package dsl_test
+
+import (
+	"math/rand/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+// hullVertices returns the distinct corners of the specified triangles.
+func hullVertices(triangles []mdl.CollisionTriangle) []dprec.Vec3 {
+	seen := make(map[dprec.Vec3]struct{})
+	var result []dprec.Vec3
+	for _, triangle := range triangles {
+		for _, point := range []dprec.Vec3{triangle.A, triangle.B, triangle.C} {
+			if _, ok := seen[point]; !ok {
+				seen[point] = struct{}{}
+				result = append(result, point)
+			}
+		}
+	}
+	return result
+}
+
+// expectOutwardFacing checks that none of the points lie outside of the
+// planes of the hull triangles.
+func expectOutwardFacing(triangles []mdl.CollisionTriangle, points []dprec.Vec3) {
+	for _, triangle := range triangles {
+		normal := dprec.UnitVec3(dprec.Vec3Cross(
+			dprec.Vec3Diff(triangle.B, triangle.A),
+			dprec.Vec3Diff(triangle.C, triangle.A),
+		))
+		for _, point := range points {
+			Expect(dprec.Vec3Dot(normal, dprec.Vec3Diff(point, triangle.A))).To(BeNumerically("<=", 1e-9))
+		}
+	}
+}
+
+var _ = Describe("ComputeConvexHull", func() {
+	var cubeCorners []dprec.Vec3
+
+	BeforeEach(func() {
+		cubeCorners = nil
+		for _, x := range []float64{-1.0, 1.0} {
+			for _, y := range []float64{-1.0, 1.0} {
+				for _, z := range []float64{-1.0, 1.0} {
+					cubeCorners = append(cubeCorners, dprec.NewVec3(x, y, z))
+				}
+			}
+		}
+	})
+
+	It("builds the hull of a cube", func() {
+		points := append([]dprec.Vec3{
+			// Points inside and on the faces of the cube do not contribute
+			// to the hull.
+			dprec.NewVec3(0.0, 0.0, 0.0),
+			dprec.NewVec3(0.5, -0.2, 0.3),
+			dprec.NewVec3(1.0, 0.0, 0.0),
+			dprec.NewVec3(0.0, -1.0, 0.5),
+		}, cubeCorners...)
+
+		hull, ok := dsl.ComputeConvexHull(points, 0)
+		Expect(ok).To(BeTrue())
+		Expect(hull).To(HaveLen(12))
+		Expect(hullVertices(hull)).To(ConsistOf(cubeCorners))
+		Expect(dsl.ConvexHullVolume(hull)).To(BeNumerically("~", 8.0, 1e-9))
+		expectOutwardFacing(hull, points)
+	})
+
+	It("ignores duplicate points", func() {
+		points := append(cubeCorners, cubeCorners...)
+		hull, ok := dsl.ComputeConvexHull(points, 0)
+		Expect(ok).To(BeTrue())
+		Expect(hullVertices(hull)).To(ConsistOf(cubeCorners))
+	})
+
+	It("limits the number of vertices", func() {
+		random := rand.New(rand.NewPCG(3, 4))
+		var points []dprec.Vec3
+		for range 200 {
+			direction := dprec.NewVec3(random.NormFloat64(), random.NormFloat64(), random.NormFloat64())
+			points = append(points, dprec.UnitVec3(direction))
+		}
+
+		fullHull, ok := dsl.ComputeConvexHull(points, 0)
+		Expect(ok).To(BeTrue())
+		Expect(hullVertices(fullHull)).To(HaveLen(200))
+		expectOutwardFacing(fullHull, points)
+
+		hull, ok := dsl.ComputeConvexHull(points, 12)
+		Expect(ok).To(BeTrue())
+		vertices := hullVertices(hull)
+		Expect(vertices).To(HaveLen(12))
+		for _, vertex := range vertices {
+			Expect(points).To(ContainElement(vertex))
+		}
+		expectOutwardFacing(hull, vertices)
+		Expect(dsl.ConvexHullVolume(hull)).To(BeNumerically(">", 0.0))
+		Expect(dsl.ConvexHullVolume(hull)).To(BeNumerically("<", dsl.ConvexHullVolume(fullHull)))
+	})
+
+	DescribeTable("points that do not span a volume",
+		func(points []dprec.Vec3) {
+			hull, ok := dsl.ComputeConvexHull(points, 0)
+			Expect(ok).To(BeFalse())
+			Expect(hull).To(BeEmpty())
+		},
+		Entry("no points", []dprec.Vec3{}),
+		Entry("too few points", []dprec.Vec3{
+			dprec.NewVec3(0.0, 0.0, 0.0),
+			dprec.NewVec3(1.0, 0.0, 0.0),
+			dprec.NewVec3(0.0, 1.0, 0.0),
+		}),
+		Entry("coincident points", []dprec.Vec3{
+			dprec.NewVec3(1.0, 1.0, 1.0),
+			dprec.NewVec3(1.0, 1.0, 1.0),
+			dprec.NewVec3(1.0, 1.0, 1.0),
+			dprec.NewVec3(1.0, 1.0, 1.0),
+		}),
+		Entry("collinear points", []dprec.Vec3{
+			dprec.NewVec3(0.0, 0.0, 0.0),
+			dprec.NewVec3(1.0, 1.0, 1.0),
+			dprec.NewVec3(2.0, 2.0, 2.0),
+			dprec.NewVec3(-3.0, -3.0, -3.0),
+			dprec.NewVec3(0.5, 0.5, 0.5),
+		}),
+		Entry("coplanar points", []dprec.Vec3{
+			dprec.NewVec3(0.0, 0.0, 0.0),
+			dprec.NewVec3(1.0, 0.0, 1.0),
+			dprec.NewVec3(0.0, 1.0, 0.0),
+			dprec.NewVec3(1.0, 1.0, 1.0),
+			dprec.NewVec3(2.0, 0.5, 2.0),
+		}),
+	)
+})
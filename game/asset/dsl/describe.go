@@ -0,0 +1,157 @@
+package dsl
+
+import (
+	"sync"
+	"time"
+)
+
+// ProviderInfo holds information that a provider or operation declares
+// about itself, for the purpose of build graph export and reporting.
+type ProviderInfo struct {
+
+	// Name is a short name that identifies the kind of provider (e.g.
+	// "open-image").
+	Name string
+
+	// Detail is optional additional information, like the path of the source
+	// file that is read by the provider.
+	Detail string
+
+	// Inputs are the providers and operations that this one depends on.
+	Inputs []Digestable
+}
+
+// Describable is implemented by providers and operations that declare
+// information about themselves. Those that do not are omitted from build
+// graphs and reports.
+type Describable interface {
+	Digestable
+
+	// DescribeProvider returns information about the provider.
+	DescribeProvider() ProviderInfo
+
+	// ProviderStats returns information on the evaluation of the provider
+	// during this process.
+	ProviderStats() ProviderStats
+}
+
+// ProviderStats holds information on the evaluation of a provider.
+type ProviderStats struct {
+
+	// Evaluated indicates whether the provider has been evaluated.
+	Evaluated bool
+
+	// CacheHit indicates whether the value was loaded from the artifact cache
+	// instead of being computed.
+	CacheHit bool
+
+	// Duration is the time it took for the first evaluation of the provider
+	// to complete, including the evaluation of its inputs.
+	Duration time.Duration
+
+	// Failed indicates whether the evaluation returned an error.
+	Failed bool
+}
+
+// DescribedProvider returns a provider that behaves like the delegate but
+// declares the specified information about itself and records its
+// evaluation.
+func DescribedProvider[T any](info ProviderInfo, delegate Provider[T]) Provider[T] {
+	return &describedProvider[T]{
+		info:     info,
+		delegate: delegate,
+	}
+}
+
+type describedProvider[T any] struct {
+	providerTrace
+	info     ProviderInfo
+	delegate Provider[T]
+}
+
+func (p *describedProvider[T]) Get() (T, error) {
+	startTime := time.Now()
+	value, err := p.delegate.Get()
+	p.record(time.Since(startTime), err)
+	return value, err
+}
+
+func (p *describedProvider[T]) Digest() ([]byte, error) {
+	return p.delegate.Digest()
+}
+
+func (p *describedProvider[T]) DescribeProvider() ProviderInfo {
+	return p.info
+}
+
+// DescribedOperation returns an operation that behaves like the delegate but
+// declares the specified information about itself and records its
+// application.
+func DescribedOperation(info ProviderInfo, delegate Operation) Operation {
+	return &describedOperation{
+		info:     info,
+		delegate: delegate,
+	}
+}
+
+type describedOperation struct {
+	providerTrace
+	info     ProviderInfo
+	delegate Operation
+}
+
+func (o *describedOperation) Apply(target any) error {
+	startTime := time.Now()
+	err := o.delegate.Apply(target)
+	o.record(time.Since(startTime), err)
+	return err
+}
+
+func (o *describedOperation) Digest() ([]byte, error) {
+	return o.delegate.Digest()
+}
+
+func (o *describedOperation) DescribeProvider() ProviderInfo {
+	return o.info
+}
+
+// providerTrace records the evaluation of a provider. Only the first
+// evaluation is recorded, since subsequent ones are expected to reuse its
+// result.
+type providerTrace struct {
+	traceMU sync.Mutex
+	stats   ProviderStats
+}
+
+func (t *providerTrace) ProviderStats() ProviderStats {
+	t.traceMU.Lock()
+	defer t.traceMU.Unlock()
+	return t.stats
+}
+
+func (t *providerTrace) record(duration time.Duration, err error) {
+	t.traceMU.Lock()
+	defer t.traceMU.Unlock()
+	if t.stats.Evaluated {
+		return
+	}
+	t.stats.Evaluated = true
+	t.stats.Duration = duration
+	t.stats.Failed = err != nil
+}
+
+func (t *providerTrace) markCacheHit() {
+	t.traceMU.Lock()
+	defer t.traceMU.Unlock()
+	t.stats.CacheHit = true
+}
+
+// operationInputs converts a list of operations into a list of provider
+// inputs.
+func operationInputs(operations []Operation) []Digestable {
+	result := make([]Digestable, len(operations))
+	for i, operation := range operations {
+		result[i] = operation
+	}
+	return result
+}
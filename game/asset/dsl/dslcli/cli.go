@@ -0,0 +1,247 @@
+package dslcli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/mokiat/lacking/core/resource"
+	"github.com/mokiat/lacking/game/asset/dsl"
+)
+
+const (
+	// ExitCodeSuccess indicates that all assets were processed successfully.
+	ExitCodeSuccess = 0
+
+	// ExitCodeFailure indicates that at least one asset failed to be
+	// processed.
+	ExitCodeFailure = 1
+
+	// ExitCodeUsage indicates that the command-line arguments were invalid.
+	ExitCodeUsage = 2
+)
+
+// Main parses the command-line arguments of the process, builds the declared
+// assets and exits the process with an appropriate exit code.
+func Main() {
+	os.Exit(Run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Run parses the specified command-line arguments, builds the declared
+// assets and returns the exit code of the operation.
+//
+// The report is written to stdout, while usage information and errors are
+// written to stderr.
+func Run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lacking-dsl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s [flags] [pattern ...]\n\n", flags.Name())
+		fmt.Fprintln(stderr, "Builds all declared assets whose paths match any of the glob patterns.")
+		fmt.Fprintln(stderr, "If no patterns are specified, all declared assets are built.")
+		fmt.Fprintln(stderr, "A \"**\" pattern segment matches any number of path segments.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	var (
+		storeDir    = flags.String("store", "", "directory of the asset store (required)")
+		dryRun      = flags.Bool("dry-run", false, "report which assets would be rebuilt and why, without building them")
+		force       = flags.Bool("force", false, "rebuild assets even if their digests have not changed")
+		parallelism = flags.Int("parallelism", 0, "maximum number of assets to build concurrently (0 means number of CPUs)")
+		jsonOutput  = flags.Bool("json", false, "write the report as JSON")
+		digestCache = flags.String("digest-cache", "", "file in which to persist source file hashes between runs")
+		artifactDir = flags.String("artifact-cache", "", "directory in which to persist expensive intermediate results between runs")
+		artifactMiB = flags.Int64("artifact-cache-size", 0, "maximum size of the artifact cache in MiB (0 means unlimited)")
+		clearCache  = flags.Bool("clear-artifact-cache", false, "remove all artifacts from the artifact cache before running")
+		graphFile   = flags.String("graph", "", "file to which to export the provider dependency graph")
+		graphFormat = flags.String("graph-format", "dot", "format of the exported graph (dot or json)")
+		timings     = flags.Bool("timings", false, "include the evaluated providers of each built asset in the text report")
+		prunePrefix = flags.String("prune", "", "remove undeclared assets and orphan digests within the specified store directory (use \".\" for the whole store)")
+	)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitCodeSuccess
+		}
+		return ExitCodeUsage
+	}
+	if *storeDir == "" {
+		fmt.Fprintln(stderr, "error: the -store flag is required")
+		flags.Usage()
+		return ExitCodeUsage
+	}
+
+	pathFilter, err := dsl.GlobPathFilter(flags.Args()...)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitCodeUsage
+	}
+
+	store, err := resource.NewFileStore(*storeDir)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitCodeUsage
+	}
+
+	if *digestCache != "" {
+		cache, err := dsl.OpenFileDigestCache(*digestCache)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return ExitCodeUsage
+		}
+		dsl.SetFileDigestCache(cache)
+		defer func() {
+			if err := cache.Save(); err != nil {
+				fmt.Fprintf(stderr, "warning: %v\n", err)
+			}
+		}()
+	}
+
+	if *graphFormat != "dot" && *graphFormat != "json" {
+		fmt.Fprintf(stderr, "error: unsupported graph format %q\n", *graphFormat)
+		return ExitCodeUsage
+	}
+	if *clearCache && *artifactDir == "" {
+		fmt.Fprintln(stderr, "error: the -clear-artifact-cache flag requires -artifact-cache")
+		return ExitCodeUsage
+	}
+	if *artifactDir != "" {
+		cache, err := dsl.NewArtifactCache(*artifactDir, *artifactMiB*1024*1024)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return ExitCodeUsage
+		}
+		if *clearCache {
+			if err := cache.Clear(); err != nil {
+				fmt.Fprintf(stderr, "error: %v\n", err)
+				return ExitCodeFailure
+			}
+		}
+		dsl.SetArtifactCache(cache)
+		defer dsl.SetArtifactCache(nil)
+	}
+
+	report, runErr := dsl.RunWithOptions(store, dsl.RunOptions{
+		PathFilter:  pathFilter,
+		DryRun:      *dryRun,
+		Force:       *force,
+		Parallelism: *parallelism,
+		PrunePrefix: *prunePrefix,
+	})
+
+	if *graphFile != "" {
+		if err := writeGraph(*graphFile, *graphFormat); err != nil {
+			fmt.Fprintf(stderr, "error: failed to write graph: %v\n", err)
+			return ExitCodeFailure
+		}
+	}
+
+	if *jsonOutput {
+		err = writeJSONReport(stdout, report)
+	} else {
+		err = writeTextReport(stdout, report, *timings)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to write report: %v\n", err)
+		return ExitCodeFailure
+	}
+
+	if report.HasFailures() {
+		return ExitCodeFailure
+	}
+	if runErr != nil {
+		// Failures that are not attributed to a specific asset or file
+		// (e.g. the store could not be listed) are not part of the report.
+		fmt.Fprintf(stderr, "error: %v\n", runErr)
+		return ExitCodeFailure
+	}
+	return ExitCodeSuccess
+}
+
+func writeJSONReport(out io.Writer, report *dsl.Report) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func writeGraph(path, format string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	graph := dsl.BuildGraph()
+	if format == "json" {
+		return graph.WriteJSON(file)
+	}
+	return graph.WriteDOT(file)
+}
+
+func writeTextReport(out io.Writer, report *dsl.Report, timings bool) error {
+	table := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "PATH\tKIND\tSTATUS\tREASON\tDURATION")
+	for _, asset := range report.Assets {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", asset.Path, asset.Kind, asset.Status, asset.Reason, asset.Duration)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	for _, asset := range report.Assets {
+		if asset.Status == dsl.AssetStatusFailed {
+			fmt.Fprintf(out, "\n%s: %s\n", asset.Path, asset.Error)
+		}
+	}
+
+	if timings {
+		for _, asset := range report.Assets {
+			if len(asset.Providers) == 0 {
+				continue
+			}
+			fmt.Fprintf(out, "\n%s:\n", asset.Path)
+			table = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(table, "  PROVIDER\tDETAIL\tSTATUS\tDURATION")
+			for _, provider := range asset.Providers {
+				fmt.Fprintf(table, "  %s\t%s\t%s\t%s\n", provider.Name, provider.Detail, provider.Status, provider.Duration)
+			}
+			if err := table.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(report.Pruned) > 0 {
+		fmt.Fprintln(out)
+		table = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "STALE PATH\tREASON\tDELETED")
+		for _, file := range report.Pruned {
+			fmt.Fprintf(table, "%s\t%s\t%t\n", file.Path, file.Reason, file.Deleted)
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+		for _, file := range report.Pruned {
+			if file.Error != "" {
+				fmt.Fprintf(out, "\n%s: %s\n", file.Path, file.Error)
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(out, "\nupdated: %d, outdated: %d, skipped: %d, failed: %d (%s)\n",
+		report.Count(dsl.AssetStatusUpdated),
+		report.Count(dsl.AssetStatusOutdated),
+		report.Count(dsl.AssetStatusSkipped),
+		report.Count(dsl.AssetStatusFailed),
+		report.Duration,
+	)
+	return err
+}
@@ -0,0 +1,21 @@
+// Package dslcli provides a command-line entry point for building the
+// assets that are declared through the dsl package.
+//
+// A project only needs to import its asset declarations for their side
+// effects and call Main:
+//
+//	package main
+//
+//	import (
+//		"github.com/mokiat/lacking/game/asset/dsl/dslcli"
+//
+//		_ "example.com/project/resources"
+//	)
+//
+//	func main() {
+//		dslcli.Main()
+//	}
+//
+// Alternatively, the lacking-dsl command can generate such a program on the
+// fly.
+package dslcli
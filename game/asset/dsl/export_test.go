@@ -0,0 +1,78 @@
+package dsl
+
+import (
+	"io"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/qmuntal/gltf"
+)
+
+// ReadGLTFJSON exposes readGLTFJSON to the external test package.
+var ReadGLTFJSON = readGLTFJSON
+
+// ParseOBJDocument exposes parseOBJDocument to the external test package.
+// Referenced material libraries are opened relative to the specified path
+// of the OBJ file.
+func ParseOBJDocument(in io.Reader, path string) (*gltf.Document, error) {
+	return parseOBJDocument(in, newGLTFResolver(path, nil))
+}
+
+// SimplifyGeometry exposes simplifyGeometry to the external test package.
+var SimplifyGeometry = simplifyGeometry
+
+// GenerateGeometryTangents exposes generateGeometryTangents to the external
+// test package.
+var GenerateGeometryTangents = generateGeometryTangents
+
+// GeometryACMR exposes geometryACMR to the external test package.
+var GeometryACMR = geometryACMR
+
+// WeldGeometryVertices exposes weldGeometryVertices to the external test
+// package.
+var WeldGeometryVertices = weldGeometryVertices
+
+// ComputeConvexHull exposes computeConvexHull to the external test package.
+var ComputeConvexHull = computeConvexHull
+
+// ConvexHullVolume exposes convexHullVolume to the external test package.
+var ConvexHullVolume = convexHullVolume
+
+// DecomposeConvex exposes decomposeConvex to the external test package.
+func DecomposeConvex(triangles []mdl.CollisionTriangle, maxHulls, vertexLimit, resolution int, concavity float64) [][]mdl.CollisionTriangle {
+	return decomposeConvex(triangles, convexDecompositionConfig{
+		maxHulls:    maxHulls,
+		vertexLimit: vertexLimit,
+		resolution:  resolution,
+		concavity:   concavity,
+	})
+}
+
+// AtlasImage is a named image that is passed to PackAtlas.
+type AtlasImage struct {
+	Name  string
+	Image *mdl.Image
+}
+
+// PackAtlas exposes packAtlas to the external test package. The pages of
+// the resulting atlas use the RGBA8 format.
+func PackAtlas(images []AtlasImage, padding, extrusion, maxSize int) (*mdl.Atlas, error) {
+	cfg := atlasConfig{
+		padding:   padding,
+		extrusion: extrusion,
+		maxSize:   maxSize,
+	}
+	for _, image := range images {
+		cfg.AddAtlasImage(image.Name, image.Image)
+	}
+	return packAtlas(cfg)
+}
+
+// GenerateImageMipmaps exposes generateImageMipmaps to the external test
+// package.
+func GenerateImageMipmaps(image *mdl.Image, filter MipmapFilter, alphaThreshold float64, normalMap, linear bool) []*mdl.Image {
+	return generateImageMipmaps(image, mipmapConfig{
+		filter:         filter,
+		alphaThreshold: alphaThreshold,
+		normalMap:      normalMap,
+	}, linear)
+}
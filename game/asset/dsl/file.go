@@ -8,7 +8,10 @@ import (
 
 // StreamFile streams a binary file from the provided path.
 func StreamFile(path string) Provider[io.ReadCloser] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "stream-file",
+		Detail: path,
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (io.ReadCloser, error) {
 			file, err := os.Open(path)
@@ -20,11 +23,11 @@ func StreamFile(path string) Provider[io.ReadCloser] {
 
 		// digest function
 		func() ([]byte, error) {
-			info, err := os.Stat(path)
+			contentDigest, err := FileContentDigest(path)
 			if err != nil {
-				return nil, fmt.Errorf("failed to stat file %q: %w", path, err)
+				return nil, err
 			}
-			return CreateDigest("stream-file", path, info.ModTime())
+			return CreateDigest("stream-file", contentDigest)
 		},
-	))
+	)))
 }
@@ -0,0 +1,179 @@
+package dsl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	fileDigestCacheMU sync.Mutex
+	fileDigestCache   = NewFileDigestCache()
+)
+
+// SetFileDigestCache changes the cache that is used to avoid rehashing of
+// source files that have not changed.
+//
+// By default an in-memory cache is used, which means that all source files
+// are hashed once per process.
+func SetFileDigestCache(cache *FileDigestCache) {
+	fileDigestCacheMU.Lock()
+	defer fileDigestCacheMU.Unlock()
+	fileDigestCache = cache
+}
+
+// FileContentDigest returns the hex-encoded SHA-256 hash of the contents of
+// the file at the specified path.
+//
+// The hash is retrieved from the current file digest cache if the size and
+// modification time of the file have not changed since it was last hashed.
+func FileContentDigest(path string) (string, error) {
+	fileDigestCacheMU.Lock()
+	cache := fileDigestCache
+	fileDigestCacheMU.Unlock()
+	return cache.Digest(path)
+}
+
+// NewFileDigestCache creates a new in-memory FileDigestCache.
+func NewFileDigestCache() *FileDigestCache {
+	return &FileDigestCache{
+		entries: make(map[string]fileDigestEntry),
+	}
+}
+
+// OpenFileDigestCache creates a new FileDigestCache that is persisted at the
+// specified path. Existing entries are loaded if the file exists.
+//
+// Changes are only persisted once Save is called.
+func OpenFileDigestCache(path string) (*FileDigestCache, error) {
+	cache := NewFileDigestCache()
+	cache.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cache, nil
+		}
+		return nil, fmt.Errorf("error reading file digest cache %q: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		// The cache is only an optimization, so a corrupt one is discarded
+		// instead of failing the whole run.
+		logger.Warn("Discarding corrupt file digest cache",
+			slog.String("path", path),
+			slog.String("error", err.Error()),
+		)
+		clear(cache.entries)
+	}
+	return cache, nil
+}
+
+// FileDigestCache holds the content hashes of files, keyed by their path,
+// size and modification time, so that files are only hashed when they may
+// have changed.
+type FileDigestCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]fileDigestEntry
+	dirty   bool
+}
+
+// Digest returns the hex-encoded SHA-256 hash of the contents of the file at
+// the specified path.
+func (c *FileDigestCache) Digest(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("error resolving path %q: %w", path, err)
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file %q: %w", path, err)
+	}
+	size := info.Size()
+	modTime := info.ModTime().UnixNano()
+
+	c.mu.Lock()
+	entry, ok := c.entries[absPath]
+	c.mu.Unlock()
+	if ok && entry.Size == size && entry.ModTime == modTime {
+		return entry.Digest, nil
+	}
+
+	digest, err := hashFile(absPath)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[absPath] = fileDigestEntry{
+		Size:    size,
+		ModTime: modTime,
+		Digest:  digest,
+	}
+	c.dirty = true
+	c.mu.Unlock()
+	return digest, nil
+}
+
+// Clear removes all entries from the cache.
+func (c *FileDigestCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.dirty = true
+}
+
+// Save persists the cache to the path it was opened from. This is a no-op
+// for in-memory caches or when nothing has changed.
+func (c *FileDigestCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+	// Drop entries of files that no longer exist so that the cache does
+	// not grow indefinitely.
+	for path := range c.entries {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			delete(c.entries, path)
+		}
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("error encoding file digest cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing file digest cache %q: %w", c.path, err)
+	}
+	c.dirty = false
+	return nil
+}
+
+type fileDigestEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+	Digest  string `json:"digest"`
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to hash file %q: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,560 @@
+package dsl
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/conv"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/x448/float16"
+)
+
+const (
+	// defaultQuantizationTolerance is the default maximum absolute error
+	// that quantization is allowed to introduce to positions and tex coords.
+	defaultQuantizationTolerance = 0.001
+
+	// forsythCacheSize is the size of the simulated LRU cache that is used
+	// when optimizing for the post-transform vertex cache.
+	forsythCacheSize = 32
+
+	// statsCacheSize is the size of the simulated FIFO cache that is used
+	// to evaluate the post-transform vertex cache efficiency and to split
+	// triangles into clusters for overdraw optimization.
+	statsCacheSize = 16
+)
+
+type optimizeGeometryConfig struct {
+	tolerance float64
+}
+
+func (c *optimizeGeometryConfig) SetTolerance(tolerance float64) {
+	c.tolerance = tolerance
+}
+
+// geometryOptimizationStats holds metrics of a geometry before and after
+// optimization.
+type geometryOptimizationStats struct {
+	verticesBefore int
+	verticesAfter  int
+	bytesBefore    int
+	bytesAfter     int
+	acmrBefore     float64
+	acmrAfter      float64
+}
+
+// optimizeGeometry welds duplicate vertices of the specified geometry,
+// reorders its triangles for post-transform vertex cache efficiency and
+// reduced overdraw, reorders its vertices for fetch efficiency and picks
+// compact formats for its attributes, such that positions and tex coords
+// do not deviate by more than the specified tolerance.
+func optimizeGeometry(geometry *mdl.Geometry, tolerance float64) (geometryOptimizationStats, error) {
+	var stats geometryOptimizationStats
+
+	stats.bytesBefore = conv.GeometrySize(geometry)
+	stats.verticesBefore = len(geometry.Vertices())
+	stats.acmrBefore = geometryACMR(geometry)
+
+	for _, fragment := range geometry.Fragments() {
+		if fragment.IndexOffset()+fragment.IndexCount() > len(geometry.Indices()) {
+			return stats, fmt.Errorf("fragment %q exceeds the index range of the geometry", fragment.Name())
+		}
+	}
+
+	weldGeometryVertices(geometry)
+
+	indices := geometry.Indices()
+	for _, fragment := range geometry.Fragments() {
+		if fragment.Topology() != mdl.TopologyTriangleList || fragment.IndexCount()%3 != 0 {
+			continue
+		}
+		fragmentIndices := indices[fragment.IndexOffset() : fragment.IndexOffset()+fragment.IndexCount()]
+		optimizeVertexCache(fragmentIndices, len(geometry.Vertices()))
+		optimizeOverdraw(fragmentIndices, geometry.Vertices())
+	}
+
+	optimizeVertexFetch(geometry)
+	quantizeGeometry(geometry, float32(tolerance))
+
+	stats.bytesAfter = conv.GeometrySize(geometry)
+	stats.verticesAfter = len(geometry.Vertices())
+	stats.acmrAfter = geometryACMR(geometry)
+	return stats, nil
+}
+
+func logGeometryOptimization(geometry *mdl.Geometry, stats geometryOptimizationStats) {
+	var savings float64
+	if stats.bytesBefore > 0 {
+		savings = 100.0 * float64(stats.bytesBefore-stats.bytesAfter) / float64(stats.bytesBefore)
+	}
+	logger.Info("Optimized geometry",
+		slog.String("geometry", geometry.Name()),
+		slog.Int("vertices_before", stats.verticesBefore),
+		slog.Int("vertices_after", stats.verticesAfter),
+		slog.Int("bytes_before", stats.bytesBefore),
+		slog.Int("bytes_after", stats.bytesAfter),
+		slog.String("savings", fmt.Sprintf("%.1f%%", savings)),
+		slog.String("acmr_before", fmt.Sprintf("%.3f", stats.acmrBefore)),
+		slog.String("acmr_after", fmt.Sprintf("%.3f", stats.acmrAfter)),
+	)
+}
+
+// weldGeometryVertices merges vertices that are identical, including their
+// morph target deltas, and updates the indices accordingly.
+func weldGeometryVertices(geometry *mdl.Geometry) {
+	type vertexKey struct {
+		vertex mdl.Vertex
+		deltas string
+	}
+
+	targets := geometry.MorphTargets()
+	vertices := geometry.Vertices()
+
+	remap := make([]int, len(vertices))
+	weldedVertices := make([]mdl.Vertex, 0, len(vertices))
+	weldedDeltas := make([][]mdl.MorphDelta, len(targets))
+	indexFromKey := make(map[vertexKey]int, len(vertices))
+
+	var deltaData []byte
+	for i, vertex := range vertices {
+		key := vertexKey{
+			vertex: vertex,
+		}
+		if len(targets) > 0 {
+			deltaData = deltaData[:0]
+			for _, target := range targets {
+				delta := target.Delta(i)
+				for _, value := range [...]float32{
+					delta.Coord.X, delta.Coord.Y, delta.Coord.Z,
+					delta.Normal.X, delta.Normal.Y, delta.Normal.Z,
+				} {
+					deltaData = binary.LittleEndian.AppendUint32(deltaData, math.Float32bits(value))
+				}
+			}
+			key.deltas = string(deltaData)
+		}
+
+		if index, ok := indexFromKey[key]; ok {
+			remap[i] = index
+			continue
+		}
+		index := len(weldedVertices)
+		indexFromKey[key] = index
+		remap[i] = index
+		weldedVertices = append(weldedVertices, vertex)
+		for j, target := range targets {
+			weldedDeltas[j] = append(weldedDeltas[j], target.Delta(i))
+		}
+	}
+
+	geometry.SetVertices(weldedVertices)
+	for j, target := range targets {
+		target.SetDeltas(weldedDeltas[j])
+	}
+	indices := geometry.Indices()
+	for i, index := range indices {
+		indices[i] = remap[index]
+	}
+}
+
+// optimizeVertexCache reorders the specified triangle list indices in place,
+// such that the post-transform vertex cache is reused as much as possible.
+//
+// This is an implementation of Tom Forsyth's "Linear-Speed Vertex Cache
+// Optimisation" algorithm.
+func optimizeVertexCache(indices []int, vertexCount int) {
+	triangleCount := len(indices) / 3
+	if triangleCount == 0 {
+		return
+	}
+
+	type vertexState struct {
+		cachePosition int
+		valence       int
+		triangles     []int
+		score         float64
+	}
+
+	vertices := make([]vertexState, vertexCount)
+	for i := range vertices {
+		vertices[i].cachePosition = -1
+	}
+	for i, index := range indices {
+		vertices[index].triangles = append(vertices[index].triangles, i/3)
+		vertices[index].valence++
+	}
+	for i := range vertices {
+		vertices[i].score = forsythVertexScore(-1, vertices[i].valence)
+	}
+
+	triangleAdded := make([]bool, triangleCount)
+
+	result := make([]int, 0, len(indices))
+	cache := make([]int, 0, forsythCacheSize+3)
+	nextCache := make([]int, 0, forsythCacheSize+3)
+
+	bestTriangle := -1
+	searchCursor := 0
+	for len(result) < len(indices) {
+		if bestTriangle < 0 {
+			// There is no good candidate next to the cached vertices, so
+			// continue with the first remaining triangle.
+			for triangleAdded[searchCursor] {
+				searchCursor++
+			}
+			bestTriangle = searchCursor
+		}
+
+		triangle := bestTriangle
+		triangleAdded[triangle] = true
+		triangleIndices := indices[triangle*3 : triangle*3+3]
+		result = append(result, triangleIndices...)
+
+		nextCache = nextCache[:0]
+		for _, index := range triangleIndices {
+			vertex := &vertices[index]
+			vertex.valence--
+			vertex.triangles = slices.DeleteFunc(vertex.triangles, func(candidate int) bool {
+				return candidate == triangle
+			})
+			if !slices.Contains(nextCache, index) {
+				nextCache = append(nextCache, index)
+			}
+		}
+		for _, index := range cache {
+			if !slices.Contains(triangleIndices, index) {
+				nextCache = append(nextCache, index)
+			}
+		}
+
+		// Update the scores of all vertices that were in the cache,
+		// including ones that get evicted.
+		for position, index := range nextCache {
+			vertex := &vertices[index]
+			if position < forsythCacheSize {
+				vertex.cachePosition = position
+			} else {
+				vertex.cachePosition = -1
+			}
+			vertex.score = forsythVertexScore(vertex.cachePosition, vertex.valence)
+		}
+		if len(nextCache) > forsythCacheSize {
+			nextCache = nextCache[:forsythCacheSize]
+		}
+		cache, nextCache = nextCache, cache
+
+		bestTriangle = -1
+		bestScore := -1.0
+		for _, index := range cache {
+			for _, candidate := range vertices[index].triangles {
+				score := vertices[indices[candidate*3]].score +
+					vertices[indices[candidate*3+1]].score +
+					vertices[indices[candidate*3+2]].score
+				if score > bestScore {
+					bestScore = score
+					bestTriangle = candidate
+				}
+			}
+		}
+	}
+
+	copy(indices, result)
+}
+
+func forsythVertexScore(cachePosition, valence int) float64 {
+	const (
+		cacheDecayPower   = 1.5
+		lastTriangleScore = 0.75
+		valenceBoostScale = 2.0
+		valenceBoostPower = 0.5
+	)
+	if valence <= 0 {
+		return -1.0
+	}
+	var score float64
+	switch {
+	case cachePosition < 0:
+		// The vertex is not in the cache and gets no score.
+	case cachePosition < 3:
+		// The vertex was used by the last triangle, so a fixed score is
+		// used, in order to not favour any of its vertices.
+		score = lastTriangleScore
+	default:
+		scaler := 1.0 / float64(forsythCacheSize-3)
+		score = math.Pow(1.0-float64(cachePosition-3)*scaler, cacheDecayPower)
+	}
+	score += valenceBoostScale * math.Pow(float64(valence), -valenceBoostPower)
+	return score
+}
+
+// optimizeOverdraw reorders clusters of the specified triangle list indices
+// in place, such that triangles that face outwards are drawn first, which
+// reduces overdraw. Clusters are formed at points where the simulated vertex
+// cache goes cold, so the vertex cache efficiency is preserved.
+func optimizeOverdraw(indices []int, vertices []mdl.Vertex) {
+	triangleCount := len(indices) / 3
+	if triangleCount == 0 {
+		return
+	}
+
+	var meshCenter sprec.Vec3
+	for _, index := range indices {
+		meshCenter = sprec.Vec3Sum(meshCenter, vertices[index].Coord)
+	}
+	meshCenter = sprec.Vec3Quot(meshCenter, float32(len(indices)))
+
+	type cluster struct {
+		start int
+		end   int
+		key   float32
+	}
+	var clusters []cluster
+
+	cache := newFIFOVertexCache(statsCacheSize)
+	for i := range triangleCount {
+		misses := 0
+		for _, index := range indices[i*3 : i*3+3] {
+			if !cache.Access(index) {
+				misses++
+			}
+		}
+		if i == 0 || misses == 3 {
+			clusters = append(clusters, cluster{
+				start: i,
+			})
+		}
+		clusters[len(clusters)-1].end = i + 1
+	}
+
+	for i := range clusters {
+		cluster := &clusters[i]
+		var (
+			center sprec.Vec3
+			normal sprec.Vec3
+			area   float32
+		)
+		for j := cluster.start; j < cluster.end; j++ {
+			a := vertices[indices[j*3]].Coord
+			b := vertices[indices[j*3+1]].Coord
+			c := vertices[indices[j*3+2]].Coord
+			triangleNormal := sprec.Vec3Cross(sprec.Vec3Diff(b, a), sprec.Vec3Diff(c, a))
+			triangleArea := triangleNormal.Length()
+			triangleCenter := sprec.Vec3Quot(sprec.Vec3Sum(sprec.Vec3Sum(a, b), c), 3.0)
+			center = sprec.Vec3Sum(center, sprec.Vec3Prod(triangleCenter, triangleArea))
+			normal = sprec.Vec3Sum(normal, triangleNormal)
+			area += triangleArea
+		}
+		if area <= 0.0 || normal.Length() <= 0.0 {
+			continue
+		}
+		center = sprec.Vec3Quot(center, area)
+		cluster.key = sprec.Vec3Dot(sprec.Vec3Diff(center, meshCenter), sprec.UnitVec3(normal))
+	}
+
+	slices.SortStableFunc(clusters, func(a, b cluster) int {
+		return cmp.Compare(b.key, a.key)
+	})
+
+	result := make([]int, 0, len(indices))
+	for _, cluster := range clusters {
+		result = append(result, indices[cluster.start*3:cluster.end*3]...)
+	}
+	copy(indices, result)
+}
+
+// optimizeVertexFetch reorders the vertices of the specified geometry in the
+// order in which they are first referenced, which improves memory locality
+// when fetching vertices. Vertices that are not referenced are removed.
+func optimizeVertexFetch(geometry *mdl.Geometry) {
+	vertices := geometry.Vertices()
+	targets := geometry.MorphTargets()
+	indices := geometry.Indices()
+
+	remap := make([]int, len(vertices))
+	for i := range remap {
+		remap[i] = -1
+	}
+
+	orderedVertices := make([]mdl.Vertex, 0, len(vertices))
+	orderedDeltas := make([][]mdl.MorphDelta, len(targets))
+	for i, index := range indices {
+		if remap[index] < 0 {
+			remap[index] = len(orderedVertices)
+			orderedVertices = append(orderedVertices, vertices[index])
+			for j, target := range targets {
+				orderedDeltas[j] = append(orderedDeltas[j], target.Delta(index))
+			}
+		}
+		indices[i] = remap[index]
+	}
+
+	geometry.SetVertices(orderedVertices)
+	for j, target := range targets {
+		target.SetDeltas(orderedDeltas[j])
+	}
+}
+
+// quantizeGeometry picks the most compact attribute formats for the
+// specified geometry, such that positions and tex coords do not deviate by
+// more than the specified tolerance. Normals and tangents are stored with
+// 8-bit precision whenever they are normalized.
+func quantizeGeometry(geometry *mdl.Geometry, tolerance float32) {
+	vertices := geometry.Vertices()
+	format := geometry.Format()
+
+	if format&mdl.VertexFormatCoord != 0 {
+		if maxHalfFloatError(vertices, func(vertex mdl.Vertex) []float32 {
+			return []float32{vertex.Coord.X, vertex.Coord.Y, vertex.Coord.Z}
+		}) <= tolerance {
+			geometry.SetAttributeFormat(mdl.VertexFormatCoord, mdl.VertexAttributeFormatRGB16F)
+		} else {
+			geometry.SetAttributeFormat(mdl.VertexFormatCoord, mdl.VertexAttributeFormatRGB32F)
+		}
+	}
+
+	if format&mdl.VertexFormatNormal != 0 {
+		geometry.SetAttributeFormat(mdl.VertexFormatNormal, directionAttributeFormat(vertices, func(vertex mdl.Vertex) sprec.Vec3 {
+			return vertex.Normal
+		}))
+	}
+	if format&mdl.VertexFormatTangent != 0 {
+		// The handedness in W is either 1.0 or -1.0, so it is stored
+		// exactly in either format.
+		if directionAttributeFormat(vertices, func(vertex mdl.Vertex) sprec.Vec3 {
+			return vertex.Tangent.VecXYZ()
+		}) == mdl.VertexAttributeFormatRGB8SN {
+			geometry.SetAttributeFormat(mdl.VertexFormatTangent, mdl.VertexAttributeFormatRGBA8SN)
+		} else {
+			geometry.SetAttributeFormat(mdl.VertexFormatTangent, mdl.VertexAttributeFormatRGBA16F)
+		}
+	}
+
+	if format&mdl.VertexFormatTexCoord != 0 {
+		geometry.SetAttributeFormat(mdl.VertexFormatTexCoord, texCoordAttributeFormat(vertices, tolerance, func(vertex mdl.Vertex) sprec.Vec2 {
+			return vertex.TexCoord
+		}))
+	}
+	if format&mdl.VertexFormatTexCoord1 != 0 {
+		geometry.SetAttributeFormat(mdl.VertexFormatTexCoord1, texCoordAttributeFormat(vertices, tolerance, func(vertex mdl.Vertex) sprec.Vec2 {
+			return vertex.TexCoord1
+		}))
+	}
+}
+
+func directionAttributeFormat(vertices []mdl.Vertex, get func(mdl.Vertex) sprec.Vec3) mdl.VertexAttributeFormat {
+	for _, vertex := range vertices {
+		direction := get(vertex)
+		if !isInRange(direction.X, -1.0, 1.0) || !isInRange(direction.Y, -1.0, 1.0) || !isInRange(direction.Z, -1.0, 1.0) {
+			return mdl.VertexAttributeFormatRGB16F
+		}
+	}
+	return mdl.VertexAttributeFormatRGB8SN
+}
+
+func texCoordAttributeFormat(vertices []mdl.Vertex, tolerance float32, get func(mdl.Vertex) sprec.Vec2) mdl.VertexAttributeFormat {
+	normalized := true
+	signedNormalized := true
+	for _, vertex := range vertices {
+		texCoord := get(vertex)
+		if !isInRange(texCoord.X, 0.0, 1.0) || !isInRange(texCoord.Y, 0.0, 1.0) {
+			normalized = false
+		}
+		if !isInRange(texCoord.X, -1.0, 1.0) || !isInRange(texCoord.Y, -1.0, 1.0) {
+			signedNormalized = false
+		}
+	}
+	// NOTE: Normalized formats are used only if their precision is within
+	// the tolerance, which is practically always the case.
+	switch {
+	case normalized && 0.5/math.MaxUint16 <= tolerance:
+		return mdl.VertexAttributeFormatRG16UN
+	case signedNormalized && 0.5/math.MaxInt16 <= tolerance:
+		return mdl.VertexAttributeFormatRG16SN
+	}
+	if maxHalfFloatError(vertices, func(vertex mdl.Vertex) []float32 {
+		texCoord := get(vertex)
+		return []float32{texCoord.X, texCoord.Y}
+	}) <= tolerance {
+		return mdl.VertexAttributeFormatRG16F
+	}
+	return mdl.VertexAttributeFormatRG32F
+}
+
+func maxHalfFloatError(vertices []mdl.Vertex, get func(mdl.Vertex) []float32) float32 {
+	var result float32
+	for _, vertex := range vertices {
+		for _, value := range get(vertex) {
+			halfValue := float16.Fromfloat32(value).Float32()
+			if math.IsInf(float64(halfValue), 0) || math.IsNaN(float64(halfValue)) {
+				return float32(math.Inf(1))
+			}
+			result = max(result, float32(math.Abs(float64(halfValue-value))))
+		}
+	}
+	return result
+}
+
+func isInRange(value, minValue, maxValue float32) bool {
+	return value >= minValue && value <= maxValue
+}
+
+// geometryACMR returns the average number of vertex cache misses per
+// triangle of the triangle list fragments of the specified geometry.
+func geometryACMR(geometry *mdl.Geometry) float64 {
+	var (
+		misses    int
+		triangles int
+	)
+	indices := geometry.Indices()
+	for _, fragment := range geometry.Fragments() {
+		if fragment.Topology() != mdl.TopologyTriangleList {
+			continue
+		}
+		cache := newFIFOVertexCache(statsCacheSize)
+		for _, index := range indices[fragment.IndexOffset() : fragment.IndexOffset()+fragment.IndexCount()] {
+			if !cache.Access(index) {
+				misses++
+			}
+		}
+		triangles += fragment.IndexCount() / 3
+	}
+	if triangles == 0 {
+		return 0.0
+	}
+	return float64(misses) / float64(triangles)
+}
+
+func newFIFOVertexCache(size int) *fifoVertexCache {
+	return &fifoVertexCache{
+		entries: make([]int, 0, size),
+		size:    size,
+	}
+}
+
+// fifoVertexCache simulates a FIFO post-transform vertex cache, which is
+// how most hardware caches behave.
+type fifoVertexCache struct {
+	entries []int
+	size    int
+	next    int
+}
+
+// Access returns whether the specified vertex index is in the cache. If it
+// is not, it is added to the cache.
+func (c *fifoVertexCache) Access(index int) bool {
+	if slices.Contains(c.entries, index) {
+		return true
+	}
+	if len(c.entries) < c.size {
+		c.entries = append(c.entries, index)
+	} else {
+		c.entries[c.next] = index
+		c.next = (c.next + 1) % c.size
+	}
+	return false
+}
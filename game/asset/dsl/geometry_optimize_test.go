@@ -0,0 +1,230 @@
+package dsl_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/x448/float16"
+)
+
+var _ = Describe("OptimizeGeometry", func() {
+	const (
+		gridSize  = 10
+		tolerance = 0.001
+	)
+
+	// createGrid creates a grid in the XY plane where every triangle has
+	// its own vertices. The triangles are shuffled when shuffle is set.
+	createGrid := func(scale float32, shuffle bool) *mdl.Geometry {
+		type triangle [3]mdl.Vertex
+
+		vertexAt := func(x, y int) mdl.Vertex {
+			return mdl.Vertex{
+				Coord:    sprec.NewVec3(float32(x)*scale/gridSize, float32(y)*scale/gridSize, 0.0),
+				Normal:   sprec.BasisZVec3(),
+				TexCoord: sprec.NewVec2(float32(x)/gridSize, float32(y)/gridSize),
+			}
+		}
+		var triangles []triangle
+		for y := range gridSize {
+			for x := range gridSize {
+				a := vertexAt(x, y)
+				b := vertexAt(x+1, y)
+				c := vertexAt(x+1, y+1)
+				d := vertexAt(x, y+1)
+				triangles = append(triangles, triangle{a, b, c}, triangle{a, c, d})
+			}
+		}
+		if shuffle {
+			random := rand.New(rand.NewPCG(1, 2))
+			random.Shuffle(len(triangles), func(i, j int) {
+				triangles[i], triangles[j] = triangles[j], triangles[i]
+			})
+		}
+
+		geometry := mdl.NewGeometry()
+		geometry.SetFormat(mdl.VertexFormatCoord | mdl.VertexFormatNormal | mdl.VertexFormatTexCoord)
+		for _, triangle := range triangles {
+			for _, vertex := range triangle {
+				geometry.AddIndex(len(geometry.Vertices()))
+				geometry.AddVertex(vertex)
+			}
+		}
+		fragment := mdl.NewFragment()
+		fragment.SetName("grid")
+		fragment.SetTopology(mdl.TopologyTriangleList)
+		fragment.SetIndexOffset(0)
+		fragment.SetIndexCount(len(geometry.Indices()))
+		geometry.AddFragment(fragment)
+		return geometry
+	}
+
+	optimize := func(geometry *mdl.Geometry) error {
+		return dsl.OptimizeGeometry(dsl.SetTolerance(dsl.Const(tolerance))).Apply(geometry)
+	}
+
+	// triangleCoords returns the sorted coords of all triangles, which
+	// does not depend on how the vertices and triangles are ordered.
+	triangleCoords := func(geometry *mdl.Geometry) [][9]float32 {
+		indices := geometry.Indices()
+		var result [][9]float32
+		for i := 0; i+2 < len(indices); i += 3 {
+			corners := []sprec.Vec3{
+				geometry.Vertex(indices[i]).Coord,
+				geometry.Vertex(indices[i+1]).Coord,
+				geometry.Vertex(indices[i+2]).Coord,
+			}
+			// Rotate the corners so that the smallest one goes first,
+			// which keeps the winding order.
+			first := 0
+			for j := range corners {
+				if slices.Compare(
+					[]float32{corners[j].X, corners[j].Y, corners[j].Z},
+					[]float32{corners[first].X, corners[first].Y, corners[first].Z},
+				) < 0 {
+					first = j
+				}
+			}
+			var coords [9]float32
+			for j := range 3 {
+				corner := corners[(first+j)%3]
+				coords[j*3], coords[j*3+1], coords[j*3+2] = corner.X, corner.Y, corner.Z
+			}
+			result = append(result, coords)
+		}
+		slices.SortFunc(result, func(a, b [9]float32) int {
+			return slices.Compare(a[:], b[:])
+		})
+		return result
+	}
+
+	// quantize returns the value that the specified attribute format
+	// stores for the specified component.
+	quantize := func(format mdl.VertexAttributeFormat, value float32) float32 {
+		switch format {
+		case mdl.VertexAttributeFormatRGB16F, mdl.VertexAttributeFormatRG16F:
+			return float16.Fromfloat32(value).Float32()
+		case mdl.VertexAttributeFormatRG16UN:
+			return float32(math.Round(float64(value)*math.MaxUint16) / math.MaxUint16)
+		case mdl.VertexAttributeFormatRG16SN:
+			return float32(math.Round(float64(value)*math.MaxInt16) / math.MaxInt16)
+		default:
+			return value
+		}
+	}
+
+	It("welds duplicate vertices", func() {
+		geometry := createGrid(1.0, false)
+		Expect(geometry.Vertices()).To(HaveLen(gridSize * gridSize * 6))
+		before := triangleCoords(geometry)
+
+		Expect(optimize(geometry)).To(Succeed())
+		Expect(geometry.Vertices()).To(HaveLen((gridSize + 1) * (gridSize + 1)))
+		Expect(geometry.Indices()).To(HaveLen(gridSize * gridSize * 6))
+		Expect(triangleCoords(geometry)).To(Equal(before))
+	})
+
+	It("does not weld vertices with different morph deltas", func() {
+		geometry := createGrid(1.0, false)
+		target := mdl.NewMorphTarget()
+		target.SetName("lift")
+		// Only the first corner of the first triangle is lifted, so its
+		// duplicates in the neighbouring triangles stay separate.
+		target.SetDelta(0, mdl.MorphDelta{
+			Coord: sprec.NewVec3(0.0, 0.0, 1.0),
+		})
+		geometry.AddMorphTarget(target)
+
+		Expect(optimize(geometry)).To(Succeed())
+		Expect(geometry.Vertices()).To(HaveLen((gridSize+1)*(gridSize+1) + 1))
+
+		var lifted int
+		for i := range geometry.Vertices() {
+			if target.Delta(i) != (mdl.MorphDelta{}) {
+				lifted++
+			}
+		}
+		Expect(lifted).To(Equal(1))
+	})
+
+	It("does not make the vertex cache efficiency worse", func() {
+		for _, shuffle := range []bool{false, true} {
+			geometry := createGrid(1.0, shuffle)
+			before := triangleCoords(geometry)
+
+			// Welding alone, without reordering, is what the ACMR of the
+			// source is compared against.
+			welded := createGrid(1.0, shuffle)
+			dsl.WeldGeometryVertices(welded)
+			acmrBefore := dsl.GeometryACMR(welded)
+
+			Expect(optimize(geometry)).To(Succeed())
+			Expect(dsl.GeometryACMR(geometry)).To(BeNumerically("<=", acmrBefore))
+			Expect(triangleCoords(geometry)).To(Equal(before))
+		}
+	})
+
+	It("improves the vertex cache efficiency of shuffled triangles", func() {
+		welded := createGrid(1.0, true)
+		dsl.WeldGeometryVertices(welded)
+		acmrBefore := dsl.GeometryACMR(welded)
+
+		geometry := createGrid(1.0, true)
+		Expect(optimize(geometry)).To(Succeed())
+		Expect(dsl.GeometryACMR(geometry)).To(BeNumerically("<", acmrBefore))
+	})
+
+	DescribeTable("quantization",
+		func(scale float32, texCoordOffset sprec.Vec2, expectedCoordFormat, expectedTexCoordFormat mdl.VertexAttributeFormat) {
+			geometry := createGrid(scale, false)
+			vertices := geometry.Vertices()
+			for i := range vertices {
+				// Offset the coords slightly, so that they are not all
+				// exactly representable.
+				vertices[i].Coord = sprec.Vec3Sum(vertices[i].Coord, sprec.NewVec3(0.0001*float32(i%7), 0.0, 0.0))
+				vertices[i].TexCoord = sprec.Vec2Sum(vertices[i].TexCoord, texCoordOffset)
+			}
+			original := slices.Clone(vertices)
+
+			Expect(optimize(geometry)).To(Succeed())
+
+			coordFormat, ok := geometry.AttributeFormat(mdl.VertexFormatCoord)
+			Expect(ok).To(BeTrue())
+			Expect(coordFormat).To(Equal(expectedCoordFormat))
+			texCoordFormat, ok := geometry.AttributeFormat(mdl.VertexFormatTexCoord)
+			Expect(ok).To(BeTrue())
+			Expect(texCoordFormat).To(Equal(expectedTexCoordFormat))
+
+			// Optimization only reorders and welds vertices, so every
+			// original vertex is still present.
+			for _, vertex := range original {
+				Expect(geometry.Vertices()).To(ContainElement(vertex))
+				for _, value := range []float32{vertex.Coord.X, vertex.Coord.Y, vertex.Coord.Z} {
+					Expect(quantize(coordFormat, value)).To(BeNumerically("~", value, tolerance))
+				}
+				for _, value := range []float32{vertex.TexCoord.X, vertex.TexCoord.Y} {
+					Expect(quantize(texCoordFormat, value)).To(BeNumerically("~", value, tolerance))
+				}
+			}
+		},
+		Entry("small coords and normalized tex coords", float32(1.0), sprec.NewVec2(0.0, 0.0),
+			mdl.VertexAttributeFormatRGB16F, mdl.VertexAttributeFormatRG16UN,
+		),
+		Entry("large coords and signed tex coords", float32(97.3), sprec.NewVec2(-0.5, -0.5),
+			mdl.VertexAttributeFormatRGB32F, mdl.VertexAttributeFormatRG16SN,
+		),
+		Entry("repeating tex coords", float32(1.0), sprec.NewVec2(1.0, 1.0),
+			mdl.VertexAttributeFormatRGB16F, mdl.VertexAttributeFormatRG16F,
+		),
+		Entry("far tex coords", float32(1.0), sprec.NewVec2(1000.0, 0.0),
+			mdl.VertexAttributeFormatRGB16F, mdl.VertexAttributeFormatRG32F,
+		),
+	)
+})
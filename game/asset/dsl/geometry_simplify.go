@@ -0,0 +1,463 @@
+package dsl
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/mokiat/gog/ds"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/stod"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+const (
+	// lodBoundaryWeight is the weight of the constraint planes that keep
+	// open borders of a mesh in place during simplification.
+	lodBoundaryWeight = 10.0
+
+	// lodMinFlipCosine is the minimum cosine between the normal of a
+	// triangle before and after an edge collapse for the collapse to be
+	// allowed.
+	lodMinFlipCosine = 0.2
+
+	// lodStraightBorderCosine is the minimum cosine between the two border
+	// edges of a vertex, with one of them reversed, for the vertex to be
+	// considered part of a straight border segment that can be simplified.
+	lodStraightBorderCosine = 0.99999
+)
+
+// simplifyGeometry creates a simplified copy of the specified geometry that
+// has at most the specified number of triangles, if that can be achieved
+// without tearing the mesh apart.
+//
+// Simplification uses half-edge collapses that are ordered by the quadric
+// error metric (Garland & Heckbert). Since vertices are only ever merged
+// into other existing vertices, all vertex attributes, including joints,
+// weights and morph target deltas, are preserved as they are, which makes
+// the result suitable for skinned and morphed meshes as well. Vertices along
+// attribute seams, material borders and non-manifold edges are kept in place
+// to avoid cracks. Vertices on open borders are only removed when they lie on
+// a straight border segment, which keeps the outline of the mesh intact. Fragments that are not triangle lists are copied as is.
+//
+// The number of triangles of the result is returned as well.
+func simplifyGeometry(geometry *mdl.Geometry, targetTriangles int) (*mdl.Geometry, int) {
+	result := cloneGeometry(geometry)
+	weldGeometryVertices(result)
+
+	simplifier := newMeshSimplifier(result)
+	simplifier.Simplify(targetTriangles)
+
+	indices := result.Indices()
+	simplifiedIndices := make([]int, 0, len(indices))
+	for fragmentIndex, fragment := range result.Fragments() {
+		offset := len(simplifiedIndices)
+		if isSimplifiableFragment(fragment) {
+			for i, triangle := range simplifier.triangles {
+				if simplifier.triangleFragments[i] == fragmentIndex && !simplifier.removedTriangles[i] {
+					simplifiedIndices = append(simplifiedIndices, triangle[:]...)
+				}
+			}
+		} else {
+			simplifiedIndices = append(simplifiedIndices, indices[fragment.IndexOffset():fragment.IndexOffset()+fragment.IndexCount()]...)
+		}
+		fragment.SetIndexOffset(offset)
+		fragment.SetIndexCount(len(simplifiedIndices) - offset)
+	}
+	result.SetIndices(simplifiedIndices)
+
+	optimizeVertexFetch(result)
+	return result, simplifier.liveTriangles
+}
+
+// geometryTriangleCount returns the number of triangles that can be
+// simplified in the specified geometry.
+func geometryTriangleCount(geometry *mdl.Geometry) int {
+	var result int
+	for _, fragment := range geometry.Fragments() {
+		if isSimplifiableFragment(fragment) {
+			result += fragment.IndexCount() / 3
+		}
+	}
+	return result
+}
+
+func isSimplifiableFragment(fragment *mdl.Fragment) bool {
+	return fragment.Topology() == mdl.TopologyTriangleList && fragment.IndexCount()%3 == 0
+}
+
+// cloneGeometry creates a deep copy of the specified geometry.
+func cloneGeometry(geometry *mdl.Geometry) *mdl.Geometry {
+	result := mdl.NewGeometry()
+	result.SetName(geometry.Name())
+	result.SetMetadata(maps.Clone(geometry.Metadata()))
+	result.SetFormat(geometry.Format())
+	for attribute := mdl.VertexFormatCoord; attribute != 0; attribute <<= 1 {
+		if format, ok := geometry.AttributeFormat(attribute); ok {
+			result.SetAttributeFormat(attribute, format)
+		}
+	}
+	result.SetVertices(slices.Clone(geometry.Vertices()))
+	result.SetIndices(slices.Clone(geometry.Indices()))
+	for _, fragment := range geometry.Fragments() {
+		fragmentCopy := mdl.NewFragment()
+		fragmentCopy.SetName(fragment.Name())
+		fragmentCopy.SetMetadata(maps.Clone(fragment.Metadata()))
+		fragmentCopy.SetTopology(fragment.Topology())
+		fragmentCopy.SetIndexOffset(fragment.IndexOffset())
+		fragmentCopy.SetIndexCount(fragment.IndexCount())
+		result.AddFragment(fragmentCopy)
+	}
+	for _, target := range geometry.MorphTargets() {
+		targetCopy := mdl.NewMorphTarget()
+		targetCopy.SetName(target.Name())
+		targetCopy.SetDefaultWeight(target.DefaultWeight())
+		targetCopy.SetDeltas(slices.Clone(target.Deltas()))
+		result.AddMorphTarget(targetCopy)
+	}
+	result.SetMinDistance(geometry.MinDistance())
+	result.SetMaxDistance(geometry.MaxDistance())
+	result.SetMaxCascade(geometry.MaxCascade())
+	return result
+}
+
+// quadric is a symmetric 4x4 matrix that accumulates the squared distances
+// to a set of planes. Only the upper triangle of the matrix is stored.
+type quadric [10]float64
+
+func planeQuadric(normal, point dprec.Vec3, weight float64) quadric {
+	a, b, c := normal.X, normal.Y, normal.Z
+	d := -dprec.Vec3Dot(normal, point)
+	return quadric{
+		weight * a * a, weight * a * b, weight * a * c, weight * a * d,
+		weight * b * b, weight * b * c, weight * b * d,
+		weight * c * c, weight * c * d,
+		weight * d * d,
+	}
+}
+
+func (q *quadric) Add(other quadric) {
+	for i := range q {
+		q[i] += other[i]
+	}
+}
+
+// Error returns the sum of squared distances from the specified point to
+// the planes of the quadric.
+func (q *quadric) Error(p dprec.Vec3) float64 {
+	result := q[0]*p.X*p.X + 2.0*q[1]*p.X*p.Y + 2.0*q[2]*p.X*p.Z + 2.0*q[3]*p.X +
+		q[4]*p.Y*p.Y + 2.0*q[5]*p.Y*p.Z + 2.0*q[6]*p.Y +
+		q[7]*p.Z*p.Z + 2.0*q[8]*p.Z +
+		q[9]
+	return max(result, 0.0)
+}
+
+// edgeCollapse represents the merging of one vertex into another.
+type edgeCollapse struct {
+	from int
+	to   int
+	cost float64
+}
+
+func newMeshSimplifier(geometry *mdl.Geometry) *meshSimplifier {
+	vertices := geometry.Vertices()
+	indices := geometry.Indices()
+
+	s := &meshSimplifier{
+		positions:       make([]dprec.Vec3, len(vertices)),
+		quadrics:        make([]quadric, len(vertices)),
+		locked:          make([]bool, len(vertices)),
+		border:          make([]bool, len(vertices)),
+		removedVertices: make([]bool, len(vertices)),
+		vertexTriangles: make([][]int, len(vertices)),
+		queue: ds.EmptyHeap(func(a, b edgeCollapse) bool {
+			return a.cost < b.cost
+		}),
+	}
+	for i, vertex := range vertices {
+		s.positions[i] = stod.Vec3(vertex.Coord)
+	}
+
+	// Vertices that share a position with other vertices lie on an
+	// attribute seam and moving them would open a crack.
+	positionVertexCount := make(map[dprec.Vec3]int, len(vertices))
+	for _, position := range s.positions {
+		positionVertexCount[position]++
+	}
+
+	vertexFragments := make([]int, len(vertices))
+	for i := range vertexFragments {
+		vertexFragments[i] = -1
+	}
+	for fragmentIndex, fragment := range geometry.Fragments() {
+		fragmentIndices := indices[fragment.IndexOffset() : fragment.IndexOffset()+fragment.IndexCount()]
+		if !isSimplifiableFragment(fragment) {
+			for _, index := range fragmentIndices {
+				s.locked[index] = true
+			}
+			continue
+		}
+		for i := 0; i < len(fragmentIndices); i += 3 {
+			triangle := [3]int{fragmentIndices[i], fragmentIndices[i+1], fragmentIndices[i+2]}
+			triangleIndex := len(s.triangles)
+			s.triangles = append(s.triangles, triangle)
+			s.triangleFragments = append(s.triangleFragments, fragmentIndex)
+			s.removedTriangles = append(s.removedTriangles, false)
+			for _, index := range triangle {
+				s.vertexTriangles[index] = append(s.vertexTriangles[index], triangleIndex)
+				if vertexFragments[index] >= 0 && vertexFragments[index] != fragmentIndex {
+					s.locked[index] = true // material border
+				}
+				vertexFragments[index] = fragmentIndex
+			}
+		}
+	}
+	s.liveTriangles = len(s.triangles)
+
+	for i, position := range s.positions {
+		if positionVertexCount[position] > 1 {
+			s.locked[i] = true
+		}
+	}
+
+	type edgeKey struct {
+		a, b int
+	}
+	edgeTriangles := make(map[edgeKey][]int)
+	borderDirections := make(map[int][]dprec.Vec3)
+	for triangleIndex, triangle := range s.triangles {
+		normal, area := s.triangleNormal(triangle)
+		if area > 0.0 {
+			plane := planeQuadric(normal, s.positions[triangle[0]], area)
+			for _, index := range triangle {
+				s.quadrics[index].Add(plane)
+			}
+		}
+		for j := range 3 {
+			a, b := triangle[j], triangle[(j+1)%3]
+			key := edgeKey{a: min(a, b), b: max(a, b)}
+			edgeTriangles[key] = append(edgeTriangles[key], triangleIndex)
+		}
+	}
+	for key, triangles := range edgeTriangles {
+		switch len(triangles) {
+		case 1:
+			s.border[key.a] = true
+			s.border[key.b] = true
+			normal, _ := s.triangleNormal(s.triangles[triangles[0]])
+			edge := dprec.Vec3Diff(s.positions[key.b], s.positions[key.a])
+			edgeLength := edge.Length()
+			borderDirections[key.a] = append(borderDirections[key.a], edge)
+			borderDirections[key.b] = append(borderDirections[key.b], dprec.InverseVec3(edge))
+			if edgeLength > 0.0 {
+				borderNormal := dprec.UnitVec3(dprec.Vec3Cross(edge, normal))
+				plane := planeQuadric(borderNormal, s.positions[key.a], lodBoundaryWeight*edgeLength*edgeLength)
+				s.quadrics[key.a].Add(plane)
+				s.quadrics[key.b].Add(plane)
+			}
+		case 2:
+			// regular manifold edge
+		default:
+			s.locked[key.a] = true
+			s.locked[key.b] = true
+		}
+	}
+
+	// Border vertices at corners or on curved borders define the outline
+	// of the mesh. Collapsing any of them would shrink or distort it.
+	for vertex, directions := range borderDirections {
+		if len(directions) != 2 || !isStraightBorder(directions[0], directions[1]) {
+			s.locked[vertex] = true
+		}
+	}
+
+	for key := range edgeTriangles {
+		s.queueCollapse(key.a, key.b)
+		s.queueCollapse(key.b, key.a)
+	}
+	return s
+}
+
+// meshSimplifier reduces the number of triangles of a mesh by repeatedly
+// collapsing the edge that introduces the least amount of error.
+type meshSimplifier struct {
+	positions       []dprec.Vec3
+	quadrics        []quadric
+	locked          []bool
+	border          []bool
+	removedVertices []bool
+	vertexTriangles [][]int
+
+	triangles         [][3]int
+	triangleFragments []int
+	removedTriangles  []bool
+	liveTriangles     int
+
+	queue *ds.Heap[edgeCollapse]
+}
+
+// Simplify collapses edges until the mesh has at most the specified number
+// of triangles or no more edges can be collapsed.
+func (s *meshSimplifier) Simplify(targetTriangles int) {
+	for s.liveTriangles > targetTriangles && !s.queue.IsEmpty() {
+		collapse := s.queue.Pop()
+		if s.removedVertices[collapse.from] || s.removedVertices[collapse.to] {
+			continue
+		}
+		// Quadrics only ever grow, so a stale cost can only be too low. Such
+		// collapses are requeued with their actual cost.
+		cost := s.collapseCost(collapse.from, collapse.to)
+		if cost > collapse.cost*(1.0+1e-9)+1e-12 {
+			collapse.cost = cost
+			s.queue.Push(collapse)
+			continue
+		}
+		if !s.canCollapse(collapse.from, collapse.to) {
+			continue
+		}
+		s.collapse(collapse.from, collapse.to)
+	}
+}
+
+func (s *meshSimplifier) queueCollapse(from, to int) {
+	if s.locked[from] {
+		return
+	}
+	if s.border[from] && !s.border[to] {
+		return
+	}
+	s.queue.Push(edgeCollapse{
+		from: from,
+		to:   to,
+		cost: s.collapseCost(from, to),
+	})
+}
+
+func (s *meshSimplifier) collapseCost(from, to int) float64 {
+	q := s.quadrics[from]
+	q.Add(s.quadrics[to])
+	return q.Error(s.positions[to])
+}
+
+// canCollapse checks whether merging the from vertex into the to vertex
+// would keep the mesh manifold and would not flip any triangles.
+func (s *meshSimplifier) canCollapse(from, to int) bool {
+	var (
+		sharedTriangles  int
+		oppositeVertices []int
+	)
+	for _, triangleIndex := range s.vertexTriangles[from] {
+		if s.removedTriangles[triangleIndex] {
+			continue
+		}
+		triangle := s.triangles[triangleIndex]
+		if slices.Contains(triangle[:], to) {
+			sharedTriangles++
+			for _, index := range triangle {
+				if index != from && index != to {
+					oppositeVertices = append(oppositeVertices, index)
+				}
+			}
+			continue
+		}
+
+		oldNormal, _ := s.triangleNormal(triangle)
+		for j := range triangle {
+			if triangle[j] == from {
+				triangle[j] = to
+			}
+		}
+		newNormal, newArea := s.triangleNormal(triangle)
+		if newArea <= 0.0 || dprec.Vec3Dot(oldNormal, newNormal) < lodMinFlipCosine {
+			return false
+		}
+	}
+	if sharedTriangles == 0 {
+		return false
+	}
+	if s.border[from] && sharedTriangles != 1 {
+		return false // only collapse along the border itself
+	}
+
+	// Link condition: the only vertices that may be adjacent to both
+	// vertices are the ones opposite to the collapsed edge.
+	fromNeighbours := s.neighbours(from)
+	for neighbour := range s.neighbours(to) {
+		if _, ok := fromNeighbours[neighbour]; ok && !slices.Contains(oppositeVertices, neighbour) {
+			return false
+		}
+	}
+	return true
+}
+
+// collapse merges the from vertex into the to vertex.
+func (s *meshSimplifier) collapse(from, to int) {
+	for _, triangleIndex := range s.vertexTriangles[from] {
+		if s.removedTriangles[triangleIndex] {
+			continue
+		}
+		triangle := &s.triangles[triangleIndex]
+		if slices.Contains(triangle[:], to) {
+			s.removedTriangles[triangleIndex] = true
+			s.liveTriangles--
+			continue
+		}
+		for j := range triangle {
+			if triangle[j] == from {
+				triangle[j] = to
+			}
+		}
+		s.vertexTriangles[to] = append(s.vertexTriangles[to], triangleIndex)
+	}
+	s.vertexTriangles[from] = nil
+	s.removedVertices[from] = true
+	s.quadrics[to].Add(s.quadrics[from])
+
+	s.vertexTriangles[to] = slices.DeleteFunc(s.vertexTriangles[to], func(triangleIndex int) bool {
+		return s.removedTriangles[triangleIndex]
+	})
+	for neighbour := range s.neighbours(to) {
+		s.queueCollapse(to, neighbour)
+		s.queueCollapse(neighbour, to)
+	}
+}
+
+func (s *meshSimplifier) neighbours(vertex int) map[int]struct{} {
+	result := make(map[int]struct{})
+	for _, triangleIndex := range s.vertexTriangles[vertex] {
+		if s.removedTriangles[triangleIndex] {
+			continue
+		}
+		for _, index := range s.triangles[triangleIndex] {
+			if index != vertex {
+				result[index] = struct{}{}
+			}
+		}
+	}
+	return result
+}
+
+// isStraightBorder checks whether the two specified border edge directions,
+// which point away from a shared vertex, form a straight line.
+func isStraightBorder(first, second dprec.Vec3) bool {
+	firstLength := first.Length()
+	secondLength := second.Length()
+	if firstLength == 0.0 || secondLength == 0.0 {
+		return false
+	}
+	cosine := -dprec.Vec3Dot(first, second) / (firstLength * secondLength)
+	return cosine >= lodStraightBorderCosine
+}
+
+// triangleNormal returns the unit normal and the area of the specified
+// triangle. The area is zero for degenerate triangles.
+func (s *meshSimplifier) triangleNormal(triangle [3]int) (dprec.Vec3, float64) {
+	a := s.positions[triangle[0]]
+	b := s.positions[triangle[1]]
+	c := s.positions[triangle[2]]
+	cross := dprec.Vec3Cross(dprec.Vec3Diff(b, a), dprec.Vec3Diff(c, a))
+	length := cross.Length()
+	if length < 1e-12 {
+		return dprec.ZeroVec3(), 0.0
+	}
+	return dprec.Vec3Quot(cross, length), length / 2.0
+}
@@ -0,0 +1,201 @@
+package dsl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("SimplifyGeometry", func() {
+	const gridSize = 10
+
+	// createGrid creates a flat grid in the XY plane that covers the unit
+	// square. The left and right halves of the grid are placed in separate
+	// fragments when split is set and use separate vertices along the
+	// middle column when seam is set.
+	createGrid := func(split, seam bool) *mdl.Geometry {
+		var (
+			vertices []mdl.Vertex
+			indices  []int
+		)
+		vertexIndex := make(map[[3]int]int)
+		vertexAt := func(x, y, side int) int {
+			if !seam || x != gridSize/2 {
+				side = 0
+			}
+			key := [3]int{x, y, side}
+			if index, ok := vertexIndex[key]; ok {
+				return index
+			}
+			index := len(vertices)
+			vertices = append(vertices, mdl.Vertex{
+				Coord:    sprec.NewVec3(float32(x)/gridSize, float32(y)/gridSize, 0.0),
+				Normal:   sprec.BasisZVec3(),
+				TexCoord: sprec.NewVec2(float32(side), 0.0),
+			})
+			vertexIndex[key] = index
+			return index
+		}
+		appendHalf := func(fromX, toX, side int) {
+			for y := range gridSize {
+				for x := fromX; x < toX; x++ {
+					a := vertexAt(x, y, side)
+					b := vertexAt(x+1, y, side)
+					c := vertexAt(x+1, y+1, side)
+					d := vertexAt(x, y+1, side)
+					indices = append(indices, a, b, c, a, c, d)
+				}
+			}
+		}
+
+		geometry := mdl.NewGeometry()
+		geometry.SetFormat(mdl.VertexFormatCoord | mdl.VertexFormatNormal | mdl.VertexFormatTexCoord)
+
+		appendHalf(0, gridSize/2, 0)
+		if split {
+			left := mdl.NewFragment()
+			left.SetName("left")
+			left.SetTopology(mdl.TopologyTriangleList)
+			left.SetIndexOffset(0)
+			left.SetIndexCount(len(indices))
+			geometry.AddFragment(left)
+		}
+		offset := len(indices)
+		appendHalf(gridSize/2, gridSize, 1)
+		if split {
+			right := mdl.NewFragment()
+			right.SetName("right")
+			right.SetTopology(mdl.TopologyTriangleList)
+			right.SetIndexOffset(offset)
+			right.SetIndexCount(len(indices) - offset)
+			geometry.AddFragment(right)
+		} else {
+			whole := mdl.NewFragment()
+			whole.SetName("whole")
+			whole.SetTopology(mdl.TopologyTriangleList)
+			whole.SetIndexOffset(0)
+			whole.SetIndexCount(len(indices))
+			geometry.AddFragment(whole)
+		}
+
+		geometry.SetVertices(vertices)
+		geometry.SetIndices(indices)
+		return geometry
+	}
+
+	// triangleArea returns the signed area of the specified triangle
+	// projected onto the XY plane.
+	triangleArea := func(vertices []mdl.Vertex, a, b, c int) float32 {
+		ab := sprec.Vec3Diff(vertices[b].Coord, vertices[a].Coord)
+		ac := sprec.Vec3Diff(vertices[c].Coord, vertices[a].Coord)
+		return sprec.Vec3Cross(ab, ac).Z / 2.0
+	}
+
+	totalArea := func(geometry *mdl.Geometry) float32 {
+		vertices := geometry.Vertices()
+		indices := geometry.Indices()
+		var result float32
+		for i := 0; i < len(indices); i += 3 {
+			area := triangleArea(vertices, indices[i], indices[i+1], indices[i+2])
+			Expect(area).To(BeNumerically(">", 0.0), "triangle is flipped or degenerate")
+			result += area
+		}
+		return result
+	}
+
+	hasVertex := func(geometry *mdl.Geometry, vertex mdl.Vertex) bool {
+		for _, candidate := range geometry.Vertices() {
+			if candidate == vertex {
+				return true
+			}
+		}
+		return false
+	}
+
+	It("reduces the triangle count to the target", func() {
+		source := createGrid(false, false)
+		Expect(source.Indices()).To(HaveLen(gridSize * gridSize * 2 * 3))
+
+		result, triangles := dsl.SimplifyGeometry(source, 50)
+		Expect(triangles).To(BeNumerically("<=", 50))
+		Expect(result.Indices()).To(HaveLen(triangles * 3))
+		Expect(result.Fragments()).To(HaveLen(1))
+		Expect(result.Fragments()[0].IndexCount()).To(Equal(triangles * 3))
+
+		// The source geometry is not modified.
+		Expect(source.Indices()).To(HaveLen(gridSize * gridSize * 2 * 3))
+	})
+
+	It("only keeps vertices of the source geometry", func() {
+		source := createGrid(false, false)
+		result, _ := dsl.SimplifyGeometry(source, 50)
+		Expect(len(result.Vertices())).To(BeNumerically("<", len(source.Vertices())))
+		for _, vertex := range result.Vertices() {
+			Expect(hasVertex(source, vertex)).To(BeTrue())
+		}
+	})
+
+	It("keeps the borders of the mesh in place", func() {
+		source := createGrid(false, false)
+		result, _ := dsl.SimplifyGeometry(source, 20)
+
+		// The corners cannot be collapsed without shrinking the outline.
+		for _, corner := range []sprec.Vec3{
+			sprec.NewVec3(0.0, 0.0, 0.0),
+			sprec.NewVec3(1.0, 0.0, 0.0),
+			sprec.NewVec3(1.0, 1.0, 0.0),
+			sprec.NewVec3(0.0, 1.0, 0.0),
+		} {
+			Expect(hasVertex(result, mdl.Vertex{
+				Coord:    corner,
+				Normal:   sprec.BasisZVec3(),
+				TexCoord: sprec.ZeroVec2(),
+			})).To(BeTrue())
+		}
+
+		// The simplified mesh still covers the whole square without any
+		// overlapping or flipped triangles.
+		Expect(totalArea(result)).To(BeNumerically("~", 1.0, 1e-4))
+	})
+
+	It("keeps material borders in place", func() {
+		source := createGrid(true, false)
+		result, triangles := dsl.SimplifyGeometry(source, 20)
+		Expect(triangles).To(BeNumerically("<", gridSize*gridSize*2))
+
+		fragments := result.Fragments()
+		Expect(fragments).To(HaveLen(2))
+		Expect(fragments[0].Name()).To(Equal("left"))
+		Expect(fragments[1].Name()).To(Equal("right"))
+		Expect(fragments[0].IndexCount() + fragments[1].IndexCount()).To(Equal(triangles * 3))
+
+		for y := range gridSize + 1 {
+			Expect(hasVertex(result, mdl.Vertex{
+				Coord:    sprec.NewVec3(0.5, float32(y)/gridSize, 0.0),
+				Normal:   sprec.BasisZVec3(),
+				TexCoord: sprec.ZeroVec2(),
+			})).To(BeTrue())
+		}
+		Expect(totalArea(result)).To(BeNumerically("~", 1.0, 1e-4))
+	})
+
+	It("keeps attribute seams in place", func() {
+		source := createGrid(false, true)
+		result, triangles := dsl.SimplifyGeometry(source, 20)
+		Expect(triangles).To(BeNumerically("<", gridSize*gridSize*2))
+
+		for y := range gridSize + 1 {
+			for _, side := range []float32{0.0, 1.0} {
+				Expect(hasVertex(result, mdl.Vertex{
+					Coord:    sprec.NewVec3(0.5, float32(y)/gridSize, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(side, 0.0),
+				})).To(BeTrue())
+			}
+		}
+		Expect(totalArea(result)).To(BeNumerically("~", 1.0, 1e-4))
+	})
+})
@@ -0,0 +1,238 @@
+package dsl
+
+import (
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/dtos"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/gomath/stod"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+// tangentDegenerateEpsilon is the minimum absolute area of a triangle in
+// tex coord space for it to contribute to the tangents of its vertices.
+const tangentDegenerateEpsilon = 1e-12
+
+// tangentGroupKey identifies the triangle corners whose tangents are
+// averaged together. Similar to MikkTSpace, corners are only merged when
+// they share position, normal and tex coord and when their tangent spaces
+// have the same handedness.
+type tangentGroupKey struct {
+	coord    dprec.Vec3
+	normal   dprec.Vec3
+	texCoord dprec.Vec2
+	mirrored bool
+}
+
+// tangentGroup accumulates the weighted tangents of a group of corners.
+type tangentGroup struct {
+	tangent dprec.Vec3
+	weight  float64
+}
+
+// generateGeometryTangents computes tangents for the vertices of the
+// specified geometry that are referenced by the triangle list fragments.
+//
+// The approach follows MikkTSpace. The tangent of each triangle is derived
+// from the tex coord deltas along its edges, projected onto the plane of
+// the normal at each corner and weighted by the angle of the corner.
+// Corners are then averaged across all triangles that share the same
+// position, normal, tex coord and handedness, which keeps tangents
+// consistent over split vertices. Triangles that are degenerate in tex coord
+// space do not contribute. Vertices that receive no contribution at all get
+// an arbitrary tangent that is perpendicular to their normal.
+//
+// The handedness of the tangent space is stored in the W component of the
+// tangent. Vertices that are shared by triangles of opposite handedness,
+// as is the case along the seams of mirrored tex coords, are split so that
+// each triangle gets a tangent space of its own handedness. Morph target
+// deltas are copied to the new vertices.
+//
+// The texCoordSet specifies whether the primary or the secondary tex coords
+// are used. The geometry format is extended to include tangents.
+func generateGeometryTangents(geometry *mdl.Geometry, fragments []*mdl.Fragment, texCoordSet int) {
+	vertices := geometry.Vertices()
+	indices := geometry.Indices()
+
+	texCoord := func(vertex mdl.Vertex) dprec.Vec2 {
+		coord := vertex.TexCoord
+		if texCoordSet == 1 {
+			coord = vertex.TexCoord1
+		}
+		return dprec.NewVec2(float64(coord.X), float64(coord.Y))
+	}
+	groupKey := func(vertex mdl.Vertex, mirrored bool) tangentGroupKey {
+		return tangentGroupKey{
+			coord:    stod.Vec3(vertex.Coord),
+			normal:   stod.Vec3(vertex.Normal),
+			texCoord: texCoord(vertex),
+			mirrored: mirrored,
+		}
+	}
+
+	groups := make(map[tangentGroupKey]*tangentGroup)
+	referenced := make(map[int]struct{})
+	// mirroredTriangles holds the handedness of each non-degenerate
+	// triangle, keyed by the position of its first index.
+	mirroredTriangles := make(map[int]bool)
+	for _, fragment := range fragments {
+		if fragment.Topology() != mdl.TopologyTriangleList {
+			continue
+		}
+		end := min(fragment.IndexOffset()+fragment.IndexCount(), len(indices))
+		for i := fragment.IndexOffset(); i+2 < end; i += 3 {
+			triangle := [3]int{indices[i], indices[i+1], indices[i+2]}
+			for _, index := range triangle {
+				referenced[index] = struct{}{}
+			}
+
+			tangent, mirrored, ok := triangleTangent(
+				[3]mdl.Vertex{vertices[triangle[0]], vertices[triangle[1]], vertices[triangle[2]]},
+				texCoord,
+			)
+			if !ok {
+				continue
+			}
+			mirroredTriangles[i] = mirrored
+			for corner, index := range triangle {
+				vertex := vertices[index]
+				normal := dprec.UnitVec3(stod.Vec3(vertex.Normal))
+				projected := projectOnPlane(tangent, normal)
+				if projected.Length() < tangentDegenerateEpsilon {
+					continue
+				}
+				prev := stod.Vec3(vertices[triangle[(corner+2)%3]].Coord)
+				next := stod.Vec3(vertices[triangle[(corner+1)%3]].Coord)
+				coord := stod.Vec3(vertex.Coord)
+				weight := cornerAngle(
+					projectOnPlane(dprec.Vec3Diff(next, coord), normal),
+					projectOnPlane(dprec.Vec3Diff(prev, coord), normal),
+				)
+
+				key := groupKey(vertex, mirrored)
+				group, ok := groups[key]
+				if !ok {
+					group = &tangentGroup{}
+					groups[key] = group
+				}
+				group.tangent = dprec.Vec3Sum(group.tangent, dprec.Vec3Prod(dprec.UnitVec3(projected), weight))
+				group.weight += weight
+			}
+		}
+	}
+
+	tangentFromGroup := func(vertex mdl.Vertex, group *tangentGroup, mirrored bool) sprec.Vec4 {
+		normal := dprec.UnitVec3(stod.Vec3(vertex.Normal))
+		var tangent dprec.Vec3
+		if group != nil {
+			tangent = projectOnPlane(group.tangent, normal)
+		}
+		if tangent.Length() < tangentDegenerateEpsilon {
+			tangent = perpendicularVec3(normal)
+		}
+		handedness := float32(1.0)
+		if mirrored {
+			handedness = -1.0
+		}
+		direction := dtos.Vec3(dprec.UnitVec3(tangent))
+		return sprec.NewVec4(direction.X, direction.Y, direction.Z, handedness)
+	}
+
+	// splitVertices maps vertices that are shared by triangles of opposite
+	// handedness to the copies that are used by the minority handedness.
+	splitVertices := make(map[int]int)
+	splitMirrored := make(map[int]bool)
+	targets := geometry.MorphTargets()
+	for _, index := range slices.Sorted(maps.Keys(referenced)) {
+		vertex := vertices[index]
+
+		// The handedness that most of the corners agree on is kept by the
+		// vertex itself.
+		group := groups[groupKey(vertex, false)]
+		mirroredGroup := groups[groupKey(vertex, true)]
+		mirrored := mirroredGroup != nil && (group == nil || mirroredGroup.weight > group.weight)
+		if mirrored {
+			group, mirroredGroup = mirroredGroup, group
+		}
+		vertex.Tangent = tangentFromGroup(vertex, group, mirrored)
+		vertices[index] = vertex
+
+		if group != nil && mirroredGroup != nil {
+			split := vertex
+			split.Tangent = tangentFromGroup(vertex, mirroredGroup, !mirrored)
+			splitVertices[index] = len(vertices)
+			splitMirrored[index] = !mirrored
+			for _, target := range targets {
+				if delta := target.Delta(index); delta != (mdl.MorphDelta{}) {
+					target.SetDelta(len(vertices), delta)
+				}
+			}
+			vertices = append(vertices, split)
+		}
+	}
+
+	if len(splitVertices) > 0 {
+		for i, mirrored := range mirroredTriangles {
+			for j := i; j < i+3; j++ {
+				if split, ok := splitVertices[indices[j]]; ok && splitMirrored[indices[j]] == mirrored {
+					indices[j] = split
+				}
+			}
+		}
+		geometry.SetIndices(indices)
+	}
+	geometry.SetVertices(vertices)
+	geometry.SetFormat(geometry.Format() | mdl.VertexFormatTangent)
+}
+
+// triangleTangent returns the direction in which the U tex coord increases
+// along the specified triangle and whether the tex coords are mirrored.
+func triangleTangent(triangle [3]mdl.Vertex, texCoord func(mdl.Vertex) dprec.Vec2) (dprec.Vec3, bool, bool) {
+	edge1 := dprec.Vec3Diff(stod.Vec3(triangle[1].Coord), stod.Vec3(triangle[0].Coord))
+	edge2 := dprec.Vec3Diff(stod.Vec3(triangle[2].Coord), stod.Vec3(triangle[0].Coord))
+	delta1 := dprec.Vec2Diff(texCoord(triangle[1]), texCoord(triangle[0]))
+	delta2 := dprec.Vec2Diff(texCoord(triangle[2]), texCoord(triangle[0]))
+
+	area := delta1.X*delta2.Y - delta2.X*delta1.Y
+	if dprec.Abs(area) < tangentDegenerateEpsilon {
+		return dprec.ZeroVec3(), false, false
+	}
+	tangent := dprec.Vec3Quot(dprec.Vec3Diff(
+		dprec.Vec3Prod(edge1, delta2.Y),
+		dprec.Vec3Prod(edge2, delta1.Y),
+	), area)
+	if tangent.Length() < tangentDegenerateEpsilon {
+		return dprec.ZeroVec3(), false, false
+	}
+	return tangent, area < 0.0, true
+}
+
+// projectOnPlane removes the component of the vector that is along the
+// specified unit normal.
+func projectOnPlane(vector, normal dprec.Vec3) dprec.Vec3 {
+	return dprec.Vec3Diff(vector, dprec.Vec3Prod(normal, dprec.Vec3Dot(vector, normal)))
+}
+
+// cornerAngle returns the angle in radians between the two edges of a
+// triangle corner.
+func cornerAngle(edge1, edge2 dprec.Vec3) float64 {
+	length1, length2 := edge1.Length(), edge2.Length()
+	if length1 < tangentDegenerateEpsilon || length2 < tangentDegenerateEpsilon {
+		return 0.0
+	}
+	cosine := dprec.Vec3Dot(edge1, edge2) / (length1 * length2)
+	return math.Acos(min(max(cosine, -1.0), 1.0))
+}
+
+// perpendicularVec3 returns an arbitrary unit vector that is perpendicular
+// to the specified unit vector.
+func perpendicularVec3(vector dprec.Vec3) dprec.Vec3 {
+	axis := dprec.BasisXVec3()
+	if dprec.Abs(vector.X) > 0.9 {
+		axis = dprec.BasisYVec3()
+	}
+	return dprec.UnitVec3(projectOnPlane(axis, vector))
+}
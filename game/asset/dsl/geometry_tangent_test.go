@@ -0,0 +1,166 @@
+package dsl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("GenerateGeometryTangents", func() {
+	var geometry *mdl.Geometry
+
+	// expectTangent checks the tangent of the vertex at the specified
+	// index, allowing for float precision errors in the direction.
+	expectTangent := func(index int, expected sprec.Vec4) {
+		GinkgoHelper()
+		tangent := geometry.Vertex(index).Tangent
+		Expect(tangent.X).To(BeNumerically("~", expected.X, 1e-5))
+		Expect(tangent.Y).To(BeNumerically("~", expected.Y, 1e-5))
+		Expect(tangent.Z).To(BeNumerically("~", expected.Z, 1e-5))
+		Expect(tangent.W).To(Equal(expected.W))
+	}
+
+	When("the tex coords of a quad are mirrored along its middle", func() {
+		var morphTarget *mdl.MorphTarget
+
+		// The quad is made of two triangles that share the B-C edge. The
+		// U tex coord increases to the right in the right triangle and to
+		// the left in the left triangle.
+		//
+		//     C
+		//    /|\
+		//   / | \
+		//  A--B--D
+		BeforeEach(func() {
+			geometry = mdl.NewGeometry()
+			geometry.SetFormat(mdl.VertexFormatCoord | mdl.VertexFormatNormal | mdl.VertexFormatTexCoord)
+			geometry.SetVertices([]mdl.Vertex{
+				{ // A
+					Coord:    sprec.NewVec3(-1.0, 0.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(1.0, 0.0),
+				},
+				{ // B
+					Coord:    sprec.NewVec3(0.0, 0.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(0.0, 0.0),
+				},
+				{ // C
+					Coord:    sprec.NewVec3(0.0, 1.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(0.0, 1.0),
+				},
+				{ // D
+					Coord:    sprec.NewVec3(1.0, 0.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(1.0, 0.0),
+				},
+			})
+			geometry.SetIndices([]int{
+				1, 3, 2, // right
+				0, 1, 2, // left
+			})
+
+			fragment := mdl.NewFragment()
+			fragment.SetName("quad")
+			fragment.SetTopology(mdl.TopologyTriangleList)
+			fragment.SetIndexOffset(0)
+			fragment.SetIndexCount(6)
+			geometry.AddFragment(fragment)
+
+			morphTarget = mdl.NewMorphTarget()
+			morphTarget.SetName("lift")
+			morphTarget.SetDelta(1, mdl.MorphDelta{
+				Coord: sprec.NewVec3(0.0, 0.0, 0.5),
+			})
+			geometry.AddMorphTarget(morphTarget)
+
+			dsl.GenerateGeometryTangents(geometry, geometry.Fragments(), 0)
+		})
+
+		It("includes tangents in the format", func() {
+			Expect(geometry.Format() & mdl.VertexFormatTangent).ToNot(BeZero())
+		})
+
+		It("splits the vertices along the mirror seam", func() {
+			Expect(geometry.Vertices()).To(HaveLen(6))
+			indices := geometry.Indices()
+			Expect(indices[:3]).To(Equal([]int{1, 3, 2}))
+			Expect(indices[3]).To(Equal(0))
+			Expect(indices[4:]).To(ConsistOf(4, 5))
+			Expect(geometry.Vertex(indices[4]).Coord).To(Equal(geometry.Vertex(1).Coord))
+			Expect(geometry.Vertex(indices[5]).Coord).To(Equal(geometry.Vertex(2).Coord))
+		})
+
+		It("assigns right-handed tangents to the right triangle", func() {
+			for _, index := range geometry.Indices()[:3] {
+				expectTangent(index, sprec.NewVec4(1.0, 0.0, 0.0, 1.0))
+			}
+		})
+
+		It("assigns left-handed tangents to the left triangle", func() {
+			for _, index := range geometry.Indices()[3:] {
+				expectTangent(index, sprec.NewVec4(-1.0, 0.0, 0.0, -1.0))
+			}
+		})
+
+		It("copies morph deltas to the split vertices", func() {
+			split := geometry.Indices()[4]
+			Expect(morphTarget.Delta(split)).To(Equal(morphTarget.Delta(1)))
+			Expect(morphTarget.Delta(geometry.Indices()[5])).To(Equal(mdl.MorphDelta{}))
+		})
+	})
+
+	When("the tex coords of a quad are continuous", func() {
+		BeforeEach(func() {
+			geometry = mdl.NewGeometry()
+			geometry.SetFormat(mdl.VertexFormatCoord | mdl.VertexFormatNormal | mdl.VertexFormatTexCoord)
+			geometry.SetVertices([]mdl.Vertex{
+				{
+					Coord:    sprec.NewVec3(0.0, 0.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(0.0, 0.0),
+				},
+				{
+					Coord:    sprec.NewVec3(1.0, 0.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(1.0, 0.0),
+				},
+				{
+					Coord:    sprec.NewVec3(1.0, 1.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(1.0, 1.0),
+				},
+				{
+					Coord:    sprec.NewVec3(0.0, 1.0, 0.0),
+					Normal:   sprec.BasisZVec3(),
+					TexCoord: sprec.NewVec2(0.0, 1.0),
+				},
+			})
+			geometry.SetIndices([]int{0, 1, 2, 0, 2, 3})
+
+			fragment := mdl.NewFragment()
+			fragment.SetName("quad")
+			fragment.SetTopology(mdl.TopologyTriangleList)
+			fragment.SetIndexOffset(0)
+			fragment.SetIndexCount(6)
+			geometry.AddFragment(fragment)
+
+			dsl.GenerateGeometryTangents(geometry, geometry.Fragments(), 0)
+		})
+
+		It("does not split any vertices", func() {
+			Expect(geometry.Vertices()).To(HaveLen(4))
+			Expect(geometry.Indices()).To(Equal([]int{0, 1, 2, 0, 2, 3}))
+		})
+
+		It("assigns right-handed tangents along U", func() {
+			for index := range geometry.Vertices() {
+				expectTangent(index, sprec.NewVec4(1.0, 0.0, 0.0, 1.0))
+			}
+		})
+	})
+})
@@ -0,0 +1,142 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// gltfContentDigest calculates a digest of the contents of the glTF file at
+// the specified path, including any external buffer and image files that
+// it references, as opened through the specified resolver.
+func gltfContentDigest(path string, resolver *gltfResolver) (string, error) {
+	modelDigest, err := FileContentDigest(path)
+	if err != nil {
+		return "", err
+	}
+
+	uris, err := gltfExternalURIs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect gltf model %q: %w", path, err)
+	}
+
+	params := make([]any, 0, 1+2*len(uris))
+	params = append(params, modelDigest)
+	for _, uri := range uris {
+		refPath, err := url.PathUnescape(uri)
+		if err != nil {
+			return "", fmt.Errorf("invalid uri %q in gltf model %q: %w", uri, path, err)
+		}
+		refDigest, err := resolver.Digest(refPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to digest file referenced by gltf model %q: %w", path, err)
+		}
+		params = append(params, uri, refDigest)
+	}
+
+	digest, err := CreateDigest("gltf-content", params...)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest), nil
+}
+
+// gltfExternalURIs returns the URIs of all buffers and images that are
+// stored outside of the glTF file at the specified path, in the order in
+// which they are declared.
+func gltfExternalURIs(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open model file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	jsonData, err := readGLTFJSON(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Buffers []struct {
+			URI string `json:"uri"`
+		} `json:"buffers"`
+		Images []struct {
+			URI string `json:"uri"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode gltf json: %w", err)
+	}
+
+	var result []string
+	for _, buffer := range doc.Buffers {
+		if isExternalGLTFURI(buffer.URI) {
+			result = append(result, buffer.URI)
+		}
+	}
+	for _, image := range doc.Images {
+		if isExternalGLTFURI(image.URI) {
+			result = append(result, image.URI)
+		}
+	}
+	return result, nil
+}
+
+// readGLTFJSON returns the JSON document of a glTF file, which can either
+// be in text (.gltf) or in binary (.glb) format.
+//
+// Only the header and the JSON chunk of a binary file are read, since the
+// binary chunk that follows can be large and is covered by the file digest.
+func readGLTFJSON(in io.Reader) ([]byte, error) {
+	const (
+		headerSize      = 12
+		chunkHeaderSize = 8
+		chunkTypeJSON   = 0x4E4F534A
+	)
+	var header [headerSize + chunkHeaderSize]byte
+	headerLength, err := io.ReadFull(in, header[:])
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		if bytes.HasPrefix(header[:headerLength], []byte("glTF")) {
+			return nil, fmt.Errorf("glb file is truncated")
+		}
+	default:
+		return nil, fmt.Errorf("failed to read gltf file: %w", err)
+	}
+
+	if !bytes.HasPrefix(header[:headerLength], []byte("glTF")) {
+		rest, err := io.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gltf file: %w", err)
+		}
+		return append(header[:headerLength:headerLength], rest...), nil
+	}
+
+	chunkLength := binary.LittleEndian.Uint32(header[headerSize:])
+	chunkType := binary.LittleEndian.Uint32(header[headerSize+4:])
+	if chunkType != chunkTypeJSON {
+		return nil, fmt.Errorf("glb file does not start with a json chunk")
+	}
+	// The chunk is read through a limited reader so that a corrupt length
+	// cannot force a large allocation.
+	data, err := io.ReadAll(io.LimitReader(in, int64(chunkLength)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glb json chunk: %w", err)
+	}
+	if len(data) < int(chunkLength) {
+		return nil, fmt.Errorf("glb json chunk is truncated")
+	}
+	return data, nil
+}
+
+func isExternalGLTFURI(uri string) bool {
+	return uri != "" && !strings.HasPrefix(uri, "data:")
+}
@@ -0,0 +1,73 @@
+package dsl_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/asset/dsl"
+)
+
+var _ = Describe("ReadGLTFJSON", func() {
+	const document = `{"asset":{"version":"2.0"}}`
+
+	createGLB := func(jsonChunk string, jsonChunkLength uint32) []byte {
+		var buffer bytes.Buffer
+		buffer.WriteString("glTF")
+		binary.Write(&buffer, binary.LittleEndian, uint32(2))
+		binary.Write(&buffer, binary.LittleEndian, uint32(0))
+		binary.Write(&buffer, binary.LittleEndian, jsonChunkLength)
+		binary.Write(&buffer, binary.LittleEndian, uint32(0x4E4F534A))
+		buffer.WriteString(jsonChunk)
+		return buffer.Bytes()
+	}
+
+	It("returns text documents as they are", func() {
+		data, err := dsl.ReadGLTFJSON(bytes.NewReader([]byte(document)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(document))
+	})
+
+	It("returns short text documents as they are", func() {
+		data, err := dsl.ReadGLTFJSON(bytes.NewReader([]byte("{}")))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("{}"))
+	})
+
+	It("returns the json chunk of binary documents", func() {
+		glb := createGLB(document, uint32(len(document)))
+		data, err := dsl.ReadGLTFJSON(bytes.NewReader(glb))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(document))
+	})
+
+	It("does not read past the json chunk of binary documents", func() {
+		glb := createGLB(document, uint32(len(document)))
+		in := io.MultiReader(bytes.NewReader(glb), failingReader{})
+		data, err := dsl.ReadGLTFJSON(in)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(document))
+	})
+
+	It("reports truncated binary headers", func() {
+		glb := createGLB(document, uint32(len(document)))
+		_, err := dsl.ReadGLTFJSON(bytes.NewReader(glb[:16]))
+		Expect(err).To(MatchError(ContainSubstring("truncated")))
+	})
+
+	It("reports truncated json chunks", func() {
+		glb := createGLB(document, 1<<30)
+		_, err := dsl.ReadGLTFJSON(bytes.NewReader(glb))
+		Expect(err).To(MatchError(ContainSubstring("truncated")))
+	})
+})
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read past json chunk")
+}
@@ -0,0 +1,910 @@
+package dsl
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/ext/lightspunctual"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+const gltfEmissiveStrengthExtensionName = "KHR_materials_emissive_strength"
+
+// SaveGLTFModel writes the specified model as a binary glTF 2.0 (.glb) file
+// at the specified path.
+func SaveGLTFModel(path string, model *mdl.Model) error {
+	var buffer bytes.Buffer
+	if err := WriteGLTFModel(&buffer, model); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("error writing model file %q: %w", path, err)
+	}
+	return nil
+}
+
+// WriteGLTFModel writes the specified model in binary glTF 2.0 (.glb) format
+// to the specified writer.
+//
+// Nodes, meshes, skins, animations, punctual lights and cameras are
+// exported. Materials are exported as PBR materials based on the properties
+// and samplers that the glTF importer produces. Metadata is stored in the
+// extras of the respective glTF objects and meshes that are used for
+// collision are marked as collidable, so that importing the result restores
+// the collision meshes.
+//
+// Ambient lights, skies and collision boxes and spheres have no glTF
+// counterpart and are omitted.
+func WriteGLTFModel(out io.Writer, model *mdl.Model) error {
+	exporter := newGLTFExporter()
+	if err := exporter.Export(model); err != nil {
+		return fmt.Errorf("error exporting gltf model: %w", err)
+	}
+	if err := gltf.NewEncoder(out).Encode(exporter.doc); err != nil {
+		return fmt.Errorf("error encoding gltf model: %w", err)
+	}
+	return nil
+}
+
+func newGLTFExporter() *gltfExporter {
+	doc := gltf.NewDocument()
+	doc.Asset.Generator = "lacking"
+	return &gltfExporter{
+		doc:                  doc,
+		nodeIndices:          make(map[*mdl.Node]int),
+		nodeIndicesFromName:  make(map[string]int),
+		imageIndices:         make(map[*mdl.Texture]int),
+		textureIndices:       make(map[*mdl.Sampler]int),
+		materialIndices:      make(map[*mdl.Material]int),
+		geometryAccessors:    make(map[*mdl.Geometry]*gltfGeometryAccessors),
+		meshIndices:          make(map[*mdl.MeshDefinition]int),
+		skinIndices:          make(map[*mdl.Armature]int),
+		collidableGeometries: make(map[*mdl.Geometry]struct{}),
+	}
+}
+
+type gltfExporter struct {
+	doc    *gltf.Document
+	lights lightspunctual.Lights
+
+	nodeIndices          map[*mdl.Node]int
+	nodeIndicesFromName  map[string]int
+	imageIndices         map[*mdl.Texture]int
+	textureIndices       map[*mdl.Sampler]int
+	materialIndices      map[*mdl.Material]int
+	geometryAccessors    map[*mdl.Geometry]*gltfGeometryAccessors
+	meshIndices          map[*mdl.MeshDefinition]int
+	skinIndices          map[*mdl.Armature]int
+	collidableGeometries map[*mdl.Geometry]struct{}
+}
+
+type gltfGeometryAccessors struct {
+	attributes gltf.PrimitiveAttributes
+	targets    []gltf.PrimitiveAttributes
+	indices    []int
+}
+
+func (e *gltfExporter) Export(model *mdl.Model) error {
+	// Collision meshes are restored by the importer for meshes that are
+	// marked as collidable, so such meshes need to be known in advance.
+	for _, node := range model.NodesIter() {
+		if !e.hasCollisionMeshes(node) {
+			continue
+		}
+		for mesh := range mdl.NodeAttachmentsOfType[*mdl.Mesh](node) {
+			e.collidableGeometries[mesh.Definition().Geometry()] = struct{}{}
+		}
+	}
+
+	scene := e.doc.Scenes[0]
+	for _, node := range model.Nodes() {
+		nodeIndex, err := e.exportNode(node)
+		if err != nil {
+			return err
+		}
+		scene.Nodes = append(scene.Nodes, nodeIndex)
+	}
+
+	for _, armature := range model.AllArmatures() {
+		if err := e.exportSkin(armature); err != nil {
+			return err
+		}
+	}
+
+	for _, animation := range model.Animations() {
+		if err := e.exportAnimation(animation); err != nil {
+			return err
+		}
+	}
+
+	if len(e.lights) > 0 {
+		// NOTE: The lights collection does not marshal into the expected
+		// envelope on its own.
+		e.addExtension(lightspunctual.ExtensionName)
+		e.doc.Extensions = gltf.Extensions{
+			lightspunctual.ExtensionName: map[string]any{
+				"lights": e.lights,
+			},
+		}
+	}
+	return nil
+}
+
+func (e *gltfExporter) hasCollisionMeshes(node *mdl.Node) bool {
+	for body := range mdl.NodeAttachmentsOfType[*mdl.Body](node) {
+		if len(body.Definition().CollisionMeshes()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *gltfExporter) exportNode(node *mdl.Node) (int, error) {
+	translation := node.Translation()
+	rotation := node.Rotation()
+	scale := node.Scale()
+
+	gltfNode := &gltf.Node{
+		Name:        node.Name(),
+		Extras:      gltfExtras(node.Metadata()),
+		Translation: [3]float64{translation.X, translation.Y, translation.Z},
+		Rotation:    [4]float64{rotation.X, rotation.Y, rotation.Z, rotation.W},
+		Scale:       [3]float64{scale.X, scale.Y, scale.Z},
+	}
+	nodeIndex := len(e.doc.Nodes)
+	e.doc.Nodes = append(e.doc.Nodes, gltfNode)
+	e.nodeIndices[node] = nodeIndex
+	e.nodeIndicesFromName[node.Name()] = nodeIndex
+
+	for _, attachment := range node.Attachments() {
+		if err := e.exportAttachment(nodeIndex, attachment); err != nil {
+			return 0, fmt.Errorf("error exporting attachment of node %q: %w", node.Name(), err)
+		}
+	}
+
+	for _, child := range node.Nodes() {
+		childIndex, err := e.exportNode(child)
+		if err != nil {
+			return 0, err
+		}
+		gltfNode := e.doc.Nodes[nodeIndex]
+		gltfNode.Children = append(gltfNode.Children, childIndex)
+	}
+	return nodeIndex, nil
+}
+
+func (e *gltfExporter) exportAttachment(nodeIndex int, attachment any) error {
+	switch attachment := attachment.(type) {
+	case *mdl.Mesh:
+		meshIndex, err := e.exportMeshDefinition(attachment.Definition())
+		if err != nil {
+			return err
+		}
+		gltfNode := e.attachmentNode(nodeIndex, func(gltfNode *gltf.Node) bool {
+			return gltfNode.Mesh != nil
+		})
+		gltfNode.Mesh = gltf.Index(meshIndex)
+		if armature := attachment.Armature(); armature != nil {
+			gltfNode.Skin = gltf.Index(e.skinIndex(armature))
+		}
+
+	case *mdl.Body:
+		definition := attachment.Definition()
+		gltfNode := e.doc.Nodes[nodeIndex]
+		extras, _ := gltfNode.Extras.(map[string]any)
+		if extras == nil {
+			extras = make(map[string]any)
+		}
+		extras["mass"] = definition.Mass()
+		extras["friction"] = definition.Material().FrictionCoefficient()
+		extras["restitution"] = definition.Material().RestitutionCoefficient()
+		gltfNode.Extras = extras
+
+	case *mdl.PointLight:
+		color, intensity := gltfLightColor(attachment.EmitColor())
+		e.attachLight(nodeIndex, &lightspunctual.Light{
+			Type:      lightspunctual.TypePoint,
+			Color:     &color,
+			Intensity: gltf.Float(intensity),
+			Range:     gltf.Float(attachment.EmitDistance()),
+		})
+
+	case *mdl.SpotLight:
+		color, intensity := gltfLightColor(attachment.EmitColor())
+		e.attachLight(nodeIndex, &lightspunctual.Light{
+			Type:      lightspunctual.TypeSpot,
+			Color:     &color,
+			Intensity: gltf.Float(intensity),
+			Range:     gltf.Float(attachment.EmitDistance()),
+			Spot: &lightspunctual.Spot{
+				InnerConeAngle: attachment.EmitAngleInner().Radians(),
+				OuterConeAngle: gltf.Float(attachment.EmitAngleOuter().Radians()),
+			},
+		})
+
+	case *mdl.DirectionalLight:
+		color, intensity := gltfLightColor(attachment.EmitColor())
+		e.attachLight(nodeIndex, &lightspunctual.Light{
+			Type:      lightspunctual.TypeDirectional,
+			Color:     &color,
+			Intensity: gltf.Float(intensity),
+		})
+
+	case *mdl.Camera:
+		gltfCamera := &gltf.Camera{
+			Name: attachment.Name(),
+		}
+		switch attachment.Projection() {
+		case mdl.CameraProjectionPerspective:
+			perspective := &gltf.Perspective{
+				Yfov:  attachment.FoV().Radians(),
+				Znear: attachment.Near(),
+				Zfar:  gltf.Float(attachment.Far()),
+			}
+			if aspectRatio := attachment.AspectRatio(); aspectRatio > 0.0 {
+				perspective.AspectRatio = gltf.Float(aspectRatio)
+			}
+			gltfCamera.Perspective = perspective
+		case mdl.CameraProjectionOrthographic:
+			ymag := attachment.OrthographicHeight() / 2.0
+			xmag := ymag
+			if aspectRatio := attachment.AspectRatio(); aspectRatio > 0.0 {
+				xmag = ymag * aspectRatio
+			}
+			gltfCamera.Orthographic = &gltf.Orthographic{
+				Xmag:  xmag,
+				Ymag:  ymag,
+				Znear: attachment.Near(),
+				Zfar:  attachment.Far(),
+			}
+		default:
+			return fmt.Errorf("unsupported camera projection %d", attachment.Projection())
+		}
+		e.doc.Cameras = append(e.doc.Cameras, gltfCamera)
+		gltfNode := e.attachmentNode(nodeIndex, func(gltfNode *gltf.Node) bool {
+			return gltfNode.Camera != nil
+		})
+		gltfNode.Camera = gltf.Index(len(e.doc.Cameras) - 1)
+
+	default:
+		logger.Warn("Skipping attachment without glTF counterpart",
+			slog.String("type", fmt.Sprintf("%T", attachment)),
+		)
+	}
+	return nil
+}
+
+// attachmentNode returns the node at the specified index, unless the
+// relevant attachment slot of that node is already taken, in which case
+// a child node is created to hold the attachment instead.
+func (e *gltfExporter) attachmentNode(nodeIndex int, isTaken func(gltfNode *gltf.Node) bool) *gltf.Node {
+	gltfNode := e.doc.Nodes[nodeIndex]
+	if !isTaken(gltfNode) {
+		return gltfNode
+	}
+	childNode := &gltf.Node{
+		Name:     fmt.Sprintf("%s_%d", gltfNode.Name, len(gltfNode.Children)),
+		Rotation: gltf.DefaultRotation,
+		Scale:    gltf.DefaultScale,
+	}
+	e.doc.Nodes = append(e.doc.Nodes, childNode)
+	gltfNode.Children = append(gltfNode.Children, len(e.doc.Nodes)-1)
+	return childNode
+}
+
+func (e *gltfExporter) attachLight(nodeIndex int, light *lightspunctual.Light) {
+	e.lights = append(e.lights, light)
+	gltfNode := e.attachmentNode(nodeIndex, func(gltfNode *gltf.Node) bool {
+		_, ok := gltfNode.Extensions[lightspunctual.ExtensionName]
+		return ok
+	})
+	gltfNode.Extensions = gltf.Extensions{
+		lightspunctual.ExtensionName: map[string]any{
+			"light": len(e.lights) - 1,
+		},
+	}
+}
+
+func (e *gltfExporter) exportMeshDefinition(definition *mdl.MeshDefinition) (int, error) {
+	if index, ok := e.meshIndices[definition]; ok {
+		return index, nil
+	}
+
+	geometry := definition.Geometry()
+	accessors, err := e.exportGeometry(geometry)
+	if err != nil {
+		return 0, fmt.Errorf("error exporting geometry %q: %w", geometry.Name(), err)
+	}
+
+	gltfMesh := &gltf.Mesh{
+		Name: definition.Name(),
+	}
+
+	extras, _ := gltfExtras(geometry.Metadata()).(map[string]any)
+	if _, ok := e.collidableGeometries[geometry]; ok && !geometry.Metadata().HasCollision() {
+		if extras == nil {
+			extras = make(map[string]any)
+		}
+		extras["collidable"] = "true"
+	}
+	if targets := geometry.MorphTargets(); len(targets) > 0 {
+		if extras == nil {
+			extras = make(map[string]any)
+		}
+		targetNames := make([]any, len(targets))
+		for i, target := range targets {
+			targetNames[i] = target.Name()
+			gltfMesh.Weights = append(gltfMesh.Weights, target.DefaultWeight())
+		}
+		extras["targetNames"] = targetNames
+	}
+	if extras != nil {
+		gltfMesh.Extras = extras
+	}
+
+	for i, fragment := range geometry.Fragments() {
+		mode, err := gltfPrimitiveMode(fragment.Topology())
+		if err != nil {
+			return 0, err
+		}
+		primitive := &gltf.Primitive{
+			Extras:     gltfExtras(fragment.Metadata()),
+			Attributes: accessors.attributes,
+			Indices:    gltf.Index(accessors.indices[i]),
+			Mode:       mode,
+			Targets:    accessors.targets,
+		}
+		if material, ok := definition.MaterialBindings()[fragment.Name()]; ok {
+			materialIndex, err := e.exportMaterial(material)
+			if err != nil {
+				return 0, fmt.Errorf("error exporting material %q: %w", material.Name(), err)
+			}
+			primitive.Material = gltf.Index(materialIndex)
+		} else {
+			// NOTE: The importer uses the name of the material of a primitive
+			// as the name of the fragment, so unbound fragments (e.g. ones that
+			// are invisible) still get a placeholder material with that name.
+			e.doc.Materials = append(e.doc.Materials, &gltf.Material{
+				Name:   fragment.Name(),
+				Extras: gltfExtras(fragment.Metadata()),
+			})
+			primitive.Material = gltf.Index(len(e.doc.Materials) - 1)
+		}
+		gltfMesh.Primitives = append(gltfMesh.Primitives, primitive)
+	}
+
+	e.doc.Meshes = append(e.doc.Meshes, gltfMesh)
+	index := len(e.doc.Meshes) - 1
+	e.meshIndices[definition] = index
+	return index, nil
+}
+
+func (e *gltfExporter) exportGeometry(geometry *mdl.Geometry) (*gltfGeometryAccessors, error) {
+	if accessors, ok := e.geometryAccessors[geometry]; ok {
+		return accessors, nil
+	}
+
+	format := geometry.Format()
+	vertices := geometry.Vertices()
+	accessors := &gltfGeometryAccessors{
+		attributes: make(gltf.PrimitiveAttributes),
+	}
+
+	if format&mdl.VertexFormatCoord != 0 {
+		coords := make([][3]float32, len(vertices))
+		for i, vertex := range vertices {
+			coords[i] = gltfVec3(vertex.Coord)
+		}
+		accessors.attributes[gltf.POSITION] = modeler.WritePosition(e.doc, coords)
+	}
+	if format&mdl.VertexFormatNormal != 0 {
+		normals := make([][3]float32, len(vertices))
+		for i, vertex := range vertices {
+			normals[i] = gltfVec3(vertex.Normal)
+		}
+		accessors.attributes[gltf.NORMAL] = modeler.WriteNormal(e.doc, normals)
+	}
+	if format&mdl.VertexFormatTangent != 0 {
+		tangents := make([][4]float32, len(vertices))
+		for i, vertex := range vertices {
+			tangents[i] = [4]float32{vertex.Tangent.X, vertex.Tangent.Y, vertex.Tangent.Z, vertex.Tangent.W}
+		}
+		accessors.attributes[gltf.TANGENT] = modeler.WriteTangent(e.doc, tangents)
+	}
+	if format&mdl.VertexFormatTexCoord != 0 {
+		texCoords := make([][2]float32, len(vertices))
+		for i, vertex := range vertices {
+			texCoords[i] = gltfTexCoord(vertex.TexCoord)
+		}
+		accessors.attributes[gltf.TEXCOORD_0] = modeler.WriteTextureCoord(e.doc, texCoords)
+	}
+	if format&mdl.VertexFormatTexCoord1 != 0 {
+		texCoords := make([][2]float32, len(vertices))
+		for i, vertex := range vertices {
+			texCoords[i] = gltfTexCoord(vertex.TexCoord1)
+		}
+		accessors.attributes[gltf.TEXCOORD_1] = modeler.WriteTextureCoord(e.doc, texCoords)
+	}
+	if format&mdl.VertexFormatColor != 0 {
+		colors := make([][4]float32, len(vertices))
+		for i, vertex := range vertices {
+			colors[i] = [4]float32{vertex.Color.X, vertex.Color.Y, vertex.Color.Z, vertex.Color.W}
+		}
+		accessors.attributes[gltf.COLOR_0] = modeler.WriteColor(e.doc, colors)
+	}
+	if format&mdl.VertexFormatWeights != 0 && format&mdl.VertexFormatJoints != 0 {
+		weights := make([][4]float32, len(vertices))
+		joints := make([][4]uint8, len(vertices))
+		for i, vertex := range vertices {
+			weights[i] = [4]float32{vertex.Weights.X, vertex.Weights.Y, vertex.Weights.Z, vertex.Weights.W}
+			joints[i] = vertex.Joints
+		}
+		accessors.attributes[gltf.WEIGHTS_0] = modeler.WriteWeights(e.doc, weights)
+		accessors.attributes[gltf.JOINTS_0] = modeler.WriteJoints(e.doc, joints)
+	}
+
+	for _, target := range geometry.MorphTargets() {
+		coords := make([][3]float32, len(vertices))
+		normals := make([][3]float32, len(vertices))
+		for i := range vertices {
+			delta := target.Delta(i)
+			coords[i] = gltfVec3(delta.Coord)
+			normals[i] = gltfVec3(delta.Normal)
+		}
+		attributes := gltf.PrimitiveAttributes{
+			gltf.POSITION: modeler.WritePosition(e.doc, coords),
+		}
+		if format&mdl.VertexFormatNormal != 0 {
+			attributes[gltf.NORMAL] = modeler.WriteNormal(e.doc, normals)
+		}
+		accessors.targets = append(accessors.targets, attributes)
+	}
+
+	indices := geometry.Indices()
+	for _, fragment := range geometry.Fragments() {
+		offset := fragment.IndexOffset()
+		count := fragment.IndexCount()
+		if offset+count > len(indices) {
+			return nil, fmt.Errorf("fragment %q exceeds the index range of the geometry", fragment.Name())
+		}
+		fragmentIndices := make([]uint32, count)
+		for i := range fragmentIndices {
+			fragmentIndices[i] = uint32(indices[offset+i])
+		}
+		accessors.indices = append(accessors.indices, modeler.WriteIndices(e.doc, fragmentIndices))
+	}
+
+	e.geometryAccessors[geometry] = accessors
+	return accessors, nil
+}
+
+func (e *gltfExporter) exportMaterial(material *mdl.Material) (int, error) {
+	if index, ok := e.materialIndices[material]; ok {
+		return index, nil
+	}
+
+	gltfMaterial := &gltf.Material{
+		Name:   material.Name(),
+		Extras: gltfExtras(material.Metadata()),
+	}
+
+	color := gltfMaterialVec4(material, "color", sprec.NewVec4(1.0, 1.0, 1.0, 1.0))
+	pbr := &gltf.PBRMetallicRoughness{
+		BaseColorFactor: &[4]float64{float64(color.X), float64(color.Y), float64(color.Z), float64(color.W)},
+		MetallicFactor:  gltf.Float(gltfMaterialFloat(material, "metallic", 1.0)),
+		RoughnessFactor: gltf.Float(gltfMaterialFloat(material, "roughness", 1.0)),
+	}
+	if sampler := material.Sampler("colorSampler"); sampler != nil {
+		info, err := e.textureInfo(sampler)
+		if err != nil {
+			return 0, err
+		}
+		pbr.BaseColorTexture = info
+	}
+	if sampler := material.Sampler("metallicRoughnessSampler"); sampler != nil {
+		info, err := e.textureInfo(sampler)
+		if err != nil {
+			return 0, err
+		}
+		pbr.MetallicRoughnessTexture = info
+	}
+	gltfMaterial.PBRMetallicRoughness = pbr
+
+	if sampler := material.Sampler("normalSampler"); sampler != nil {
+		info, err := e.textureInfo(sampler)
+		if err != nil {
+			return 0, err
+		}
+		gltfMaterial.NormalTexture = &gltf.NormalTexture{
+			Index: gltf.Index(info.Index),
+			Scale: gltf.Float(gltfMaterialFloat(material, "normalScale", 1.0)),
+		}
+	}
+	if sampler := material.Sampler("occlusionSampler"); sampler != nil {
+		info, err := e.textureInfo(sampler)
+		if err != nil {
+			return 0, err
+		}
+		gltfMaterial.OcclusionTexture = &gltf.OcclusionTexture{
+			Index:    gltf.Index(info.Index),
+			Strength: gltf.Float(gltfMaterialFloat(material, "occlusionStrength", 1.0)),
+		}
+	}
+	if sampler := material.Sampler("emissiveSampler"); sampler != nil {
+		info, err := e.textureInfo(sampler)
+		if err != nil {
+			return 0, err
+		}
+		gltfMaterial.EmissiveTexture = info
+	}
+
+	emissive := gltfMaterialVec3(material, "emissive", sprec.ZeroVec3())
+	if strength := float64(max(emissive.X, emissive.Y, emissive.Z)); strength > 0.0 {
+		factor := 1.0
+		if strength > 1.0 {
+			factor = strength
+			e.addExtension(gltfEmissiveStrengthExtensionName)
+			gltfMaterial.Extensions = gltf.Extensions{
+				gltfEmissiveStrengthExtensionName: map[string]any{
+					"emissiveStrength": strength,
+				},
+			}
+		}
+		gltfMaterial.EmissiveFactor = [3]float64{
+			float64(emissive.X) / factor,
+			float64(emissive.Y) / factor,
+			float64(emissive.Z) / factor,
+		}
+	}
+
+	for _, pass := range material.GeometryPasses() {
+		if pass.Culling() == mdl.CullModeNone {
+			gltfMaterial.DoubleSided = true
+		}
+		if shader := pass.Shader(); shader != nil && strings.Contains(shader.SourceCode(), "discard") {
+			gltfMaterial.AlphaMode = gltf.AlphaMask
+			gltfMaterial.AlphaCutoff = gltf.Float(gltfMaterialFloat(material, "alphaThreshold", 0.5))
+		}
+	}
+
+	e.doc.Materials = append(e.doc.Materials, gltfMaterial)
+	index := len(e.doc.Materials) - 1
+	e.materialIndices[material] = index
+	return index, nil
+}
+
+func (e *gltfExporter) textureInfo(sampler *mdl.Sampler) (*gltf.TextureInfo, error) {
+	if index, ok := e.textureIndices[sampler]; ok {
+		return &gltf.TextureInfo{Index: index}, nil
+	}
+
+	imageIndex, err := e.exportImage(sampler.Texture())
+	if err != nil {
+		return nil, err
+	}
+
+	gltfSampler := &gltf.Sampler{}
+	switch sampler.WrapMode() {
+	case mdl.WrapModeClamp:
+		gltfSampler.WrapS = gltf.WrapClampToEdge
+		gltfSampler.WrapT = gltf.WrapClampToEdge
+	case mdl.WrapModeMirroredRepeat:
+		gltfSampler.WrapS = gltf.WrapMirroredRepeat
+		gltfSampler.WrapT = gltf.WrapMirroredRepeat
+	default:
+		gltfSampler.WrapS = gltf.WrapRepeat
+		gltfSampler.WrapT = gltf.WrapRepeat
+	}
+	switch sampler.FilterMode() {
+	case mdl.FilterModeNearest:
+		gltfSampler.MagFilter = gltf.MagNearest
+		if sampler.Mipmapping() {
+			gltfSampler.MinFilter = gltf.MinNearestMipMapNearest
+		} else {
+			gltfSampler.MinFilter = gltf.MinNearest
+		}
+	default:
+		gltfSampler.MagFilter = gltf.MagLinear
+		if sampler.Mipmapping() {
+			gltfSampler.MinFilter = gltf.MinLinearMipMapLinear
+		} else {
+			gltfSampler.MinFilter = gltf.MinLinear
+		}
+	}
+	e.doc.Samplers = append(e.doc.Samplers, gltfSampler)
+
+	e.doc.Textures = append(e.doc.Textures, &gltf.Texture{
+		Name:    sampler.Texture().Name(),
+		Sampler: gltf.Index(len(e.doc.Samplers) - 1),
+		Source:  gltf.Index(imageIndex),
+	})
+	index := len(e.doc.Textures) - 1
+	e.textureIndices[sampler] = index
+	return &gltf.TextureInfo{Index: index}, nil
+}
+
+func (e *gltfExporter) exportImage(texture *mdl.Texture) (int, error) {
+	if index, ok := e.imageIndices[texture]; ok {
+		return index, nil
+	}
+	if texture.Kind() != mdl.TextureKind2D {
+		return 0, fmt.Errorf("texture %q is not a 2D texture", texture.Name())
+	}
+	if texture.Format() != mdl.TextureFormatRGBA8 {
+		return 0, fmt.Errorf("texture %q has unsupported format %v", texture.Name(), texture.Format())
+	}
+
+	mipmap := texture.MipmapLayers()[0]
+	width, height := mipmap.Width(), mipmap.Height()
+	data := mipmap.Layers()[0].Data()
+
+	// Texture data starts from the bottom row, whereas images start from the
+	// top row.
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	rowSize := 4 * width
+	for y := range height {
+		srcOffset := (height - y - 1) * rowSize
+		copy(img.Pix[y*img.Stride:y*img.Stride+rowSize], data[srcOffset:srcOffset+rowSize])
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return 0, fmt.Errorf("error encoding texture %q: %w", texture.Name(), err)
+	}
+	index, err := modeler.WriteImage(e.doc, texture.Name(), "image/png", &buffer)
+	if err != nil {
+		return 0, fmt.Errorf("error writing texture %q: %w", texture.Name(), err)
+	}
+	e.imageIndices[texture] = index
+	return index, nil
+}
+
+func (e *gltfExporter) skinIndex(armature *mdl.Armature) int {
+	if index, ok := e.skinIndices[armature]; ok {
+		return index
+	}
+	// NOTE: Joints can reference nodes that have not been exported yet,
+	// so skins are only reserved here and filled in by exportSkin.
+	e.doc.Skins = append(e.doc.Skins, &gltf.Skin{})
+	index := len(e.doc.Skins) - 1
+	e.skinIndices[armature] = index
+	return index
+}
+
+func (e *gltfExporter) exportSkin(armature *mdl.Armature) error {
+	gltfSkin := e.doc.Skins[e.skinIndex(armature)]
+
+	joints := armature.Joints()
+	inverseBindMatrices := make([][4][4]float32, len(joints))
+	for i, joint := range joints {
+		nodeIndex, ok := e.nodeIndices[joint.Node()]
+		if !ok {
+			return fmt.Errorf("joint node %q is not part of the model", joint.Node().Name())
+		}
+		gltfSkin.Joints = append(gltfSkin.Joints, nodeIndex)
+
+		m := joint.InverseBindMatrix()
+		inverseBindMatrices[i] = [4][4]float32{
+			{m.M11, m.M21, m.M31, m.M41},
+			{m.M12, m.M22, m.M32, m.M42},
+			{m.M13, m.M23, m.M33, m.M43},
+			{m.M14, m.M24, m.M34, m.M44},
+		}
+	}
+	if len(joints) > 0 {
+		gltfSkin.InverseBindMatrices = gltf.Index(modeler.WriteInverseBindMatrices(e.doc, inverseBindMatrices))
+	}
+	return nil
+}
+
+func (e *gltfExporter) exportAnimation(animation *mdl.Animation) error {
+	gltfAnimation := &gltf.Animation{
+		Name: animation.Name(),
+	}
+
+	addChannel := func(nodeIndex int, path gltf.TRSProperty, timestamps []float32, interpolation mdl.Interpolation, outputs any) error {
+		gltfInterpolation, err := gltfAnimationInterpolation(interpolation)
+		if err != nil {
+			return err
+		}
+		inputIndex := modeler.WriteAccessor(e.doc, gltf.TargetNone, timestamps)
+		inputAccessor := e.doc.Accessors[inputIndex]
+		inputAccessor.Min = []float64{float64(timestamps[0])}
+		inputAccessor.Max = []float64{float64(timestamps[len(timestamps)-1])}
+		outputIndex := modeler.WriteAccessor(e.doc, gltf.TargetNone, outputs)
+
+		gltfAnimation.Samplers = append(gltfAnimation.Samplers, &gltf.AnimationSampler{
+			Input:         inputIndex,
+			Interpolation: gltfInterpolation,
+			Output:        outputIndex,
+		})
+		gltfAnimation.Channels = append(gltfAnimation.Channels, &gltf.AnimationChannel{
+			Sampler: len(gltfAnimation.Samplers) - 1,
+			Target: gltf.AnimationChannelTarget{
+				Node: gltf.Index(nodeIndex),
+				Path: path,
+			},
+		})
+		return nil
+	}
+
+	for _, binding := range animation.Bindings() {
+		nodeIndex, ok := e.nodeIndicesFromName[binding.NodeName()]
+		if !ok {
+			logger.Warn("Skipping animation binding for missing node",
+				slog.String("animation", animation.Name()),
+				slog.String("node", binding.NodeName()),
+			)
+			continue
+		}
+
+		if keyframes := binding.TranslationKeyframes(); len(keyframes) > 0 {
+			timestamps, interpolation, outputs := gltfKeyframeOutputs(keyframes, func(value dprec.Vec3) [][3]float32 {
+				return [][3]float32{{float32(value.X), float32(value.Y), float32(value.Z)}}
+			})
+			if err := addChannel(nodeIndex, gltf.TRSTranslation, timestamps, interpolation, outputs); err != nil {
+				return fmt.Errorf("error exporting translation of node %q: %w", binding.NodeName(), err)
+			}
+		}
+		if keyframes := binding.RotationKeyframes(); len(keyframes) > 0 {
+			timestamps, interpolation, outputs := gltfKeyframeOutputs(keyframes, func(value dprec.Quat) [][4]float32 {
+				return [][4]float32{{float32(value.X), float32(value.Y), float32(value.Z), float32(value.W)}}
+			})
+			if err := addChannel(nodeIndex, gltf.TRSRotation, timestamps, interpolation, outputs); err != nil {
+				return fmt.Errorf("error exporting rotation of node %q: %w", binding.NodeName(), err)
+			}
+		}
+		if keyframes := binding.ScaleKeyframes(); len(keyframes) > 0 {
+			timestamps, interpolation, outputs := gltfKeyframeOutputs(keyframes, func(value dprec.Vec3) [][3]float32 {
+				return [][3]float32{{float32(value.X), float32(value.Y), float32(value.Z)}}
+			})
+			if err := addChannel(nodeIndex, gltf.TRSScale, timestamps, interpolation, outputs); err != nil {
+				return fmt.Errorf("error exporting scale of node %q: %w", binding.NodeName(), err)
+			}
+		}
+		if keyframes := binding.MorphWeightKeyframes(); len(keyframes) > 0 {
+			timestamps, interpolation, outputs := gltfKeyframeOutputs(keyframes, func(value []float64) []float32 {
+				weights := make([]float32, len(value))
+				for i, weight := range value {
+					weights[i] = float32(weight)
+				}
+				return weights
+			})
+			if err := addChannel(nodeIndex, gltf.TRSWeights, timestamps, interpolation, outputs); err != nil {
+				return fmt.Errorf("error exporting morph weights of node %q: %w", binding.NodeName(), err)
+			}
+		}
+	}
+
+	if len(gltfAnimation.Channels) > 0 {
+		e.doc.Animations = append(e.doc.Animations, gltfAnimation)
+	}
+	return nil
+}
+
+func (e *gltfExporter) addExtension(name string) {
+	for _, candidate := range e.doc.ExtensionsUsed {
+		if candidate == name {
+			return
+		}
+	}
+	e.doc.ExtensionsUsed = append(e.doc.ExtensionsUsed, name)
+}
+
+// gltfKeyframeOutputs returns the timestamps, the interpolation and the
+// flattened output values of the specified keyframes. Cubic spline outputs
+// are stored as in tangent, value and out tangent triplets, as required by
+// glTF.
+//
+// NOTE: glTF does not support per-keyframe interpolation, so the
+// interpolation of the first keyframe is used for the whole channel.
+func gltfKeyframeOutputs[T, O any](keyframes []mdl.Keyframe[T], convert func(T) []O) ([]float32, mdl.Interpolation, []O) {
+	interpolation := keyframes[0].Interpolation
+	timestamps := make([]float32, len(keyframes))
+	var outputs []O
+	for i, keyframe := range keyframes {
+		timestamps[i] = float32(keyframe.Timestamp)
+		if interpolation == mdl.InterpolationCubicSpline {
+			outputs = append(outputs, convert(keyframe.InTangent)...)
+			outputs = append(outputs, convert(keyframe.Value)...)
+			outputs = append(outputs, convert(keyframe.OutTangent)...)
+		} else {
+			outputs = append(outputs, convert(keyframe.Value)...)
+		}
+	}
+	return timestamps, interpolation, outputs
+}
+
+func gltfAnimationInterpolation(interpolation mdl.Interpolation) (gltf.Interpolation, error) {
+	switch interpolation {
+	case mdl.InterpolationLinear:
+		return gltf.InterpolationLinear, nil
+	case mdl.InterpolationStep:
+		return gltf.InterpolationStep, nil
+	case mdl.InterpolationCubicSpline:
+		return gltf.InterpolationCubicSpline, nil
+	default:
+		return 0, fmt.Errorf("unsupported interpolation %d", interpolation)
+	}
+}
+
+func gltfPrimitiveMode(topology mdl.Topology) (gltf.PrimitiveMode, error) {
+	switch topology {
+	case mdl.TopologyPoints:
+		return gltf.PrimitivePoints, nil
+	case mdl.TopologyLineList:
+		return gltf.PrimitiveLines, nil
+	case mdl.TopologyLineStrip:
+		return gltf.PrimitiveLineStrip, nil
+	case mdl.TopologyTriangleList:
+		return gltf.PrimitiveTriangles, nil
+	case mdl.TopologyTriangleStrip:
+		return gltf.PrimitiveTriangleStrip, nil
+	default:
+		return 0, fmt.Errorf("unsupported topology %d", topology)
+	}
+}
+
+func gltfExtras(metadata mdl.Metadata) any {
+	if len(metadata) == 0 {
+		return nil
+	}
+	extras := make(map[string]any, len(metadata))
+	for key, value := range metadata {
+		extras[key] = value
+	}
+	return extras
+}
+
+// gltfLightColor splits the specified emit color into a normalized color
+// and an intensity, which multiplied together produce the emit color.
+func gltfLightColor(emitColor dprec.Vec3) ([3]float64, float64) {
+	intensity := max(emitColor.X, emitColor.Y, emitColor.Z)
+	if intensity <= 0.0 {
+		return [3]float64{0.0, 0.0, 0.0}, 0.0
+	}
+	return [3]float64{
+		emitColor.X / intensity,
+		emitColor.Y / intensity,
+		emitColor.Z / intensity,
+	}, intensity
+}
+
+func gltfVec3(value sprec.Vec3) [3]float32 {
+	return [3]float32{value.X, value.Y, value.Z}
+}
+
+func gltfTexCoord(value sprec.Vec2) [2]float32 {
+	return [2]float32{value.X, 1.0 - value.Y}
+}
+
+func gltfMaterialFloat(material *mdl.Material, name string, defaultValue float32) float64 {
+	if value, ok := material.Property(name).(float32); ok {
+		return float64(value)
+	}
+	return float64(defaultValue)
+}
+
+func gltfMaterialVec3(material *mdl.Material, name string, defaultValue sprec.Vec3) sprec.Vec3 {
+	if value, ok := material.Property(name).(sprec.Vec3); ok {
+		return value
+	}
+	return defaultValue
+}
+
+func gltfMaterialVec4(material *mdl.Material, name string, defaultValue sprec.Vec4) sprec.Vec4 {
+	if value, ok := material.Property(name).(sprec.Vec4); ok {
+		return value
+	}
+	return defaultValue
+}
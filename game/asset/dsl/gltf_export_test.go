@@ -0,0 +1,226 @@
+package dsl_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/gomath/testing/dprectest"
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/qmuntal/gltf"
+)
+
+var _ = Describe("WriteGLTFModel", func() {
+	var (
+		sourceGeometry *mdl.Geometry
+		sourceModel    *mdl.Model
+		model          *mdl.Model
+	)
+
+	createMesh := func() *mdl.Mesh {
+		geometry := mdl.NewGeometry()
+		geometry.SetName("triangle")
+		geometry.SetFormat(mdl.VertexFormatCoord | mdl.VertexFormatNormal | mdl.VertexFormatTexCoord)
+		geometry.SetVertices([]mdl.Vertex{
+			{Coord: sprec.NewVec3(0.0, 0.0, 0.0), Normal: sprec.NewVec3(0.0, 0.0, 1.0), TexCoord: sprec.NewVec2(0.0, 0.0)},
+			{Coord: sprec.NewVec3(1.0, 0.0, 0.0), Normal: sprec.NewVec3(0.0, 0.0, 1.0), TexCoord: sprec.NewVec2(1.0, 0.0)},
+			{Coord: sprec.NewVec3(0.0, 1.0, 0.0), Normal: sprec.NewVec3(0.0, 0.0, 1.0), TexCoord: sprec.NewVec2(0.0, 1.0)},
+		})
+		geometry.SetIndices([]int{0, 1, 2})
+
+		fragment := mdl.NewFragment()
+		fragment.SetName("paint")
+		fragment.SetTopology(mdl.TopologyTriangleList)
+		fragment.SetIndexOffset(0)
+		fragment.SetIndexCount(3)
+		geometry.AddFragment(fragment)
+
+		target := mdl.NewMorphTarget()
+		target.SetName("raise")
+		target.SetDefaultWeight(0.25)
+		target.SetDelta(2, mdl.MorphDelta{
+			Coord:  sprec.NewVec3(0.0, 0.0, 0.5),
+			Normal: sprec.NewVec3(0.0, -0.5, 0.0),
+		})
+		geometry.AddMorphTarget(target)
+
+		material := mdl.NewMaterial("paint")
+		material.SetProperty("color", sprec.NewVec4(1.0, 0.5, 0.25, 1.0))
+		material.SetProperty("metallic", float32(0.0))
+		material.SetProperty("roughness", float32(0.75))
+
+		definition := mdl.NewMeshDefinition()
+		definition.SetName("triangle")
+		definition.SetGeometry(geometry)
+		definition.BindMaterial("paint", material)
+
+		mesh := mdl.NewMesh()
+		mesh.SetDefinition(definition)
+		sourceGeometry = geometry
+		return mesh
+	}
+
+	createAnimation := func() *mdl.Animation {
+		binding := mdl.NewAnimationBinding("root")
+		binding.AddTranslationKeyframe(mdl.TranslationKeyframe{
+			Timestamp: 0.0,
+			Value:     dprec.NewVec3(1.0, 2.0, 3.0),
+		})
+		binding.AddTranslationKeyframe(mdl.TranslationKeyframe{
+			Timestamp: 2.0,
+			Value:     dprec.NewVec3(1.0, 5.0, 3.0),
+		})
+
+		animation := mdl.NewAnimation()
+		animation.SetName("hover")
+		animation.SetStartTime(0.0)
+		animation.SetEndTime(2.0)
+		animation.AddBinding(binding)
+		return animation
+	}
+
+	findNode := func(nodes []*mdl.Node, name string) *mdl.Node {
+		for _, node := range nodes {
+			if node.Name() == name {
+				return node
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		sourceModel, err = dsl.CreateModel(
+			dsl.AddNode(dsl.CreateNode("root",
+				dsl.SetTranslation(dsl.Const(dprec.NewVec3(1.0, 2.0, 3.0))),
+				dsl.SetRotation(dsl.Const(dprec.RotationQuat(dprec.Degrees(90.0), dprec.BasisYVec3()))),
+				dsl.SetScale(dsl.Const(dprec.NewVec3(2.0, 2.0, 2.0))),
+				dsl.AddAttachment(dsl.Const(createMesh())),
+				dsl.AddNode(dsl.CreateNode("lamp",
+					dsl.SetTranslation(dsl.Const(dprec.NewVec3(0.0, 4.0, 0.0))),
+					dsl.AddAttachment(dsl.CreatePointLight(
+						dsl.SetEmitColor(dsl.RGB(4.0, 2.0, 1.0)),
+						dsl.SetEmitDistance(dsl.Const(25.0)),
+					)),
+				)),
+			)),
+		).Get()
+		Expect(err).ToNot(HaveOccurred())
+		sourceModel.AddAnimation(createAnimation())
+
+		var buffer bytes.Buffer
+		Expect(dsl.WriteGLTFModel(&buffer, sourceModel)).To(Succeed())
+
+		doc := new(gltf.Document)
+		Expect(gltf.NewDecoder(&buffer).Decode(doc)).To(Succeed())
+		model, err = dsl.BuildModelResource(doc, false, false)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("preserves the node hierarchy and transforms", func() {
+		root := findNode(model.Nodes(), "root")
+		Expect(root).ToNot(BeNil())
+		Expect(root.Translation()).To(dprectest.HaveVec3Coords(1.0, 2.0, 3.0))
+		Expect(root.Scale()).To(dprectest.HaveVec3Coords(2.0, 2.0, 2.0))
+		expectedRotation := dprec.RotationQuat(dprec.Degrees(90.0), dprec.BasisYVec3())
+		Expect(root.Rotation()).To(dprectest.HaveQuatCoords(expectedRotation.W, expectedRotation.X, expectedRotation.Y, expectedRotation.Z))
+
+		lamp := findNode(root.Nodes(), "lamp")
+		Expect(lamp).ToNot(BeNil())
+		Expect(lamp.Translation()).To(dprectest.HaveVec3Coords(0.0, 4.0, 0.0))
+	})
+
+	It("preserves point lights", func() {
+		root := findNode(model.Nodes(), "root")
+		Expect(root).ToNot(BeNil())
+		lamp := findNode(root.Nodes(), "lamp")
+		Expect(lamp).ToNot(BeNil())
+
+		var lights []*mdl.PointLight
+		for light := range mdl.NodeAttachmentsOfType[*mdl.PointLight](lamp) {
+			lights = append(lights, light)
+		}
+		Expect(lights).To(HaveLen(1))
+		Expect(lights[0].EmitColor()).To(dprectest.HaveVec3Coords(4.0, 2.0, 1.0))
+		Expect(lights[0].EmitDistance()).To(BeNumerically("~", 25.0, 1e-6))
+	})
+
+	It("preserves the mesh geometry", func() {
+		definitions := model.AllMeshDefinitions()
+		Expect(definitions).To(HaveLen(1))
+		geometry := definitions[0].Geometry()
+		Expect(geometry.Format() & sourceGeometry.Format()).To(Equal(sourceGeometry.Format()))
+
+		fragments := geometry.Fragments()
+		Expect(fragments).To(HaveLen(1))
+		Expect(fragments[0].Name()).To(Equal("paint"))
+		Expect(fragments[0].Topology()).To(Equal(mdl.TopologyTriangleList))
+		Expect(fragments[0].IndexCount()).To(Equal(3))
+
+		// The importer may reorder vertices, so they are compared through
+		// the indices of the triangle.
+		sourceVertices := sourceGeometry.Vertices()
+		sourceIndices := sourceGeometry.Indices()
+		vertices := geometry.Vertices()
+		indices := geometry.Indices()
+		Expect(indices).To(HaveLen(len(sourceIndices)))
+		for i := range indices {
+			expected := sourceVertices[sourceIndices[i]]
+			actual := vertices[indices[i]]
+			Expect(actual.Coord).To(Equal(expected.Coord))
+			Expect(actual.Normal).To(Equal(expected.Normal))
+			Expect(actual.TexCoord).To(Equal(expected.TexCoord))
+		}
+	})
+
+	It("preserves morph targets", func() {
+		definitions := model.AllMeshDefinitions()
+		Expect(definitions).To(HaveLen(1))
+		geometry := definitions[0].Geometry()
+
+		targets := geometry.MorphTargets()
+		Expect(targets).To(HaveLen(1))
+		Expect(targets[0].Name()).To(Equal("raise"))
+		Expect(targets[0].DefaultWeight()).To(BeNumerically("~", 0.25, 1e-6))
+
+		sourceTarget := sourceGeometry.MorphTargets()[0]
+		sourceIndices := sourceGeometry.Indices()
+		indices := geometry.Indices()
+		for i := range indices {
+			Expect(targets[0].Delta(indices[i])).To(Equal(sourceTarget.Delta(sourceIndices[i])))
+		}
+	})
+
+	It("preserves material properties", func() {
+		definitions := model.AllMeshDefinitions()
+		Expect(definitions).To(HaveLen(1))
+		material := definitions[0].MaterialBindings()["paint"]
+		Expect(material).ToNot(BeNil())
+		Expect(material.Name()).To(Equal("paint"))
+		Expect(material.Property("color")).To(Equal(sprec.NewVec4(1.0, 0.5, 0.25, 1.0)))
+		Expect(material.Property("roughness")).To(BeNumerically("~", 0.75, 1e-6))
+	})
+
+	It("preserves animations", func() {
+		animations := model.Animations()
+		Expect(animations).To(HaveLen(1))
+		Expect(animations[0].Name()).To(Equal("hover"))
+		Expect(animations[0].StartTime()).To(BeNumerically("~", 0.0, 1e-6))
+		Expect(animations[0].EndTime()).To(BeNumerically("~", 2.0, 1e-6))
+
+		bindings := animations[0].Bindings()
+		Expect(bindings).To(HaveLen(1))
+		Expect(bindings[0].NodeName()).To(Equal("root"))
+
+		keyframes := bindings[0].TranslationKeyframes()
+		Expect(keyframes).To(HaveLen(2))
+		Expect(keyframes[0].Timestamp).To(BeNumerically("~", 0.0, 1e-6))
+		Expect(keyframes[0].Value).To(dprectest.HaveVec3Coords(1.0, 2.0, 3.0))
+		Expect(keyframes[1].Timestamp).To(BeNumerically("~", 2.0, 1e-6))
+		Expect(keyframes[1].Value).To(dprectest.HaveVec3Coords(1.0, 5.0, 3.0))
+	})
+})
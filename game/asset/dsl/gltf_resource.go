@@ -0,0 +1,162 @@
+package dsl
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mokiat/lacking/core/resource"
+)
+
+// gltfResolver opens files that are referenced by a glTF file (external
+// buffers and images).
+//
+// Referenced paths are resolved relative to the directory of the glTF file.
+// If a locator is configured, the files are opened through it, using
+// slash-separated paths, otherwise they are opened from the file system.
+type gltfResolver struct {
+	dir     string
+	locator resource.Locator
+}
+
+func newGLTFResolver(modelPath string, locator resource.Locator) *gltfResolver {
+	return &gltfResolver{
+		dir:     filepath.Dir(modelPath),
+		locator: locator,
+	}
+}
+
+// Open opens the file at the specified path, which should already be
+// unescaped and relative to the glTF file.
+func (r *gltfResolver) Open(refPath string) (io.ReadCloser, error) {
+	if r.locator != nil {
+		locatorPath := path.Join(filepath.ToSlash(r.dir), refPath)
+		in, err := r.locator.Open(locatorPath)
+		if err != nil {
+			if errors.Is(err, resource.ErrNotFound) {
+				return nil, fmt.Errorf("file %q referenced by gltf model not found: %w", locatorPath, err)
+			}
+			return nil, fmt.Errorf("failed to open file %q referenced by gltf model: %w", locatorPath, err)
+		}
+		return in, nil
+	}
+
+	filePath := r.filePath(refPath)
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("file %q referenced by gltf model not found: %w", filePath, err)
+		}
+		return nil, fmt.Errorf("failed to open file %q referenced by gltf model: %w", filePath, err)
+	}
+	return file, nil
+}
+
+// ReadFile returns the contents of the file at the specified path, which
+// should already be unescaped and relative to the glTF file.
+func (r *gltfResolver) ReadFile(refPath string) ([]byte, error) {
+	in, err := r.Open(refPath)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %q referenced by gltf model: %w", refPath, err)
+	}
+	return data, nil
+}
+
+// Digest returns the hex-encoded SHA-256 hash of the file at the specified
+// path, which should already be unescaped and relative to the glTF file.
+func (r *gltfResolver) Digest(refPath string) (string, error) {
+	if r.locator == nil {
+		filePath := r.filePath(refPath)
+		if _, err := os.Stat(filePath); errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("file %q referenced by gltf model not found: %w", filePath, err)
+		}
+		return FileContentDigest(filePath)
+	}
+
+	in, err := r.Open(refPath)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return "", fmt.Errorf("failed to hash file %q referenced by gltf model: %w", refPath, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// FS returns an fs.FS view of the resolver, which can be used by the glTF
+// decoder to load external buffers.
+func (r *gltfResolver) FS() fs.FS {
+	return gltfResolverFS{
+		resolver: r,
+	}
+}
+
+func (r *gltfResolver) filePath(refPath string) string {
+	return filepath.Join(r.dir, filepath.FromSlash(refPath))
+}
+
+type gltfResolverFS struct {
+	resolver *gltfResolver
+}
+
+func (f gltfResolverFS) Open(name string) (fs.File, error) {
+	in, err := f.resolver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &gltfResolverFile{
+		ReadCloser: in,
+		name:       name,
+	}, nil
+}
+
+type gltfResolverFile struct {
+	io.ReadCloser
+	name string
+}
+
+func (f *gltfResolverFile) Stat() (fs.FileInfo, error) {
+	// NOTE: Locators do not provide file information. Returning an error
+	// here is fine, since fs.ReadFile uses Stat only to presize its buffer.
+	return nil, &fs.PathError{Op: "stat", Path: f.name, Err: errors.ErrUnsupported}
+}
+
+// decodeDataURI returns the data that is embedded in the specified data URI.
+//
+// Both base64 and percent-encoded data URIs of any media type are supported.
+func decodeDataURI(uri string) ([]byte, error) {
+	header, content, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("data uri is missing a data section")
+	}
+	if strings.HasSuffix(header, ";base64") {
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 data uri: %w", err)
+		}
+		return data, nil
+	}
+	data, err := url.PathUnescape(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data uri: %w", err)
+	}
+	return []byte(data), nil
+}
@@ -0,0 +1,198 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Graph represents the dependencies between the declared assets and the
+// describable providers and operations that produce them.
+type Graph struct {
+
+	// Nodes holds all describable providers and operations.
+	Nodes []GraphNode `json:"nodes"`
+
+	// Edges holds the dependencies between the nodes.
+	Edges []GraphEdge `json:"edges"`
+
+	// Assets maps the declared assets to the nodes that produce them,
+	// sorted by path.
+	Assets []GraphAsset `json:"assets"`
+}
+
+// GraphNode represents a provider or operation in a Graph.
+type GraphNode struct {
+
+	// ID uniquely identifies the node within the graph.
+	ID int `json:"id"`
+
+	// Name is the name that the provider declared.
+	Name string `json:"name"`
+
+	// Detail is the optional detail that the provider declared.
+	Detail string `json:"detail,omitempty"`
+}
+
+// GraphEdge represents a dependency of one node on another.
+type GraphEdge struct {
+
+	// From is the ID of the dependent node.
+	From int `json:"from"`
+
+	// To is the ID of the node that is depended upon.
+	To int `json:"to"`
+}
+
+// GraphAsset links an asset path to the node that produces it.
+type GraphAsset struct {
+
+	// Path is the path of the asset.
+	Path string `json:"path"`
+
+	// Node is the ID of the node that produces the asset.
+	Node int `json:"node"`
+}
+
+// BuildGraph returns the dependency graph of all declared assets.
+//
+// Providers and operations that are not Describable are omitted, together
+// with any inputs that are only reachable through them.
+func BuildGraph() *Graph {
+	builder := &graphBuilder{
+		graph: &Graph{},
+		ids:   make(map[Describable]int),
+	}
+
+	paths := make([]string, 0, len(resourceProviders)+len(rawResourceProviders))
+	sources := make(map[string]Digestable, cap(paths))
+	for path, provider := range resourceProviders {
+		paths = append(paths, path)
+		sources[path] = provider
+	}
+	for path, provider := range rawResourceProviders {
+		paths = append(paths, path)
+		sources[path] = provider
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		if describable, ok := sources[path].(Describable); ok {
+			builder.graph.Assets = append(builder.graph.Assets, GraphAsset{
+				Path: path,
+				Node: builder.visit(describable),
+			})
+		}
+	}
+	return builder.graph
+}
+
+// WriteJSON writes the graph in JSON format.
+func (g *Graph) WriteJSON(out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format. Edges point from
+// inputs to the providers that consume them.
+func (g *Graph) WriteDOT(out io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("digraph dsl {\n")
+	builder.WriteString("\trankdir=LR;\n")
+	builder.WriteString("\tnode [shape=box];\n")
+	for _, node := range g.Nodes {
+		label := node.Name
+		if node.Detail != "" {
+			label += "\n" + node.Detail
+		}
+		fmt.Fprintf(&builder, "\tn%d [label=%q];\n", node.ID, label)
+	}
+	for i, asset := range g.Assets {
+		fmt.Fprintf(&builder, "\ta%d [label=%q, shape=note];\n", i, asset.Path)
+		fmt.Fprintf(&builder, "\tn%d -> a%d;\n", asset.Node, i)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&builder, "\tn%d -> n%d;\n", edge.To, edge.From)
+	}
+	builder.WriteString("}\n")
+	_, err := io.WriteString(out, builder.String())
+	return err
+}
+
+type graphBuilder struct {
+	graph *Graph
+	ids   map[Describable]int
+}
+
+func (b *graphBuilder) visit(describable Describable) int {
+	if id, ok := b.ids[describable]; ok {
+		return id
+	}
+	info := describable.DescribeProvider()
+	id := len(b.graph.Nodes)
+	b.ids[describable] = id
+	b.graph.Nodes = append(b.graph.Nodes, GraphNode{
+		ID:     id,
+		Name:   info.Name,
+		Detail: info.Detail,
+	})
+	for _, input := range info.Inputs {
+		if inputDescribable, ok := input.(Describable); ok {
+			b.graph.Edges = append(b.graph.Edges, GraphEdge{
+				From: id,
+				To:   b.visit(inputDescribable),
+			})
+		}
+	}
+	return id
+}
+
+// collectProviderReports returns reports for all describable providers that
+// are reachable from the specified source, in depth-first order.
+func collectProviderReports(source Digestable) []ProviderReport {
+	var (
+		result  []ProviderReport
+		visited = make(map[Describable]struct{})
+		visit   func(Digestable)
+	)
+	visit = func(digestable Digestable) {
+		describable, ok := digestable.(Describable)
+		if !ok {
+			return
+		}
+		if _, ok := visited[describable]; ok {
+			return
+		}
+		visited[describable] = struct{}{}
+
+		info := describable.DescribeProvider()
+		for _, input := range info.Inputs {
+			visit(input)
+		}
+
+		stats := describable.ProviderStats()
+		report := ProviderReport{
+			Name:   info.Name,
+			Detail: info.Detail,
+		}
+		switch {
+		case stats.CacheHit:
+			report.Status = ProviderStatusCacheHit
+			report.Duration = stats.Duration
+		case stats.Failed:
+			report.Status = ProviderStatusFailed
+			report.Duration = stats.Duration
+		case stats.Evaluated:
+			report.Status = ProviderStatusEvaluated
+			report.Duration = stats.Duration
+		default:
+			report.Status = ProviderStatusNotEvaluated
+		}
+		result = append(result, report)
+	}
+	visit(source)
+	return result
+}
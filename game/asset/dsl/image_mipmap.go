@@ -0,0 +1,375 @@
+package dsl
+
+import (
+	"math"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+const (
+	// MipmapFilterBox averages the texels that a mipmap texel covers. It
+	// is the fastest filter but produces the blurriest results.
+	MipmapFilterBox MipmapFilter = iota
+
+	// MipmapFilterKaiser uses a Kaiser-windowed sinc filter, which keeps
+	// mipmaps sharp with little ringing.
+	MipmapFilterKaiser
+
+	// MipmapFilterLanczos uses a three-lobed Lanczos filter, which produces
+	// the sharpest mipmaps but may cause ringing around hard edges.
+	MipmapFilterLanczos
+)
+
+// MipmapFilter represents the filter that is used to compute mipmaps.
+type MipmapFilter uint8
+
+const (
+	kaiserFilterWidth  = 3.0
+	kaiserFilterAlpha  = 4.0
+	lanczosFilterWidth = 3.0
+
+	// alphaCoverageIterations is the number of bisection steps used to find
+	// the alpha scale that preserves coverage.
+	alphaCoverageIterations = 16
+)
+
+type mipmapConfig struct {
+	filter         MipmapFilter
+	alphaThreshold float64
+	normalMap      bool
+}
+
+func (c *mipmapConfig) SetMipmapFilter(filter MipmapFilter) {
+	c.filter = filter
+}
+
+func (c *mipmapConfig) SetAlphaCoverage(threshold float64) {
+	c.alphaThreshold = threshold
+}
+
+func (c *mipmapConfig) SetNormalMap(normalMap bool) {
+	c.normalMap = normalMap
+}
+
+// generateImageMipmaps returns the image followed by its full mipmap chain,
+// down to a size of one by one.
+//
+// Filtering is performed in linear space, so images that are not linear are
+// converted from sRGB first and converted back afterwards. Normal map texels
+// are treated as vectors that are renormalized after filtering. When an
+// alpha threshold is configured, the alpha of each mipmap is scaled so that
+// the portion of texels that pass the alpha test stays the same as in the
+// source image.
+func generateImageMipmaps(image *mdl.Image, cfg mipmapConfig, linear bool) []*mdl.Image {
+	encoding := mipmapEncodingSRGB
+	switch {
+	case cfg.normalMap:
+		encoding = mipmapEncodingNormal
+	case linear:
+		encoding = mipmapEncodingLinear
+	}
+
+	source := decodeMipmapBuffer(image, encoding)
+	coverage := 0.0
+	if cfg.alphaThreshold > 0.0 {
+		coverage = source.alphaCoverage(cfg.alphaThreshold, 1.0)
+	}
+
+	result := []*mdl.Image{image}
+	for source.width > 1 || source.height > 1 {
+		width := max(1, source.width/2)
+		height := max(1, source.height/2)
+		mipmap := source.resample(width, height, cfg.filter)
+		if cfg.normalMap {
+			mipmap.normalize()
+		}
+
+		output := mipmap
+		if cfg.alphaThreshold > 0.0 {
+			// The scaled alpha is only used for the output, so that errors do
+			// not accumulate across the chain.
+			output = mipmap.clone()
+			output.scaleAlpha(mipmap.alphaScaleForCoverage(cfg.alphaThreshold, coverage))
+		}
+		result = append(result, output.encode(encoding))
+		source = mipmap
+	}
+	return result
+}
+
+type mipmapEncoding uint8
+
+const (
+	mipmapEncodingLinear mipmapEncoding = iota
+	mipmapEncodingSRGB
+	mipmapEncodingNormal
+)
+
+// mipmapBuffer holds texels in a space where they can be filtered linearly.
+type mipmapBuffer struct {
+	width  int
+	height int
+	texels [][4]float64
+}
+
+func newMipmapBuffer(width, height int) *mipmapBuffer {
+	return &mipmapBuffer{
+		width:  width,
+		height: height,
+		texels: make([][4]float64, width*height),
+	}
+}
+
+func decodeMipmapBuffer(image *mdl.Image, encoding mipmapEncoding) *mipmapBuffer {
+	buffer := newMipmapBuffer(image.Width(), image.Height())
+	for y := range buffer.height {
+		for x := range buffer.width {
+			texel := image.Texel(x, y)
+			value := [4]float64{texel.R, texel.G, texel.B, texel.A}
+			switch encoding {
+			case mipmapEncodingSRGB:
+				for c := range 3 {
+					value[c] = srgbToLinear(value[c])
+				}
+			case mipmapEncodingNormal:
+				for c := range 3 {
+					value[c] = value[c]*2.0 - 1.0
+				}
+			}
+			buffer.texels[y*buffer.width+x] = value
+		}
+	}
+	return buffer
+}
+
+func (b *mipmapBuffer) encode(encoding mipmapEncoding) *mdl.Image {
+	image := mdl.NewImage(b.width, b.height)
+	for y := range b.height {
+		for x := range b.width {
+			value := b.texels[y*b.width+x]
+			switch encoding {
+			case mipmapEncodingSRGB:
+				for c := range 3 {
+					value[c] = linearToSRGB(max(value[c], 0.0))
+				}
+			case mipmapEncodingNormal:
+				for c := range 3 {
+					value[c] = value[c]*0.5 + 0.5
+				}
+			}
+			if encoding != mipmapEncodingLinear {
+				for c := range 3 {
+					value[c] = min(max(value[c], 0.0), 1.0)
+				}
+			}
+			image.SetTexel(x, y, mdl.Color{
+				R: value[0],
+				G: value[1],
+				B: value[2],
+				A: min(max(value[3], 0.0), 1.0),
+			})
+		}
+	}
+	return image
+}
+
+func (b *mipmapBuffer) clone() *mipmapBuffer {
+	return &mipmapBuffer{
+		width:  b.width,
+		height: b.height,
+		texels: append([][4]float64(nil), b.texels...),
+	}
+}
+
+// resample scales the buffer to the specified size with a separable
+// filter, first horizontally and then vertically.
+func (b *mipmapBuffer) resample(width, height int, filter MipmapFilter) *mipmapBuffer {
+	horizontalWeights := resampleWeights(b.width, width, filter)
+	horizontal := newMipmapBuffer(width, b.height)
+	for y := range b.height {
+		for x, weights := range horizontalWeights {
+			var sum [4]float64
+			for _, weight := range weights {
+				texel := b.texels[y*b.width+weight.index]
+				for c := range 4 {
+					sum[c] += texel[c] * weight.value
+				}
+			}
+			horizontal.texels[y*width+x] = sum
+		}
+	}
+
+	verticalWeights := resampleWeights(b.height, height, filter)
+	result := newMipmapBuffer(width, height)
+	for y, weights := range verticalWeights {
+		for x := range width {
+			var sum [4]float64
+			for _, weight := range weights {
+				texel := horizontal.texels[weight.index*width+x]
+				for c := range 4 {
+					sum[c] += texel[c] * weight.value
+				}
+			}
+			result.texels[y*width+x] = sum
+		}
+	}
+	return result
+}
+
+func (b *mipmapBuffer) normalize() {
+	for i, texel := range b.texels {
+		length := math.Sqrt(texel[0]*texel[0] + texel[1]*texel[1] + texel[2]*texel[2])
+		if length < 1e-6 {
+			b.texels[i] = [4]float64{0.0, 0.0, 1.0, texel[3]}
+			continue
+		}
+		b.texels[i] = [4]float64{texel[0] / length, texel[1] / length, texel[2] / length, texel[3]}
+	}
+}
+
+// alphaCoverage returns the portion of texels whose alpha, when scaled,
+// passes an alpha test with the specified threshold.
+func (b *mipmapBuffer) alphaCoverage(threshold, scale float64) float64 {
+	var count int
+	for _, texel := range b.texels {
+		if texel[3]*scale >= threshold {
+			count++
+		}
+	}
+	return float64(count) / float64(len(b.texels))
+}
+
+// alphaScaleForCoverage finds the alpha scale with which the buffer has the
+// specified alpha coverage.
+func (b *mipmapBuffer) alphaScaleForCoverage(threshold, coverage float64) float64 {
+	low, high := 0.0, 1.0/threshold
+	for high < 1e6 && b.alphaCoverage(threshold, high) < coverage {
+		high *= 2.0
+	}
+	for range alphaCoverageIterations {
+		middle := (low + high) / 2.0
+		if b.alphaCoverage(threshold, middle) < coverage {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return high
+}
+
+func (b *mipmapBuffer) scaleAlpha(scale float64) {
+	for i := range b.texels {
+		b.texels[i][3] = min(b.texels[i][3]*scale, 1.0)
+	}
+}
+
+type resampleWeight struct {
+	index int
+	value float64
+}
+
+// resampleWeights returns the source texels and their weights that
+// contribute to each destination texel. Texels outside of the source are
+// clamped to the edge.
+func resampleWeights(srcSize, dstSize int, filter MipmapFilter) [][]resampleWeight {
+	scale := float64(srcSize) / float64(dstSize)
+	support := mipmapFilterSupport(filter) * scale
+
+	result := make([][]resampleWeight, dstSize)
+	for i := range result {
+		center := (float64(i) + 0.5) * scale
+		first := int(math.Floor(center - support))
+		last := int(math.Ceil(center + support))
+
+		var weights []resampleWeight
+		var total float64
+		for j := first; j <= last; j++ {
+			value := mipmapFilterValue(filter, (float64(j)+0.5-center)/scale)
+			if value == 0.0 {
+				continue
+			}
+			weights = append(weights, resampleWeight{
+				index: min(max(j, 0), srcSize-1),
+				value: value,
+			})
+			total += value
+		}
+		for k := range weights {
+			weights[k].value /= total
+		}
+		result[i] = weights
+	}
+	return result
+}
+
+func mipmapFilterSupport(filter MipmapFilter) float64 {
+	switch filter {
+	case MipmapFilterKaiser:
+		return kaiserFilterWidth
+	case MipmapFilterLanczos:
+		return lanczosFilterWidth
+	default:
+		return 0.5
+	}
+}
+
+// mipmapFilterValue evaluates the filter at the specified distance, which
+// is measured in destination texels.
+func mipmapFilterValue(filter MipmapFilter, x float64) float64 {
+	x = math.Abs(x)
+	switch filter {
+	case MipmapFilterKaiser:
+		if x >= kaiserFilterWidth {
+			return 0.0
+		}
+		ratio := x / kaiserFilterWidth
+		window := besselI0(kaiserFilterAlpha*math.Sqrt(1.0-ratio*ratio)) / besselI0(kaiserFilterAlpha)
+		return sinc(x) * window
+	case MipmapFilterLanczos:
+		if x >= lanczosFilterWidth {
+			return 0.0
+		}
+		return sinc(x) * sinc(x/lanczosFilterWidth)
+	default:
+		if x > 0.5 {
+			return 0.0
+		}
+		return 1.0
+	}
+}
+
+func sinc(x float64) float64 {
+	if x < 1e-9 {
+		return 1.0
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// besselI0 evaluates the zeroth order modified Bessel function of the first
+// kind through its power series.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	halfX := x / 2.0
+	for k := 1; k < 32; k++ {
+		term *= (halfX / float64(k)) * (halfX / float64(k))
+		sum += term
+		if term < sum*1e-12 {
+			break
+		}
+	}
+	return sum
+}
+
+func srgbToLinear(value float64) float64 {
+	if value <= 0.04045 {
+		return value / 12.92
+	}
+	return math.Pow((value+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) float64 {
+	if value <= 0.0031308 {
+		return value * 12.92
+	}
+	return 1.055*math.Pow(value, 1.0/2.4) - 0.055
+}
@@ -0,0 +1,155 @@
+package dsl_test
+
+import (
+	"math"
+	"math/rand/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("GenerateImageMipmaps", func() {
+	// createCheckerboard creates an image of alternating black and white
+	// texels.
+	createCheckerboard := func(size int) *mdl.Image {
+		image := mdl.NewImage(size, size)
+		for y := range size {
+			for x := range size {
+				value := float64((x + y) % 2)
+				image.SetTexel(x, y, mdl.Color{R: value, G: value, B: value, A: 1.0})
+			}
+		}
+		return image
+	}
+
+	alphaCoverage := func(image *mdl.Image, threshold float64) float64 {
+		var count int
+		for y := range image.Height() {
+			for x := range image.Width() {
+				if image.Texel(x, y).A >= threshold {
+					count++
+				}
+			}
+		}
+		return float64(count) / float64(image.Width()*image.Height())
+	}
+
+	It("produces the full chain", func() {
+		image := mdl.NewImage(8, 2)
+		mipmaps := dsl.GenerateImageMipmaps(image, dsl.MipmapFilterBox, 0.0, false, false)
+		Expect(mipmaps).To(HaveLen(4))
+		Expect(mipmaps[0]).To(BeIdenticalTo(image))
+		sizes := make([][2]int, len(mipmaps))
+		for i, mipmap := range mipmaps {
+			sizes[i] = [2]int{mipmap.Width(), mipmap.Height()}
+		}
+		Expect(sizes).To(Equal([][2]int{{8, 2}, {4, 1}, {2, 1}, {1, 1}}))
+	})
+
+	It("averages sRGB images in linear space", func() {
+		mipmaps := dsl.GenerateImageMipmaps(createCheckerboard(4), dsl.MipmapFilterBox, 0.0, false, false)
+		// Half of the light of white is encoded as the sRGB value of 0.5,
+		// which is noticeably brighter than 0.5.
+		expected := 1.055*math.Pow(0.5, 1.0/2.4) - 0.055
+		for _, mipmap := range mipmaps[1:] {
+			for y := range mipmap.Height() {
+				for x := range mipmap.Width() {
+					texel := mipmap.Texel(x, y)
+					Expect(texel.R).To(BeNumerically("~", expected, 1e-9))
+					Expect(texel.G).To(BeNumerically("~", expected, 1e-9))
+					Expect(texel.B).To(BeNumerically("~", expected, 1e-9))
+					Expect(texel.A).To(BeNumerically("~", 1.0, 1e-9))
+				}
+			}
+		}
+	})
+
+	It("averages linear images as they are", func() {
+		mipmaps := dsl.GenerateImageMipmaps(createCheckerboard(4), dsl.MipmapFilterBox, 0.0, false, true)
+		for _, mipmap := range mipmaps[1:] {
+			for y := range mipmap.Height() {
+				for x := range mipmap.Width() {
+					Expect(mipmap.Texel(x, y).R).To(BeNumerically("~", 0.5, 1e-9))
+				}
+			}
+		}
+	})
+
+	Describe("alpha coverage", func() {
+		const (
+			size      = 64
+			threshold = 0.5
+		)
+		var image *mdl.Image
+
+		BeforeEach(func() {
+			// Few texels pass the alpha test and they are scattered, so
+			// plain averaging would make them fade away.
+			random := rand.New(rand.NewPCG(7, 8))
+			image = mdl.NewImage(size, size)
+			for y := range size {
+				for x := range size {
+					image.SetTexel(x, y, mdl.Color{R: 1.0, G: 1.0, B: 1.0, A: 0.6 * random.Float64()})
+				}
+			}
+		})
+
+		It("keeps the pass rate of the alpha test", func() {
+			coverage := alphaCoverage(image, threshold)
+			Expect(coverage).To(BeNumerically("~", 1.0/6.0, 0.05))
+
+			mipmaps := dsl.GenerateImageMipmaps(image, dsl.MipmapFilterBox, threshold, false, false)
+			for _, mipmap := range mipmaps[1:] {
+				if texelCount := mipmap.Width() * mipmap.Height(); texelCount >= 16 {
+					Expect(alphaCoverage(mipmap, threshold)).To(BeNumerically("~", coverage, 1.0/float64(texelCount)))
+				}
+			}
+		})
+
+		It("loses coverage without an alpha threshold", func() {
+			coverage := alphaCoverage(image, threshold)
+			mipmaps := dsl.GenerateImageMipmaps(image, dsl.MipmapFilterBox, 0.0, false, false)
+			Expect(alphaCoverage(mipmaps[2], threshold)).To(BeNumerically("<", coverage/2.0))
+		})
+	})
+
+	DescribeTable("normal maps",
+		func(filter dsl.MipmapFilter) {
+			const size = 16
+			random := rand.New(rand.NewPCG(9, 10))
+			image := mdl.NewImage(size, size)
+			for y := range size {
+				for x := range size {
+					nx, ny := random.Float64()*1.6-0.8, random.Float64()*1.6-0.8
+					nz := 1.0 + random.Float64()
+					length := math.Sqrt(nx*nx + ny*ny + nz*nz)
+					image.SetTexel(x, y, mdl.Color{
+						R: nx/length*0.5 + 0.5,
+						G: ny/length*0.5 + 0.5,
+						B: nz/length*0.5 + 0.5,
+						A: 1.0,
+					})
+				}
+			}
+
+			mipmaps := dsl.GenerateImageMipmaps(image, filter, 0.0, true, true)
+			Expect(mipmaps).To(HaveLen(5))
+			for _, mipmap := range mipmaps[1:] {
+				for y := range mipmap.Height() {
+					for x := range mipmap.Width() {
+						texel := mipmap.Texel(x, y)
+						nx, ny, nz := texel.R*2.0-1.0, texel.G*2.0-1.0, texel.B*2.0-1.0
+						Expect(math.Sqrt(nx*nx + ny*ny + nz*nz)).To(BeNumerically("~", 1.0, 1e-9))
+						Expect(nz).To(BeNumerically(">", 0.0))
+					}
+				}
+			}
+		},
+		Entry("box filter", dsl.MipmapFilterBox),
+		Entry("kaiser filter", dsl.MipmapFilterKaiser),
+		Entry("lanczos filter", dsl.MipmapFilterLanczos),
+	)
+})
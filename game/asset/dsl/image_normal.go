@@ -0,0 +1,63 @@
+package dsl
+
+import (
+	"math"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+// normalMapFromHeight converts a height map into a tangent-space normal map.
+//
+// The height of a texel is the luminance of its color. Slopes are computed
+// with a Sobel filter, which wraps around the edges so that tiling height
+// maps produce seamless normal maps, and are scaled by the strength. The
+// tangent points right and the bitangent points up in the image, which
+// matches the tex coord convention of the engine. The alpha channel of the
+// result is one.
+func normalMapFromHeight(heightMap *mdl.Image, strength float64) *mdl.Image {
+	width, height := heightMap.Width(), heightMap.Height()
+
+	heights := make([]float64, width*height)
+	for y := range height {
+		for x := range width {
+			texel := heightMap.Texel(x, y)
+			heights[y*width+x] = 0.2126*texel.R + 0.7152*texel.G + 0.0722*texel.B
+		}
+	}
+	heightAt := func(x, y int) float64 {
+		x = (x%width + width) % width
+		y = (y%height + height) % height
+		return heights[y*width+x]
+	}
+
+	result := mdl.NewImage(width, height)
+	for y := range height {
+		for x := range width {
+			// The image starts with the top row, so the row above has a
+			// lower y.
+			var (
+				topLeft     = heightAt(x-1, y-1)
+				top         = heightAt(x, y-1)
+				topRight    = heightAt(x+1, y-1)
+				left        = heightAt(x-1, y)
+				right       = heightAt(x+1, y)
+				bottomLeft  = heightAt(x-1, y+1)
+				bottom      = heightAt(x, y+1)
+				bottomRight = heightAt(x+1, y+1)
+			)
+			slopeU := ((topRight + 2.0*right + bottomRight) - (topLeft + 2.0*left + bottomLeft)) / 8.0
+			slopeV := ((topLeft + 2.0*top + topRight) - (bottomLeft + 2.0*bottom + bottomRight)) / 8.0
+
+			normalX := -slopeU * strength
+			normalY := -slopeV * strength
+			length := math.Sqrt(normalX*normalX + normalY*normalY + 1.0)
+			result.SetTexel(x, y, mdl.Color{
+				R: (normalX/length)*0.5 + 0.5,
+				G: (normalY/length)*0.5 + 0.5,
+				B: (1.0/length)*0.5 + 0.5,
+				A: 1.0,
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package dsl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// objContentDigest calculates a digest of the contents of the OBJ file at
+// the specified path, including any material libraries and texture files
+// that it references, as opened through the specified resolver.
+func objContentDigest(path string, resolver *gltfResolver) (string, error) {
+	modelDigest, err := FileContentDigest(path)
+	if err != nil {
+		return "", err
+	}
+
+	libraries, err := objMaterialLibraries(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect obj model %q: %w", path, err)
+	}
+
+	params := []any{modelDigest}
+	for _, library := range libraries {
+		libraryDigest, err := resolver.Digest(library)
+		if err != nil {
+			return "", fmt.Errorf("failed to digest file referenced by obj model %q: %w", path, err)
+		}
+		params = append(params, library, libraryDigest)
+
+		materials, err := openMTL(library, resolver)
+		if err != nil {
+			return "", err
+		}
+		for _, material := range materials {
+			textureMaps := []*mtlTextureMap{
+				material.diffuseMap,
+				material.normalMap,
+				material.emissiveMap,
+				material.dissolveMap,
+			}
+			for _, textureMap := range textureMaps {
+				if textureMap == nil {
+					continue
+				}
+				textureDigest, err := resolver.Digest(textureMap.path)
+				if err != nil {
+					return "", fmt.Errorf("failed to digest file referenced by obj model %q: %w", path, err)
+				}
+				params = append(params, textureMap.path, textureDigest)
+			}
+		}
+	}
+
+	digest, err := CreateDigest("obj-content", params...)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest), nil
+}
+
+// objMaterialLibraries returns the material libraries that are referenced
+// by the OBJ file at the specified path, in the order in which they are
+// declared.
+func objMaterialLibraries(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open model file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	var result []string
+	err = scanOBJLines(file, func(_ int, keyword string, args []string) error {
+		if keyword == "mtllib" {
+			result = append(result, args...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning obj: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,857 @@
+package dsl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/ext/texturetransform"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+const objDefaultName = "default"
+
+// NOTE: Instead of building the model directly, OBJ files are converted to
+// an in-memory glTF document, which is then processed by the glTF importer.
+// This way OBJ models get the exact same materials, shaders and collision
+// handling as glTF models.
+
+// parseOBJDocument parses the OBJ data from the specified reader, including
+// any MTL material libraries that it references, and converts it to a glTF
+// document. The resolver is used to open the referenced files.
+func parseOBJDocument(in io.Reader, resolver *gltfResolver) (*gltf.Document, error) {
+	obj, err := parseOBJ(in)
+	if err != nil {
+		return nil, err
+	}
+
+	materials := make(map[string]*mtlMaterial)
+	for _, library := range obj.materialLibraries {
+		libraryMaterials, err := openMTL(library, resolver)
+		if err != nil {
+			return nil, err
+		}
+		for _, material := range libraryMaterials {
+			materials[material.name] = material
+		}
+	}
+
+	builder := newOBJDocumentBuilder(obj, materials)
+	return builder.Build()
+}
+
+// objFile holds the parsed contents of an OBJ file.
+type objFile struct {
+	positions         []sprec.Vec3
+	colors            []sprec.Vec3
+	texCoords         []sprec.Vec2
+	normals           []sprec.Vec3
+	materialLibraries []string
+	groups            []*objGroup
+}
+
+// objGroup is a named set of faces, as introduced by the "o" and "g"
+// statements.
+type objGroup struct {
+	name  string
+	faces []objFace
+}
+
+type objFace struct {
+	material  string
+	smoothing int
+	corners   []objCorner
+}
+
+// objCorner references the attributes of a face corner. Missing attributes
+// are marked with -1.
+type objCorner struct {
+	position int
+	texCoord int
+	normal   int
+}
+
+func parseOBJ(in io.Reader) (*objFile, error) {
+	result := &objFile{}
+	group := &objGroup{
+		name: objDefaultName,
+	}
+	result.groups = append(result.groups, group)
+
+	var (
+		material  string
+		smoothing int
+	)
+
+	startGroup := func(name string) {
+		if name == "" {
+			name = objDefaultName
+		}
+		if len(group.faces) == 0 {
+			group.name = name
+			return
+		}
+		group = &objGroup{
+			name: name,
+		}
+		result.groups = append(result.groups, group)
+	}
+
+	skipped := make(map[string]struct{})
+
+	err := scanOBJLines(in, func(lineNumber int, keyword string, args []string) error {
+		switch keyword {
+		case "v":
+			values, err := parseOBJFloats(args, 3, 7)
+			if err != nil {
+				return err
+			}
+			result.positions = append(result.positions, sprec.NewVec3(values[0], values[1], values[2]))
+			// NOTE: Some tools store vertex colors as additional components
+			// of the position.
+			if len(values) >= 6 {
+				result.colors = append(result.colors, sprec.NewVec3(values[3], values[4], values[5]))
+			}
+		case "vt":
+			values, err := parseOBJFloats(args, 1, 3)
+			if err != nil {
+				return err
+			}
+			texCoord := sprec.NewVec2(values[0], 0.0)
+			if len(values) > 1 {
+				texCoord.Y = values[1]
+			}
+			result.texCoords = append(result.texCoords, texCoord)
+		case "vn":
+			values, err := parseOBJFloats(args, 3, 3)
+			if err != nil {
+				return err
+			}
+			result.normals = append(result.normals, sprec.NewVec3(values[0], values[1], values[2]))
+		case "f":
+			if len(args) < 3 {
+				return fmt.Errorf("face has %d vertices, at least three are required", len(args))
+			}
+			face := objFace{
+				material:  material,
+				smoothing: smoothing,
+				corners:   make([]objCorner, len(args)),
+			}
+			for i, arg := range args {
+				corner, err := result.parseCorner(arg)
+				if err != nil {
+					return err
+				}
+				face.corners[i] = corner
+			}
+			group.faces = append(group.faces, face)
+		case "o", "g":
+			startGroup(strings.Join(args, " "))
+		case "s":
+			if len(args) != 1 {
+				return fmt.Errorf("expected a single smoothing group")
+			}
+			if args[0] == "off" {
+				smoothing = 0
+			} else {
+				value, err := strconv.Atoi(args[0])
+				if err != nil {
+					return fmt.Errorf("invalid smoothing group %q: %w", args[0], err)
+				}
+				smoothing = value
+			}
+		case "usemtl":
+			material = strings.Join(args, " ")
+		case "mtllib":
+			// NOTE: Library file names cannot be distinguished from multiple
+			// libraries when they contain spaces. Multiple libraries are
+			// far more common.
+			result.materialLibraries = append(result.materialLibraries, args...)
+		default:
+			if _, ok := skipped[keyword]; !ok {
+				skipped[keyword] = struct{}{}
+				logger.Warn("Skipping unsupported OBJ statement",
+					slog.String("statement", keyword),
+					slog.Int("line", lineNumber),
+				)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error parsing obj: %w", err)
+	}
+
+	if len(result.colors) != len(result.positions) {
+		result.colors = nil
+	}
+	return result, nil
+}
+
+func (f *objFile) parseCorner(value string) (objCorner, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) > 3 {
+		return objCorner{}, fmt.Errorf("invalid face vertex %q", value)
+	}
+	corner := objCorner{
+		position: -1,
+		texCoord: -1,
+		normal:   -1,
+	}
+	var err error
+	if corner.position, err = parseOBJIndex(parts[0], len(f.positions)); err != nil {
+		return objCorner{}, fmt.Errorf("invalid position of face vertex %q: %w", value, err)
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		if corner.texCoord, err = parseOBJIndex(parts[1], len(f.texCoords)); err != nil {
+			return objCorner{}, fmt.Errorf("invalid tex coord of face vertex %q: %w", value, err)
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if corner.normal, err = parseOBJIndex(parts[2], len(f.normals)); err != nil {
+			return objCorner{}, fmt.Errorf("invalid normal of face vertex %q: %w", value, err)
+		}
+	}
+	return corner, nil
+}
+
+// parseOBJIndex converts a one-based (or negative relative) OBJ index into
+// a zero-based index.
+func parseOBJIndex(value string, count int) (int, error) {
+	index, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case index > 0:
+		index--
+	case index < 0:
+		index += count
+	default:
+		return 0, fmt.Errorf("index cannot be zero")
+	}
+	if index < 0 || index >= count {
+		return 0, fmt.Errorf("index %s is out of range", value)
+	}
+	return index, nil
+}
+
+func parseOBJFloats(args []string, minCount, maxCount int) ([]float32, error) {
+	if len(args) < minCount || len(args) > maxCount {
+		return nil, fmt.Errorf("expected between %d and %d values, got %d", minCount, maxCount, len(args))
+	}
+	result := make([]float32, len(args))
+	for i, arg := range args {
+		value, err := strconv.ParseFloat(arg, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q: %w", arg, err)
+		}
+		result[i] = float32(value)
+	}
+	return result, nil
+}
+
+// scanOBJLines calls the specified function for each statement of the
+// OBJ or MTL data. Comments and empty lines are skipped and continued lines
+// are joined.
+func scanOBJLines(in io.Reader, fn func(lineNumber int, keyword string, args []string) error) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var (
+		lineNumber int
+		statement  strings.Builder
+	)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if before, _, ok := strings.Cut(line, "#"); ok {
+			line = before
+		}
+		if continued, ok := strings.CutSuffix(strings.TrimSpace(line), "\\"); ok {
+			statement.WriteString(continued)
+			statement.WriteString(" ")
+			continue
+		}
+		statement.WriteString(line)
+		fields := strings.Fields(statement.String())
+		statement.Reset()
+		if len(fields) == 0 {
+			continue
+		}
+		if err := fn(lineNumber, fields[0], fields[1:]); err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading lines: %w", err)
+	}
+	return nil
+}
+
+// mtlMaterial holds the parsed properties of an MTL material.
+type mtlMaterial struct {
+	name        string
+	diffuse     sprec.Vec3
+	dissolve    float32
+	emissive    sprec.Vec3
+	roughness   opt.T[float32]
+	metallic    float32
+	diffuseMap  *mtlTextureMap
+	normalMap   *mtlTextureMap
+	emissiveMap *mtlTextureMap
+	dissolveMap *mtlTextureMap
+}
+
+// mtlTextureMap is a texture reference of an MTL material. The path is
+// relative to the OBJ file.
+type mtlTextureMap struct {
+	path           string
+	offset         [2]float64
+	scale          [2]float64
+	clamp          bool
+	bumpMultiplier float64
+}
+
+func openMTL(library string, resolver *gltfResolver) ([]*mtlMaterial, error) {
+	in, err := resolver.Open(library)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	materials, err := parseMTL(in, path.Dir(library))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing material library %q: %w", library, err)
+	}
+	return materials, nil
+}
+
+func parseMTL(in io.Reader, dir string) ([]*mtlMaterial, error) {
+	var (
+		result   []*mtlMaterial
+		material *mtlMaterial
+	)
+
+	skipped := make(map[string]struct{})
+
+	err := scanOBJLines(in, func(lineNumber int, keyword string, args []string) error {
+		if keyword == "newmtl" {
+			material = &mtlMaterial{
+				name:     strings.Join(args, " "),
+				diffuse:  sprec.NewVec3(1.0, 1.0, 1.0),
+				dissolve: 1.0,
+			}
+			result = append(result, material)
+			return nil
+		}
+		if material == nil {
+			return fmt.Errorf("statement %q outside of material", keyword)
+		}
+
+		var err error
+		switch keyword {
+		case "Kd":
+			material.diffuse, err = parseMTLColor(args)
+		case "Ke":
+			material.emissive, err = parseMTLColor(args)
+		case "d":
+			material.dissolve, err = parseMTLFloat(args)
+		case "Tr":
+			var transparency float32
+			transparency, err = parseMTLFloat(args)
+			material.dissolve = 1.0 - transparency
+		case "Ns":
+			// NOTE: The specular exponent is only used when the material
+			// does not specify a PBR roughness.
+			if !material.roughness.Specified {
+				var exponent float32
+				exponent, err = parseMTLFloat(args)
+				material.roughness = opt.V(float32(math.Sqrt(2.0 / (max(float64(exponent), 0.0) + 2.0))))
+			}
+		case "Pr":
+			var roughness float32
+			roughness, err = parseMTLFloat(args)
+			material.roughness = opt.V(roughness)
+		case "Pm":
+			material.metallic, err = parseMTLFloat(args)
+		case "map_Kd":
+			material.diffuseMap, err = parseMTLTextureMap(args, dir)
+		case "map_Bump", "map_bump", "bump", "norm":
+			material.normalMap, err = parseMTLTextureMap(args, dir)
+		case "map_Ke":
+			material.emissiveMap, err = parseMTLTextureMap(args, dir)
+		case "map_d":
+			material.dissolveMap, err = parseMTLTextureMap(args, dir)
+		default:
+			if _, ok := skipped[keyword]; !ok {
+				skipped[keyword] = struct{}{}
+				logger.Debug("Skipping unsupported MTL statement",
+					slog.String("statement", keyword),
+					slog.Int("line", lineNumber),
+				)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %q statement of material %q: %w", keyword, material.name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseMTLColor(args []string) (sprec.Vec3, error) {
+	if len(args) > 0 && (args[0] == "spectral" || args[0] == "xyz") {
+		return sprec.Vec3{}, fmt.Errorf("unsupported color space %q", args[0])
+	}
+	values, err := parseOBJFloats(args, 1, 3)
+	if err != nil {
+		return sprec.Vec3{}, err
+	}
+	if len(values) < 3 {
+		// A single value specifies a gray color.
+		return sprec.NewVec3(values[0], values[0], values[0]), nil
+	}
+	return sprec.NewVec3(values[0], values[1], values[2]), nil
+}
+
+func parseMTLFloat(args []string) (float32, error) {
+	values, err := parseOBJFloats(args, 1, 1)
+	if err != nil {
+		return 0.0, err
+	}
+	return values[0], nil
+}
+
+func parseMTLTextureMap(args []string, dir string) (*mtlTextureMap, error) {
+	result := &mtlTextureMap{
+		scale:          [2]float64{1.0, 1.0},
+		bumpMultiplier: 1.0,
+	}
+
+	// parseVector consumes up to three numeric arguments, of which only
+	// the first two are relevant for 2D textures.
+	parseVector := func(target *[2]float64) {
+		for i := 0; i < 3 && len(args) > 0; i++ {
+			value, err := strconv.ParseFloat(args[0], 64)
+			if err != nil {
+				return
+			}
+			if i < 2 {
+				target[i] = value
+			}
+			args = args[1:]
+		}
+	}
+
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		option := args[0]
+		args = args[1:]
+		switch option {
+		case "-o":
+			parseVector(&result.offset)
+		case "-s":
+			parseVector(&result.scale)
+		case "-t":
+			parseVector(&[2]float64{})
+		case "-mm":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("option %q requires two values", option)
+			}
+			args = args[2:]
+		case "-bm":
+			if len(args) < 1 {
+				return nil, fmt.Errorf("option %q requires a value", option)
+			}
+			value, err := strconv.ParseFloat(args[0], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bump multiplier %q: %w", args[0], err)
+			}
+			result.bumpMultiplier = value
+			args = args[1:]
+		case "-clamp":
+			if len(args) < 1 {
+				return nil, fmt.Errorf("option %q requires a value", option)
+			}
+			result.clamp = args[0] == "on"
+			args = args[1:]
+		case "-blendu", "-blendv", "-boost", "-texres", "-imfchan", "-type", "-cc":
+			if len(args) < 1 {
+				return nil, fmt.Errorf("option %q requires a value", option)
+			}
+			args = args[1:]
+		default:
+			return nil, fmt.Errorf("unsupported texture option %q", option)
+		}
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing texture file")
+	}
+
+	// NOTE: Texture file names can contain spaces and files exported on
+	// Windows often use backslashes.
+	file := strings.ReplaceAll(strings.Join(args, " "), "\\", "/")
+	result.path = path.Join(dir, file)
+	return result, nil
+}
+
+func newOBJDocumentBuilder(obj *objFile, materials map[string]*mtlMaterial) *objDocumentBuilder {
+	return &objDocumentBuilder{
+		doc:             gltf.NewDocument(),
+		obj:             obj,
+		materials:       materials,
+		materialIndices: make(map[string]int),
+		imageIndices:    make(map[string]int),
+		textureIndices:  make(map[mtlTextureKey]int),
+	}
+}
+
+type objDocumentBuilder struct {
+	doc       *gltf.Document
+	obj       *objFile
+	materials map[string]*mtlMaterial
+
+	materialIndices map[string]int
+	imageIndices    map[string]int
+	textureIndices  map[mtlTextureKey]int
+}
+
+type mtlTextureKey struct {
+	path  string
+	clamp bool
+}
+
+type objVertexKey struct {
+	position int
+	texCoord int
+	normal   int
+
+	// generatedNormal is used to distinguish vertices whose normals are
+	// generated based on their smoothing group.
+	generatedNormal sprec.Vec3
+}
+
+type objSmoothingKey struct {
+	smoothing int
+	position  int
+}
+
+func (b *objDocumentBuilder) Build() (*gltf.Document, error) {
+	scene := b.doc.Scenes[0]
+	for _, group := range b.obj.groups {
+		if len(group.faces) == 0 {
+			continue
+		}
+		meshIndex, err := b.buildMesh(group)
+		if err != nil {
+			return nil, fmt.Errorf("error building mesh %q: %w", group.name, err)
+		}
+		b.doc.Nodes = append(b.doc.Nodes, &gltf.Node{
+			Name:     group.name,
+			Mesh:     gltf.Index(meshIndex),
+			Rotation: gltf.DefaultRotation,
+			Scale:    gltf.DefaultScale,
+		})
+		scene.Nodes = append(scene.Nodes, len(b.doc.Nodes)-1)
+	}
+	return b.doc, nil
+}
+
+func (b *objDocumentBuilder) buildMesh(group *objGroup) (int, error) {
+	obj := b.obj
+
+	// Faces without normals get generated ones. Faces that are part of a
+	// smoothing group share the (area weighted) sum of the normals of all
+	// faces in the group that touch the same position.
+	faceNormals := make([]sprec.Vec3, len(group.faces))
+	smoothNormals := make(map[objSmoothingKey]sprec.Vec3)
+	hasTexCoords := false
+	for i, face := range group.faces {
+		faceNormals[i] = objFaceNormal(obj.positions, face)
+		for _, corner := range face.corners {
+			if corner.texCoord >= 0 {
+				hasTexCoords = true
+			}
+			if corner.normal < 0 && face.smoothing != 0 {
+				key := objSmoothingKey{
+					smoothing: face.smoothing,
+					position:  corner.position,
+				}
+				smoothNormals[key] = sprec.Vec3Sum(smoothNormals[key], faceNormals[i])
+			}
+		}
+	}
+
+	var (
+		coords    [][3]float32
+		normals   [][3]float32
+		texCoords [][2]float32
+		colors    [][4]float32
+
+		materials       []string
+		materialIndices = make(map[string][]uint32)
+		vertexIndices   = make(map[objVertexKey]uint32)
+	)
+
+	addVertex := func(face objFace, faceNormal sprec.Vec3, corner objCorner) uint32 {
+		key := objVertexKey{
+			position: corner.position,
+			texCoord: corner.texCoord,
+			normal:   corner.normal,
+		}
+		if corner.normal < 0 {
+			if face.smoothing != 0 {
+				key.generatedNormal = smoothNormals[objSmoothingKey{
+					smoothing: face.smoothing,
+					position:  corner.position,
+				}]
+			} else {
+				key.generatedNormal = faceNormal
+			}
+			key.generatedNormal = objNormalize(key.generatedNormal)
+		}
+		if index, ok := vertexIndices[key]; ok {
+			return index
+		}
+
+		index := uint32(len(coords))
+		vertexIndices[key] = index
+
+		coord := obj.positions[corner.position]
+		coords = append(coords, [3]float32{coord.X, coord.Y, coord.Z})
+
+		normal := key.generatedNormal
+		if corner.normal >= 0 {
+			normal = objNormalize(obj.normals[corner.normal])
+		}
+		normals = append(normals, [3]float32{normal.X, normal.Y, normal.Z})
+
+		if hasTexCoords {
+			var texCoord sprec.Vec2
+			if corner.texCoord >= 0 {
+				texCoord = obj.texCoords[corner.texCoord]
+			}
+			// The origin of glTF tex coords is at the top left corner.
+			texCoords = append(texCoords, [2]float32{texCoord.X, 1.0 - texCoord.Y})
+		}
+		if obj.colors != nil {
+			color := obj.colors[corner.position]
+			colors = append(colors, [4]float32{color.X, color.Y, color.Z, 1.0})
+		}
+		return index
+	}
+
+	for i, face := range group.faces {
+		if _, ok := materialIndices[face.material]; !ok {
+			materials = append(materials, face.material)
+		}
+		indices := materialIndices[face.material]
+		first := addVertex(face, faceNormals[i], face.corners[0])
+		for j := 1; j+1 < len(face.corners); j++ {
+			indices = append(indices,
+				first,
+				addVertex(face, faceNormals[i], face.corners[j]),
+				addVertex(face, faceNormals[i], face.corners[j+1]),
+			)
+		}
+		materialIndices[face.material] = indices
+	}
+
+	attributes := gltf.PrimitiveAttributes{
+		gltf.POSITION: modeler.WritePosition(b.doc, coords),
+		gltf.NORMAL:   modeler.WriteNormal(b.doc, normals),
+	}
+	if texCoords != nil {
+		attributes[gltf.TEXCOORD_0] = modeler.WriteTextureCoord(b.doc, texCoords)
+	}
+	if colors != nil {
+		attributes[gltf.COLOR_0] = modeler.WriteColor(b.doc, colors)
+	}
+
+	gltfMesh := &gltf.Mesh{
+		Name: group.name,
+	}
+	for _, material := range materials {
+		gltfMesh.Primitives = append(gltfMesh.Primitives, &gltf.Primitive{
+			Attributes: attributes,
+			Indices:    gltf.Index(modeler.WriteIndices(b.doc, materialIndices[material])),
+			Mode:       gltf.PrimitiveTriangles,
+			Material:   gltf.Index(b.materialIndex(material)),
+		})
+	}
+	b.doc.Meshes = append(b.doc.Meshes, gltfMesh)
+	return len(b.doc.Meshes) - 1, nil
+}
+
+func (b *objDocumentBuilder) materialIndex(name string) int {
+	if index, ok := b.materialIndices[name]; ok {
+		return index
+	}
+
+	material, ok := b.materials[name]
+	if !ok {
+		if name != "" {
+			logger.Warn("Using default material in place of missing OBJ material",
+				slog.String("material", name),
+			)
+		}
+		material = &mtlMaterial{
+			diffuse:  sprec.NewVec3(1.0, 1.0, 1.0),
+			dissolve: 1.0,
+		}
+	}
+	if name == "" {
+		name = objDefaultName
+	}
+
+	roughness := float32(1.0)
+	if material.roughness.Specified {
+		roughness = material.roughness.Value
+	}
+	gltfMaterial := &gltf.Material{
+		Name: name,
+		PBRMetallicRoughness: &gltf.PBRMetallicRoughness{
+			BaseColorFactor: &[4]float64{
+				float64(material.diffuse.X),
+				float64(material.diffuse.Y),
+				float64(material.diffuse.Z),
+				float64(material.dissolve),
+			},
+			MetallicFactor:  gltf.Float(float64(material.metallic)),
+			RoughnessFactor: gltf.Float(float64(roughness)),
+		},
+	}
+	if textureMap := material.diffuseMap; textureMap != nil {
+		gltfMaterial.PBRMetallicRoughness.BaseColorTexture = b.textureInfo(textureMap)
+	}
+	if textureMap := material.normalMap; textureMap != nil {
+		info := b.textureInfo(textureMap)
+		gltfMaterial.NormalTexture = &gltf.NormalTexture{
+			Index:      gltf.Index(info.Index),
+			Scale:      gltf.Float(textureMap.bumpMultiplier),
+			Extensions: info.Extensions,
+		}
+	}
+
+	emissive := material.emissive
+	if material.emissiveMap != nil {
+		if emissive == (sprec.Vec3{}) {
+			emissive = sprec.NewVec3(1.0, 1.0, 1.0)
+		}
+		gltfMaterial.EmissiveTexture = b.textureInfo(material.emissiveMap)
+	}
+	if strength := max(emissive.X, emissive.Y, emissive.Z); strength > 0.0 {
+		factor := float32(1.0)
+		if strength > 1.0 {
+			factor = strength
+			gltfMaterial.Extensions = gltf.Extensions{
+				gltfEmissiveStrengthExtensionName: json.RawMessage(
+					fmt.Sprintf(`{"emissiveStrength":%g}`, strength),
+				),
+			}
+		}
+		gltfMaterial.EmissiveFactor = [3]float64{
+			float64(emissive.X / factor),
+			float64(emissive.Y / factor),
+			float64(emissive.Z / factor),
+		}
+	}
+
+	// NOTE: The dissolve map usually holds the alpha channel of the diffuse
+	// map, which is what alpha testing uses.
+	if material.dissolveMap != nil {
+		gltfMaterial.AlphaMode = gltf.AlphaMask
+	}
+
+	b.doc.Materials = append(b.doc.Materials, gltfMaterial)
+	index := len(b.doc.Materials) - 1
+	b.materialIndices[name] = index
+	return index
+}
+
+func (b *objDocumentBuilder) textureInfo(textureMap *mtlTextureMap) *gltf.TextureInfo {
+	key := mtlTextureKey{
+		path:  textureMap.path,
+		clamp: textureMap.clamp,
+	}
+	index, ok := b.textureIndices[key]
+	if !ok {
+		imageIndex, ok := b.imageIndices[textureMap.path]
+		if !ok {
+			b.doc.Images = append(b.doc.Images, &gltf.Image{
+				Name: path.Base(textureMap.path),
+				URI:  textureMap.path,
+			})
+			imageIndex = len(b.doc.Images) - 1
+			b.imageIndices[textureMap.path] = imageIndex
+		}
+
+		wrapMode := gltf.WrapRepeat
+		if textureMap.clamp {
+			wrapMode = gltf.WrapClampToEdge
+		}
+		b.doc.Samplers = append(b.doc.Samplers, &gltf.Sampler{
+			MagFilter: gltf.MagLinear,
+			MinFilter: gltf.MinLinearMipMapLinear,
+			WrapS:     wrapMode,
+			WrapT:     wrapMode,
+		})
+		b.doc.Textures = append(b.doc.Textures, &gltf.Texture{
+			Sampler: gltf.Index(len(b.doc.Samplers) - 1),
+			Source:  gltf.Index(imageIndex),
+		})
+		index = len(b.doc.Textures) - 1
+		b.textureIndices[key] = index
+	}
+
+	info := &gltf.TextureInfo{
+		Index: index,
+	}
+	if textureMap.offset != [2]float64{} || textureMap.scale != [2]float64{1.0, 1.0} {
+		// The OBJ transform is specified in the bottom-up tex coord space,
+		// whereas glTF uses a top-down one.
+		info.Extensions = gltf.Extensions{
+			texturetransform.ExtensionName: &texturetransform.TextureTranform{
+				Offset: [2]float64{
+					textureMap.offset[0],
+					1.0 - textureMap.scale[1] - textureMap.offset[1],
+				},
+				Scale: textureMap.scale,
+			},
+		}
+	}
+	return info
+}
+
+// objFaceNormal returns the area weighted normal of the specified face,
+// using Newell's method so that non-planar polygons are handled as well.
+func objFaceNormal(positions []sprec.Vec3, face objFace) sprec.Vec3 {
+	var normal sprec.Vec3
+	for i, corner := range face.corners {
+		current := positions[corner.position]
+		next := positions[face.corners[(i+1)%len(face.corners)].position]
+		normal.X += (current.Y - next.Y) * (current.Z + next.Z)
+		normal.Y += (current.Z - next.Z) * (current.X + next.X)
+		normal.Z += (current.X - next.X) * (current.Y + next.Y)
+	}
+	return sprec.Vec3Quot(normal, 2.0)
+}
+
+func objNormalize(normal sprec.Vec3) sprec.Vec3 {
+	if normal.Length() < 1e-12 {
+		return sprec.NewVec3(0.0, 1.0, 0.0)
+	}
+	return sprec.UnitVec3(normal)
+}
@@ -0,0 +1,417 @@
+package dsl_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking/util/gltfutil"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/ext/texturetransform"
+)
+
+var _ = Describe("OBJ parsing", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	writeFile := func(name, content string) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)).To(Succeed())
+	}
+
+	parse := func(content string) (*gltf.Document, error) {
+		return dsl.ParseOBJDocument(strings.NewReader(content), filepath.Join(dir, "model.obj"))
+	}
+
+	mustParse := func(content string) *gltf.Document {
+		doc, err := parse(content)
+		Expect(err).ToNot(HaveOccurred())
+		return doc
+	}
+
+	// cornerValues returns the values of the specified attribute for each
+	// index of the primitive, in triangle order.
+	cornerValues := func(doc *gltf.Document, primitive *gltf.Primitive, read func(*gltf.Document, *gltf.Primitive) ([]sprec.Vec3, error)) []sprec.Vec3 {
+		indices, err := gltfutil.Indices(doc, primitive)
+		Expect(err).ToNot(HaveOccurred())
+		values, err := read(doc, primitive)
+		Expect(err).ToNot(HaveOccurred())
+		result := make([]sprec.Vec3, len(indices))
+		for i, index := range indices {
+			result[i] = values[index]
+		}
+		return result
+	}
+
+	Describe("indices", func() {
+		It("resolves negative indices relative to the preceding vertices", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+f -3 -2 -1
+v 5 5 5
+v 6 5 5
+v 5 6 5
+f -3 -2 -1
+`)
+			Expect(doc.Meshes).To(HaveLen(1))
+			coords := cornerValues(doc, doc.Meshes[0].Primitives[0], gltfutil.Coords)
+			Expect(coords).To(HaveLen(6))
+			Expect(coords[0]).To(Equal(sprec.NewVec3(0.0, 0.0, 0.0)))
+			Expect(coords[1]).To(Equal(sprec.NewVec3(1.0, 0.0, 0.0)))
+			Expect(coords[2]).To(Equal(sprec.NewVec3(0.0, 1.0, 0.0)))
+			Expect(coords[3]).To(Equal(sprec.NewVec3(5.0, 5.0, 5.0)))
+			Expect(coords[4]).To(Equal(sprec.NewVec3(6.0, 5.0, 5.0)))
+			Expect(coords[5]).To(Equal(sprec.NewVec3(5.0, 6.0, 5.0)))
+		})
+
+		It("resolves negative tex coord and normal indices", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vt 0.0 0.0
+vt 0.25 0.75
+vn 0 0 1
+vn 1 0 0
+f 1/-1/-1 2/-1/-1 3/-1/-1
+`)
+			primitive := doc.Meshes[0].Primitives[0]
+			normals := cornerValues(doc, primitive, gltfutil.Normals)
+			for _, normal := range normals {
+				Expect(normal).To(Equal(sprec.NewVec3(1.0, 0.0, 0.0)))
+			}
+			texCoords, err := gltfutil.TexCoord0s(doc, primitive)
+			Expect(err).ToNot(HaveOccurred())
+			for _, texCoord := range texCoords {
+				// The V coordinate is flipped for glTF and flipped back
+				// when read through gltfutil.
+				Expect(texCoord).To(Equal(sprec.NewVec2(0.25, 0.75)))
+			}
+		})
+
+		DescribeTable("invalid indices",
+			func(face string) {
+				_, err := parse("v 0 0 0\nv 1 0 0\nv 0 1 0\n" + face + "\n")
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("zero index", "f 0 1 2"),
+			Entry("index past the end", "f 1 2 4"),
+			Entry("negative index before the start", "f -4 -2 -1"),
+			Entry("missing tex coord", "f 1/1 2/1 3/1"),
+			Entry("too few corners", "f 1 2"),
+		)
+	})
+
+	Describe("missing attributes", func() {
+		It("generates flat normals for faces without normals", func() {
+			doc := mustParse(`
+v 0 0 0
+v 2 0 0
+v 0 2 0
+f 1 2 3
+`)
+			normals := cornerValues(doc, doc.Meshes[0].Primitives[0], gltfutil.Normals)
+			for _, normal := range normals {
+				Expect(normal).To(Equal(sprec.NewVec3(0.0, 0.0, 1.0)))
+			}
+		})
+
+		It("normalizes the specified normals", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vn 0 0 3
+f 1//1 2//1 3//1
+`)
+			normals := cornerValues(doc, doc.Meshes[0].Primitives[0], gltfutil.Normals)
+			for _, normal := range normals {
+				Expect(normal).To(Equal(sprec.NewVec3(0.0, 0.0, 1.0)))
+			}
+		})
+
+		It("omits tex coords when no face uses them", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+f 1 2 3
+`)
+			primitive := doc.Meshes[0].Primitives[0]
+			Expect(gltfutil.HasAttribute(primitive, gltf.TEXCOORD_0)).To(BeFalse())
+		})
+
+		It("uses the origin for corners without tex coords in a textured mesh", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+v 1 1 0
+vt 0.5 0.5
+f 1/1 2/1 3/1
+f 2 4 3
+`)
+			primitive := doc.Meshes[0].Primitives[0]
+			Expect(gltfutil.HasAttribute(primitive, gltf.TEXCOORD_0)).To(BeTrue())
+			indices, err := gltfutil.Indices(doc, primitive)
+			Expect(err).ToNot(HaveOccurred())
+			texCoords, err := gltfutil.TexCoord0s(doc, primitive)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(texCoords[indices[0]]).To(Equal(sprec.NewVec2(0.5, 0.5)))
+			Expect(texCoords[indices[4]]).To(Equal(sprec.NewVec2(0.0, 0.0)))
+		})
+	})
+
+	Describe("groups", func() {
+		It("creates a node and a mesh for each group", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+o first
+f 1 2 3
+g second part
+f 3 2 1
+`)
+			Expect(doc.Nodes).To(HaveLen(2))
+			Expect(doc.Nodes[0].Name).To(Equal("first"))
+			Expect(doc.Nodes[1].Name).To(Equal("second part"))
+			Expect(doc.Meshes).To(HaveLen(2))
+			Expect(doc.Meshes[0].Name).To(Equal("first"))
+			Expect(doc.Meshes[1].Name).To(Equal("second part"))
+		})
+
+		It("uses a default name for faces outside of groups", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+f 1 2 3
+g
+f 3 2 1
+`)
+			Expect(doc.Nodes).To(HaveLen(2))
+			Expect(doc.Nodes[0].Name).To(Equal("default"))
+			Expect(doc.Nodes[1].Name).To(Equal("default"))
+		})
+
+		It("skips groups without faces", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+g empty
+g filled
+f 1 2 3
+g trailing
+`)
+			Expect(doc.Nodes).To(HaveLen(1))
+			Expect(doc.Nodes[0].Name).To(Equal("filled"))
+		})
+
+		It("creates a primitive for each material of a group", func() {
+			doc := mustParse(`
+v 0 0 0
+v 1 0 0
+v 0 1 0
+usemtl red
+f 1 2 3
+usemtl blue
+f 3 2 1
+usemtl red
+f 1 3 2
+`)
+			Expect(doc.Meshes).To(HaveLen(1))
+			primitives := doc.Meshes[0].Primitives
+			Expect(primitives).To(HaveLen(2))
+			Expect(doc.Materials[*primitives[0].Material].Name).To(Equal("red"))
+			Expect(doc.Materials[*primitives[1].Material].Name).To(Equal("blue"))
+
+			redIndices, err := gltfutil.Indices(doc, primitives[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(redIndices).To(HaveLen(6))
+		})
+	})
+
+	Describe("smoothing groups", func() {
+		// Two triangles that share the edge between the second and the
+		// third vertex and form a ridge.
+		const ridge = `
+v 0 0 0
+v 1 1 0
+v 1 0 1
+v 2 0 0
+`
+
+		It("shares normals between the faces of a smoothing group", func() {
+			doc := mustParse(ridge + `
+s 1
+f 1 3 2
+f 2 3 4
+`)
+			primitive := doc.Meshes[0].Primitives[0]
+			coords, err := gltfutil.Coords(doc, primitive)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(coords).To(HaveLen(4))
+
+			normals := cornerValues(doc, primitive, gltfutil.Normals)
+			// Corners 1 and 4 refer to the third vertex and corners 2 and
+			// 3 refer to the second vertex.
+			Expect(normals[1]).To(Equal(normals[4]))
+			Expect(normals[2]).To(Equal(normals[3]))
+			Expect(normals[0]).ToNot(Equal(normals[1]))
+		})
+
+		It("uses flat normals when smoothing is off", func() {
+			doc := mustParse(ridge + `
+s off
+f 1 3 2
+f 2 3 4
+`)
+			primitive := doc.Meshes[0].Primitives[0]
+			coords, err := gltfutil.Coords(doc, primitive)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(coords).To(HaveLen(6))
+
+			normals := cornerValues(doc, primitive, gltfutil.Normals)
+			Expect(normals[0]).To(Equal(normals[1]))
+			Expect(normals[0]).To(Equal(normals[2]))
+			Expect(normals[3]).To(Equal(normals[4]))
+			Expect(normals[3]).To(Equal(normals[5]))
+			Expect(normals[0]).ToNot(Equal(normals[3]))
+		})
+
+		It("does not share normals between different smoothing groups", func() {
+			doc := mustParse(ridge + `
+s 1
+f 1 3 2
+s 2
+f 2 3 4
+`)
+			primitive := doc.Meshes[0].Primitives[0]
+			coords, err := gltfutil.Coords(doc, primitive)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(coords).To(HaveLen(6))
+		})
+
+		It("rejects invalid smoothing groups", func() {
+			_, err := parse("s soft\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("materials", func() {
+		material := func(doc *gltf.Document, name string) *gltf.Material {
+			for _, material := range doc.Materials {
+				if material.Name == name {
+					return material
+				}
+			}
+			return nil
+		}
+
+		const triangle = `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vt 0 0
+mtllib materials.mtl
+usemtl surface
+f 1/1 2/1 3/1
+`
+
+		It("converts the material properties", func() {
+			writeFile("materials.mtl", `
+newmtl surface
+Kd 0.5 0.25 1.0
+d 0.75
+Pr 0.3
+Pm 0.6
+`)
+			doc := mustParse(triangle)
+			surface := material(doc, "surface")
+			Expect(surface).ToNot(BeNil())
+			pbr := surface.PBRMetallicRoughness
+			Expect(*pbr.BaseColorFactor).To(Equal([4]float64{0.5, 0.25, 1.0, 0.75}))
+			Expect(*pbr.RoughnessFactor).To(BeNumerically("~", 0.3, 1e-6))
+			Expect(*pbr.MetallicFactor).To(BeNumerically("~", 0.6, 1e-6))
+		})
+
+		It("applies the offset, scale and clamp options of texture maps", func() {
+			writeFile("materials.mtl", `
+newmtl surface
+map_Kd -o 0.25 0.5 0.0 -s 2 3 1 -clamp on textures\diffuse map.png
+`)
+			doc := mustParse(triangle)
+			surface := material(doc, "surface")
+			Expect(surface).ToNot(BeNil())
+
+			info := surface.PBRMetallicRoughness.BaseColorTexture
+			Expect(info).ToNot(BeNil())
+			texture := doc.Textures[info.Index]
+			Expect(doc.Images[*texture.Source].URI).To(Equal("textures/diffuse map.png"))
+			sampler := doc.Samplers[*texture.Sampler]
+			Expect(sampler.WrapS).To(Equal(gltf.WrapClampToEdge))
+			Expect(sampler.WrapT).To(Equal(gltf.WrapClampToEdge))
+
+			transform, ok := info.Extensions[texturetransform.ExtensionName].(*texturetransform.TextureTranform)
+			Expect(ok).To(BeTrue())
+			Expect(transform.Scale).To(Equal([2]float64{2.0, 3.0}))
+			Expect(transform.Offset[0]).To(BeNumerically("~", 0.25, 1e-9))
+			Expect(transform.Offset[1]).To(BeNumerically("~", 1.0-3.0-0.5, 1e-9))
+		})
+
+		It("applies the bump multiplier of normal maps", func() {
+			writeFile("materials.mtl", `
+newmtl surface
+map_Bump -bm 0.5 -imfchan l normal.png
+`)
+			doc := mustParse(triangle)
+			surface := material(doc, "surface")
+			Expect(surface).ToNot(BeNil())
+			Expect(surface.NormalTexture).ToNot(BeNil())
+			Expect(*surface.NormalTexture.Scale).To(BeNumerically("~", 0.5, 1e-9))
+			texture := doc.Textures[*surface.NormalTexture.Index]
+			Expect(doc.Images[*texture.Source].URI).To(Equal("normal.png"))
+			sampler := doc.Samplers[*texture.Sampler]
+			Expect(sampler.WrapS).To(Equal(gltf.WrapRepeat))
+		})
+
+		It("uses alpha testing for materials with a dissolve map", func() {
+			writeFile("materials.mtl", `
+newmtl surface
+map_Kd diffuse.png
+map_d diffuse.png
+`)
+			doc := mustParse(triangle)
+			surface := material(doc, "surface")
+			Expect(surface).ToNot(BeNil())
+			Expect(surface.AlphaMode).To(Equal(gltf.AlphaMask))
+		})
+
+		DescribeTable("invalid texture maps",
+			func(statement string) {
+				writeFile("materials.mtl", "newmtl surface\n"+statement+"\n")
+				_, err := parse(triangle)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("unsupported option", "map_Kd -unknown 1 diffuse.png"),
+			Entry("missing option value", "map_Kd -bm"),
+			Entry("missing file", "map_Kd -clamp on"),
+		)
+
+		It("fails when the material library is missing", func() {
+			_, err := parse(triangle)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package dsl
+
+import (
+	"fmt"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+// AddAtlasImage adds an image with the specified name to the target atlas.
+func AddAtlasImage(name string, imageProvider Provider[*mdl.Image]) Operation {
+	type atlasImageAddable interface {
+		AddAtlasImage(string, *mdl.Image)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			image, err := imageProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting image %q: %w", name, err)
+			}
+
+			addable, ok := target.(atlasImageAddable)
+			if !ok {
+				return fmt.Errorf("target %T does not support atlas images", target)
+			}
+			addable.AddAtlasImage(name, image)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("add-atlas-image", name, imageProvider)
+		},
+	)
+}
+
+// SetPadding configures the number of empty texels that the target keeps
+// between neighbouring elements.
+func SetPadding(paddingProvider Provider[int]) Operation {
+	type paddingConfigurable interface {
+		SetPadding(int)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			padding, err := paddingProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting padding: %w", err)
+			}
+
+			configurable, ok := target.(paddingConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with padding", target)
+			}
+			configurable.SetPadding(padding)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-padding", paddingProvider)
+		},
+	)
+}
+
+// SetExtrusion configures the number of texels by which the target repeats
+// the edges of each element outwards.
+func SetExtrusion(extrusionProvider Provider[int]) Operation {
+	type extrusionConfigurable interface {
+		SetExtrusion(int)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			extrusion, err := extrusionProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting extrusion: %w", err)
+			}
+
+			configurable, ok := target.(extrusionConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with extrusion", target)
+			}
+			configurable.SetExtrusion(extrusion)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-extrusion", extrusionProvider)
+		},
+	)
+}
+
+// SetMaxSize configures the maximum width and height of the target.
+func SetMaxSize(sizeProvider Provider[int]) Operation {
+	type maxSizeConfigurable interface {
+		SetMaxSize(int)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			size, err := sizeProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting max size: %w", err)
+			}
+
+			configurable, ok := target.(maxSizeConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with max size", target)
+			}
+			configurable.SetMaxSize(size)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-max-size", sizeProvider)
+		},
+	)
+}
@@ -0,0 +1,384 @@
+package dsl
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+const (
+	defaultConvexVertexLimit = 64
+	defaultConvexMaxHulls    = 16
+	defaultConvexResolution  = 32
+	defaultConvexConcavity   = 0.01
+)
+
+// GenerateConvexHull creates an operation that replaces the collision shape
+// of the target node, or of all nodes with collision meshes in the target
+// node holder, with the convex hull of the node's geometry.
+//
+// The number of hull vertices can be limited with SetVertexLimit, in which
+// case the hull is built from the most significant vertices.
+//
+// A node without a body gets a new one.
+func GenerateConvexHull(opts ...Operation) Operation {
+	type nodeHolder interface {
+		Nodes() []*mdl.Node
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			cfg := generateConvexHullConfig{
+				vertexLimit: defaultConvexVertexLimit,
+			}
+			for _, opt := range opts {
+				if err := opt.Apply(&cfg); err != nil {
+					return fmt.Errorf("failed to configure convex hull generation: %w", err)
+				}
+			}
+			generate := func(triangles []mdl.CollisionTriangle) [][]mdl.CollisionTriangle {
+				var points []dprec.Vec3
+				for _, triangle := range triangles {
+					points = append(points, triangle.A, triangle.B, triangle.C)
+				}
+				hull, ok := computeConvexHull(points, cfg.vertexLimit)
+				if !ok {
+					return nil
+				}
+				return [][]mdl.CollisionTriangle{hull}
+			}
+
+			switch target := target.(type) {
+			case *mdl.Node:
+				return generateConvexCollision(target, generate)
+			case nodeHolder:
+				return generateConvexCollisions(target.Nodes(), generate)
+			default:
+				return fmt.Errorf("target %T is not a node or a node holder", target)
+			}
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("generate-convex-hull", opts)
+		},
+	)
+}
+
+// GenerateConvexDecomposition creates an operation that replaces the
+// collision shape of the target node, or of all nodes with collision meshes
+// in the target node holder, with an approximate convex decomposition of the
+// node's geometry.
+//
+// The geometry is voxelized, as configured with SetResolution, and split
+// into parts until the volume that the hulls add on top of the geometry is
+// below a portion of the total volume, as configured with SetConcavity, or
+// until the maximum number of hulls, as configured with SetMaxHulls, is
+// reached. The vertices of each hull can be limited with SetVertexLimit.
+//
+// A node without a body gets a new one.
+func GenerateConvexDecomposition(opts ...Operation) Operation {
+	type nodeHolder interface {
+		Nodes() []*mdl.Node
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			cfg := convexDecompositionConfig{
+				maxHulls:    defaultConvexMaxHulls,
+				vertexLimit: defaultConvexVertexLimit,
+				resolution:  defaultConvexResolution,
+				concavity:   defaultConvexConcavity,
+			}
+			for _, opt := range opts {
+				if err := opt.Apply(&cfg); err != nil {
+					return fmt.Errorf("failed to configure convex decomposition: %w", err)
+				}
+			}
+			if cfg.maxHulls < 1 {
+				return fmt.Errorf("max hulls %d is less than one", cfg.maxHulls)
+			}
+			if cfg.resolution < 1 {
+				return fmt.Errorf("resolution %d is less than one", cfg.resolution)
+			}
+			generate := func(triangles []mdl.CollisionTriangle) [][]mdl.CollisionTriangle {
+				return decomposeConvex(triangles, cfg)
+			}
+
+			switch target := target.(type) {
+			case *mdl.Node:
+				return generateConvexCollision(target, generate)
+			case nodeHolder:
+				return generateConvexCollisions(target.Nodes(), generate)
+			default:
+				return fmt.Errorf("target %T is not a node or a node holder", target)
+			}
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("generate-convex-decomposition", opts)
+		},
+	)
+}
+
+// SetVertexLimit configures the maximum number of vertices of the target.
+func SetVertexLimit(limitProvider Provider[int]) Operation {
+	type vertexLimitConfigurable interface {
+		SetVertexLimit(int)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			limit, err := limitProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting vertex limit: %w", err)
+			}
+
+			configurable, ok := target.(vertexLimitConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with vertex limit", target)
+			}
+			configurable.SetVertexLimit(limit)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-vertex-limit", limitProvider)
+		},
+	)
+}
+
+// SetMaxHulls configures the maximum number of convex hulls that the target
+// produces.
+func SetMaxHulls(countProvider Provider[int]) Operation {
+	type maxHullsConfigurable interface {
+		SetMaxHulls(int)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			count, err := countProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting max hulls: %w", err)
+			}
+
+			configurable, ok := target.(maxHullsConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with max hulls", target)
+			}
+			configurable.SetMaxHulls(count)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-max-hulls", countProvider)
+		},
+	)
+}
+
+// SetResolution configures the number of cells along the longest axis of
+// the target.
+func SetResolution(resolutionProvider Provider[int]) Operation {
+	type resolutionConfigurable interface {
+		SetResolution(int)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			resolution, err := resolutionProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting resolution: %w", err)
+			}
+
+			configurable, ok := target.(resolutionConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with resolution", target)
+			}
+			configurable.SetResolution(resolution)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-resolution", resolutionProvider)
+		},
+	)
+}
+
+// SetConcavity configures the portion of the volume that the target is
+// allowed to add when approximating a shape with convex ones.
+func SetConcavity(concavityProvider Provider[float64]) Operation {
+	type concavityConfigurable interface {
+		SetConcavity(float64)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			concavity, err := concavityProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting concavity: %w", err)
+			}
+
+			configurable, ok := target.(concavityConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with concavity", target)
+			}
+			configurable.SetConcavity(concavity)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-concavity", concavityProvider)
+		},
+	)
+}
+
+type generateConvexHullConfig struct {
+	vertexLimit int
+}
+
+func (c *generateConvexHullConfig) SetVertexLimit(limit int) {
+	c.vertexLimit = limit
+}
+
+func (c *convexDecompositionConfig) SetVertexLimit(limit int) {
+	c.vertexLimit = limit
+}
+
+func (c *convexDecompositionConfig) SetMaxHulls(count int) {
+	c.maxHulls = count
+}
+
+func (c *convexDecompositionConfig) SetResolution(resolution int) {
+	c.resolution = resolution
+}
+
+func (c *convexDecompositionConfig) SetConcavity(concavity float64) {
+	c.concavity = concavity
+}
+
+type convexGenerator func(triangles []mdl.CollisionTriangle) [][]mdl.CollisionTriangle
+
+// generateConvexCollisions replaces the collision meshes of all nodes in the
+// hierarchy with convex hulls. Body definitions that are shared between nodes
+// are processed only once.
+func generateConvexCollisions(nodes []*mdl.Node, generate convexGenerator) error {
+	processed := make(map[*mdl.BodyDefinition]struct{})
+	for _, node := range flattenNodes(nodes) {
+		for body := range mdl.NodeAttachmentsOfType[*mdl.Body](node) {
+			definition := body.Definition()
+			if _, ok := processed[definition]; ok {
+				continue
+			}
+			processed[definition] = struct{}{}
+			if len(definition.CollisionMeshes()) == 0 {
+				continue
+			}
+			if err := generateConvexCollision(node, generate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateConvexCollision replaces the collision shape of the node's body
+// with convex hulls generated from the node's geometry.
+func generateConvexCollision(node *mdl.Node, generate convexGenerator) error {
+	triangles := nodeCollisionTriangles(node)
+	if len(triangles) == 0 {
+		return fmt.Errorf("node %q has no collision geometry", node.Name())
+	}
+	hullTriangles := generate(triangles)
+	if len(hullTriangles) == 0 {
+		return fmt.Errorf("geometry of node %q does not enclose a volume", node.Name())
+	}
+
+	hulls := make([]*mdl.CollisionConvexHull, len(hullTriangles))
+	for i, triangles := range hullTriangles {
+		hulls[i] = createCollisionConvexHull(triangles)
+	}
+
+	var definition *mdl.BodyDefinition
+	for body := range mdl.NodeAttachmentsOfType[*mdl.Body](node) {
+		definition = body.Definition()
+		break
+	}
+	if definition == nil {
+		definition = mdl.NewBodyDefinition(mdl.NewBodyMaterial())
+		node.AddAttachment(mdl.NewBody(definition))
+	}
+	definition.SetCollisionMeshes(nil)
+	definition.SetCollisionConvexHulls(hulls)
+
+	logger.Info("Generated convex collision",
+		slog.String("node", node.Name()),
+		slog.Int("triangles", len(triangles)),
+		slog.Int("hulls", len(hulls)),
+	)
+	return nil
+}
+
+// nodeCollisionTriangles returns the triangles of all meshes of the node,
+// except for the fragments that are marked to be skipped for collision.
+func nodeCollisionTriangles(node *mdl.Node) []mdl.CollisionTriangle {
+	var triangles []mdl.CollisionTriangle
+	for mesh := range mdl.NodeAttachmentsOfType[*mdl.Mesh](node) {
+		geometry := mesh.Definition().Geometry()
+		for _, fragment := range geometry.Fragments() {
+			if fragment.Topology() != mdl.TopologyTriangleList {
+				continue
+			}
+			if fragment.Metadata().HasSkipCollision() {
+				continue
+			}
+			triangles = append(triangles, createCollisionTriangles(geometry, fragment)...)
+		}
+	}
+	return triangles
+}
+
+// createCollisionConvexHull creates a collision hull from the specified hull
+// triangles, such that the hull is positioned at the center of its vertices.
+func createCollisionConvexHull(triangles []mdl.CollisionTriangle) *mdl.CollisionConvexHull {
+	points := make(map[dprec.Vec3]struct{})
+	for _, triangle := range triangles {
+		points[triangle.A] = struct{}{}
+		points[triangle.B] = struct{}{}
+		points[triangle.C] = struct{}{}
+	}
+	center := dprec.ZeroVec3()
+	for point := range points {
+		center = dprec.Vec3Sum(center, point)
+	}
+	center = dprec.Vec3Quot(center, float64(len(points)))
+
+	localTriangles := make([]mdl.CollisionTriangle, len(triangles))
+	for i, triangle := range triangles {
+		localTriangles[i] = mdl.CollisionTriangle{
+			A: dprec.Vec3Diff(triangle.A, center),
+			B: dprec.Vec3Diff(triangle.B, center),
+			C: dprec.Vec3Diff(triangle.C, center),
+		}
+	}
+
+	hull := mdl.NewCollisionConvexHull()
+	hull.SetTranslation(center)
+	hull.SetRotation(dprec.IdentityQuat())
+	hull.SetTriangles(localTriangles)
+	return hull
+}
@@ -52,6 +52,129 @@ func AddFragment(fragmentProvider Provider[*mdl.Fragment]) Operation {
 	)
 }
 
+// OptimizeGeometry creates an operation that optimizes the target geometry,
+// or all geometries of the target model, for rendering.
+//
+// Duplicate vertices are welded, triangles are reordered for post-transform
+// vertex cache efficiency and reduced overdraw and attributes are quantized
+// into compact formats. Positions and tex coords are not allowed to deviate
+// by more than the tolerance, which can be configured with SetTolerance.
+// The size savings are logged for each geometry.
+func OptimizeGeometry(opts ...Operation) Operation {
+	type geometryHolder interface {
+		AllGeometries() []*mdl.Geometry
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			cfg := optimizeGeometryConfig{
+				tolerance: defaultQuantizationTolerance,
+			}
+			for _, opt := range opts {
+				if err := opt.Apply(&cfg); err != nil {
+					return fmt.Errorf("failed to configure geometry optimization: %w", err)
+				}
+			}
+
+			var geometries []*mdl.Geometry
+			switch target := target.(type) {
+			case *mdl.Geometry:
+				geometries = []*mdl.Geometry{target}
+			case geometryHolder:
+				geometries = target.AllGeometries()
+			default:
+				return fmt.Errorf("target %T is not a geometry or a geometry holder", target)
+			}
+
+			for _, geometry := range geometries {
+				stats, err := optimizeGeometry(geometry, cfg.tolerance)
+				if err != nil {
+					return fmt.Errorf("error optimizing geometry %q: %w", geometry.Name(), err)
+				}
+				logGeometryOptimization(geometry, stats)
+			}
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("optimize-geometry", opts)
+		},
+	)
+}
+
+// GenerateTangents creates an operation that computes MikkTSpace-compatible
+// tangents for the target geometry, or all geometries of the target model,
+// from their normals and primary tex coords. Existing tangents are replaced.
+//
+// Tangents are generated automatically for glTF meshes that use a normal
+// map but lack tangents, so this is mostly needed for geometries that are
+// constructed or modified by other means.
+func GenerateTangents() Operation {
+	type geometryHolder interface {
+		AllGeometries() []*mdl.Geometry
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			var geometries []*mdl.Geometry
+			switch target := target.(type) {
+			case *mdl.Geometry:
+				geometries = []*mdl.Geometry{target}
+			case geometryHolder:
+				geometries = target.AllGeometries()
+			default:
+				return fmt.Errorf("target %T is not a geometry or a geometry holder", target)
+			}
+
+			requiredFormat := mdl.VertexFormatCoord | mdl.VertexFormatNormal | mdl.VertexFormatTexCoord
+			for _, geometry := range geometries {
+				if geometry.Format()&requiredFormat != requiredFormat {
+					return fmt.Errorf("geometry %q lacks coords, normals or tex coords", geometry.Name())
+				}
+				generateGeometryTangents(geometry, geometry.Fragments(), 0)
+			}
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("generate-tangents")
+		},
+	)
+}
+
+// SetTolerance sets the maximum error that the target is allowed to
+// introduce.
+func SetTolerance(toleranceProvider Provider[float64]) Operation {
+	type toleranceConfigurable interface {
+		SetTolerance(float64)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			tolerance, err := toleranceProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting tolerance: %w", err)
+			}
+
+			configurable, ok := target.(toleranceConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T does not support tolerance", target)
+			}
+			configurable.SetTolerance(tolerance)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-tolerance", toleranceProvider)
+		},
+	)
+}
+
 // TODO: Make it possible to add pre-defined shapes to a geometry:
 // func AddBox(opts ...Operation) Operation {
 // }
@@ -171,6 +171,38 @@ func SetRefractionTexture(textureProvider Provider[*mdl.Texture]) Operation {
 	)
 }
 
+// SetIrradianceSH9 configures the target to use the specified spherical
+// harmonics coefficients for diffuse lighting instead of its refraction
+// texture.
+func SetIrradianceSH9(coefficientsProvider Provider[mdl.SH9]) Operation {
+	type irradianceSH9Configurable interface {
+		SetIrradianceSH9(mdl.SH9)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			coefficients, err := coefficientsProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting irradiance coefficients: %w", err)
+			}
+
+			configurable, ok := target.(irradianceSH9Configurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with irradiance coefficients", target)
+			}
+			configurable.SetIrradianceSH9(coefficients)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-irradiance-sh9", coefficientsProvider)
+		},
+	)
+}
+
 // SetCastShadow configures the cast shadow of the target.
 func SetCastShadow(castShadowProvider Provider[bool]) Operation {
 	return FuncOperation(
@@ -222,6 +222,34 @@ func SetBlending(modeProvider Provider[bool]) Operation {
 	)
 }
 
+// SetAdditiveBlending creates an operation that sets whether the target
+// blends additively.
+func SetAdditiveBlending(additiveProvider Provider[bool]) Operation {
+	type additiveBlendingSetter interface {
+		SetAdditiveBlending(bool)
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			additive, err := additiveProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting additive blending mode: %w", err)
+			}
+			setter, ok := target.(additiveBlendingSetter)
+			if !ok {
+				return fmt.Errorf("target %T is not an additive blending setter", target)
+			}
+			setter.SetAdditiveBlending(additive)
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-additive-blending", additiveProvider)
+		},
+	)
+}
+
 // SetLayer creates an operation that sets the layer of the target.
 func SetLayer(modeProvider Provider[int]) Operation {
 	type layerSetter interface {
@@ -0,0 +1,233 @@
+package dsl
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+const (
+	defaultLODLevelCount = 3
+	defaultLODReduction  = 0.5
+	defaultLODScreenSize = 0.25
+)
+
+// GenerateLODs creates an operation that generates simplified levels of
+// detail for the target mesh definition, or all mesh definitions of the
+// target model, replacing any existing ones.
+//
+// Each level keeps a portion of the triangles of the previous one, as
+// configured with SetReduction, and is used once the mesh covers less than
+// a portion of the viewport height, as configured with SetScreenSize, where
+// the screen size is halved for each subsequent level. The number of levels
+// can be configured with SetLevelCount. Generation stops early when a mesh
+// cannot be simplified any further.
+//
+// Any geometry optimization should be applied after this operation, so
+// that the generated geometries are optimized as well.
+func GenerateLODs(opts ...Operation) Operation {
+	type meshDefinitionHolder interface {
+		AllMeshDefinitions() []*mdl.MeshDefinition
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			cfg := generateLODsConfig{
+				levelCount: defaultLODLevelCount,
+				reduction:  defaultLODReduction,
+				screenSize: defaultLODScreenSize,
+			}
+			for _, opt := range opts {
+				if err := opt.Apply(&cfg); err != nil {
+					return fmt.Errorf("failed to configure lod generation: %w", err)
+				}
+			}
+			if cfg.reduction <= 0.0 || cfg.reduction >= 1.0 {
+				return fmt.Errorf("lod reduction %f is not in the range (0, 1)", cfg.reduction)
+			}
+
+			var definitions []*mdl.MeshDefinition
+			switch target := target.(type) {
+			case *mdl.MeshDefinition:
+				definitions = []*mdl.MeshDefinition{target}
+			case meshDefinitionHolder:
+				definitions = target.AllMeshDefinitions()
+			default:
+				return fmt.Errorf("target %T is not a mesh definition or a mesh definition holder", target)
+			}
+
+			// Definitions that share a geometry should share the simplified
+			// geometries as well.
+			lodsFromGeometry := make(map[*mdl.Geometry][]mdl.MeshLOD)
+			for _, definition := range definitions {
+				geometry := definition.Geometry()
+				lods, ok := lodsFromGeometry[geometry]
+				if !ok {
+					lods = generateLODs(geometry, cfg)
+					lodsFromGeometry[geometry] = lods
+				}
+				definition.SetLODs(lods)
+			}
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("generate-lods", opts)
+		},
+	)
+}
+
+// SetLevelCount configures the number of levels of the target.
+func SetLevelCount(countProvider Provider[int]) Operation {
+	type levelCountConfigurable interface {
+		SetLevelCount(int)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			count, err := countProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting level count: %w", err)
+			}
+
+			configurable, ok := target.(levelCountConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with level count", target)
+			}
+			configurable.SetLevelCount(count)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-level-count", countProvider)
+		},
+	)
+}
+
+// SetReduction configures the portion of elements that the target keeps
+// at each step.
+func SetReduction(reductionProvider Provider[float64]) Operation {
+	type reductionConfigurable interface {
+		SetReduction(float64)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			reduction, err := reductionProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting reduction: %w", err)
+			}
+
+			configurable, ok := target.(reductionConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with reduction", target)
+			}
+			configurable.SetReduction(reduction)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-reduction", reductionProvider)
+		},
+	)
+}
+
+// SetScreenSize configures the portion of the viewport height that the
+// target relates to.
+func SetScreenSize(sizeProvider Provider[float64]) Operation {
+	type screenSizeConfigurable interface {
+		SetScreenSize(float64)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			size, err := sizeProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting screen size: %w", err)
+			}
+
+			configurable, ok := target.(screenSizeConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with screen size", target)
+			}
+			configurable.SetScreenSize(size)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-screen-size", sizeProvider)
+		},
+	)
+}
+
+type generateLODsConfig struct {
+	levelCount int
+	reduction  float64
+	screenSize float64
+}
+
+func (c *generateLODsConfig) SetLevelCount(count int) {
+	c.levelCount = count
+}
+
+func (c *generateLODsConfig) SetReduction(reduction float64) {
+	c.reduction = reduction
+}
+
+func (c *generateLODsConfig) SetScreenSize(size float64) {
+	c.screenSize = size
+}
+
+// generateLODs creates the levels of detail of the specified geometry. Each
+// level is simplified from the previous one.
+func generateLODs(geometry *mdl.Geometry, cfg generateLODsConfig) []mdl.MeshLOD {
+	var result []mdl.MeshLOD
+
+	source := geometry
+	sourceTriangles := geometryTriangleCount(geometry)
+	screenSize := cfg.screenSize
+	for level := range cfg.levelCount {
+		targetTriangles := int(float64(sourceTriangles) * cfg.reduction)
+		if targetTriangles == 0 {
+			break
+		}
+		lodGeometry, lodTriangles := simplifyGeometry(source, targetTriangles)
+		if float64(lodTriangles) > float64(sourceTriangles)*(1.0+cfg.reduction)/2.0 {
+			logger.Debug("Geometry cannot be simplified further",
+				slog.String("geometry", geometry.Name()),
+				slog.Int("level", level+1),
+				slog.Int("triangles", lodTriangles),
+			)
+			break
+		}
+		lodGeometry.SetName(fmt.Sprintf("%s_lod%d", geometry.Name(), level+1))
+
+		logger.Info("Generated geometry LOD",
+			slog.String("geometry", geometry.Name()),
+			slog.Int("level", level+1),
+			slog.Int("triangles_before", sourceTriangles),
+			slog.Int("triangles_after", lodTriangles),
+			slog.Float64("screen_size", screenSize),
+		)
+		result = append(result, mdl.MeshLOD{
+			Geometry:   lodGeometry,
+			ScreenSize: screenSize,
+		})
+
+		source = lodGeometry
+		sourceTriangles = lodTriangles
+		screenSize /= 2.0
+	}
+	return result
+}
@@ -3,13 +3,17 @@ package dsl
 import (
 	"fmt"
 
+	"github.com/mokiat/lacking/core/resource"
 	"github.com/mokiat/lacking/game/asset/mdl"
 )
 
 // AppendModel creates an operation that appends the contents
 // of the provided model to the target model.
 func AppendModel(modelProvider Provider[*mdl.Model]) Operation {
-	return FuncOperation(
+	return DescribedOperation(ProviderInfo{
+		Name:   "append-model",
+		Inputs: []Digestable{modelProvider},
+	}, FuncOperation(
 		// apply function
 		func(target any) error {
 			model, err := modelProvider.Get()
@@ -35,7 +39,7 @@ func AppendModel(modelProvider Provider[*mdl.Model]) Operation {
 		func() ([]byte, error) {
 			return CreateDigest("append-model", modelProvider)
 		},
-	)
+	))
 }
 
 // ForceCollision creates an operation that forces the target
@@ -86,6 +90,36 @@ func OnlyAnimations() Operation {
 	)
 }
 
+// WithResourceLocator creates an operation that configures the target to
+// open files referenced by a model (e.g. external images and buffers)
+// through the specified locator instead of the file system.
+//
+// The locator receives slash-separated paths that are relative to the
+// directory of the model.
+func WithResourceLocator(locator resource.Locator) Operation {
+	type locatorConfigurable interface {
+		SetResourceLocator(resource.Locator)
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			configurable, ok := target.(locatorConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable for resource locator", target)
+			}
+			configurable.SetResourceLocator(locator)
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			// NOTE: The contents of the located files are part of the
+			// model digest, so the locator itself need not be digested.
+			return CreateDigest("with-resource-locator")
+		},
+	)
+}
+
 // EditMaterial creates an operation that edits the material with the
 // provided name in the target node holder.
 func EditMaterial(name string, opts ...Operation) Operation {
@@ -118,6 +152,47 @@ func EditMaterial(name string, opts ...Operation) Operation {
 	)
 }
 
+// EditNode creates an operation that edits the node with the provided name
+// in the target node holder.
+func EditNode(name string, opts ...Operation) Operation {
+	type nodeHolder interface {
+		Nodes() []*mdl.Node
+	}
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			nodeHolder, ok := target.(nodeHolder)
+			if !ok {
+				return fmt.Errorf("target %T is not a node holder", target)
+			}
+			node := findNode(nodeHolder.Nodes(), name)
+			if node == nil {
+				return fmt.Errorf("node %q not found", name)
+			}
+			for _, opt := range opts {
+				if err := opt.Apply(node); err != nil {
+					return fmt.Errorf("error applying node operation: %w", err)
+				}
+			}
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("edit-node", name, opts)
+		},
+	)
+}
+
+func findNode(nodes []*mdl.Node, name string) *mdl.Node {
+	for _, node := range flattenNodes(nodes) {
+		if node.Name() == name {
+			return node
+		}
+	}
+	return nil
+}
+
 func findMaterial(nodes []*mdl.Node, name string) *mdl.Material {
 	nodes = flattenNodes(nodes)
 	for _, node := range nodes {
@@ -63,3 +63,171 @@ func SetGenerateMipmaps(generateMipmapsProvider Provider[bool]) Operation {
 		},
 	)
 }
+
+// SetLinear configures whether the target holds data in linear space, as
+// opposed to sRGB color.
+func SetLinear(linearProvider Provider[bool]) Operation {
+	type linearConfigurable interface {
+		SetLinear(bool)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			linear, err := linearProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting linear flag: %w", err)
+			}
+
+			configurable, ok := target.(linearConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with linear flag", target)
+			}
+			configurable.SetLinear(linear)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-linear", linearProvider)
+		},
+	)
+}
+
+// PrecomputeMipmaps configures the target texture to have its full mipmap
+// chain computed during asset processing, instead of at runtime.
+//
+// The filter can be configured with SetMipmapFilter, where the default is
+// MipmapFilterKaiser. Textures that are used for alpha testing should use
+// SetAlphaCoverage, so that they do not fade out in the distance, and
+// normal textures should use SetNormalMap. Textures that are not linear,
+// as configured with SetLinear, are filtered in linear space.
+func PrecomputeMipmaps(opts ...Operation) Operation {
+	type precomputedMipmapsConfigurable interface {
+		SetPrecomputedMipmaps(mipmapConfig)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			cfg := mipmapConfig{
+				filter: MipmapFilterKaiser,
+			}
+			for _, opt := range opts {
+				if err := opt.Apply(&cfg); err != nil {
+					return fmt.Errorf("failed to configure mipmap generation: %w", err)
+				}
+			}
+			if cfg.alphaThreshold < 0.0 || cfg.alphaThreshold >= 1.0 {
+				return fmt.Errorf("alpha coverage threshold %f is not in the range [0, 1)", cfg.alphaThreshold)
+			}
+
+			configurable, ok := target.(precomputedMipmapsConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with precomputed mipmaps", target)
+			}
+			configurable.SetPrecomputedMipmaps(cfg)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("precompute-mipmaps", opts)
+		},
+	)
+}
+
+// SetMipmapFilter configures the filter that the target uses to compute
+// mipmaps.
+func SetMipmapFilter(filterProvider Provider[MipmapFilter]) Operation {
+	type mipmapFilterConfigurable interface {
+		SetMipmapFilter(MipmapFilter)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			filter, err := filterProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting mipmap filter: %w", err)
+			}
+
+			configurable, ok := target.(mipmapFilterConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with mipmap filter", target)
+			}
+			configurable.SetMipmapFilter(filter)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-mipmap-filter", filterProvider)
+		},
+	)
+}
+
+// SetAlphaCoverage configures the target to preserve the portion of texels
+// that pass an alpha test with the specified threshold. A threshold of zero
+// disables this.
+func SetAlphaCoverage(thresholdProvider Provider[float64]) Operation {
+	type alphaCoverageConfigurable interface {
+		SetAlphaCoverage(float64)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			threshold, err := thresholdProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting alpha coverage threshold: %w", err)
+			}
+
+			configurable, ok := target.(alphaCoverageConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with alpha coverage", target)
+			}
+			configurable.SetAlphaCoverage(threshold)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-alpha-coverage", thresholdProvider)
+		},
+	)
+}
+
+// SetNormalMap configures whether the target holds tangent-space normals.
+func SetNormalMap(normalMapProvider Provider[bool]) Operation {
+	type normalMapConfigurable interface {
+		SetNormalMap(bool)
+	}
+
+	return FuncOperation(
+		// apply function
+		func(target any) error {
+			normalMap, err := normalMapProvider.Get()
+			if err != nil {
+				return fmt.Errorf("error getting normal map flag: %w", err)
+			}
+
+			configurable, ok := target.(normalMapConfigurable)
+			if !ok {
+				return fmt.Errorf("target %T is not configurable with normal map flag", target)
+			}
+			configurable.SetNormalMap(normalMap)
+
+			return nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("set-normal-map", normalMapProvider)
+		},
+	)
+}
@@ -0,0 +1,55 @@
+package dsl
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mokiat/gog/filter"
+)
+
+// GlobPathFilter returns a filter that accepts asset paths that match any of
+// the specified glob patterns. The patterns use the syntax of path.Match,
+// with the addition of a "**" segment, which matches any number of path
+// segments.
+//
+// If no patterns are specified, all paths are accepted.
+func GlobPathFilter(patterns ...string) (filter.Func[string], error) {
+	if len(patterns) == 0 {
+		return filter.True[string](), nil
+	}
+	filters := make([]filter.Func[string], len(patterns))
+	for i, pattern := range patterns {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+		patternSegments := strings.Split(pattern, "/")
+		filters[i] = func(candidate string) bool {
+			return matchSegments(patternSegments, strings.Split(candidate, "/"))
+		}
+	}
+	return filter.Or(filters...), nil
+}
+
+func matchSegments(pattern, candidate []string) bool {
+	if len(pattern) == 0 {
+		return len(candidate) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(candidate); i++ {
+			if matchSegments(pattern[1:], candidate[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(candidate) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], candidate[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], candidate[1:])
+}
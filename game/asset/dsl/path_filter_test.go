@@ -0,0 +1,61 @@
+package dsl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/asset/dsl"
+)
+
+var _ = Describe("GlobPathFilter", func() {
+	DescribeTable("matching",
+		func(pattern, candidate string, expected bool) {
+			filter, err := dsl.GlobPathFilter(pattern)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filter(candidate)).To(Equal(expected))
+		},
+		Entry("exact path", "models/tree.dat", "models/tree.dat", true),
+		Entry("different path", "models/tree.dat", "models/rock.dat", false),
+		Entry("wildcard within a segment", "models/*.dat", "models/tree.dat", true),
+		Entry("wildcard does not cross segments", "models/*.dat", "models/forest/tree.dat", false),
+
+		Entry("leading ** with a nested path", "**/tree.dat", "models/forest/tree.dat", true),
+		Entry("leading ** with no segments", "**/tree.dat", "tree.dat", true),
+		Entry("leading ** with a different name", "**/tree.dat", "models/rock.dat", false),
+
+		Entry("middle ** with no segments", "models/**/tree.dat", "models/tree.dat", true),
+		Entry("middle ** with one segment", "models/**/tree.dat", "models/forest/tree.dat", true),
+		Entry("middle ** with many segments", "models/**/tree.dat", "models/a/b/c/tree.dat", true),
+		Entry("middle ** with a different prefix", "models/**/tree.dat", "textures/forest/tree.dat", false),
+		Entry("middle ** with a different suffix", "models/**/tree.dat", "models/forest/tree.png", false),
+
+		Entry("trailing ** with one segment", "models/**", "models/tree.dat", true),
+		Entry("trailing ** with many segments", "models/**", "models/forest/tree.dat", true),
+		Entry("trailing ** with the directory itself", "models/**", "models", true),
+		Entry("trailing ** with a different prefix", "models/**", "textures/bark.png", false),
+		Entry("trailing ** with a similar prefix", "models/**", "models2/tree.dat", false),
+
+		Entry("** combined with a wildcard", "**/*.png", "textures/nature/bark.png", true),
+		Entry("** combined with a wildcard and a different extension", "**/*.png", "textures/nature/bark.jpg", false),
+		Entry("partial segment is not a **", "models/a**/tree.dat", "models/a/b/tree.dat", false),
+	)
+
+	It("accepts paths that match any of the patterns", func() {
+		filter, err := dsl.GlobPathFilter("models/**", "**/*.png")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter("models/tree.dat")).To(BeTrue())
+		Expect(filter("textures/bark.png")).To(BeTrue())
+		Expect(filter("textures/bark.jpg")).To(BeFalse())
+	})
+
+	It("accepts all paths when there are no patterns", func() {
+		filter, err := dsl.GlobPathFilter()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter("anything/at/all.dat")).To(BeTrue())
+	})
+
+	It("rejects malformed patterns", func() {
+		_, err := dsl.GlobPathFilter("models/[a.dat")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,46 @@
+package dsl
+
+import (
+	"fmt"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+// CreateAtlas packs images into one or more textures, called pages, and
+// keeps track of the tex coords of each image by name. Images are added
+// with AddAtlasImage.
+//
+// Packing uses the MaxRects algorithm. SetPadding configures the number of
+// transparent texels between images and SetExtrusion the number of texels by
+// which the edges of each image are repeated outwards, which prevents
+// neighbouring images from bleeding in when filtering. SetMaxSize limits
+// the size of each page, where the default is 2048. The pages can be
+// configured like 2D textures, for example with SetFormat or
+// SetMipmapping, and default to RGBA8 without mipmapping.
+//
+// The resulting atlas can be saved as a standalone asset, which can then be
+// loaded by the game and the UI layers.
+func CreateAtlas(opts ...Operation) Provider[*mdl.Atlas] {
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-atlas",
+		Inputs: operationInputs(opts),
+	}, OnceProvider(FuncProvider(
+		// get function
+		func() (*mdl.Atlas, error) {
+			cfg := atlasConfig{
+				maxSize: defaultAtlasMaxSize,
+			}
+			for _, opt := range opts {
+				if err := opt.Apply(&cfg); err != nil {
+					return nil, fmt.Errorf("failed to configure atlas: %w", err)
+				}
+			}
+			return packAtlas(cfg)
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("create-atlas", opts)
+		},
+	)))
+}
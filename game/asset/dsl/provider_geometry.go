@@ -4,7 +4,11 @@ import "github.com/mokiat/lacking/game/asset/mdl"
 
 // CreateGeometry creates a new geometry.
 func CreateGeometry(name string, opts ...Operation) Provider[*mdl.Geometry] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-geometry",
+		Detail: name,
+		Inputs: operationInputs(opts),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Geometry, error) {
 			geometry := mdl.NewGeometry()
@@ -22,7 +26,7 @@ func CreateGeometry(name string, opts ...Operation) Provider[*mdl.Geometry] {
 		func() ([]byte, error) {
 			return CreateDigest("create-geometry", name, opts)
 		},
-	))
+	)))
 }
 
 // CreateFragment creates a new geometry fragment.
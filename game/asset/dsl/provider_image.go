@@ -12,7 +12,10 @@ import (
 
 // OpenImage opens an image file from the provided path.
 func OpenImage(path string) Provider[*mdl.Image] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "open-image",
+		Detail: path,
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Image, error) {
 			file, err := os.Open(path)
@@ -31,18 +34,21 @@ func OpenImage(path string) Provider[*mdl.Image] {
 
 		// digest function
 		func() ([]byte, error) {
-			info, err := os.Stat(path)
+			contentDigest, err := FileContentDigest(path)
 			if err != nil {
-				return nil, fmt.Errorf("failed to stat file %q: %w", path, err)
+				return nil, err
 			}
-			return CreateDigest("open-image", path, info.ModTime())
+			return CreateDigest("open-image", contentDigest)
 		},
-	))
+	)))
 }
 
 // ResizedImage returns an image with the provided dimensions.
 func ResizedImage(imageProvider Provider[*mdl.Image], newWidthProvider, newHeightProvider Provider[int]) Provider[*mdl.Image] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "resized-image",
+		Inputs: []Digestable{imageProvider, newWidthProvider, newHeightProvider},
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Image, error) {
 			newWidth, err := newWidthProvider.Get()
@@ -66,13 +72,51 @@ func ResizedImage(imageProvider Provider[*mdl.Image], newWidthProvider, newHeigh
 		func() ([]byte, error) {
 			return CreateDigest("resized-image", imageProvider, newWidthProvider, newHeightProvider)
 		},
-	))
+	)))
+}
+
+// NormalMapFromHeight converts a height or bump map image into a
+// tangent-space normal map image. The strength scales the slopes of the
+// height map, where higher values produce more pronounced bumps.
+//
+// The height of each texel is taken from its luminance and the edges of the
+// image wrap around, so tiling height maps produce tiling normal maps. The
+// resulting image should be used with a linear texture.
+func NormalMapFromHeight(imageProvider Provider[*mdl.Image], strengthProvider Provider[float64]) Provider[*mdl.Image] {
+	return DescribedProvider(ProviderInfo{
+		Name:   "normal-map-from-height",
+		Inputs: []Digestable{imageProvider, strengthProvider},
+	}, OnceProvider(FuncProvider(
+		// get function
+		func() (*mdl.Image, error) {
+			strength, err := strengthProvider.Get()
+			if err != nil {
+				return nil, fmt.Errorf("error getting strength: %w", err)
+			}
+
+			image, err := imageProvider.Get()
+			if err != nil {
+				return nil, fmt.Errorf("error getting image: %w", err)
+			}
+			return normalMapFromHeight(image, strength), nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("normal-map-from-height", imageProvider, strengthProvider)
+		},
+	)))
 }
 
 // CubeImageFromEquirectangular creates a cube image from an
 // equirectangular image.
+//
+// The result is persisted in the artifact cache, if one is configured.
 func CubeImageFromEquirectangular(imageProvider Provider[*mdl.Image]) Provider[*mdl.CubeImage] {
-	return OnceProvider(FuncProvider(
+	return CachedProvider(DescribedProvider(ProviderInfo{
+		Name:   "cube-image-from-equirectangular",
+		Inputs: []Digestable{imageProvider},
+	}, FuncProvider(
 		// get function
 		func() (*mdl.CubeImage, error) {
 			image, err := imageProvider.Get()
@@ -101,12 +145,15 @@ func CubeImageFromEquirectangular(imageProvider Provider[*mdl.Image]) Provider[*
 		func() ([]byte, error) {
 			return CreateDigest("cube-image-from-equirectangular", imageProvider)
 		},
-	))
+	)))
 }
 
 // ResizedCubeImage returns a cube image with the provided dimensions.
 func ResizedCubeImage(imageProvider Provider[*mdl.CubeImage], newSizeProvider Provider[int]) Provider[*mdl.CubeImage] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "resized-cube-image",
+		Inputs: []Digestable{imageProvider, newSizeProvider},
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.CubeImage, error) {
 			newSize, err := newSizeProvider.Get()
@@ -125,13 +172,18 @@ func ResizedCubeImage(imageProvider Provider[*mdl.CubeImage], newSizeProvider Pr
 		func() ([]byte, error) {
 			return CreateDigest("resized-cube-image", imageProvider, newSizeProvider)
 		},
-	))
+	)))
 }
 
 // IrradianceCubeImage creates an irradiance cube image from the provided
 // HDR skybox cube image.
+//
+// The result is persisted in the artifact cache, if one is configured.
 func IrradianceCubeImage(imageProvider Provider[*mdl.CubeImage], opts ...Operation) Provider[*mdl.CubeImage] {
-	return OnceProvider(FuncProvider(
+	return CachedProvider(DescribedProvider(ProviderInfo{
+		Name:   "irradiance-cube-image",
+		Inputs: append([]Digestable{imageProvider}, operationInputs(opts)...),
+	}, FuncProvider(
 		// get function
 		func() (*mdl.CubeImage, error) {
 			var cfg irradianceConfig
@@ -154,13 +206,43 @@ func IrradianceCubeImage(imageProvider Provider[*mdl.CubeImage], opts ...Operati
 		func() ([]byte, error) {
 			return CreateDigest("irradiance-cube-image", imageProvider, opts)
 		},
-	))
+	)))
+}
+
+// IrradianceSH9 creates spherical harmonics irradiance coefficients from the
+// provided HDR skybox cube image. They can be used by an ambient light
+// instead of an irradiance cube image (see SetIrradianceSH9), which is
+// cheaper to bake and to evaluate but loses high-frequency detail.
+func IrradianceSH9(imageProvider Provider[*mdl.CubeImage]) Provider[mdl.SH9] {
+	return DescribedProvider(ProviderInfo{
+		Name:   "irradiance-sh9",
+		Inputs: []Digestable{imageProvider},
+	}, OnceProvider(FuncProvider(
+		// get function
+		func() (mdl.SH9, error) {
+			image, err := imageProvider.Get()
+			if err != nil {
+				return mdl.SH9{}, fmt.Errorf("error getting image: %w", err)
+			}
+			return mdl.BuildIrradianceSH9(image), nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("irradiance-sh9", imageProvider)
+		},
+	)))
 }
 
 // ReflectionCubeImages creates a reflection cube image mipmap set from the
 // provided HDR skybox cube image.
+//
+// The result is persisted in the artifact cache, if one is configured.
 func ReflectionCubeImages(imageProvider Provider[*mdl.CubeImage], opts ...Operation) Provider[[]*mdl.CubeImage] {
-	return OnceProvider(FuncProvider(
+	return CachedProvider(DescribedProvider(ProviderInfo{
+		Name:   "reflection-cube-images",
+		Inputs: append([]Digestable{imageProvider}, operationInputs(opts)...),
+	}, FuncProvider(
 		// get function
 		func() ([]*mdl.CubeImage, error) {
 			var cfg irradianceConfig
@@ -209,7 +291,7 @@ func ReflectionCubeImages(imageProvider Provider[*mdl.CubeImage], opts ...Operat
 		func() ([]byte, error) {
 			return CreateDigest("reflection-cube-images", imageProvider, opts)
 		},
-	))
+	)))
 }
 
 type irradianceConfig struct {
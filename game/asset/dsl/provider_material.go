@@ -5,7 +5,11 @@ import "github.com/mokiat/lacking/game/asset/mdl"
 // CreateMaterial creates a provider that will create a material with the
 // specified options.
 func CreateMaterial(name string, opts ...Operation) Provider[*mdl.Material] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-material",
+		Detail: name,
+		Inputs: operationInputs(opts),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Material, error) {
 			material := mdl.NewMaterial(name)
@@ -21,7 +25,7 @@ func CreateMaterial(name string, opts ...Operation) Provider[*mdl.Material] {
 		func() ([]byte, error) {
 			return CreateDigest("create-material", name, opts)
 		},
-	))
+	)))
 }
 
 // CreateMaterialPass creates a provider that will create a material pass
@@ -13,6 +13,7 @@ import (
 	"github.com/mokiat/gomath/dprec"
 	"github.com/mokiat/gomath/sprec"
 	"github.com/mokiat/gomath/stod"
+	"github.com/mokiat/lacking/core/resource"
 	"github.com/mokiat/lacking/game/asset/mdl"
 	"github.com/mokiat/lacking/util/gltfutil"
 	"github.com/qmuntal/gltf"
@@ -21,7 +22,10 @@ import (
 
 // CreateModel creates a new model with the specified name and operations.
 func CreateModel(operations ...Operation) Provider[*mdl.Model] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-model",
+		Inputs: operationInputs(operations),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Model, error) {
 			model := mdl.NewModel()
@@ -37,20 +41,22 @@ func CreateModel(operations ...Operation) Provider[*mdl.Model] {
 		func() ([]byte, error) {
 			return CreateDigest("create-model", operations)
 		},
-	))
+	)))
 }
 
 // OpenGLTFModel creates a new model provider that loads a model from the
 // specified path.
 func OpenGLTFModel(path string, opts ...Operation) Provider[*mdl.Model] {
-	return FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "open-gltf-model",
+		Detail: path,
+		Inputs: operationInputs(opts),
+	}, FuncProvider(
 		// get function
 		func() (*mdl.Model, error) {
-			var cfg openGLTFModelConfig
-			for _, opt := range opts {
-				if err := opt.Apply(&cfg); err != nil {
-					return nil, fmt.Errorf("failed to configure gltf model: %w", err)
-				}
+			cfg, err := newOpenGLTFModelConfig(opts)
+			if err != nil {
+				return nil, err
 			}
 
 			file, err := os.Open(path)
@@ -59,7 +65,8 @@ func OpenGLTFModel(path string, opts ...Operation) Provider[*mdl.Model] {
 			}
 			defer file.Close()
 
-			model, err := parseGLTFResource(file, cfg.forceCollision, cfg.onlyAnimations)
+			resolver := newGLTFResolver(path, cfg.locator)
+			model, err := parseGLTFResource(file, resolver, cfg.forceCollision, cfg.onlyAnimations)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse gltf model: %w", err)
 			}
@@ -68,18 +75,33 @@ func OpenGLTFModel(path string, opts ...Operation) Provider[*mdl.Model] {
 
 		// digest function
 		func() ([]byte, error) {
-			info, err := os.Stat(path)
+			cfg, err := newOpenGLTFModelConfig(opts)
+			if err != nil {
+				return nil, err
+			}
+			contentDigest, err := gltfContentDigest(path, newGLTFResolver(path, cfg.locator))
 			if err != nil {
-				return nil, fmt.Errorf("failed to stat file %q: %w", path, err)
+				return nil, err
 			}
-			return CreateDigest("opengl-gltf-model", path, info.ModTime(), opts)
+			return CreateDigest("opengl-gltf-model", contentDigest, opts)
 		},
-	)
+	))
 }
 
 type openGLTFModelConfig struct {
 	forceCollision bool
 	onlyAnimations bool
+	locator        resource.Locator
+}
+
+func newOpenGLTFModelConfig(opts []Operation) (*openGLTFModelConfig, error) {
+	var cfg openGLTFModelConfig
+	for _, opt := range opts {
+		if err := opt.Apply(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to configure gltf model: %w", err)
+		}
+	}
+	return &cfg, nil
 }
 
 func (c *openGLTFModelConfig) SetForceCollision(value bool) {
@@ -90,6 +112,82 @@ func (c *openGLTFModelConfig) SetOnlyAnimations(value bool) {
 	c.onlyAnimations = value
 }
 
+func (c *openGLTFModelConfig) SetResourceLocator(locator resource.Locator) {
+	c.locator = locator
+}
+
+// OpenOBJModel creates a new model provider that loads a Wavefront OBJ
+// model, along with the MTL material libraries that it references, from
+// the specified path.
+func OpenOBJModel(path string, opts ...Operation) Provider[*mdl.Model] {
+	return DescribedProvider(ProviderInfo{
+		Name:   "open-obj-model",
+		Detail: path,
+		Inputs: operationInputs(opts),
+	}, FuncProvider(
+		// get function
+		func() (*mdl.Model, error) {
+			cfg, err := newOpenOBJModelConfig(opts)
+			if err != nil {
+				return nil, err
+			}
+
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open model file %q: %w", path, err)
+			}
+			defer file.Close()
+
+			resolver := newGLTFResolver(path, cfg.locator)
+			gltfDoc, err := parseOBJDocument(file, resolver)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse obj model: %w", err)
+			}
+			model, err := buildModelResource(gltfDoc, resolver, cfg.forceCollision, false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build obj model: %w", err)
+			}
+			return model, nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			cfg, err := newOpenOBJModelConfig(opts)
+			if err != nil {
+				return nil, err
+			}
+			contentDigest, err := objContentDigest(path, newGLTFResolver(path, cfg.locator))
+			if err != nil {
+				return nil, err
+			}
+			return CreateDigest("open-obj-model", contentDigest, opts)
+		},
+	))
+}
+
+type openOBJModelConfig struct {
+	forceCollision bool
+	locator        resource.Locator
+}
+
+func newOpenOBJModelConfig(opts []Operation) (*openOBJModelConfig, error) {
+	var cfg openOBJModelConfig
+	for _, opt := range opts {
+		if err := opt.Apply(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to configure obj model: %w", err)
+		}
+	}
+	return &cfg, nil
+}
+
+func (c *openOBJModelConfig) SetForceCollision(value bool) {
+	c.forceCollision = value
+}
+
+func (c *openOBJModelConfig) SetResourceLocator(locator resource.Locator) {
+	c.locator = locator
+}
+
 // NOTE: glTF allows a sub-mesh to use totally different
 // mesh vertices and indices. It may even reuse part of the
 // attributes but use dedicated buffers for the remaining ones.
@@ -103,22 +201,31 @@ func (c *openGLTFModelConfig) SetOnlyAnimations(value bool) {
 // mesh partitioning, we would be getting rid of the unnecessary
 // partitioning.
 
-func parseGLTFResource(in io.Reader, forceCollision, onlyAnimations bool) (*mdl.Model, error) {
+func parseGLTFResource(in io.Reader, resolver *gltfResolver, forceCollision, onlyAnimations bool) (*mdl.Model, error) {
 	gltfDoc := new(gltf.Document)
-	if err := gltf.NewDecoder(in).Decode(gltfDoc); err != nil {
+	if err := gltf.NewDecoderFS(in, resolver.FS()).Decode(gltfDoc); err != nil {
 		return nil, fmt.Errorf("failed to parse gltf model: %w", err)
 	}
-	return BuildModelResource(gltfDoc, forceCollision, onlyAnimations)
+	return buildModelResource(gltfDoc, resolver, forceCollision, onlyAnimations)
 }
 
+// BuildModelResource converts the specified glTF document into a model.
+//
+// Images that reference external files are not supported, since there is
+// no way to know where the document was loaded from. Use OpenGLTFModel
+// for such documents instead.
 func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations bool) (*mdl.Model, error) {
+	return buildModelResource(gltfDoc, nil, forceCollision, onlyAnimations)
+}
+
+func buildModelResource(gltfDoc *gltf.Document, resolver *gltfResolver, forceCollision, onlyAnimations bool) (*mdl.Model, error) {
 	model := mdl.NewModel()
 
 	// build images
 	imagesFromIndex := make(map[int]*mdl.Image)
 	if !onlyAnimations {
 		for i, gltfImage := range gltfDoc.Images {
-			img, err := openGLTFImage(gltfDoc, gltfImage)
+			img, err := openGLTFImage(gltfDoc, gltfImage, resolver)
 			if err != nil {
 				return nil, fmt.Errorf("error loading image: %w", err)
 			}
@@ -200,27 +307,29 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 	if !onlyAnimations {
 		for i, gltfMaterial := range gltfDoc.Materials {
 			var (
-				color          sprec.Vec4
-				metallic       float32
-				roughness      float32
-				normalScale    float32
-				alphaThreshold float32
-
-				colorTextureIndex             *int
-				metallicRoughnessTextureIndex *int
-				normalTextureIndex            *int
+				color             sprec.Vec4
+				metallic          float32
+				roughness         float32
+				normalScale       float32
+				occlusionStrength float32
+				emissive          sprec.Vec3
+				alphaThreshold    float32
+
+				colorTexture             *gltfutil.TextureReference
+				metallicRoughnessTexture *gltfutil.TextureReference
+				normalTexture            *gltfutil.TextureReference
+				occlusionTexture         *gltfutil.TextureReference
+				emissiveTexture          *gltfutil.TextureReference
 			)
 
 			if gltfPBR := gltfMaterial.PBRMetallicRoughness; gltfPBR != nil {
 				color = gltfutil.BaseColor(gltfPBR)
 				metallic = float32(gltfPBR.MetallicFactorOrDefault())
 				roughness = float32(gltfPBR.RoughnessFactorOrDefault())
-				if texIndex := gltfutil.ColorTextureIndex(gltfDoc, gltfPBR); texIndex != nil {
-					colorTextureIndex = texIndex
-				}
-				if texIndex := gltfutil.MetallicRoughnessTextureIndex(gltfDoc, gltfPBR); texIndex != nil {
-					metallicRoughnessTextureIndex = texIndex
-					sampler := samplersFromIndex[*texIndex]
+				colorTexture = gltfutil.ColorTexture(gltfDoc, gltfPBR)
+				if texRef := gltfutil.MetallicRoughnessTexture(gltfDoc, gltfPBR); texRef != nil {
+					metallicRoughnessTexture = texRef
+					sampler := samplersFromIndex[texRef.Index]
 					sampler.Texture().SetLinear(true)
 				}
 			} else {
@@ -231,21 +340,35 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 
 			alphaThreshold = float32(gltfMaterial.AlphaCutoffOrDefault())
 
-			if texIndex, texScale := gltfutil.NormalTextureIndexScale(gltfDoc, gltfMaterial); texIndex != nil {
-				normalTextureIndex = texIndex
+			if texRef, texScale := gltfutil.NormalTextureScale(gltfDoc, gltfMaterial); texRef != nil {
+				normalTexture = texRef
 				normalScale = texScale
-				sampler := samplersFromIndex[*texIndex]
+				sampler := samplersFromIndex[texRef.Index]
 				sampler.Texture().SetLinear(true)
 			} else {
 				normalScale = 1.0
 			}
 
+			if texRef, texStrength := gltfutil.OcclusionTextureStrength(gltfDoc, gltfMaterial); texRef != nil {
+				occlusionTexture = texRef
+				occlusionStrength = texStrength
+				sampler := samplersFromIndex[texRef.Index]
+				sampler.Texture().SetLinear(true)
+			} else {
+				occlusionStrength = 1.0
+			}
+
+			emissive = gltfutil.EmissiveColor(gltfMaterial)
+			emissiveTexture = gltfutil.EmissiveTexture(gltfDoc, gltfMaterial)
+			hasEmission := emissive.X > 0.0 || emissive.Y > 0.0 || emissive.Z > 0.0
+
 			geometryShader := mdl.NewShader(mdl.ShaderTypeGeometry)
 			geometryShader.SetSourceCode(createPBRShader(pbrShaderConfig{
-				hasColorTexture:             colorTextureIndex != nil,
-				hasMetallicRoughnessTexture: metallicRoughnessTextureIndex != nil,
-				hasNormalTexture:            normalTextureIndex != nil,
-				hasAlphaTesting:             gltfMaterial.AlphaMode == gltf.AlphaMask,
+				colorTexture:             colorTexture,
+				metallicRoughnessTexture: metallicRoughnessTexture,
+				normalTexture:            normalTexture,
+				occlusionTexture:         occlusionTexture,
+				hasAlphaTesting:          gltfMaterial.AlphaMode == gltf.AlphaMask,
 			}))
 
 			geometryPass := mdl.NewMaterialPass()
@@ -287,15 +410,57 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 			material.SetProperty("metallic", metallic)
 			material.SetProperty("roughness", roughness)
 			material.SetProperty("normalScale", normalScale)
+			material.SetProperty("occlusionStrength", occlusionStrength)
 			material.SetProperty("alphaThreshold", alphaThreshold)
-			if colorTextureIndex != nil {
-				material.SetSampler("colorSampler", samplersFromIndex[*colorTextureIndex])
+			if colorTexture != nil {
+				material.SetSampler("colorSampler", samplersFromIndex[colorTexture.Index])
+				setPBRTextureTransform(material, "color", colorTexture)
 			}
-			if metallicRoughnessTextureIndex != nil {
-				material.SetSampler("metallicRoughnessSampler", samplersFromIndex[*metallicRoughnessTextureIndex])
+			if metallicRoughnessTexture != nil {
+				material.SetSampler("metallicRoughnessSampler", samplersFromIndex[metallicRoughnessTexture.Index])
+				setPBRTextureTransform(material, "metallicRoughness", metallicRoughnessTexture)
 			}
-			if normalTextureIndex != nil {
-				material.SetSampler("normalSampler", samplersFromIndex[*normalTextureIndex])
+			if normalTexture != nil {
+				material.SetSampler("normalSampler", samplersFromIndex[normalTexture.Index])
+				setPBRTextureTransform(material, "normal", normalTexture)
+			}
+			if occlusionTexture != nil {
+				material.SetSampler("occlusionSampler", samplersFromIndex[occlusionTexture.Index])
+				setPBRTextureTransform(material, "occlusion", occlusionTexture)
+			}
+
+			// The lighting pass has no notion of emission, so emissive
+			// materials get an extra forward pass that adds their emission
+			// on top of the lit scene.
+			if hasEmission {
+				emissiveShader := mdl.NewShader(mdl.ShaderTypeForward)
+				emissiveShader.SetSourceCode(createPBREmissiveShader(pbrEmissiveShaderConfig{
+					emissiveTexture: emissiveTexture,
+					colorTexture:    colorTexture,
+					hasAlphaTesting: gltfMaterial.AlphaMode == gltf.AlphaMask,
+				}))
+
+				emissivePass := mdl.NewMaterialPass()
+				emissivePass.SetLayer(0)
+				if gltfMaterial.DoubleSided {
+					emissivePass.SetCulling(mdl.CullModeNone)
+				} else {
+					emissivePass.SetCulling(mdl.CullModeBack)
+				}
+				emissivePass.SetFrontFace(mdl.FaceOrientationCCW)
+				emissivePass.SetDepthTest(true)
+				emissivePass.SetDepthWrite(false)
+				emissivePass.SetDepthComparison(mdl.ComparisonLessOrEqual)
+				emissivePass.SetBlending(true)
+				emissivePass.SetAdditiveBlending(true)
+				emissivePass.SetShader(emissiveShader)
+
+				material.AddForwardPass(emissivePass)
+				material.SetProperty("emissive", emissive)
+				if emissiveTexture != nil {
+					material.SetSampler("emissiveSampler", samplersFromIndex[emissiveTexture.Index])
+					setPBRTextureTransform(material, "emissive", emissiveTexture)
+				}
 			}
 
 			materialFromIndex[i] = material
@@ -329,9 +494,23 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 			meshDefinition.SetName(gltfMesh.Name)
 			meshDefinition.SetGeometry(geometry)
 
-			indexFromVertex := make(map[mdl.Vertex]int)
+			morphTargetCount := gltfutil.MorphTargetCount(gltfMesh)
+			for j, name := range gltfutil.MorphTargetNames(gltfMesh) {
+				target := mdl.NewMorphTarget()
+				target.SetName(name)
+				if j < len(gltfMesh.Weights) {
+					target.SetDefaultWeight(gltfMesh.Weights[j])
+				}
+				geometry.AddMorphTarget(target)
+			}
+
+			indexFromVertex := make(map[gltfVertexKey]int)
+
+			// Fragments that use a normal map but lack tangents, grouped by
+			// the tex coord set of the normal map.
+			tangentlessFragments := make(map[int][]*mdl.Fragment)
 
-			for _, gltfPrimitive := range gltfMesh.Primitives {
+			for primitiveIndex, gltfPrimitive := range gltfMesh.Primitives {
 				indexOffset := geometry.IndexOffset() // this needs to happen first
 
 				gltfIndices, err := gltfutil.Indices(gltfDoc, gltfPrimitive)
@@ -354,6 +533,10 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 				if err != nil {
 					return nil, fmt.Errorf("error reading tex coords: %w", err)
 				}
+				gltfTexCoords1, err := gltfutil.TexCoord1s(gltfDoc, gltfPrimitive)
+				if err != nil {
+					return nil, fmt.Errorf("error reading secondary tex coords: %w", err)
+				}
 				gltfColors, err := gltfutil.Color0s(gltfDoc, gltfPrimitive)
 				if err != nil {
 					return nil, fmt.Errorf("error reading colors: %w", err)
@@ -366,6 +549,18 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 				if err != nil {
 					return nil, fmt.Errorf("error reading joints: %w", err)
 				}
+				gltfMorphCoords := make([][]sprec.Vec3, morphTargetCount)
+				gltfMorphNormals := make([][]sprec.Vec3, morphTargetCount)
+				for j := range morphTargetCount {
+					gltfMorphCoords[j], err = gltfutil.MorphTargetCoords(gltfDoc, gltfPrimitive, j)
+					if err != nil {
+						return nil, fmt.Errorf("error reading morph target coords: %w", err)
+					}
+					gltfMorphNormals[j], err = gltfutil.MorphTargetNormals(gltfDoc, gltfPrimitive, j)
+					if err != nil {
+						return nil, fmt.Errorf("error reading morph target normals: %w", err)
+					}
+				}
 
 				geometryFormat := geometry.Format()
 				if gltfCoords != nil {
@@ -380,6 +575,9 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 				if gltfTexCoords != nil {
 					geometryFormat |= mdl.VertexFormatTexCoord
 				}
+				if gltfTexCoords1 != nil {
+					geometryFormat |= mdl.VertexFormatTexCoord1
+				}
 				if gltfColors != nil {
 					geometryFormat |= mdl.VertexFormatColor
 				}
@@ -405,6 +603,9 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 					if gltfTexCoords != nil {
 						vertex.TexCoord = gltfTexCoords[gltfIndex]
 					}
+					if gltfTexCoords1 != nil {
+						vertex.TexCoord1 = gltfTexCoords1[gltfIndex]
+					}
 					if gltfColors != nil {
 						vertex.Color = gltfColors[gltfIndex]
 					}
@@ -415,13 +616,34 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 						vertex.Joints = gltfJoints[gltfIndex]
 					}
 
-					if index, ok := indexFromVertex[vertex]; ok {
+					key := gltfVertexKey{
+						vertex: vertex,
+					}
+					if morphTargetCount > 0 {
+						// Equal vertices can still have different morph
+						// deltas, so only the same glTF vertex is reused.
+						key.primitive = primitiveIndex
+						key.index = gltfIndex
+					}
+
+					if index, ok := indexFromVertex[key]; ok {
 						geometry.AddIndex(index)
 					} else {
 						index = geometry.VertexOffset()
 						geometry.AddVertex(vertex)
 						geometry.AddIndex(index)
-						indexFromVertex[vertex] = index
+						indexFromVertex[key] = index
+
+						for j, target := range geometry.MorphTargets() {
+							var delta mdl.MorphDelta
+							if gltfMorphCoords[j] != nil {
+								delta.Coord = gltfMorphCoords[j][gltfIndex]
+							}
+							if gltfMorphNormals[j] != nil {
+								delta.Normal = gltfMorphNormals[j][gltfIndex]
+							}
+							target.SetDelta(index, delta)
+						}
 					}
 				}
 
@@ -457,11 +679,32 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 				}
 				geometry.AddFragment(fragment)
 
+				if gltfTangents == nil && gltfNormals != nil && fragment.Topology() == mdl.TopologyTriangleList {
+					gltfMaterial := gltfDoc.Materials[*gltfPrimitive.Material]
+					if normalTexture, _ := gltfutil.NormalTextureScale(gltfDoc, gltfMaterial); normalTexture != nil {
+						hasTexCoords := gltfTexCoords != nil
+						if normalTexture.TexCoord == 1 {
+							hasTexCoords = gltfTexCoords1 != nil
+						}
+						if hasTexCoords {
+							tangentlessFragments[normalTexture.TexCoord] = append(tangentlessFragments[normalTexture.TexCoord], fragment)
+						}
+					}
+				}
+
 				if (geometry.Metadata().HasCollision() || forceCollision) && !fragment.Metadata().HasSkipCollision() {
 					bodyDefinition.AddCollisionMeshes(createCollisionMeshes(geometry, fragment))
 				}
 			}
 
+			for texCoordSet, fragments := range tangentlessFragments {
+				generateGeometryTangents(geometry, fragments, texCoordSet)
+				logger.Info("Generated missing tangents",
+					slog.String("geometry", geometry.Name()),
+					slog.Int("fragments", len(fragments)),
+				)
+			}
+
 			meshDefinition.SetName(gltfMesh.Name)
 			meshDefinition.SetGeometry(geometry)
 			meshDefinitionFromIndex[i] = meshDefinition
@@ -536,6 +779,45 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 		}
 	}
 
+	for i, gltfCamera := range gltfDoc.Cameras {
+		if gltfCamera.Perspective == nil && gltfCamera.Orthographic == nil {
+			return nil, fmt.Errorf("camera %d (%q) has neither a perspective nor an orthographic projection", i, gltfCamera.Name)
+		}
+	}
+
+	createCamera := func(gltfNode *gltf.Node) *mdl.Camera {
+		gltfCamera := gltfDoc.Cameras[*gltfNode.Camera]
+
+		name := gltfCamera.Name
+		if name == "" {
+			name = gltfNode.Name
+		}
+		camera := mdl.NewCamera(name)
+		switch {
+		case gltfCamera.Perspective != nil:
+			perspective := gltfCamera.Perspective
+			camera.SetProjection(mdl.CameraProjectionPerspective)
+			camera.SetFoV(dprec.Radians(perspective.Yfov))
+			camera.SetAspectRatio(gog.ValueOf(perspective.AspectRatio, 0.0))
+			camera.SetNear(perspective.Znear)
+			// NOTE: An unspecified far plane indicates an infinite projection,
+			// which is not supported, so the default far distance is kept.
+			if perspective.Zfar != nil {
+				camera.SetFar(*perspective.Zfar)
+			}
+		default:
+			orthographic := gltfCamera.Orthographic
+			camera.SetProjection(mdl.CameraProjectionOrthographic)
+			camera.SetOrthographicHeight(2.0 * orthographic.Ymag)
+			if orthographic.Ymag > 0.0 {
+				camera.SetAspectRatio(orthographic.Xmag / orthographic.Ymag)
+			}
+			camera.SetNear(orthographic.Znear)
+			camera.SetFar(orthographic.Zfar)
+		}
+		return camera
+	}
+
 	createMesh := func(gltfNode *gltf.Node) *mdl.Mesh {
 		mesh := mdl.NewMesh()
 		mesh.SetDefinition(meshDefinitionFromIndex[*gltfNode.Mesh])
@@ -580,6 +862,9 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 		case gltfNodeHasLight(gltfNode):
 			node.AddAttachment(createLight(gltfNode))
 		}
+		if gltfNode.Camera != nil {
+			node.AddAttachment(createCamera(gltfNode))
+		}
 
 		if gltfNode.MatrixOrDefault() != gltf.DefaultMatrix {
 			matrix := dprec.ColumnMajorArrayToMat4(gltfNode.Matrix)
@@ -653,8 +938,19 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 			}
 
 			gltfSampler := gltfAnimation.Samplers[samplerRef]
-			if gltfSampler.Interpolation != gltf.InterpolationLinear {
-				logger.Warn("Unsupported animation interpolation - results may be wrong")
+			var interpolation mdl.Interpolation
+			switch gltfSampler.Interpolation {
+			case gltf.InterpolationLinear:
+				interpolation = mdl.InterpolationLinear
+			case gltf.InterpolationStep:
+				interpolation = mdl.InterpolationStep
+			case gltf.InterpolationCubicSpline:
+				interpolation = mdl.InterpolationCubicSpline
+			default:
+				logger.Warn("Unsupported animation interpolation - falling back to linear",
+					slog.String("interpolation", gltfSampler.Interpolation.String()),
+				)
+				interpolation = mdl.InterpolationLinear
 			}
 
 			timestamps := gltfutil.AnimationKeyframes(gltfDoc, gltfSampler)
@@ -670,50 +966,80 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 			switch gltfChannel.Target.Path {
 			case gltf.TRSTranslation:
 				translations := gltfutil.AnimationTranslations(gltfDoc, gltfSampler)
-				if len(translations) != len(timestamps) {
+				keyframes, ok := buildGLTFKeyframes(timestamps, translations, interpolation)
+				if !ok {
 					logger.Warn("Translations do not match number of keyframes",
 						slog.Int("translations", len(translations)),
 						slog.Int("keyframes", len(timestamps)),
 					)
 					continue
 				}
-				for i := range len(timestamps) {
-					binding.AddTranslationKeyframe(mdl.TranslationKeyframe{
-						Timestamp: timestamps[i],
-						Value:     translations[i],
-					})
+				for _, keyframe := range keyframes {
+					binding.AddTranslationKeyframe(keyframe)
 				}
 
 			case gltf.TRSRotation:
-				rotations := gltfutil.AnimationRotations(gltfDoc, gltfSampler)
-				if len(rotations) != len(timestamps) {
+				// Tangents of cubic splines are not unit quaternions, so only
+				// the values are normalized.
+				rotations := gltfutil.AnimationRawRotations(gltfDoc, gltfSampler)
+				keyframes, ok := buildGLTFKeyframes(timestamps, rotations, interpolation)
+				if !ok {
 					logger.Warn("Rotations do not match number of keyframes",
 						slog.Int("rotations", len(rotations)),
 						slog.Int("keyframes", len(timestamps)),
 					)
 					continue
 				}
-				for i := range len(timestamps) {
-					binding.AddRotationKeyframe(mdl.RotationKeyframe{
-						Timestamp: timestamps[i],
-						Value:     rotations[i],
-					})
+				for _, keyframe := range keyframes {
+					keyframe.Value = dprec.UnitQuat(keyframe.Value)
+					binding.AddRotationKeyframe(keyframe)
 				}
 
 			case gltf.TRSScale:
 				scales := gltfutil.AnimationScales(gltfDoc, gltfSampler)
-				if len(scales) != len(timestamps) {
+				keyframes, ok := buildGLTFKeyframes(timestamps, scales, interpolation)
+				if !ok {
 					logger.Warn("Scales do not match number of keyframes",
 						slog.Int("scales", len(scales)),
 						slog.Int("keyframes", len(timestamps)),
 					)
 					continue
 				}
-				for i := range len(timestamps) {
-					binding.AddScaleKeyframe(mdl.ScaleKeyframe{
-						Timestamp: timestamps[i],
-						Value:     scales[i],
-					})
+				for _, keyframe := range keyframes {
+					binding.AddScaleKeyframe(keyframe)
+				}
+
+			case gltf.TRSWeights:
+				gltfNode := gltfDoc.Nodes[*nodeRef]
+				if gltfNode.Mesh == nil {
+					logger.Warn("Weights channel references a node without a mesh",
+						slog.String("node", gltfNode.Name),
+					)
+					continue
+				}
+				targetCount := gltfutil.MorphTargetCount(gltfDoc.Meshes[*gltfNode.Mesh])
+				weights := gltfutil.AnimationWeights(gltfDoc, gltfSampler)
+				if targetCount == 0 || len(weights)%targetCount != 0 {
+					logger.Warn("Weights do not match number of morph targets",
+						slog.Int("weights", len(weights)),
+						slog.Int("targets", targetCount),
+					)
+					continue
+				}
+				weightGroups := make([][]float64, 0, len(weights)/targetCount)
+				for offset := 0; offset < len(weights); offset += targetCount {
+					weightGroups = append(weightGroups, weights[offset:offset+targetCount])
+				}
+				keyframes, ok := buildGLTFKeyframes(timestamps, weightGroups, interpolation)
+				if !ok {
+					logger.Warn("Weights do not match number of keyframes",
+						slog.Int("weights", len(weightGroups)),
+						slog.Int("keyframes", len(timestamps)),
+					)
+					continue
+				}
+				for _, keyframe := range keyframes {
+					binding.AddMorphWeightKeyframe(keyframe)
 				}
 
 			default:
@@ -727,21 +1053,69 @@ func BuildModelResource(gltfDoc *gltf.Document, forceCollision, onlyAnimations b
 	return model, nil
 }
 
-func openGLTFImage(doc *gltf.Document, img *gltf.Image) (*mdl.Image, error) {
+// gltfVertexKey is used to identify vertices that can be shared when
+// building a geometry.
+type gltfVertexKey struct {
+	vertex    mdl.Vertex
+	primitive int
+	index     int
+}
+
+// buildGLTFKeyframes combines the timestamps and outputs of a glTF animation
+// sampler into keyframes. Cubic spline samplers have three outputs per
+// keyframe - an in tangent, a value and an out tangent.
+func buildGLTFKeyframes[T any](timestamps []float64, outputs []T, interpolation mdl.Interpolation) ([]mdl.Keyframe[T], bool) {
+	result := make([]mdl.Keyframe[T], len(timestamps))
+	if interpolation == mdl.InterpolationCubicSpline {
+		if len(outputs) != 3*len(timestamps) {
+			return nil, false
+		}
+		for i := range timestamps {
+			result[i] = mdl.Keyframe[T]{
+				Timestamp:     timestamps[i],
+				Value:         outputs[3*i+1],
+				Interpolation: interpolation,
+				InTangent:     outputs[3*i+0],
+				OutTangent:    outputs[3*i+2],
+			}
+		}
+		return result, true
+	}
+	if len(outputs) != len(timestamps) {
+		return nil, false
+	}
+	for i := range timestamps {
+		result[i] = mdl.Keyframe[T]{
+			Timestamp:     timestamps[i],
+			Value:         outputs[i],
+			Interpolation: interpolation,
+		}
+	}
+	return result, true
+}
+
+func openGLTFImage(doc *gltf.Document, img *gltf.Image, resolver *gltfResolver) (*mdl.Image, error) {
 	var content []byte
-	if img.BufferView != nil {
+	switch {
+	case img.BufferView != nil:
 		content = gltfutil.BufferViewData(doc, *img.BufferView)
-	} else {
-		// TODO: Add support for external images
-		// in, err := locator.ReadResource(path.Join(path.Dir(a.uri), img.URI))
-		// if err != nil {
-		// 	return nil, fmt.Errorf("error opening resource: %w", err)
-		// }
-		// content, err = io.ReadAll(in)
-		// if err != nil {
-		// 	return nil, fmt.Errorf("error reading resource: %w", err)
-		// }
-		return nil, fmt.Errorf("external images not supported right now")
+	case strings.HasPrefix(img.URI, "data:"):
+		data, err := decodeDataURI(img.URI)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding image %q: %w", img.Name, err)
+		}
+		content = data
+	case img.URI != "":
+		if resolver == nil {
+			return nil, fmt.Errorf("external image %q cannot be resolved", img.URI)
+		}
+		data, err := resolver.ReadFile(img.URI)
+		if err != nil {
+			return nil, fmt.Errorf("error reading image %q: %w", img.Name, err)
+		}
+		content = data
+	default:
+		return nil, fmt.Errorf("image %q has neither a buffer view nor a uri", img.Name)
 	}
 
 	result, err := mdl.ParseImage(bytes.NewReader(content))
@@ -753,54 +1127,64 @@ func openGLTFImage(doc *gltf.Document, img *gltf.Image) (*mdl.Image, error) {
 }
 
 type pbrShaderConfig struct {
-	hasColorTexture             bool
-	hasMetallicRoughnessTexture bool
-	hasNormalTexture            bool
-	hasAlphaTesting             bool
+	colorTexture             *gltfutil.TextureReference
+	metallicRoughnessTexture *gltfutil.TextureReference
+	normalTexture            *gltfutil.TextureReference
+	occlusionTexture         *gltfutil.TextureReference
+	hasAlphaTesting          bool
 }
 
 func createPBRShader(cfg pbrShaderConfig) string {
 	var builder strings.Builder
 
-	if cfg.hasColorTexture {
-		builder.WriteString("texture colorSampler sampler2D\n")
-	}
-	if cfg.hasMetallicRoughnessTexture {
-		builder.WriteString("texture metallicRoughnessSampler sampler2D\n")
-	}
-	if cfg.hasNormalTexture {
-		builder.WriteString("texture normalSampler sampler2D\n")
-	}
+	uvs := [2]string{"#varyingUV", "#varyingUV1"}
+
+	writePBRTextureDeclaration(&builder, "color", cfg.colorTexture)
+	writePBRTextureDeclaration(&builder, "metallicRoughness", cfg.metallicRoughnessTexture)
+	writePBRTextureDeclaration(&builder, "normal", cfg.normalTexture)
+	writePBRTextureDeclaration(&builder, "occlusion", cfg.occlusionTexture)
 
 	builder.WriteString("uniform color vec4\n")
 	builder.WriteString("uniform metallic float\n")
 	builder.WriteString("uniform roughness float\n")
 	builder.WriteString("uniform normalScale float\n")
+	builder.WriteString("uniform occlusionStrength float\n")
 	builder.WriteString("uniform alphaThreshold float\n")
+	writePBRTextureUniforms(&builder, "color", cfg.colorTexture)
+	writePBRTextureUniforms(&builder, "metallicRoughness", cfg.metallicRoughnessTexture)
+	writePBRTextureUniforms(&builder, "normal", cfg.normalTexture)
+	writePBRTextureUniforms(&builder, "occlusion", cfg.occlusionTexture)
 
 	builder.WriteString("func #fragment() {\n")
 	builder.WriteString("  #color = color * #varyingColor\n")
-	if cfg.hasColorTexture {
-		builder.WriteString("  #color *= sample(colorSampler, #varyingUV)\n")
+	if cfg.colorTexture != nil {
+		fmt.Fprintf(&builder, "  #color *= sample(colorSampler, %s)\n", pbrTextureUV("color", cfg.colorTexture, uvs))
 	}
 	if cfg.hasAlphaTesting {
 		builder.WriteString("  if #color.a < alphaThreshold {\n")
 		builder.WriteString("    discard\n")
 		builder.WriteString("  }\n")
 	}
-	if cfg.hasNormalTexture {
+	if cfg.occlusionTexture != nil {
+		// The lighting pass does not separate indirect from direct light, so
+		// occlusion is applied to the albedo instead.
+		fmt.Fprintf(&builder, "  var occlusion float = sample(occlusionSampler, %s).x\n", pbrTextureUV("occlusion", cfg.occlusionTexture, uvs))
+		builder.WriteString("  var occlusionFactor float = 1.0 + occlusionStrength * (occlusion - 1.0)\n")
+		builder.WriteString("  #color = vec4(#color.xyz * occlusionFactor, #color.w)\n")
+	}
+	if cfg.normalTexture != nil {
 		builder.WriteString("  var surfaceNormal vec3 = normalize(#varyingNormal)\n")
 		builder.WriteString("  var surfaceTangent vec3 = normalize(#varyingTangent)\n")
-		builder.WriteString("  var normalTexel vec3 = sample(normalSampler, #varyingUV).xyz\n")
+		fmt.Fprintf(&builder, "  var normalTexel vec3 = sample(normalSampler, %s).xyz\n", pbrTextureUV("normal", cfg.normalTexture, uvs))
 		builder.WriteString("  var normal vec3 = normalFromTexel(normalTexel, normalScale)\n")
-		builder.WriteString("  #normal = vectorToSurface(normal, surfaceNormal, surfaceTangent)\n")
+		builder.WriteString("  #normal = vectorToSurface(normal, surfaceNormal, surfaceTangent, #varyingTangentSign)\n")
 	} else {
 		builder.WriteString("  #normal = normalize(#varyingNormal)\n")
 	}
 	builder.WriteString("  #roughness = roughness\n")
 	builder.WriteString("  #metallic = metallic\n")
-	if cfg.hasMetallicRoughnessTexture {
-		builder.WriteString("  var metallicRoughness vec4 = sample(metallicRoughnessSampler, #varyingUV)\n")
+	if cfg.metallicRoughnessTexture != nil {
+		fmt.Fprintf(&builder, "  var metallicRoughness vec4 = sample(metallicRoughnessSampler, %s)\n", pbrTextureUV("metallicRoughness", cfg.metallicRoughnessTexture, uvs))
 		builder.WriteString("  #roughness *= metallicRoughness.g\n")
 		builder.WriteString("  #metallic *= metallicRoughness.b\n")
 	}
@@ -809,6 +1193,91 @@ func createPBRShader(cfg pbrShaderConfig) string {
 	return builder.String()
 }
 
+type pbrEmissiveShaderConfig struct {
+	emissiveTexture *gltfutil.TextureReference
+	colorTexture    *gltfutil.TextureReference
+	hasAlphaTesting bool
+}
+
+func createPBREmissiveShader(cfg pbrEmissiveShaderConfig) string {
+	var builder strings.Builder
+
+	uvs := [2]string{"uv", "uv1"}
+
+	// The color texture is only needed to discard alpha tested fragments.
+	colorTexture := cfg.colorTexture
+	if !cfg.hasAlphaTesting {
+		colorTexture = nil
+	}
+
+	writePBRTextureDeclaration(&builder, "emissive", cfg.emissiveTexture)
+	writePBRTextureDeclaration(&builder, "color", colorTexture)
+
+	builder.WriteString("uniform color vec4\n")
+	builder.WriteString("uniform emissive vec3\n")
+	builder.WriteString("uniform alphaThreshold float\n")
+	writePBRTextureUniforms(&builder, "emissive", cfg.emissiveTexture)
+	writePBRTextureUniforms(&builder, "color", colorTexture)
+
+	builder.WriteString("varying uv vec2\n")
+	builder.WriteString("varying uv1 vec2\n")
+
+	builder.WriteString("func #vertex() {\n")
+	builder.WriteString("  #position = #projectionMatrix * (#viewMatrix * (#modelMatrix * #vertexCoord))\n")
+	builder.WriteString("  uv = #vertexUV\n")
+	builder.WriteString("  uv1 = #vertexUV1\n")
+	builder.WriteString("}\n")
+
+	builder.WriteString("func #fragment() {\n")
+	if cfg.hasAlphaTesting {
+		builder.WriteString("  var alpha float = color.a\n")
+		if colorTexture != nil {
+			fmt.Fprintf(&builder, "  alpha *= sample(colorSampler, %s).a\n", pbrTextureUV("color", colorTexture, uvs))
+		}
+		builder.WriteString("  if alpha < alphaThreshold {\n")
+		builder.WriteString("    discard\n")
+		builder.WriteString("  }\n")
+	}
+	builder.WriteString("  var emission vec3 = emissive\n")
+	if cfg.emissiveTexture != nil {
+		fmt.Fprintf(&builder, "  emission *= sample(emissiveSampler, %s).xyz\n", pbrTextureUV("emissive", cfg.emissiveTexture, uvs))
+	}
+	builder.WriteString("  #color = vec4(emission, 1.0)\n")
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+func writePBRTextureDeclaration(builder *strings.Builder, name string, ref *gltfutil.TextureReference) {
+	if ref != nil {
+		fmt.Fprintf(builder, "texture %sSampler sampler2D\n", name)
+	}
+}
+
+func writePBRTextureUniforms(builder *strings.Builder, name string, ref *gltfutil.TextureReference) {
+	if ref != nil && ref.Transform.Specified {
+		fmt.Fprintf(builder, "uniform %sTransformU vec3\n", name)
+		fmt.Fprintf(builder, "uniform %sTransformV vec3\n", name)
+	}
+}
+
+// pbrTextureUV returns the shader expression that evaluates to the texture
+// coordinates with which the specified texture should be sampled.
+func pbrTextureUV(name string, ref *gltfutil.TextureReference, uvs [2]string) string {
+	uv := uvs[ref.TexCoord]
+	if !ref.Transform.Specified {
+		return uv
+	}
+	return fmt.Sprintf("vec2(dot(%[1]sTransformU, vec3(%[2]s, 1.0)), dot(%[1]sTransformV, vec3(%[2]s, 1.0)))", name, uv)
+}
+
+func setPBRTextureTransform(material *mdl.Material, name string, ref *gltfutil.TextureReference) {
+	if ref.Transform.Specified {
+		material.SetProperty(name+"TransformU", ref.Transform.Value.U)
+		material.SetProperty(name+"TransformV", ref.Transform.Value.V)
+	}
+}
+
 func gltfNodeHasMesh(node *gltf.Node) bool {
 	return node.Mesh != nil
 }
@@ -831,30 +1300,7 @@ func createCollisionMeshes(geometry *mdl.Geometry, fragment *mdl.Fragment) []*md
 		logger.Warn("Skipping collision mesh due to primitive not being triangles")
 		return nil
 	}
-
-	var triangles []mdl.CollisionTriangle
-	for i := fragment.IndexOffset(); i < fragment.IndexOffset()+fragment.IndexCount(); i += 3 {
-		indexA := geometry.Index(i + 0)
-		indexB := geometry.Index(i + 1)
-		indexC := geometry.Index(i + 2)
-
-		coordA := geometry.Vertex(indexA).Coord
-		coordB := geometry.Vertex(indexB).Coord
-		coordC := geometry.Vertex(indexC).Coord
-
-		vecAB := sprec.Vec3Diff(coordB, coordA)
-		vecAC := sprec.Vec3Diff(coordC, coordA)
-		if sprec.Vec3Cross(vecAB, vecAC).Length() < 0.00001 {
-			logger.Warn("Skipping degenerate triangle")
-			continue
-		}
-
-		triangles = append(triangles, mdl.CollisionTriangle{
-			A: stod.Vec3(coordA),
-			B: stod.Vec3(coordB),
-			C: stod.Vec3(coordC),
-		})
-	}
+	triangles := createCollisionTriangles(geometry, fragment)
 
 	const gridSize = 10 // TODO: Dynamic grid size based on density
 
@@ -897,3 +1343,32 @@ func createCollisionMeshes(geometry *mdl.Geometry, fragment *mdl.Fragment) []*md
 
 	return meshes
 }
+
+// createCollisionTriangles returns the non-degenerate triangles of the
+// specified triangle list fragment.
+func createCollisionTriangles(geometry *mdl.Geometry, fragment *mdl.Fragment) []mdl.CollisionTriangle {
+	var triangles []mdl.CollisionTriangle
+	for i := fragment.IndexOffset(); i < fragment.IndexOffset()+fragment.IndexCount(); i += 3 {
+		indexA := geometry.Index(i + 0)
+		indexB := geometry.Index(i + 1)
+		indexC := geometry.Index(i + 2)
+
+		coordA := geometry.Vertex(indexA).Coord
+		coordB := geometry.Vertex(indexB).Coord
+		coordC := geometry.Vertex(indexC).Coord
+
+		vecAB := sprec.Vec3Diff(coordB, coordA)
+		vecAC := sprec.Vec3Diff(coordC, coordA)
+		if sprec.Vec3Cross(vecAB, vecAC).Length() < 0.00001 {
+			logger.Warn("Skipping degenerate triangle")
+			continue
+		}
+
+		triangles = append(triangles, mdl.CollisionTriangle{
+			A: stod.Vec3(coordA),
+			B: stod.Vec3(coordB),
+			C: stod.Vec3(coordC),
+		})
+	}
+	return triangles
+}
@@ -6,7 +6,11 @@ import (
 
 // CreateNode creates a new node with the specified name and operations.
 func CreateNode(name string, operations ...Operation) Provider[*mdl.Node] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-node",
+		Detail: name,
+		Inputs: operationInputs(operations),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Node, error) {
 			node := mdl.NewNode(name)
@@ -22,5 +26,5 @@ func CreateNode(name string, operations ...Operation) Provider[*mdl.Node] {
 		func() ([]byte, error) {
 			return CreateDigest("create-node", name, operations)
 		},
-	))
+	)))
 }
@@ -8,7 +8,10 @@ import (
 
 // CreateSampler creates a new sampler with the provided texture and operations.
 func CreateSampler(textureProvider Provider[*mdl.Texture], operations ...Operation) Provider[*mdl.Sampler] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-sampler",
+		Inputs: append([]Digestable{textureProvider}, operationInputs(operations)...),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Sampler, error) {
 			texture, err := textureProvider.Get()
@@ -33,5 +36,5 @@ func CreateSampler(textureProvider Provider[*mdl.Texture], operations ...Operati
 		func() ([]byte, error) {
 			return CreateDigest("create-sampler", textureProvider, operations)
 		},
-	))
+	)))
 }
@@ -10,7 +10,10 @@ import (
 
 // CreateSky creates a new sky with the provided name and operations.
 func CreateSky(materialProvider Provider[*mdl.Material], opts ...Operation) Provider[*mdl.Sky] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-sky",
+		Inputs: append([]Digestable{materialProvider}, operationInputs(opts)...),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Sky, error) {
 			material, err := materialProvider.Get()
@@ -32,7 +35,7 @@ func CreateSky(materialProvider Provider[*mdl.Material], opts ...Operation) Prov
 		func() ([]byte, error) {
 			return CreateDigest("create-sky", materialProvider, opts)
 		},
-	))
+	)))
 }
 
 // CreateColorSkyMaterial creates a new color sky material.
@@ -75,7 +78,10 @@ func CreateColorSkyMaterial(colorProvider Provider[dprec.Vec4]) Provider[*mdl.Ma
 
 // CreateTextureSkyMaterial creates a new texture sky material.
 func CreateTextureSkyMaterial(samplerProvider Provider[*mdl.Sampler]) Provider[*mdl.Material] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-texture-sky-material",
+		Inputs: []Digestable{samplerProvider},
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Material, error) {
 			shader, err := defaultTextureSkyShader.Get()
@@ -108,7 +114,7 @@ func CreateTextureSkyMaterial(samplerProvider Provider[*mdl.Sampler]) Provider[*
 		func() ([]byte, error) {
 			return CreateDigest("create-texture-sky-material", samplerProvider)
 		},
-	))
+	)))
 }
 
 var defaultColorSkyShader = func() Provider[*mdl.Shader] {
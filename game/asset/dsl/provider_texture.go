@@ -2,15 +2,87 @@ package dsl
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/lacking/game/asset/mdl"
 )
 
+// OpenKTX2Texture opens a texture from a KTX2 file at the provided path.
+//
+// The mipmaps that are stored in the file are used as they are.
+func OpenKTX2Texture(path string) Provider[*mdl.Texture] {
+	return DescribedProvider(ProviderInfo{
+		Name:   "open-ktx2-texture",
+		Detail: path,
+	}, OnceProvider(FuncProvider(
+		// get function
+		func() (*mdl.Texture, error) {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open texture file %q: %w", path, err)
+			}
+			defer file.Close()
+
+			texture, err := mdl.ParseKTX2Texture(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse KTX2 texture %q: %w", path, err)
+			}
+			return texture, nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			contentDigest, err := FileContentDigest(path)
+			if err != nil {
+				return nil, err
+			}
+			return CreateDigest("open-ktx2-texture", contentDigest)
+		},
+	)))
+}
+
+// OpenDDSTexture opens a texture from a DDS file at the provided path.
+//
+// The mipmaps that are stored in the file are used as they are.
+func OpenDDSTexture(path string) Provider[*mdl.Texture] {
+	return DescribedProvider(ProviderInfo{
+		Name:   "open-dds-texture",
+		Detail: path,
+	}, OnceProvider(FuncProvider(
+		// get function
+		func() (*mdl.Texture, error) {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open texture file %q: %w", path, err)
+			}
+			defer file.Close()
+
+			texture, err := mdl.ParseDDSTexture(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse DDS texture %q: %w", path, err)
+			}
+			return texture, nil
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			contentDigest, err := FileContentDigest(path)
+			if err != nil {
+				return nil, err
+			}
+			return CreateDigest("open-dds-texture", contentDigest)
+		},
+	)))
+}
+
 // Create2DTexture creates a new 2D texture with the specified format and
 // source image.
 func Create2DTexture(imageProvider Provider[*mdl.Image], opts ...Operation) Provider[*mdl.Texture] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-2d-texture",
+		Inputs: append([]Digestable{imageProvider}, operationInputs(opts)...),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Texture, error) {
 			var cfg textureConfig
@@ -25,24 +97,23 @@ func Create2DTexture(imageProvider Provider[*mdl.Image], opts ...Operation) Prov
 				return nil, fmt.Errorf("failed to get image: %w", err)
 			}
 
-			texture := mdl.Create2DTexture(image.Width(), image.Height(), 1, cfg.format.ValueOrDefault(mdl.TextureFormatRGBA8))
-			texture.SetName(image.Name())
-			texture.SetGenerateMipmaps(cfg.mipmapping)
-			texture.SetLayerImage(0, 0, image)
-			return texture, nil
+			return create2DTexture(image, cfg), nil
 		},
 
 		// digest function
 		func() ([]byte, error) {
 			return CreateDigest("create-2d-texture", imageProvider, opts)
 		},
-	))
+	)))
 }
 
 // CreateCubeTexture creates a new cube texture with the specified format and
 // source image.
 func CreateCubeTexture(cubeImageProvider Provider[*mdl.CubeImage], opts ...Operation) Provider[*mdl.Texture] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-cube-texture",
+		Inputs: append([]Digestable{cubeImageProvider}, operationInputs(opts)...),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Texture, error) {
 			var cfg textureConfig
@@ -64,7 +135,29 @@ func CreateCubeTexture(cubeImageProvider Provider[*mdl.CubeImage], opts ...Opera
 			topImage := cubeImage.Side(mdl.CubeSideTop)
 			bottomImage := cubeImage.Side(mdl.CubeSideBottom)
 
-			texture := mdl.CreateCubeTexture(frontImage.Width(), 1, cfg.format.ValueOrDefault(mdl.TextureFormatRGBA16F))
+			format := cfg.format.ValueOrDefault(mdl.TextureFormatRGBA16F)
+			if mipmapCfg, ok := cfg.precomputedMipmaps(format); ok {
+				linear := cfg.isLinear(format, mipmapCfg)
+				sideMipmaps := [6][]*mdl.Image{
+					generateImageMipmaps(frontImage, mipmapCfg, linear),
+					generateImageMipmaps(rearImage, mipmapCfg, linear),
+					generateImageMipmaps(leftImage, mipmapCfg, linear),
+					generateImageMipmaps(rightImage, mipmapCfg, linear),
+					generateImageMipmaps(topImage, mipmapCfg, linear),
+					generateImageMipmaps(bottomImage, mipmapCfg, linear),
+				}
+				texture := mdl.CreateCubeTexture(frontImage.Width(), len(sideMipmaps[0]), format)
+				texture.SetLinear(linear)
+				for side, mipmaps := range sideMipmaps {
+					for i, mipmap := range mipmaps {
+						texture.SetLayerImage(i, side, mipmap)
+					}
+				}
+				return texture, nil
+			}
+
+			texture := mdl.CreateCubeTexture(frontImage.Width(), 1, format)
+			texture.SetLinear(cfg.linear)
 			texture.SetGenerateMipmaps(cfg.mipmapping)
 			texture.SetLayerImage(0, 0, frontImage)
 			texture.SetLayerImage(0, 1, rearImage)
@@ -79,13 +172,16 @@ func CreateCubeTexture(cubeImageProvider Provider[*mdl.CubeImage], opts ...Opera
 		func() ([]byte, error) {
 			return CreateDigest("create-cube-texture", cubeImageProvider, opts)
 		},
-	))
+	)))
 }
 
 // CreateCubeMipmapTexture creates a new cube texture with the specified format
 // and source mipmap images.
 func CreateCubeMipmapTexture(cubeImagesProvider Provider[[]*mdl.CubeImage], opts ...Operation) Provider[*mdl.Texture] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "create-cube-mipmap-texture",
+		Inputs: append([]Digestable{cubeImagesProvider}, operationInputs(opts)...),
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Texture, error) {
 			var cfg textureConfig
@@ -117,11 +213,60 @@ func CreateCubeMipmapTexture(cubeImagesProvider Provider[[]*mdl.CubeImage], opts
 		func() ([]byte, error) {
 			return CreateDigest("create-cube-mipmap-texture", cubeImagesProvider, opts)
 		},
+	)))
+}
+
+const (
+	// TextureUsageColor indicates that a texture holds color data, possibly
+	// with transparency.
+	TextureUsageColor TextureUsage = iota
+
+	// TextureUsageNormal indicates that a texture holds tangent-space normals.
+	TextureUsageNormal
+
+	// TextureUsageMask indicates that a texture holds a single channel of
+	// data, like occlusion or a mask.
+	TextureUsageMask
+)
+
+// TextureUsage describes the kind of data that a texture holds.
+type TextureUsage uint8
+
+// CompressedFormat returns the block-compressed texture format that best
+// suits the specified usage. It is meant to be used with SetFormat.
+//
+// Color textures use BC7, normal textures use BC5, which only keeps the X
+// and Y components, and mask textures use BC4, which only keeps the red
+// channel.
+func CompressedFormat(usage TextureUsage) Provider[mdl.TextureFormat] {
+	return OnceProvider(FuncProvider(
+		// get function
+		func() (mdl.TextureFormat, error) {
+			switch usage {
+			case TextureUsageColor:
+				return mdl.TextureFormatBC7, nil
+			case TextureUsageNormal:
+				return mdl.TextureFormatBC5, nil
+			case TextureUsageMask:
+				return mdl.TextureFormatBC4, nil
+			default:
+				return 0, fmt.Errorf("unknown texture usage %d", usage)
+			}
+		},
+
+		// digest function
+		func() ([]byte, error) {
+			return CreateDigest("compressed-format", uint8(usage))
+		},
 	))
 }
 
 func GetTexture(modelProvider Provider[*mdl.Model], name string) Provider[*mdl.Texture] {
-	return OnceProvider(FuncProvider(
+	return DescribedProvider(ProviderInfo{
+		Name:   "get-texture",
+		Detail: name,
+		Inputs: []Digestable{modelProvider},
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (*mdl.Texture, error) {
 			model, err := modelProvider.Get()
@@ -145,12 +290,14 @@ func GetTexture(modelProvider Provider[*mdl.Model], name string) Provider[*mdl.T
 		func() ([]byte, error) {
 			return CreateDigest("get-texture", modelProvider, name)
 		},
-	))
+	)))
 }
 
 type textureConfig struct {
 	format     opt.T[mdl.TextureFormat]
 	mipmapping bool
+	linear     bool
+	mipmaps    opt.T[mipmapConfig]
 }
 
 func (c *textureConfig) SetFormat(format mdl.TextureFormat) {
@@ -165,4 +312,63 @@ func (c *textureConfig) SetMipmapping(mipmapping bool) {
 	c.mipmapping = mipmapping
 }
 
+func (c *textureConfig) SetLinear(linear bool) {
+	c.linear = linear
+}
+
+func (c *textureConfig) SetPrecomputedMipmaps(cfg mipmapConfig) {
+	c.mipmaps = opt.V(cfg)
+}
+
+// create2DTexture creates a 2D texture from the specified image, according
+// to the configuration.
+func create2DTexture(image *mdl.Image, cfg textureConfig) *mdl.Texture {
+	format := cfg.format.ValueOrDefault(mdl.TextureFormatRGBA8)
+	if mipmapCfg, ok := cfg.precomputedMipmaps(format); ok {
+		linear := cfg.isLinear(format, mipmapCfg)
+		mipmaps := generateImageMipmaps(image, mipmapCfg, linear)
+		texture := mdl.Create2DTexture(image.Width(), image.Height(), len(mipmaps), format)
+		texture.SetName(image.Name())
+		texture.SetLinear(linear)
+		for i, mipmap := range mipmaps {
+			texture.SetLayerImage(i, 0, mipmap)
+		}
+		return texture
+	}
+
+	texture := mdl.Create2DTexture(image.Width(), image.Height(), 1, format)
+	texture.SetName(image.Name())
+	texture.SetLinear(cfg.linear)
+	texture.SetGenerateMipmaps(cfg.mipmapping)
+	texture.SetLayerImage(0, 0, image)
+	return texture
+}
+
+// precomputedMipmaps returns the configuration with which mipmaps should be
+// computed during asset processing, if at all. Block-compressed textures
+// that use mipmapping always have their mipmaps computed, since they cannot
+// be generated at runtime.
+func (c *textureConfig) precomputedMipmaps(format mdl.TextureFormat) (mipmapConfig, bool) {
+	if c.mipmaps.Specified {
+		return c.mipmaps.Value, true
+	}
+	if c.mipmapping && mdl.IsBlockCompressed(format) {
+		return mipmapConfig{
+			filter: MipmapFilterKaiser,
+		}, true
+	}
+	return mipmapConfig{}, false
+}
+
+// isLinear returns whether the texture data is in linear space, where normal
+// maps and floating-point formats are always considered to be linear.
+func (c *textureConfig) isLinear(format mdl.TextureFormat, cfg mipmapConfig) bool {
+	switch format {
+	case mdl.TextureFormatRGBA16F, mdl.TextureFormatRGBA32F:
+		return true
+	default:
+		return c.linear || cfg.normalMap
+	}
+}
+
 var defaultCubeTextureProvider = CreateCubeTexture(defaultCubeImageProvider)
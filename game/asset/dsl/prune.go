@@ -0,0 +1,158 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mokiat/lacking/core/resource"
+)
+
+const (
+	// PruneReasonUndeclaredAsset indicates that the file is an asset that is
+	// no longer declared through Save or SaveRaw.
+	PruneReasonUndeclaredAsset PruneReason = "undeclared-asset"
+
+	// PruneReasonOrphanDigest indicates that the file is a digest of an asset
+	// that is no longer declared.
+	PruneReasonOrphanDigest PruneReason = "orphan-digest"
+)
+
+// PruneReason explains why a file is considered stale.
+type PruneReason string
+
+// PruneOptions configures how stale files are removed from the store.
+type PruneOptions struct {
+
+	// Prefix is the directory within the store that is managed by the dsl.
+	// Only files within this directory are considered for removal. Use "."
+	// to manage the whole store.
+	//
+	// This is required, since any file within the prefix that was produced
+	// by the dsl but is no longer declared is removed.
+	Prefix string
+
+	// DryRun specifies that stale files should only be reported and not
+	// deleted.
+	DryRun bool
+}
+
+// PrunedFile holds information on a stale file that was found in the store.
+type PrunedFile struct {
+
+	// Path is the path of the file in the store.
+	Path string `json:"path"`
+
+	// Reason explains why the file is considered stale.
+	Reason PruneReason `json:"reason"`
+
+	// Deleted indicates whether the file was removed from the store.
+	Deleted bool `json:"deleted"`
+
+	// Error describes why the file could not be removed.
+	Error string `json:"error,omitempty"`
+}
+
+// Prune finds files in the store that belong to assets that are no longer
+// declared, as well as their digest files, and removes them unless this is
+// a dry run.
+//
+// Only files produced by the dsl are considered, which are recognized by
+// their sibling digest file. Files that were placed in the store by other
+// means and files outside the configured prefix are never touched.
+func Prune(store resource.Store, options PruneOptions) ([]PrunedFile, error) {
+	if options.Prefix == "" {
+		return nil, errors.New("prune prefix must be specified")
+	}
+	prefix := normalizeAssetPath(options.Prefix)
+
+	storePaths, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing store: %w", err)
+	}
+
+	declaredPaths := make(map[string]struct{}, len(resourceProviders)+len(rawResourceProviders))
+	for declaredPath := range resourceProviders {
+		declaredPaths[normalizeAssetPath(declaredPath)] = struct{}{}
+	}
+	for declaredPath := range rawResourceProviders {
+		declaredPaths[normalizeAssetPath(declaredPath)] = struct{}{}
+	}
+
+	existingPaths := make(map[string]struct{}, len(storePaths))
+	for _, storePath := range storePaths {
+		existingPaths[normalizeAssetPath(storePath)] = struct{}{}
+	}
+
+	var (
+		result []PrunedFile
+		errs   []error
+	)
+	for _, storePath := range storePaths {
+		storePath = normalizeAssetPath(storePath)
+		if !isWithinPrefix(storePath, prefix) {
+			continue // not managed by us
+		}
+
+		var reason PruneReason
+		if assetPath, ok := strings.CutSuffix(storePath, digestSuffix); ok {
+			if _, declared := declaredPaths[assetPath]; declared {
+				continue
+			}
+			reason = PruneReasonOrphanDigest
+		} else {
+			if _, declared := declaredPaths[storePath]; declared {
+				continue
+			}
+			if _, produced := existingPaths[digestPath(storePath)]; !produced {
+				continue // not produced by us
+			}
+			reason = PruneReasonUndeclaredAsset
+		}
+
+		prunedFile := PrunedFile{
+			Path:   storePath,
+			Reason: reason,
+		}
+		if options.DryRun {
+			logger.Info("Stale file found",
+				slog.String("path", storePath),
+				slog.String("reason", string(reason)),
+			)
+		} else if err := store.Delete(storePath); err != nil {
+			logger.Error("Failed to delete stale file",
+				slog.String("path", storePath),
+				slog.String("error", err.Error()),
+			)
+			prunedFile.Error = err.Error()
+			errs = append(errs, fmt.Errorf("error deleting stale file %q: %w", storePath, err))
+		} else {
+			logger.Info("Stale file deleted",
+				slog.String("path", storePath),
+				slog.String("reason", string(reason)),
+			)
+			prunedFile.Deleted = true
+		}
+		result = append(result, prunedFile)
+	}
+
+	slices.SortFunc(result, func(a, b PrunedFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return result, errors.Join(errs...)
+}
+
+func normalizeAssetPath(assetPath string) string {
+	return path.Clean(filepath.ToSlash(assetPath))
+}
+
+func isWithinPrefix(assetPath, prefix string) bool {
+	if prefix == "." {
+		return !strings.HasPrefix(assetPath, "../") && assetPath != ".."
+	}
+	return assetPath == prefix || strings.HasPrefix(assetPath, prefix+"/")
+}
@@ -0,0 +1,108 @@
+package dsl_test
+
+import (
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/core/resource"
+	"github.com/mokiat/lacking/game/asset/dsl"
+)
+
+var _ = Describe("Prune", func() {
+	const prefix = "prune-test"
+
+	var store resource.Store
+
+	writeFile := func(path string) {
+		out, err := store.Create(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.Close()).To(Succeed())
+	}
+
+	storePaths := func() []string {
+		paths, err := store.List()
+		Expect(err).ToNot(HaveOccurred())
+		return paths
+	}
+
+	BeforeEach(func() {
+		store = resource.NewMemStore()
+	})
+
+	// Assets can only be declared once per process, hence the declarations
+	// are shared by all the specs.
+	dsl.SaveRaw(prefix+"/declared.dat", dsl.Const(io.NopCloser(strings.NewReader(""))))
+
+	It("removes undeclared assets and orphan digests that were produced", func() {
+		writeFile(prefix + "/declared.dat")
+		writeFile(prefix + "/declared.dat.srcsha")
+		writeFile(prefix + "/stale.dat")
+		writeFile(prefix + "/stale.dat.srcsha")
+		writeFile(prefix + "/removed.dat.srcsha")
+
+		pruned, err := dsl.Prune(store, dsl.PruneOptions{
+			Prefix: prefix,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(Equal([]dsl.PrunedFile{
+			{Path: prefix + "/removed.dat.srcsha", Reason: dsl.PruneReasonOrphanDigest, Deleted: true},
+			{Path: prefix + "/stale.dat", Reason: dsl.PruneReasonUndeclaredAsset, Deleted: true},
+			{Path: prefix + "/stale.dat.srcsha", Reason: dsl.PruneReasonOrphanDigest, Deleted: true},
+		}))
+		Expect(storePaths()).To(ConsistOf(
+			prefix+"/declared.dat",
+			prefix+"/declared.dat.srcsha",
+		))
+	})
+
+	It("keeps files that were not produced", func() {
+		writeFile(prefix + "/readme.txt")
+		writeFile(prefix + "/textures/manual.png")
+
+		pruned, err := dsl.Prune(store, dsl.PruneOptions{
+			Prefix: prefix,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(storePaths()).To(ConsistOf(
+			prefix+"/readme.txt",
+			prefix+"/textures/manual.png",
+		))
+	})
+
+	It("keeps files outside the prefix", func() {
+		writeFile("other/stale.dat")
+		writeFile("other/stale.dat.srcsha")
+
+		pruned, err := dsl.Prune(store, dsl.PruneOptions{
+			Prefix: prefix,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(storePaths()).To(HaveLen(2))
+	})
+
+	It("only reports files in a dry run", func() {
+		writeFile(prefix + "/stale.dat")
+		writeFile(prefix + "/stale.dat.srcsha")
+
+		pruned, err := dsl.Prune(store, dsl.PruneOptions{
+			Prefix: prefix,
+			DryRun: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(Equal([]dsl.PrunedFile{
+			{Path: prefix + "/stale.dat", Reason: dsl.PruneReasonUndeclaredAsset},
+			{Path: prefix + "/stale.dat.srcsha", Reason: dsl.PruneReasonOrphanDigest},
+		}))
+		Expect(storePaths()).To(HaveLen(2))
+	})
+
+	It("requires a prefix", func() {
+		_, err := dsl.Prune(store, dsl.PruneOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,177 @@
+package dsl
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// AssetKindResource indicates an asset that was declared through Save.
+	AssetKindResource AssetKind = "resource"
+
+	// AssetKindRaw indicates an asset that was declared through SaveRaw.
+	AssetKindRaw AssetKind = "raw"
+)
+
+// AssetKind represents the type of an asset declaration.
+type AssetKind string
+
+const (
+	// AssetStatusSkipped indicates that the asset was up to date and was not
+	// processed.
+	AssetStatusSkipped AssetStatus = "skipped"
+
+	// AssetStatusOutdated indicates that the asset needs to be rebuilt but was
+	// not, since this was a dry run.
+	AssetStatusOutdated AssetStatus = "outdated"
+
+	// AssetStatusUpdated indicates that the asset was rebuilt.
+	AssetStatusUpdated AssetStatus = "updated"
+
+	// AssetStatusFailed indicates that the asset could not be processed.
+	AssetStatusFailed AssetStatus = "failed"
+)
+
+// AssetStatus represents the outcome of processing an asset.
+type AssetStatus string
+
+const (
+	// AssetReasonUpToDate indicates that the stored digest matches the digest
+	// of the asset declaration.
+	AssetReasonUpToDate AssetReason = "up-to-date"
+
+	// AssetReasonMissingDigest indicates that there is no stored digest for
+	// the asset, usually because it has never been built.
+	AssetReasonMissingDigest AssetReason = "missing-digest"
+
+	// AssetReasonDigestChanged indicates that the stored digest differs from
+	// the digest of the asset declaration.
+	AssetReasonDigestChanged AssetReason = "digest-changed"
+
+	// AssetReasonForced indicates that the asset was rebuilt because this was
+	// explicitly requested.
+	AssetReasonForced AssetReason = "forced"
+)
+
+// AssetReason explains why an asset was or was not rebuilt.
+type AssetReason string
+
+const (
+	// ProviderStatusEvaluated indicates that the provider computed its value.
+	ProviderStatusEvaluated ProviderStatus = "evaluated"
+
+	// ProviderStatusCacheHit indicates that the value of the provider was
+	// loaded from the artifact cache.
+	ProviderStatusCacheHit ProviderStatus = "cache-hit"
+
+	// ProviderStatusFailed indicates that the provider returned an error.
+	ProviderStatusFailed ProviderStatus = "failed"
+
+	// ProviderStatusNotEvaluated indicates that the value of the provider was
+	// not needed.
+	ProviderStatusNotEvaluated ProviderStatus = "not-evaluated"
+)
+
+// ProviderStatus represents the outcome of evaluating a provider.
+type ProviderStatus string
+
+// ProviderReport holds information on how a single describable provider was
+// evaluated.
+//
+// Providers that are shared between assets are only evaluated once, so they
+// are reported with the same outcome for each asset that uses them.
+type ProviderReport struct {
+
+	// Name is the name that the provider declared.
+	Name string `json:"name"`
+
+	// Detail is the optional detail that the provider declared.
+	Detail string `json:"detail,omitempty"`
+
+	// Status is the outcome of evaluating the provider.
+	Status ProviderStatus `json:"status"`
+
+	// Duration is the time it took to evaluate the provider, including the
+	// evaluation of its inputs.
+	Duration time.Duration `json:"duration"`
+}
+
+// AssetReport holds information on how a single asset was processed.
+type AssetReport struct {
+
+	// Path is the path of the asset in the store.
+	Path string `json:"path"`
+
+	// Kind is the type of the asset declaration.
+	Kind AssetKind `json:"kind"`
+
+	// Status is the outcome of processing the asset.
+	Status AssetStatus `json:"status"`
+
+	// Reason explains why the asset was or was not rebuilt. This is empty
+	// if the asset failed before its digest could be determined.
+	Reason AssetReason `json:"reason,omitempty"`
+
+	// Digest is the digest of the asset declaration.
+	Digest string `json:"digest,omitempty"`
+
+	// PreviousDigest is the digest that was stored from a previous run.
+	PreviousDigest string `json:"previousDigest,omitempty"`
+
+	// Duration is the time it took to process the asset.
+	Duration time.Duration `json:"duration"`
+
+	// Error describes why the asset failed to be processed.
+	Error string `json:"error,omitempty"`
+
+	// Providers holds the reports of the describable providers that
+	// contribute to the asset, with inputs listed before the providers that
+	// consume them. This is only populated for assets that were built.
+	Providers []ProviderReport `json:"providers,omitempty"`
+}
+
+// Report holds information on the outcome of a Run.
+type Report struct {
+
+	// DryRun indicates whether assets were only checked and not rebuilt.
+	DryRun bool `json:"dryRun"`
+
+	// Assets holds the reports of all processed assets, sorted by path.
+	Assets []AssetReport `json:"assets"`
+
+	// Pruned holds the stale files that were found in the store, sorted by
+	// path. This is only populated when pruning is enabled.
+	Pruned []PrunedFile `json:"pruned,omitempty"`
+
+	// Duration is the total time the run took.
+	Duration time.Duration `json:"duration"`
+}
+
+// Count returns the number of assets that have the specified status.
+func (r *Report) Count(status AssetStatus) int {
+	var count int
+	for _, asset := range r.Assets {
+		if asset.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// HasFailures returns whether any of the assets failed to be processed or
+// any of the stale files failed to be removed.
+func (r *Report) HasFailures() bool {
+	for _, file := range r.Pruned {
+		if file.Error != "" {
+			return true
+		}
+	}
+	return r.Count(AssetStatusFailed) > 0
+}
+
+func (r *Report) sort() {
+	slices.SortFunc(r.Assets, func(a, b AssetReport) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+}
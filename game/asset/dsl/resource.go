@@ -15,7 +15,11 @@ func Save[T any](path string, provider Provider[T]) any {
 	if _, ok := resourceProviders[path]; ok {
 		panic(fmt.Sprintf("provider for asset at path %q already exists", path))
 	}
-	resourceProviders[path] = OnceProvider(FuncProvider(
+	resourceProviders[path] = DescribedProvider(ProviderInfo{
+		Name:   "save-asset",
+		Detail: path,
+		Inputs: []Digestable{provider},
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (any, error) {
 			resource, err := provider.Get()
@@ -29,7 +33,7 @@ func Save[T any](path string, provider Provider[T]) any {
 		func() ([]byte, error) {
 			return CreateDigest("save-asset", path, provider)
 		},
-	))
+	)))
 	return nil
 }
 
@@ -38,7 +42,11 @@ func SaveRaw(path string, provider Provider[io.ReadCloser]) any {
 	if _, ok := rawResourceProviders[path]; ok {
 		panic(fmt.Sprintf("provider for asset at path %q already exists", path))
 	}
-	rawResourceProviders[path] = OnceProvider(FuncProvider(
+	rawResourceProviders[path] = DescribedProvider(ProviderInfo{
+		Name:   "save-raw-asset",
+		Detail: path,
+		Inputs: []Digestable{provider},
+	}, OnceProvider(FuncProvider(
 		// get function
 		func() (io.ReadCloser, error) {
 			in, err := provider.Get()
@@ -52,6 +60,6 @@ func SaveRaw(path string, provider Provider[io.ReadCloser]) any {
 		func() ([]byte, error) {
 			return CreateDigest("save-raw-asset", path, provider)
 		},
-	))
+	)))
 	return nil
 }
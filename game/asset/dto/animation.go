@@ -59,8 +59,31 @@ type AnimationBinding struct {
 	// ScaleKeyframes is a list of keyframes that animate the scale
 	// of the node.
 	ScaleKeyframes []AnimationKeyframe[dprec.Vec3]
+
+	// MorphWeightKeyframes is a list of keyframes that animate the weights
+	// of the morph targets of the mesh of the node. Each value holds one
+	// weight per morph target.
+	MorphWeightKeyframes []AnimationKeyframe[[]float64]
 }
 
+const (
+	// AnimationInterpolationLinear specifies that values are linearly
+	// interpolated towards the next keyframe.
+	AnimationInterpolationLinear AnimationInterpolation = iota
+
+	// AnimationInterpolationStep specifies that the value is held until the
+	// next keyframe.
+	AnimationInterpolationStep
+
+	// AnimationInterpolationCubicSpline specifies that values follow a cubic
+	// Hermite spline towards the next keyframe.
+	AnimationInterpolationCubicSpline
+)
+
+// AnimationInterpolation specifies how values are interpolated between
+// keyframes.
+type AnimationInterpolation uint8
+
 // AnimationKeyframe represents a single keyframe in an animation.
 type AnimationKeyframe[T any] struct {
 
@@ -69,4 +92,16 @@ type AnimationKeyframe[T any] struct {
 
 	// Value is the value that is applied at the given timestamp.
 	Value T
+
+	// Interpolation specifies how values are interpolated between this
+	// keyframe and the next one.
+	Interpolation AnimationInterpolation
+
+	// InTangent is the tangent (per second) with which the spline arrives
+	// at this keyframe. It is only used for cubic spline interpolation.
+	InTangent T
+
+	// OutTangent is the tangent (per second) with which the spline leaves
+	// this keyframe. It is only used for cubic spline interpolation.
+	OutTangent T
 }
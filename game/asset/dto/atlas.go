@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/mokiat/gomath/sprec"
+
+const AtlasChunkID = "lacking:atlas"
+
+type AtlasChunkHolder struct {
+	AtlasChunk *AtlasChunk `chunk:"lacking:atlas"`
+}
+
+type AtlasChunk struct {
+	// Regions is the collection of named images that are packed into the
+	// textures of the file.
+	Regions []AtlasRegion
+}
+
+// Atlas represents a set of named images that have been packed into the
+// textures of a shading chunk.
+type Atlas struct {
+	ShadingChunkHolder
+	AtlasChunkHolder
+}
+
+// AtlasRegion represents the location of a packed image within a texture.
+type AtlasRegion struct {
+
+	// Name is the name of the image, which can be used to look it up.
+	Name string
+
+	// TextureID is the ID of the texture that holds the image.
+	TextureID uint32
+
+	// X, Y, Width and Height specify the texels of the texture that hold the
+	// image, where the origin is the top-left corner of the texture.
+	X      uint32
+	Y      uint32
+	Width  uint32
+	Height uint32
+
+	// MinUV and MaxUV are the tex coords of the bottom-left and top-right
+	// corners of the image.
+	MinUV sprec.Vec2
+	MaxUV sprec.Vec2
+}
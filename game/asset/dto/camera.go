@@ -1,11 +1,11 @@
 package dto
 
-import "github.com/mokiat/gomath/sprec"
+import "github.com/mokiat/gomath/dprec"
 
 const CameraChunkID = "lacking:camera"
 
 type CameraChunkHolder struct {
-	Camera *CameraChunk `chunk:"lacking:camera"`
+	CameraChunk *CameraChunk `chunk:"lacking:camera"`
 }
 
 type CameraChunk struct {
@@ -14,14 +14,17 @@ type CameraChunk struct {
 }
 
 const (
-	FoVModeFoVModeHorizontalPlus FoVMode = iota
+	// CameraProjectionPerspective indicates that the camera uses a
+	// perspective projection.
+	CameraProjectionPerspective CameraProjection = iota
 
-	FoVModeFoVModeVertialMinus
+	// CameraProjectionOrthographic indicates that the camera uses an
+	// orthographic projection.
+	CameraProjectionOrthographic
 )
 
-// FoVMode determines how the camera field of view is calculated
-// in the horizontal and vertical directions.
-type FoVMode uint8
+// CameraProjection determines how a camera projects the scene.
+type CameraProjection uint8
 
 // Camera represents a camera that is part of a model.
 type Camera struct {
@@ -29,22 +32,30 @@ type Camera struct {
 	// ID is the unique identifier of the camera within the file.
 	ID uint32
 
-	// NodeIndex is the index of the node that is used by this camera.
-	NodeIndex uint32
+	// NodeID is the ID of the node that is associated with the camera.
+	NodeID uint32
 
-	// FoVMode determines how the camera field of view is calculated
-	// in the horizontal and vertical directions.
-	FoVMode FoVMode
+	// Name is the name of the camera, which can be used to find it on a
+	// model instance.
+	Name string
 
-	// FoVAngle is the field of view angle of the camera.
-	FoVAngle sprec.Angle
+	// Projection determines how the camera projects the scene.
+	Projection CameraProjection
+
+	// FoVAngle is the vertical field of view angle of a perspective camera.
+	FoVAngle dprec.Angle
+
+	// AspectRatio is the preferred aspect ratio of the camera. A value of
+	// zero indicates that the aspect ratio of the viewport should be used.
+	AspectRatio float64
+
+	// OrthographicHeight is the visible vertical extent of an orthographic
+	// camera.
+	OrthographicHeight float64
 
 	// Near is the distance to the near clipping plane.
-	Near float32
+	Near float64
 
 	// Far is the distance to the far clipping plane.
-	Far float32
-
-	// Exposure is the exposure value of the camera.
-	Exposure float32
+	Far float64
 }
@@ -24,6 +24,20 @@ type LightingChunk struct {
 	DirectionalLights []DirectionalLight
 }
 
+const (
+	// AmbientLightModeTexture indicates that the ambient light uses the
+	// refraction cube texture for diffuse lighting.
+	AmbientLightModeTexture AmbientLightMode = iota
+
+	// AmbientLightModeSH9 indicates that the ambient light uses spherical
+	// harmonics coefficients for diffuse lighting.
+	AmbientLightModeSH9
+)
+
+// AmbientLightMode determines how an ambient light represents its diffuse
+// lighting.
+type AmbientLightMode uint8
+
 // AmbientLight represents a light source that emits light in all directions
 // from all points in space.
 type AmbientLight struct {
@@ -42,6 +56,14 @@ type AmbientLight struct {
 	// for refraction mapping.
 	RefractionTextureID uint32
 
+	// Mode determines whether the refraction texture or the IrradianceSH9
+	// coefficients are used for diffuse lighting.
+	Mode AmbientLightMode
+
+	// IrradianceSH9 holds the nine spherical harmonics coefficients of the
+	// irradiance, ordered by band. It is only used in AmbientLightModeSH9.
+	IrradianceSH9 [9]dprec.Vec3
+
 	// CastShadow specifies whether a SSAO-type technique should be applied.
 	CastShadow bool
 }
@@ -146,6 +146,10 @@ type VertexLayout struct {
 
 	// Joints specifies the layout of the vertex joints attribute.
 	Joints VertexAttribute
+
+	// TexCoord1 specifies the layout of the secondary vertex texture
+	// coordinate attribute.
+	TexCoord1 VertexAttribute
 }
 
 // Fragment represents a portion of a mesh that is drawn with a specific
@@ -0,0 +1,41 @@
+package dto
+
+const LODChunkID = "lacking:lod"
+
+type LODChunkHolder struct {
+	LODChunk *LODChunk `chunk:"lacking:lod"`
+}
+
+type LODChunk struct {
+	// LODSets is the collection of level of detail sets of the mesh
+	// definitions that are part of the scene.
+	LODSets []LODSet
+}
+
+// LODSet represents the simplified levels of detail of a mesh definition.
+type LODSet struct {
+
+	// MeshDefinitionID is the ID of the mesh definition that the levels of
+	// detail apply to.
+	MeshDefinitionID uint32
+
+	// Levels is the list of levels of detail, ordered from the most to the
+	// least detailed one. The geometry of the mesh definition itself is not
+	// included.
+	Levels []LODLevel
+}
+
+// LODLevel represents a simplified version of a mesh definition.
+type LODLevel struct {
+
+	// GeometryID is the ID of the simplified geometry.
+	GeometryID uint32
+
+	// MaterialBindings is the collection of material bindings that are used
+	// by the fragments of the simplified geometry.
+	MaterialBindings []MaterialBinding
+
+	// ScreenSize is the portion of the viewport height that the bounding
+	// sphere of the mesh needs to drop below for this level to be used.
+	ScreenSize float64
+}
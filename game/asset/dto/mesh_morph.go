@@ -0,0 +1,46 @@
+package dto
+
+const MorphChunkID = "lacking:morph"
+
+type MorphChunkHolder struct {
+	MorphChunk *MorphChunk `chunk:"lacking:morph"`
+}
+
+type MorphChunk struct {
+	// MorphSets is the collection of morph target sets of the geometries that
+	// are part of the scene.
+	MorphSets []MorphSet
+}
+
+// MorphSet represents the morph targets (blend shapes) of a geometry.
+type MorphSet struct {
+
+	// GeometryID is the ID of the geometry that the morph targets apply to.
+	GeometryID uint32
+
+	// Targets is the list of morph targets of the geometry.
+	Targets []MorphTarget
+
+	// HasNormalDeltas specifies whether Deltas contains normal deltas in
+	// addition to the vertex coordinate deltas.
+	HasNormalDeltas bool
+
+	// Deltas contains the per-vertex deltas of all morph targets as a
+	// sequence of RGBA32F values, ordered by morph target and then by vertex.
+	// Each vertex has one value with the coordinate delta, followed by one
+	// value with the normal delta when HasNormalDeltas is set. The alpha
+	// components are unused.
+	Deltas []byte
+}
+
+// MorphTarget represents a set of per-vertex deltas that are added to the
+// vertices of a geometry, scaled by the weight of the morph target.
+type MorphTarget struct {
+
+	// Name is the name of the morph target.
+	Name string
+
+	// DefaultWeight is the weight that is used for the morph target unless
+	// it is changed at runtime.
+	DefaultWeight float32
+}
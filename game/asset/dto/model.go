@@ -11,4 +11,6 @@ type Model struct {
 	PhysicsChunkHolder
 	CameraChunkHolder
 	BackgroundChunkHolder
+	MorphChunkHolder
+	LODChunkHolder
 }
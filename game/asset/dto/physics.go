@@ -66,6 +66,10 @@ type BodyDefinition struct {
 	// collision shape of the body.
 	CollisionSpheres []CollisionSphere
 
+	// CollisionConvexHulls is a list of convex collision volumes that define
+	// the collision shape of the body.
+	CollisionConvexHulls []CollisionConvexHull
+
 	// CollisionMeshes is a list of collision meshes that define the
 	// collision shape of the body.
 	CollisionMeshes []CollisionMesh
@@ -115,6 +119,20 @@ type CollisionSphere struct {
 	Radius float64
 }
 
+// CollisionConvexHull represents a convex collision volume.
+type CollisionConvexHull struct {
+
+	// Translation is the position of the hull.
+	Translation dprec.Vec3
+
+	// Rotation is the orientation of the hull.
+	Rotation dprec.Quat
+
+	// Triangles is a list of triangles that form the boundary of the hull.
+	// Their vertices are ordered so that the normals face outward.
+	Triangles []CollisionTriangle
+}
+
 // CollisionMesh represents a mesh-shaped collision volume.
 type CollisionMesh struct {
 
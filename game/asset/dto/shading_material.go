@@ -56,6 +56,11 @@ type MaterialPass struct {
 
 	// ShaderID is the ID of the shader to be used.
 	ShaderID uint32
+
+	// AdditiveBlending specifies whether the output should be added to the
+	// background instead of being mixed with it. Only relevant when Blending
+	// is enabled.
+	AdditiveBlending bool
 }
 
 // Material represents a material that can be applied to a mesh.
@@ -19,6 +19,11 @@ const (
 	TexelFormatRGBA32F
 	TexelFormatDepth16F
 	TexelFormatDepth32F
+	TexelFormatBC1
+	TexelFormatBC3
+	TexelFormatBC4
+	TexelFormatBC5
+	TexelFormatBC7
 )
 
 type TexelFormat uint8
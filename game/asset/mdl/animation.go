@@ -59,6 +59,7 @@ type AnimationBinding struct {
 	translationKeyframes []TranslationKeyframe
 	rotationKeyframes    []RotationKeyframe
 	scaleKeyframes       []ScaleKeyframe
+	morphWeightKeyframes []MorphWeightKeyframe
 }
 
 func (a *AnimationBinding) NodeName() string {
@@ -93,9 +94,38 @@ func (a *AnimationBinding) AddScaleKeyframe(keyframe ScaleKeyframe) {
 	a.scaleKeyframes = append(a.scaleKeyframes, keyframe)
 }
 
+func (a *AnimationBinding) MorphWeightKeyframes() []MorphWeightKeyframe {
+	return a.morphWeightKeyframes
+}
+
+func (a *AnimationBinding) AddMorphWeightKeyframe(keyframe MorphWeightKeyframe) {
+	a.morphWeightKeyframes = append(a.morphWeightKeyframes, keyframe)
+}
+
+const (
+	// InterpolationLinear specifies that values are linearly interpolated
+	// towards the next keyframe.
+	InterpolationLinear Interpolation = iota
+
+	// InterpolationStep specifies that the value is held until the next
+	// keyframe.
+	InterpolationStep
+
+	// InterpolationCubicSpline specifies that values follow a cubic Hermite
+	// spline towards the next keyframe, as defined by the out tangent of the
+	// keyframe and the in tangent of the next keyframe.
+	InterpolationCubicSpline
+)
+
+// Interpolation specifies how values are interpolated between keyframes.
+type Interpolation uint8
+
 type Keyframe[T any] struct {
-	Timestamp float64
-	Value     T
+	Timestamp     float64
+	Value         T
+	Interpolation Interpolation
+	InTangent     T
+	OutTangent    T
 }
 
 type TranslationKeyframe = Keyframe[dprec.Vec3]
@@ -103,3 +133,5 @@ type TranslationKeyframe = Keyframe[dprec.Vec3]
 type RotationKeyframe = Keyframe[dprec.Quat]
 
 type ScaleKeyframe = Keyframe[dprec.Vec3]
+
+type MorphWeightKeyframe = Keyframe[[]float64]
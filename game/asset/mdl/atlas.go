@@ -0,0 +1,94 @@
+package mdl
+
+import "github.com/mokiat/gomath/sprec"
+
+// NewAtlas creates a new empty texture atlas.
+func NewAtlas() *Atlas {
+	return &Atlas{}
+}
+
+// Atlas represents a set of named images that have been packed into one or
+// more textures, called pages.
+type Atlas struct {
+	name    string
+	pages   []*Texture
+	regions []AtlasRegion
+}
+
+// Name returns the name of the atlas.
+func (a *Atlas) Name() string {
+	return a.name
+}
+
+// SetName changes the name of the atlas.
+func (a *Atlas) SetName(name string) {
+	a.name = name
+}
+
+// Pages returns the textures that hold the packed images.
+func (a *Atlas) Pages() []*Texture {
+	return a.pages
+}
+
+// AddPage adds a texture that holds packed images.
+func (a *Atlas) AddPage(page *Texture) {
+	a.pages = append(a.pages, page)
+}
+
+// Regions returns the locations of the packed images.
+func (a *Atlas) Regions() []AtlasRegion {
+	return a.regions
+}
+
+// AddRegion adds the location of a packed image.
+func (a *Atlas) AddRegion(region AtlasRegion) {
+	a.regions = append(a.regions, region)
+}
+
+// FindRegion returns the region with the specified name.
+func (a *Atlas) FindRegion(name string) (AtlasRegion, bool) {
+	for _, region := range a.regions {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return AtlasRegion{}, false
+}
+
+func (a *Atlas) AllShaders() []*Shader {
+	return nil
+}
+
+func (a *Atlas) AllTextures() []*Texture {
+	return a.pages
+}
+
+func (a *Atlas) AllMaterials() []*Material {
+	return nil
+}
+
+func (a *Atlas) AllAtlasRegions() []AtlasRegion {
+	return a.regions
+}
+
+// AtlasRegion represents the location of a packed image within an atlas.
+type AtlasRegion struct {
+
+	// Name is the name of the image.
+	Name string
+
+	// Page is the texture that holds the image.
+	Page *Texture
+
+	// X, Y, Width and Height specify the texels of the page that hold the
+	// image, where the origin is the top-left corner of the page.
+	X      int
+	Y      int
+	Width  int
+	Height int
+
+	// MinUV and MaxUV are the tex coords of the bottom-left and top-right
+	// corners of the image.
+	MinUV sprec.Vec2
+	MaxUV sprec.Vec2
+}
@@ -0,0 +1,95 @@
+package mdl
+
+import "github.com/mokiat/gomath/dprec"
+
+const (
+	CameraProjectionPerspective CameraProjection = iota
+	CameraProjectionOrthographic
+)
+
+// CameraProjection determines how a camera projects the scene.
+type CameraProjection uint8
+
+func NewCamera(name string) *Camera {
+	return &Camera{
+		Object:      NewObject(),
+		name:        name,
+		projection:  CameraProjectionPerspective,
+		fov:         dprec.Degrees(60),
+		orthoHeight: 10.0,
+		near:        0.1,
+		far:         4000.0,
+	}
+}
+
+type Camera struct {
+	*Object
+	name        string
+	projection  CameraProjection
+	fov         dprec.Angle
+	aspectRatio float64
+	orthoHeight float64
+	near        float64
+	far         float64
+}
+
+func (c *Camera) Name() string {
+	return c.name
+}
+
+func (c *Camera) SetName(name string) {
+	c.name = name
+}
+
+func (c *Camera) Projection() CameraProjection {
+	return c.projection
+}
+
+func (c *Camera) SetProjection(projection CameraProjection) {
+	c.projection = projection
+}
+
+// FoV returns the vertical field of view of a perspective camera.
+func (c *Camera) FoV() dprec.Angle {
+	return c.fov
+}
+
+func (c *Camera) SetFoV(fov dprec.Angle) {
+	c.fov = fov
+}
+
+// AspectRatio returns the preferred aspect ratio of the camera. A value of
+// zero means that the aspect ratio of the viewport should be used.
+func (c *Camera) AspectRatio() float64 {
+	return c.aspectRatio
+}
+
+func (c *Camera) SetAspectRatio(ratio float64) {
+	c.aspectRatio = ratio
+}
+
+// OrthographicHeight returns the visible vertical extent of an orthographic
+// camera.
+func (c *Camera) OrthographicHeight() float64 {
+	return c.orthoHeight
+}
+
+func (c *Camera) SetOrthographicHeight(height float64) {
+	c.orthoHeight = height
+}
+
+func (c *Camera) Near() float64 {
+	return c.near
+}
+
+func (c *Camera) SetNear(near float64) {
+	c.near = near
+}
+
+func (c *Camera) Far() float64 {
+	return c.far
+}
+
+func (c *Camera) SetFar(far float64) {
+	c.far = far
+}
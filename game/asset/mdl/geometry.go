@@ -27,6 +27,22 @@ const (
 	VertexFormatColor
 	VertexFormatWeights
 	VertexFormatJoints
+	VertexFormatTexCoord1
+)
+
+type VertexAttributeFormat = dto.VertexAttributeFormat
+
+const (
+	VertexAttributeFormatRGB32F  VertexAttributeFormat = dto.VertexAttributeFormatRGB32F
+	VertexAttributeFormatRG32F   VertexAttributeFormat = dto.VertexAttributeFormatRG32F
+	VertexAttributeFormatRGB16F  VertexAttributeFormat = dto.VertexAttributeFormatRGB16F
+	VertexAttributeFormatRG16F   VertexAttributeFormat = dto.VertexAttributeFormatRG16F
+	VertexAttributeFormatRGB16SN VertexAttributeFormat = dto.VertexAttributeFormatRGB16SN
+	VertexAttributeFormatRG16SN  VertexAttributeFormat = dto.VertexAttributeFormatRG16SN
+	VertexAttributeFormatRG16UN  VertexAttributeFormat = dto.VertexAttributeFormatRG16UN
+	VertexAttributeFormatRGB8SN  VertexAttributeFormat = dto.VertexAttributeFormatRGB8SN
+	VertexAttributeFormatRGBA16F VertexAttributeFormat = dto.VertexAttributeFormatRGBA16F
+	VertexAttributeFormatRGBA8SN VertexAttributeFormat = dto.VertexAttributeFormatRGBA8SN
 )
 
 func NewGeometry() *Geometry {
@@ -40,15 +56,17 @@ func NewGeometry() *Geometry {
 
 type Geometry struct {
 	*Object
-	name         string
-	metadata     Metadata
-	vertexFormat VertexFormat
-	vertices     []Vertex
-	indices      []int
-	fragments    []*Fragment
-	minDistance  float64
-	maxDistance  float64
-	maxCascade   int
+	name          string
+	metadata      Metadata
+	vertexFormat  VertexFormat
+	attribFormats map[VertexFormat]VertexAttributeFormat
+	vertices      []Vertex
+	indices       []int
+	fragments     []*Fragment
+	morphTargets  []*MorphTarget
+	minDistance   float64
+	maxDistance   float64
+	maxCascade    int
 }
 
 func (g *Geometry) Metadata() Metadata {
@@ -75,6 +93,24 @@ func (g *Geometry) SetFormat(format VertexFormat) {
 	g.vertexFormat = format
 }
 
+// AttributeFormat returns the storage format that is requested for the
+// specified vertex attribute. If no format has been requested, false is
+// returned and the default format for the attribute is used.
+func (g *Geometry) AttributeFormat(attribute VertexFormat) (VertexAttributeFormat, bool) {
+	format, ok := g.attribFormats[attribute]
+	return format, ok
+}
+
+// SetAttributeFormat requests that the specified vertex attribute be
+// stored in the specified format. Only the coord, normal, tangent and
+// tex coord attributes support custom formats.
+func (g *Geometry) SetAttributeFormat(attribute VertexFormat, format VertexAttributeFormat) {
+	if g.attribFormats == nil {
+		g.attribFormats = make(map[VertexFormat]VertexAttributeFormat)
+	}
+	g.attribFormats[attribute] = format
+}
+
 func (g *Geometry) VertexOffset() int {
 	return len(g.vertices)
 }
@@ -91,6 +127,12 @@ func (g *Geometry) AddVertex(vertex Vertex) {
 	g.vertices = append(g.vertices, vertex)
 }
 
+// SetVertices replaces the vertices of the geometry. Indices and morph
+// target deltas are not adjusted.
+func (g *Geometry) SetVertices(vertices []Vertex) {
+	g.vertices = vertices
+}
+
 func (g *Geometry) IndexOffset() int {
 	return len(g.indices)
 }
@@ -107,6 +149,12 @@ func (g *Geometry) AddIndex(index int) {
 	g.indices = append(g.indices, index)
 }
 
+// SetIndices replaces the indices of the geometry. Fragment offsets and
+// counts are not adjusted.
+func (g *Geometry) SetIndices(indices []int) {
+	g.indices = indices
+}
+
 func (g *Geometry) Fragments() []*Fragment {
 	return g.fragments
 }
@@ -115,6 +163,14 @@ func (g *Geometry) AddFragment(fragment *Fragment) {
 	g.fragments = append(g.fragments, fragment)
 }
 
+func (g *Geometry) MorphTargets() []*MorphTarget {
+	return g.morphTargets
+}
+
+func (g *Geometry) AddMorphTarget(target *MorphTarget) {
+	g.morphTargets = append(g.morphTargets, target)
+}
+
 func (g *Geometry) MinDistance() float64 {
 	return g.minDistance
 }
@@ -198,14 +254,80 @@ func (f *Fragment) SetIndexCount(count int) {
 	f.indexCount = count
 }
 
+func NewMorphTarget() *MorphTarget {
+	return &MorphTarget{}
+}
+
+// MorphTarget represents a blend shape of a geometry. It holds deltas for the
+// vertices of the geometry, which are indexed the same way as the vertices.
+type MorphTarget struct {
+	name          string
+	defaultWeight float64
+	deltas        []MorphDelta
+}
+
+func (t *MorphTarget) Name() string {
+	return t.name
+}
+
+func (t *MorphTarget) SetName(name string) {
+	t.name = name
+}
+
+func (t *MorphTarget) DefaultWeight() float64 {
+	return t.defaultWeight
+}
+
+func (t *MorphTarget) SetDefaultWeight(weight float64) {
+	t.defaultWeight = weight
+}
+
+// Deltas returns the deltas of the morph target. Vertices past the end of
+// the returned slice are not affected by the morph target.
+func (t *MorphTarget) Deltas() []MorphDelta {
+	return t.deltas
+}
+
+// Delta returns the delta for the vertex at the specified index.
+func (t *MorphTarget) Delta(at int) MorphDelta {
+	if at >= len(t.deltas) {
+		return MorphDelta{}
+	}
+	return t.deltas[at]
+}
+
+// SetDeltas replaces the deltas of the morph target.
+func (t *MorphTarget) SetDeltas(deltas []MorphDelta) {
+	t.deltas = deltas
+}
+
+// SetDelta sets the delta for the vertex at the specified index.
+func (t *MorphTarget) SetDelta(at int, delta MorphDelta) {
+	if at >= len(t.deltas) {
+		t.deltas = append(t.deltas, make([]MorphDelta, at-len(t.deltas)+1)...)
+	}
+	t.deltas[at] = delta
+}
+
+// MorphDelta represents the change that a morph target applies to a vertex
+// at full weight.
+type MorphDelta struct {
+	Coord  sprec.Vec3
+	Normal sprec.Vec3
+}
+
 type Vertex struct {
-	Coord    sprec.Vec3
-	Normal   sprec.Vec3
-	Tangent  sprec.Vec3
-	TexCoord sprec.Vec2
-	Color    sprec.Vec4
-	Weights  sprec.Vec4
-	Joints   [4]uint8
+	Coord  sprec.Vec3
+	Normal sprec.Vec3
+	// Tangent holds the tangent direction in XYZ and the handedness of the
+	// tangent space in W, which is either 1.0 or -1.0. The bitangent is
+	// the cross product of the normal and the tangent, multiplied by W.
+	Tangent   sprec.Vec4
+	TexCoord  sprec.Vec2
+	TexCoord1 sprec.Vec2
+	Color     sprec.Vec4
+	Weights   sprec.Vec4
+	Joints    [4]uint8
 }
 
 func (v Vertex) Translate(offset sprec.Vec3) Vertex {
@@ -216,7 +338,8 @@ func (v Vertex) Translate(offset sprec.Vec3) Vertex {
 func (v Vertex) Rotate(rotation sprec.Quat) Vertex {
 	v.Coord = sprec.QuatVec3Rotation(rotation, v.Coord)
 	v.Normal = sprec.QuatVec3Rotation(rotation, v.Normal)
-	v.Tangent = sprec.QuatVec3Rotation(rotation, v.Tangent)
+	tangent := sprec.QuatVec3Rotation(rotation, v.Tangent.VecXYZ())
+	v.Tangent = sprec.NewVec4(tangent.X, tangent.Y, tangent.Z, v.Tangent.W)
 	return v
 }
 
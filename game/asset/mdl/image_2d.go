@@ -1,6 +1,8 @@
 package mdl
 
 import (
+	"encoding/binary"
+	"fmt"
 	"math"
 
 	"github.com/mokiat/gblob"
@@ -187,3 +189,57 @@ func (i *Image) DataRGBA32F() []byte {
 	}
 	return data
 }
+
+// MarshalBinary encodes the image, including its name, in a lossless binary
+// form.
+func (i *Image) MarshalBinary() ([]byte, error) {
+	const texelSize = 4 * 8
+	data := make([]byte, 0, 4+len(i.name)+8+texelSize*len(i.texels))
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(i.name)))
+	data = append(data, i.name...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(i.width))
+	data = binary.LittleEndian.AppendUint32(data, uint32(i.height))
+	for _, texel := range i.texels {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(texel.R))
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(texel.G))
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(texel.B))
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(texel.A))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes an image that was encoded with MarshalBinary.
+func (i *Image) UnmarshalBinary(data []byte) error {
+	const texelSize = 4 * 8
+	if len(data) < 4 {
+		return fmt.Errorf("image data is truncated")
+	}
+	nameLength := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	if len(data) < nameLength+8 {
+		return fmt.Errorf("image data is truncated")
+	}
+	name := string(data[:nameLength])
+	data = data[nameLength:]
+	width := int(binary.LittleEndian.Uint32(data[0:]))
+	height := int(binary.LittleEndian.Uint32(data[4:]))
+	data = data[8:]
+	if len(data)%texelSize != 0 || uint64(len(data)/texelSize) != uint64(width)*uint64(height) {
+		return fmt.Errorf("image data size %d does not match dimensions %dx%d", len(data), width, height)
+	}
+	texels := make([]Color, width*height)
+	for j := range texels {
+		offset := j * texelSize
+		texels[j] = Color{
+			R: math.Float64frombits(binary.LittleEndian.Uint64(data[offset+0:])),
+			G: math.Float64frombits(binary.LittleEndian.Uint64(data[offset+8:])),
+			B: math.Float64frombits(binary.LittleEndian.Uint64(data[offset+16:])),
+			A: math.Float64frombits(binary.LittleEndian.Uint64(data[offset+24:])),
+		}
+	}
+	i.name = name
+	i.width = width
+	i.height = height
+	i.texels = texels
+	return nil
+}
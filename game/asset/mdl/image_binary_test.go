@@ -0,0 +1,153 @@
+package mdl_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("Image binary encoding", func() {
+	buildImage := func(name string, width, height int, seed float64) *mdl.Image {
+		image := mdl.NewImage(width, height)
+		image.SetName(name)
+		for y := range height {
+			for x := range width {
+				image.SetTexel(x, y, mdl.Color{
+					R: seed + float64(x),
+					G: seed - float64(y),
+					B: seed * 1.0e-7,
+					A: 1.0 / (seed + float64(x*y) + 3.0),
+				})
+			}
+		}
+		return image
+	}
+
+	expectEqualImages := func(actual, expected *mdl.Image) {
+		Expect(actual.Name()).To(Equal(expected.Name()))
+		Expect(actual.Width()).To(Equal(expected.Width()))
+		Expect(actual.Height()).To(Equal(expected.Height()))
+		for y := range expected.Height() {
+			for x := range expected.Width() {
+				Expect(actual.Texel(x, y)).To(Equal(expected.Texel(x, y)))
+			}
+		}
+	}
+
+	allSides := []mdl.CubeSide{
+		mdl.CubeSideFront,
+		mdl.CubeSideRear,
+		mdl.CubeSideLeft,
+		mdl.CubeSideRight,
+		mdl.CubeSideTop,
+		mdl.CubeSideBottom,
+	}
+
+	buildCubeImage := func(size int) *mdl.CubeImage {
+		image := mdl.NewCubeImage(size)
+		for i, side := range allSides {
+			image.SetSide(side, buildImage("", size, size, float64(i)*10.0+0.25))
+		}
+		return image
+	}
+
+	Describe("Image", func() {
+		It("round-trips the name, dimensions and texels", func() {
+			original := buildImage("albedo", 5, 3, 1.5)
+			data, err := original.MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded := new(mdl.Image)
+			Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+			expectEqualImages(decoded, original)
+		})
+
+		It("preserves special floating point values", func() {
+			original := mdl.NewImage(2, 1)
+			original.SetTexel(0, 0, mdl.Color{R: math.Inf(1), G: math.Inf(-1), B: math.SmallestNonzeroFloat64, A: math.Copysign(0.0, -1.0)})
+			original.SetTexel(1, 0, mdl.Color{R: math.MaxFloat64, G: 1.0e300, B: -1.0e-300, A: 1.0})
+			data, err := original.MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded := new(mdl.Image)
+			Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+			expectEqualImages(decoded, original)
+			Expect(math.Signbit(decoded.Texel(0, 0).A)).To(BeTrue())
+		})
+
+		It("round-trips an empty image", func() {
+			original := mdl.NewImage(0, 0)
+			data, err := original.MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded := new(mdl.Image)
+			Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+			expectEqualImages(decoded, original)
+		})
+
+		It("round-trips through gob", func() {
+			original := buildImage("normal", 4, 4, 2.0)
+			var buffer bytes.Buffer
+			Expect(gob.NewEncoder(&buffer).Encode(original)).To(Succeed())
+
+			var decoded *mdl.Image
+			Expect(gob.NewDecoder(&buffer).Decode(&decoded)).To(Succeed())
+			expectEqualImages(decoded, original)
+		})
+
+		It("rejects truncated data", func() {
+			data, err := buildImage("albedo", 3, 3, 1.0).MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+			for _, length := range []int{0, 3, 8, 16, len(data) - 1} {
+				Expect(new(mdl.Image).UnmarshalBinary(data[:length])).ToNot(Succeed())
+			}
+		})
+	})
+
+	Describe("CubeImage", func() {
+		It("round-trips the size and all sides", func() {
+			original := buildCubeImage(4)
+			data, err := original.MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded := new(mdl.CubeImage)
+			Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+			for _, side := range allSides {
+				expectEqualImages(decoded.Side(side), original.Side(side))
+			}
+		})
+
+		It("round-trips through gob", func() {
+			original := buildCubeImage(2)
+			var buffer bytes.Buffer
+			Expect(gob.NewEncoder(&buffer).Encode(original)).To(Succeed())
+
+			var decoded *mdl.CubeImage
+			Expect(gob.NewDecoder(&buffer).Decode(&decoded)).To(Succeed())
+			for _, side := range allSides {
+				expectEqualImages(decoded.Side(side), original.Side(side))
+			}
+		})
+
+		It("rejects truncated data", func() {
+			data, err := buildCubeImage(2).MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+			for _, length := range []int{0, 3, 8, len(data) / 2, len(data) - 1} {
+				Expect(new(mdl.CubeImage).UnmarshalBinary(data[:length])).ToNot(Succeed())
+			}
+		})
+
+		It("rejects sides that do not match the cube size", func() {
+			data, err := buildCubeImage(2).MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+			binary.LittleEndian.PutUint32(data, 3)
+			Expect(new(mdl.CubeImage).UnmarshalBinary(data)).ToNot(Succeed())
+		})
+	})
+})
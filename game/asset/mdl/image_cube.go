@@ -1,6 +1,9 @@
 package mdl
 
 import (
+	"encoding/binary"
+	"fmt"
+
 	"github.com/mokiat/gomath/dprec"
 )
 
@@ -71,3 +74,50 @@ func (i *CubeImage) MapTexels(fn func(texel Color) Color) *CubeImage {
 	}
 	return dstImage
 }
+
+// MarshalBinary encodes the cube image in a lossless binary form.
+func (i *CubeImage) MarshalBinary() ([]byte, error) {
+	data := binary.LittleEndian.AppendUint32(nil, uint32(i.size))
+	for _, sideImage := range i.sides {
+		sideData, err := sideImage.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(sideData)))
+		data = append(data, sideData...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a cube image that was encoded with MarshalBinary.
+func (i *CubeImage) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("cube image data is truncated")
+	}
+	size := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+
+	var sides [6]*Image
+	for side := range sides {
+		if len(data) < 4 {
+			return fmt.Errorf("cube image data is truncated")
+		}
+		sideLength := int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		if len(data) < sideLength {
+			return fmt.Errorf("cube image data is truncated")
+		}
+		sideImage := new(Image)
+		if err := sideImage.UnmarshalBinary(data[:sideLength]); err != nil {
+			return fmt.Errorf("error decoding side %d: %w", side, err)
+		}
+		if sideImage.width != size || sideImage.height != size {
+			return fmt.Errorf("side %d has invalid dimensions %dx%d", side, sideImage.width, sideImage.height)
+		}
+		sides[side] = sideImage
+		data = data[sideLength:]
+	}
+	i.size = size
+	i.sides = sides
+	return nil
+}
@@ -0,0 +1,128 @@
+package mdl
+
+import (
+	"math"
+	"sync"
+
+	"github.com/mokiat/gomath/dprec"
+)
+
+// SH9 holds nine RGB coefficients of a real spherical harmonics expansion
+// that uses the first three bands.
+//
+// The coefficients are ordered by band and then by index within the band,
+// that is (0,0), (1,-1), (1,0), (1,1), (2,-2), (2,-1), (2,0), (2,1), (2,2).
+type SH9 [9]dprec.Vec3
+
+// Evaluate returns the value of the expansion in the specified direction,
+// which needs to be normalized.
+func (s SH9) Evaluate(direction dprec.Vec3) dprec.Vec3 {
+	basis := sh9Basis(direction)
+	var result dprec.Vec3
+	for i, coefficient := range s {
+		result = dprec.Vec3Sum(result, dprec.Vec3Prod(coefficient, basis[i]))
+	}
+	return result
+}
+
+// BuildIrradianceSH9 projects the provided HDR skybox cube image onto the
+// first three spherical harmonics bands and convolves the result with a
+// clamped cosine lobe.
+//
+// Evaluating the returned coefficients in the direction of a surface normal
+// approximates the irradiance that BuildIrradianceCubeImage produces for that
+// direction when minDot is zero. The approximation is accurate for smooth
+// lighting environments but cannot represent small and bright light sources.
+func BuildIrradianceSH9(srcImage *CubeImage) SH9 {
+	var sideProjections [6]SH9
+	var group sync.WaitGroup
+	for i := range srcImage.sides {
+		group.Go(func() {
+			sideProjections[i] = projectSH9CubeImageSide(srcImage, CubeSide(i))
+		})
+	}
+	group.Wait()
+
+	// Convolution with a clamped cosine reduces to a per-band scale
+	// (Ramamoorthi and Hanrahan, An Efficient Representation for Irradiance
+	// Environment Maps).
+	bandScales := [3]float64{
+		dprec.Pi,
+		2.0 * dprec.Pi / 3.0,
+		dprec.Pi / 4.0,
+	}
+	var result SH9
+	for _, projection := range sideProjections {
+		for i, coefficient := range projection {
+			result[i] = dprec.Vec3Sum(result[i], coefficient)
+		}
+	}
+	for i := range result {
+		result[i] = dprec.Vec3Prod(result[i], bandScales[sh9Band(i)])
+	}
+	return result
+}
+
+func projectSH9CubeImageSide(srcImage *CubeImage, side CubeSide) SH9 {
+	dimension := srcImage.size
+	srcSide := srcImage.Side(side)
+	texelSize := 2.0 / float64(dimension)
+
+	var result SH9
+	for y := range dimension {
+		for x := range dimension {
+			// The first row of an image is at the top, where v is one.
+			uv := dprec.NewVec2(
+				(float64(x)+0.5)/float64(dimension),
+				1.0-(float64(y)+0.5)/float64(dimension),
+			)
+			direction := CubeUVToUVW(side, uv)
+
+			left := float64(x)*texelSize - 1.0
+			bottom := float64(dimension-y-1)*texelSize - 1.0
+			weight := cubeTexelSolidAngle(left, bottom, left+texelSize, bottom+texelSize)
+
+			texel := srcSide.Texel(x, y)
+			radiance := dprec.NewVec3(texel.R, texel.G, texel.B)
+			for i, basis := range sh9Basis(direction) {
+				result[i] = dprec.Vec3Sum(result[i], dprec.Vec3Prod(radiance, basis*weight))
+			}
+		}
+	}
+	return result
+}
+
+// cubeTexelSolidAngle returns the solid angle of the area of a cube side
+// that is bounded by the specified coordinates, each in the range [-1, 1].
+func cubeTexelSolidAngle(minX, minY, maxX, maxY float64) float64 {
+	areaElement := func(x, y float64) float64 {
+		return math.Atan2(x*y, math.Sqrt(x*x+y*y+1.0))
+	}
+	return areaElement(minX, minY) - areaElement(minX, maxY) - areaElement(maxX, minY) + areaElement(maxX, maxY)
+}
+
+func sh9Band(index int) int {
+	switch {
+	case index < 1:
+		return 0
+	case index < 4:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func sh9Basis(direction dprec.Vec3) [9]float64 {
+	x, y, z := direction.X, direction.Y, direction.Z
+	return [9]float64{
+		0.282095,
+		0.488603 * y,
+		0.488603 * z,
+		0.488603 * x,
+		1.092548 * x * y,
+		1.092548 * y * z,
+		0.315392 * (3.0*z*z - 1.0),
+		1.092548 * x * z,
+		0.546274 * (x*x - y*y),
+	}
+}
@@ -0,0 +1,120 @@
+package mdl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("SH9", func() {
+	const (
+		cubeSize    = 16
+		sampleCount = 40
+	)
+
+	allSides := []mdl.CubeSide{
+		mdl.CubeSideFront,
+		mdl.CubeSideRear,
+		mdl.CubeSideLeft,
+		mdl.CubeSideRight,
+		mdl.CubeSideTop,
+		mdl.CubeSideBottom,
+	}
+
+	buildCubeImage := func(radiance func(direction dprec.Vec3) dprec.Vec3) *mdl.CubeImage {
+		image := mdl.NewCubeImage(cubeSize)
+		for _, side := range allSides {
+			sideImage := image.Side(side)
+			for y := range cubeSize {
+				for x := range cubeSize {
+					direction := mdl.CubeUVToUVW(side, dprec.NewVec2(
+						(float64(x)+0.5)/cubeSize,
+						1.0-(float64(y)+0.5)/cubeSize,
+					))
+					color := radiance(direction)
+					sideImage.SetTexel(x, y, mdl.Color{
+						R: color.X,
+						G: color.Y,
+						B: color.Z,
+						A: 1.0,
+					})
+				}
+			}
+		}
+		return image
+	}
+
+	// expectMatchingIrradiance checks that the coefficients evaluate to the
+	// same irradiance as the irradiance cube image in the direction of each
+	// of its texels. The cube image is produced by numerical integration,
+	// which has an error of a few percent on its own.
+	expectMatchingIrradiance := func(coefficients mdl.SH9, irradianceImage *mdl.CubeImage) {
+		for _, side := range allSides {
+			sideImage := irradianceImage.Side(side)
+			for y := range cubeSize {
+				for x := range cubeSize {
+					// This matches the directions used by BuildIrradianceCubeImage.
+					direction := mdl.CubeUVToUVW(side, dprec.NewVec2(
+						float64(x)/(cubeSize-1),
+						1.0-float64(y)/(cubeSize-1),
+					))
+					expected := sideImage.Texel(x, y)
+					actual := coefficients.Evaluate(direction)
+					Expect(actual.X).To(BeNumerically("~", expected.R, expected.R*0.06))
+					Expect(actual.Y).To(BeNumerically("~", expected.G, expected.G*0.06))
+					Expect(actual.Z).To(BeNumerically("~", expected.B, expected.B*0.06))
+				}
+			}
+		}
+	}
+
+	Describe("BuildIrradianceSH9", func() {
+		It("produces uniform irradiance for a uniform environment", func() {
+			image := buildCubeImage(func(dprec.Vec3) dprec.Vec3 {
+				return dprec.NewVec3(0.5, 1.0, 2.0)
+			})
+			coefficients := mdl.BuildIrradianceSH9(image)
+
+			for _, direction := range []dprec.Vec3{
+				dprec.BasisXVec3(),
+				dprec.InverseVec3(dprec.BasisYVec3()),
+				dprec.UnitVec3(dprec.NewVec3(1.0, 2.0, 3.0)),
+			} {
+				irradiance := coefficients.Evaluate(direction)
+				Expect(irradiance.X).To(BeNumerically("~", 0.5*dprec.Pi, 0.001))
+				Expect(irradiance.Y).To(BeNumerically("~", 1.0*dprec.Pi, 0.001))
+				Expect(irradiance.Z).To(BeNumerically("~", 2.0*dprec.Pi, 0.001))
+			}
+			expectMatchingIrradiance(coefficients, mdl.BuildIrradianceCubeImage(image, sampleCount, 0.0))
+		})
+
+		It("matches the irradiance cube image for a sky environment", func() {
+			image := buildCubeImage(func(direction dprec.Vec3) dprec.Vec3 {
+				up := max(direction.Y, 0.0)
+				return dprec.NewVec3(
+					0.2+1.5*up,
+					0.3+1.0*up+0.2*direction.X,
+					0.6+0.5*up*up+0.3*direction.Z,
+				)
+			})
+			coefficients := mdl.BuildIrradianceSH9(image)
+			expectMatchingIrradiance(coefficients, mdl.BuildIrradianceCubeImage(image, sampleCount, 0.0))
+		})
+
+		It("is brighter towards a light source", func() {
+			image := buildCubeImage(func(direction dprec.Vec3) dprec.Vec3 {
+				intensity := max(direction.X, 0.0)
+				return dprec.NewVec3(intensity, intensity, intensity)
+			})
+			coefficients := mdl.BuildIrradianceSH9(image)
+
+			towards := coefficients.Evaluate(dprec.BasisXVec3())
+			sideways := coefficients.Evaluate(dprec.BasisZVec3())
+			away := coefficients.Evaluate(dprec.InverseVec3(dprec.BasisXVec3()))
+			Expect(towards.X).To(BeNumerically(">", sideways.X))
+			Expect(sideways.X).To(BeNumerically(">", away.X))
+		})
+	})
+})
@@ -94,6 +94,7 @@ type AmbientLight struct {
 	BaseShadowCaster
 	reflectionTexture *Texture
 	refractionTexture *Texture
+	irradianceSH9     *SH9
 }
 
 func (l *AmbientLight) ReflectionTexture() *Texture {
@@ -112,6 +113,23 @@ func (l *AmbientLight) SetRefractionTexture(texture *Texture) {
 	l.refractionTexture = texture
 }
 
+// IrradianceSH9 returns the spherical harmonics coefficients that the light
+// uses for diffuse lighting instead of the refraction texture. The second
+// return value is false if the light uses the refraction texture.
+func (l *AmbientLight) IrradianceSH9() (SH9, bool) {
+	if l.irradianceSH9 == nil {
+		return SH9{}, false
+	}
+	return *l.irradianceSH9, true
+}
+
+// SetIrradianceSH9 configures the light to use the specified spherical
+// harmonics coefficients (see BuildIrradianceSH9) for diffuse lighting
+// instead of the refraction texture.
+func (l *AmbientLight) SetIrradianceSH9(coefficients SH9) {
+	l.irradianceSH9 = &coefficients
+}
+
 func NewPointLight() *PointLight {
 	return &PointLight{
 		Object: NewObject(),
@@ -177,25 +177,27 @@ func (m *Material) AllPasses() []*MaterialPass {
 
 func NewMaterialPass() *MaterialPass {
 	return &MaterialPass{
-		layer:           0,
-		culling:         CullModeNone,
-		frontFace:       FaceOrientationCCW,
-		depthTest:       true,
-		depthWrite:      true,
-		depthComparison: ComparisonLessOrEqual,
-		blending:        false,
+		layer:            0,
+		culling:          CullModeNone,
+		frontFace:        FaceOrientationCCW,
+		depthTest:        true,
+		depthWrite:       true,
+		depthComparison:  ComparisonLessOrEqual,
+		blending:         false,
+		additiveBlending: false,
 	}
 }
 
 type MaterialPass struct {
-	layer           int
-	culling         CullMode
-	frontFace       FaceOrientation
-	depthTest       bool
-	depthWrite      bool
-	depthComparison Comparison
-	blending        bool
-	shader          *Shader
+	layer            int
+	culling          CullMode
+	frontFace        FaceOrientation
+	depthTest        bool
+	depthWrite       bool
+	depthComparison  Comparison
+	blending         bool
+	additiveBlending bool
+	shader           *Shader
 }
 
 func (m *MaterialPass) Layer() int {
@@ -254,6 +256,14 @@ func (m *MaterialPass) SetBlending(blending bool) {
 	m.blending = blending
 }
 
+func (m *MaterialPass) AdditiveBlending() bool {
+	return m.additiveBlending
+}
+
+func (m *MaterialPass) SetAdditiveBlending(additive bool) {
+	m.additiveBlending = additive
+}
+
 func (m *MaterialPass) Shader() *Shader {
 	return m.shader
 }
@@ -19,6 +19,7 @@ type MeshDefinition struct {
 	name             string
 	geometry         *Geometry
 	materialBindings map[string]*Material
+	lods             []MeshLOD
 }
 
 func (m *MeshDefinition) Name() string {
@@ -49,6 +50,33 @@ func (m *MeshDefinition) MaterialBindings() map[string]*Material {
 	return m.materialBindings
 }
 
+// LODs returns the simplified levels of detail of the mesh definition,
+// ordered from the most to the least detailed one.
+func (m *MeshDefinition) LODs() []MeshLOD {
+	return m.lods
+}
+
+// SetLODs replaces the simplified levels of detail of the mesh definition.
+// The levels should be ordered from the most to the least detailed one.
+func (m *MeshDefinition) SetLODs(lods []MeshLOD) {
+	m.lods = lods
+}
+
+// MeshLOD represents a simplified version of the geometry of a mesh
+// definition that is used when the mesh is small on the screen.
+//
+// The fragments of the LOD geometry are bound to materials by name, the
+// same way as the fragments of the main geometry.
+type MeshLOD struct {
+
+	// Geometry is the simplified geometry.
+	Geometry *Geometry
+
+	// ScreenSize is the portion of the viewport height that the bounding
+	// sphere of the mesh needs to drop below for this LOD to be used.
+	ScreenSize float64
+}
+
 func NewMesh() *Mesh {
 	return &Mesh{
 		Object: NewObject(),
@@ -160,6 +160,19 @@ func (s *Model) AllDirectionalLightPlacements() []Placed[*DirectionalLight] {
 	return result
 }
 
+func (s *Model) AllCameraPlacements() []Placed[*Camera] {
+	var result []Placed[*Camera]
+	for _, node := range s.NodesIter() {
+		for camera := range NodeAttachmentsOfType[*Camera](node) {
+			result = append(result, Placed[*Camera]{
+				Node:  node,
+				Value: camera,
+			})
+		}
+	}
+	return result
+}
+
 func (s *Model) AllArmatures() []*Armature {
 	var result []*Armature
 	for _, placement := range s.AllMeshPlacements() {
@@ -175,6 +188,9 @@ func (s *Model) AllGeometries() []*Geometry {
 	var result []*Geometry
 	for _, definition := range s.AllMeshDefinitions() {
 		result = append(result, definition.Geometry())
+		for _, lod := range definition.LODs() {
+			result = append(result, lod.Geometry)
+		}
 	}
 	return gog.Dedupe(result)
 }
@@ -41,14 +41,15 @@ func NewBodyDefinition(material *BodyMaterial) *BodyDefinition {
 
 type BodyDefinition struct {
 	*Object
-	material          *BodyMaterial
-	mass              float64
-	momentOfInertia   dprec.Mat3
-	dragFactor        float64
-	angularDragFactor float64
-	collisionBoxes    []*CollisionBox
-	collisionSpheres  []*CollisionSphere
-	collisionMeshes   []*CollisionMesh
+	material             *BodyMaterial
+	mass                 float64
+	momentOfInertia      dprec.Mat3
+	dragFactor           float64
+	angularDragFactor    float64
+	collisionBoxes       []*CollisionBox
+	collisionSpheres     []*CollisionSphere
+	collisionConvexHulls []*CollisionConvexHull
+	collisionMeshes      []*CollisionMesh
 }
 
 func (d *BodyDefinition) Material() *BodyMaterial {
@@ -103,6 +104,18 @@ func (d *BodyDefinition) AddCollisionSphere(value *CollisionSphere) {
 	d.collisionSpheres = append(d.collisionSpheres, value)
 }
 
+func (d *BodyDefinition) CollisionConvexHulls() []*CollisionConvexHull {
+	return d.collisionConvexHulls
+}
+
+func (d *BodyDefinition) SetCollisionConvexHulls(collisionConvexHulls []*CollisionConvexHull) {
+	d.collisionConvexHulls = collisionConvexHulls
+}
+
+func (d *BodyDefinition) AddCollisionConvexHull(value *CollisionConvexHull) {
+	d.collisionConvexHulls = append(d.collisionConvexHulls, value)
+}
+
 func (d *BodyDefinition) CollisionMeshes() []*CollisionMesh {
 	return d.collisionMeshes
 }
@@ -242,6 +255,45 @@ func (m *CollisionMesh) AddTriangle(value CollisionTriangle) {
 	m.triangles = append(m.triangles, value)
 }
 
+func NewCollisionConvexHull() *CollisionConvexHull {
+	return &CollisionConvexHull{
+		translation: dprec.ZeroVec3(),
+		rotation:    dprec.IdentityQuat(),
+	}
+}
+
+// CollisionConvexHull represents a convex collision volume that is described
+// by the triangles of its boundary, with outward-facing normals.
+type CollisionConvexHull struct {
+	translation dprec.Vec3
+	rotation    dprec.Quat
+	triangles   []CollisionTriangle
+}
+
+func (h *CollisionConvexHull) Translation() dprec.Vec3 {
+	return h.translation
+}
+
+func (h *CollisionConvexHull) SetTranslation(value dprec.Vec3) {
+	h.translation = value
+}
+
+func (h *CollisionConvexHull) Rotation() dprec.Quat {
+	return h.rotation
+}
+
+func (h *CollisionConvexHull) SetRotation(value dprec.Quat) {
+	h.rotation = value
+}
+
+func (h *CollisionConvexHull) Triangles() []CollisionTriangle {
+	return h.triangles
+}
+
+func (h *CollisionConvexHull) SetTriangles(triangles []CollisionTriangle) {
+	h.triangles = triangles
+}
+
 type CollisionTriangle struct {
 	A dprec.Vec3
 	B dprec.Vec3
@@ -0,0 +1,13 @@
+package mdl_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMDL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MDL Suite")
+}
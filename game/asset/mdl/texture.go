@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/util/bcn"
 )
 
 const (
@@ -25,6 +26,11 @@ const (
 	TextureFormatRGBA32F  TextureFormat = dto.TexelFormatRGBA32F
 	TextureFormatDepth16F TextureFormat = dto.TexelFormatDepth16F
 	TextureFormatDepth32F TextureFormat = dto.TexelFormatDepth32F
+	TextureFormatBC1      TextureFormat = dto.TexelFormatBC1
+	TextureFormatBC3      TextureFormat = dto.TexelFormatBC3
+	TextureFormatBC4      TextureFormat = dto.TexelFormatBC4
+	TextureFormatBC5      TextureFormat = dto.TexelFormatBC5
+	TextureFormatBC7      TextureFormat = dto.TexelFormatBC7
 )
 
 type TextureFormat = dto.TexelFormat
@@ -43,14 +49,13 @@ func Create2DTexture(width, height, mipmaps int, format TextureFormat) *Texture
 	for i := range mipmapLayers {
 		mipWidth := max(1, width>>i)
 		mipHeight := max(1, height>>i)
-		mipTexelSize := textureFormatSize(format)
 		mipmapLayers[i] = MipmapLayer{
 			width:  mipWidth,
 			height: mipHeight,
 			depth:  1,
 			layers: []TextureLayer{
 				{
-					data: make([]byte, mipWidth*mipHeight*mipTexelSize),
+					data: make([]byte, textureDataSize(format, mipWidth, mipHeight)),
 				},
 			},
 		}
@@ -63,33 +68,59 @@ func Create2DTexture(width, height, mipmaps int, format TextureFormat) *Texture
 	}
 }
 
+func Create2DArrayTexture(width, height, layers, mipmaps int, format TextureFormat) *Texture {
+	mipmapLayers := make([]MipmapLayer, mipmaps)
+	for i := range mipmapLayers {
+		mipWidth := max(1, width>>i)
+		mipHeight := max(1, height>>i)
+		textureLayers := make([]TextureLayer, layers)
+		for j := range textureLayers {
+			textureLayers[j] = TextureLayer{
+				data: make([]byte, textureDataSize(format, mipWidth, mipHeight)),
+			}
+		}
+		mipmapLayers[i] = MipmapLayer{
+			width:  mipWidth,
+			height: mipHeight,
+			depth:  1,
+			layers: textureLayers,
+		}
+	}
+	return &Texture{
+		Object:       NewObject(),
+		kind:         TextureKind2DArray,
+		format:       format,
+		mipmapLayers: mipmapLayers,
+	}
+}
+
 func CreateCubeTexture(dimension, mipmaps int, format TextureFormat) *Texture {
 	mipmapLayers := make([]MipmapLayer, mipmaps)
 	for i := range mipmapLayers {
 		mipDimension := max(1, dimension>>i)
-		mipTexelSize := textureFormatSize(format)
+		mipDataSize := textureDataSize(format, mipDimension, mipDimension)
 		mipmapLayers[i] = MipmapLayer{
 			width:  mipDimension,
 			height: mipDimension,
 			depth:  1,
 			layers: []TextureLayer{
 				{
-					data: make([]byte, mipDimension*mipDimension*mipTexelSize),
+					data: make([]byte, mipDataSize),
 				},
 				{
-					data: make([]byte, mipDimension*mipDimension*mipTexelSize),
+					data: make([]byte, mipDataSize),
 				},
 				{
-					data: make([]byte, mipDimension*mipDimension*mipTexelSize),
+					data: make([]byte, mipDataSize),
 				},
 				{
-					data: make([]byte, mipDimension*mipDimension*mipTexelSize),
+					data: make([]byte, mipDataSize),
 				},
 				{
-					data: make([]byte, mipDimension*mipDimension*mipTexelSize),
+					data: make([]byte, mipDataSize),
 				},
 				{
-					data: make([]byte, mipDimension*mipDimension*mipTexelSize),
+					data: make([]byte, mipDataSize),
 				},
 			},
 		}
@@ -154,10 +185,20 @@ func (t *Texture) SetLayerImage(mipmap, index int, image *Image) {
 	case TextureFormatRGBA32F:
 		copy(mipmapLayer.layers[index].data, image.DataRGBA32F())
 	default:
-		panic(fmt.Errorf("unsupported texture format: %v", t.format))
+		blockFormat, ok := textureBlockFormat(t.format)
+		if !ok {
+			panic(fmt.Errorf("unsupported texture format: %v", t.format))
+		}
+		copy(mipmapLayer.layers[index].data, bcn.Encode(blockFormat, image.DataRGBA8(), image.Width(), image.Height()))
 	}
 }
 
+// SetLayerData sets the raw data of the specified layer, which needs to
+// already be in the format of the texture.
+func (t *Texture) SetLayerData(mipmap, index int, data []byte) {
+	copy(t.mipmapLayers[mipmap].layers[index].data, data)
+}
+
 func (t *Texture) MipmapLayers() []MipmapLayer {
 	return t.mipmapLayers
 }
@@ -193,6 +234,37 @@ func (l *TextureLayer) Data() []byte {
 	return l.data
 }
 
+// IsBlockCompressed returns whether the specified format stores texels in
+// compressed blocks.
+func IsBlockCompressed(format TextureFormat) bool {
+	_, ok := textureBlockFormat(format)
+	return ok
+}
+
+func textureDataSize(format TextureFormat, width, height int) int {
+	if blockFormat, ok := textureBlockFormat(format); ok {
+		return blockFormat.DataSize(width, height)
+	}
+	return width * height * textureFormatSize(format)
+}
+
+func textureBlockFormat(format TextureFormat) (bcn.Format, bool) {
+	switch format {
+	case TextureFormatBC1:
+		return bcn.FormatBC1, true
+	case TextureFormatBC3:
+		return bcn.FormatBC3, true
+	case TextureFormatBC4:
+		return bcn.FormatBC4, true
+	case TextureFormatBC5:
+		return bcn.FormatBC5, true
+	case TextureFormatBC7:
+		return bcn.FormatBC7, true
+	default:
+		return 0, false
+	}
+}
+
 func textureFormatSize(format TextureFormat) int {
 	switch format {
 	case TextureFormatRGBA8:
@@ -0,0 +1,135 @@
+package mdl
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"github.com/mokiat/lacking/util/bcn"
+)
+
+// containerCubeSides maps the face order of texture container files, which
+// is +X, -X, +Y, -Y, +Z, -Z, to cube sides.
+var containerCubeSides = [6]CubeSide{
+	CubeSideRight,
+	CubeSideLeft,
+	CubeSideTop,
+	CubeSideBottom,
+	CubeSideFront,
+	CubeSideRear,
+}
+
+const (
+	// containerMaxDimension is the largest width or height that is accepted
+	// from a texture container file.
+	containerMaxDimension = 1 << 16
+
+	// containerMaxLayers is the largest number of array layers that is
+	// accepted from a texture container file.
+	containerMaxLayers = 1 << 11
+)
+
+// containerLayout describes how a texture is stored in a texture container
+// file, like KTX2 or DDS.
+type containerLayout struct {
+	kind        TextureKind
+	width       int
+	height      int
+	layers      int
+	mipmaps     int
+	format      TextureFormat
+	linear      bool
+	swapRedBlue bool
+	topDown     bool
+}
+
+// validate checks that the layout describes a texture whose data fits in
+// the specified number of bytes. This must be done before the texture is
+// created, since the dimensions come from an untrusted header.
+func (l containerLayout) validate(maxDataSize uint64) error {
+	if l.width < 1 || l.width > containerMaxDimension || l.height < 1 || l.height > containerMaxDimension {
+		return fmt.Errorf("unsupported texture dimensions %dx%d", l.width, l.height)
+	}
+	if l.layers < 1 || l.layers > containerMaxLayers {
+		return fmt.Errorf("unsupported layer count %d", l.layers)
+	}
+	if maxMipmaps := bits.Len(uint(max(l.width, l.height))); l.mipmaps < 1 || l.mipmaps > maxMipmaps {
+		return fmt.Errorf("mipmap count %d is invalid for dimensions %dx%d", l.mipmaps, l.width, l.height)
+	}
+	if dataSize := l.dataSize(); dataSize > maxDataSize {
+		return fmt.Errorf("texture requires %d bytes but the file can hold at most %d", dataSize, maxDataSize)
+	}
+	return nil
+}
+
+// dataSize returns the number of bytes that all layers of all mipmaps
+// occupy.
+func (l containerLayout) dataSize() uint64 {
+	var result uint64
+	for mipmap := range l.mipmaps {
+		result += uint64(l.layers) * uint64(l.layerDataSize(mipmap))
+	}
+	return result
+}
+
+func (l containerLayout) createTexture() (*Texture, error) {
+	var texture *Texture
+	switch l.kind {
+	case TextureKind2D:
+		texture = Create2DTexture(l.width, l.height, l.mipmaps, l.format)
+	case TextureKind2DArray:
+		texture = Create2DArrayTexture(l.width, l.height, l.layers, l.mipmaps, l.format)
+	case TextureKindCube:
+		if l.width != l.height {
+			return nil, fmt.Errorf("cube texture sides are not square (%dx%d)", l.width, l.height)
+		}
+		texture = CreateCubeTexture(l.width, l.mipmaps, l.format)
+	default:
+		return nil, fmt.Errorf("unsupported texture kind %d", l.kind)
+	}
+	texture.SetLinear(l.linear)
+	return texture, nil
+}
+
+// layerDataSize returns the number of bytes that a single layer of the
+// specified mipmap occupies.
+func (l containerLayout) layerDataSize(mipmap int) int {
+	width := max(1, l.width>>mipmap)
+	height := max(1, l.height>>mipmap)
+	return textureDataSize(l.format, width, height)
+}
+
+// setLayerData converts the data of a layer from the container into the
+// texel order of the texture, where rows go from the bottom to the top, and
+// assigns it to the texture.
+func (l containerLayout) setLayerData(texture *Texture, mipmap, layer int, data []byte) error {
+	size := l.layerDataSize(mipmap)
+	if len(data) < size {
+		return fmt.Errorf("mipmap %d layer %d has %d bytes but requires %d", mipmap, layer, len(data), size)
+	}
+	data = slices.Clone(data[:size])
+	if l.swapRedBlue {
+		for i := 0; i+3 < len(data); i += 4 {
+			data[i], data[i+2] = data[i+2], data[i]
+		}
+	}
+	if l.topDown {
+		width := max(1, l.width>>mipmap)
+		height := max(1, l.height>>mipmap)
+		data = flipTextureData(l.format, data, width, height)
+	}
+	texture.SetLayerData(mipmap, layer, data)
+	return nil
+}
+
+func flipTextureData(format TextureFormat, data []byte, width, height int) []byte {
+	if blockFormat, ok := textureBlockFormat(format); ok {
+		return bcn.FlipVertical(blockFormat, data, width, height)
+	}
+	rowSize := width * textureFormatSize(format)
+	result := make([]byte, len(data))
+	for row := range height {
+		copy(result[row*rowSize:(row+1)*rowSize], data[(height-row-1)*rowSize:(height-row)*rowSize])
+	}
+	return result
+}
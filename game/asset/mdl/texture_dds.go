@@ -0,0 +1,223 @@
+package mdl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	ddsMagic            = 0x20534444 // "DDS "
+	ddsHeaderSize       = 4 + 124
+	ddsHeaderDX10Size   = 20
+	ddsFlagMipmapCount  = 0x20000
+	ddsPixelFlagFourCC  = 0x4
+	ddsPixelFlagRGB     = 0x40
+	ddsCaps2Cubemap     = 0x200
+	ddsCaps2CubemapAll  = 0xFC00
+	ddsCaps2Volume      = 0x200000
+	ddsDimension2D      = 3
+	ddsMiscTextureCube  = 0x4
+	ddsFourCCDX10       = 0x30315844 // "DX10"
+	ddsFourCCDXT1       = 0x31545844 // "DXT1"
+	ddsFourCCDXT5       = 0x35545844 // "DXT5"
+	ddsFourCCATI1       = 0x31495441 // "ATI1"
+	ddsFourCCBC4U       = 0x55344342 // "BC4U"
+	ddsFourCCATI2       = 0x32495441 // "ATI2"
+	ddsFourCCBC5U       = 0x55354342 // "BC5U"
+	ddsFourCCRGBA16F    = 113        // D3DFMT_A16B16G16R16F
+	ddsFourCCRGBA32F    = 116        // D3DFMT_A32B32G32R32F
+	ddsRedMask          = 0x000000FF
+	ddsGreenMask        = 0x0000FF00
+	ddsBlueMask         = 0x00FF0000
+	ddsRGBBitCount      = 32
+	ddsPixelFormatStart = 4 + 72
+)
+
+// ParseDDSTexture parses a texture from a DDS file.
+//
+// 2D textures, 2D array textures and cube textures are supported, both with
+// the legacy header and with the DX10 header extension. The mipmaps that are
+// stored in the file are preserved.
+func ParseDDSTexture(in io.Reader) (*Texture, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data: %w", err)
+	}
+	if len(data) < ddsHeaderSize || binary.LittleEndian.Uint32(data) != ddsMagic {
+		return nil, fmt.Errorf("data is not in DDS format")
+	}
+
+	var (
+		flags       = binary.LittleEndian.Uint32(data[8:])
+		height      = binary.LittleEndian.Uint32(data[12:])
+		width       = binary.LittleEndian.Uint32(data[16:])
+		mipmapCount = binary.LittleEndian.Uint32(data[28:])
+		pixelFormat = data[ddsPixelFormatStart:]
+		caps2       = binary.LittleEndian.Uint32(data[112:])
+	)
+
+	layout := containerLayout{
+		kind:    TextureKind2D,
+		width:   int(width),
+		height:  int(height),
+		layers:  1,
+		mipmaps: 1,
+		topDown: true,
+	}
+	if flags&ddsFlagMipmapCount != 0 {
+		layout.mipmaps = max(1, int(mipmapCount))
+	}
+
+	offset := ddsHeaderSize
+	pixelFlags := binary.LittleEndian.Uint32(pixelFormat[4:])
+	fourCC := binary.LittleEndian.Uint32(pixelFormat[8:])
+	if pixelFlags&ddsPixelFlagFourCC != 0 && fourCC == ddsFourCCDX10 {
+		if len(data) < ddsHeaderSize+ddsHeaderDX10Size {
+			return nil, fmt.Errorf("DX10 header is out of bounds")
+		}
+		var (
+			dxgiFormat        = binary.LittleEndian.Uint32(data[offset:])
+			resourceDimension = binary.LittleEndian.Uint32(data[offset+4:])
+			miscFlag          = binary.LittleEndian.Uint32(data[offset+8:])
+			arraySize         = binary.LittleEndian.Uint32(data[offset+12:])
+		)
+		offset += ddsHeaderDX10Size
+
+		layout.format, layout.linear, layout.swapRedBlue, err = resolveDXGIFormat(dxgiFormat)
+		if err != nil {
+			return nil, err
+		}
+		if resourceDimension != ddsDimension2D {
+			return nil, fmt.Errorf("unsupported resource dimension %d", resourceDimension)
+		}
+		switch {
+		case miscFlag&ddsMiscTextureCube != 0 && arraySize > 1:
+			return nil, fmt.Errorf("cube array textures are not supported")
+		case miscFlag&ddsMiscTextureCube != 0:
+			layout.kind = TextureKindCube
+			layout.layers = 6
+		case arraySize > 1:
+			layout.kind = TextureKind2DArray
+			layout.layers = int(arraySize)
+		}
+	} else {
+		layout.format, layout.swapRedBlue, err = resolveDDSPixelFormat(pixelFormat)
+		if err != nil {
+			return nil, err
+		}
+		// Legacy files do not specify the color space, in which case only
+		// the floating-point formats are considered to be linear.
+		layout.linear = layout.format == TextureFormatRGBA16F || layout.format == TextureFormatRGBA32F
+		switch {
+		case caps2&ddsCaps2Volume != 0:
+			return nil, fmt.Errorf("3D textures are not supported")
+		case caps2&ddsCaps2Cubemap != 0:
+			if caps2&ddsCaps2CubemapAll != ddsCaps2CubemapAll {
+				return nil, fmt.Errorf("partial cube textures are not supported")
+			}
+			layout.kind = TextureKindCube
+			layout.layers = 6
+		}
+	}
+
+	if err := layout.validate(uint64(len(data) - offset)); err != nil {
+		return nil, err
+	}
+	texture, err := layout.createTexture()
+	if err != nil {
+		return nil, err
+	}
+
+	// Images are ordered by array layer, or face, and then by mipmap.
+	for i := range layout.layers {
+		layer := i
+		if layout.kind == TextureKindCube {
+			layer = int(containerCubeSides[i])
+		}
+		for mipmap := range layout.mipmaps {
+			size := layout.layerDataSize(mipmap)
+			if offset+size > len(data) {
+				return nil, fmt.Errorf("layer %d is missing data for mipmap %d", i, mipmap)
+			}
+			if err := layout.setLayerData(texture, mipmap, layer, data[offset:offset+size]); err != nil {
+				return nil, err
+			}
+			offset += size
+		}
+	}
+	return texture, nil
+}
+
+func resolveDXGIFormat(dxgiFormat uint32) (format TextureFormat, linear, swapRedBlue bool, err error) {
+	switch dxgiFormat {
+	case 2: // DXGI_FORMAT_R32G32B32A32_FLOAT
+		return TextureFormatRGBA32F, true, false, nil
+	case 10: // DXGI_FORMAT_R16G16B16A16_FLOAT
+		return TextureFormatRGBA16F, true, false, nil
+	case 28: // DXGI_FORMAT_R8G8B8A8_UNORM
+		return TextureFormatRGBA8, true, false, nil
+	case 29: // DXGI_FORMAT_R8G8B8A8_UNORM_SRGB
+		return TextureFormatRGBA8, false, false, nil
+	case 71: // DXGI_FORMAT_BC1_UNORM
+		return TextureFormatBC1, true, false, nil
+	case 72: // DXGI_FORMAT_BC1_UNORM_SRGB
+		return TextureFormatBC1, false, false, nil
+	case 77: // DXGI_FORMAT_BC3_UNORM
+		return TextureFormatBC3, true, false, nil
+	case 78: // DXGI_FORMAT_BC3_UNORM_SRGB
+		return TextureFormatBC3, false, false, nil
+	case 80: // DXGI_FORMAT_BC4_UNORM
+		return TextureFormatBC4, true, false, nil
+	case 83: // DXGI_FORMAT_BC5_UNORM
+		return TextureFormatBC5, true, false, nil
+	case 87: // DXGI_FORMAT_B8G8R8A8_UNORM
+		return TextureFormatRGBA8, true, true, nil
+	case 91: // DXGI_FORMAT_B8G8R8A8_UNORM_SRGB
+		return TextureFormatRGBA8, false, true, nil
+	case 98: // DXGI_FORMAT_BC7_UNORM
+		return TextureFormatBC7, true, false, nil
+	case 99: // DXGI_FORMAT_BC7_UNORM_SRGB
+		return TextureFormatBC7, false, false, nil
+	default:
+		return 0, false, false, fmt.Errorf("unsupported DXGI format %d", dxgiFormat)
+	}
+}
+
+func resolveDDSPixelFormat(pixelFormat []byte) (format TextureFormat, swapRedBlue bool, err error) {
+	var (
+		flags     = binary.LittleEndian.Uint32(pixelFormat[4:])
+		fourCC    = binary.LittleEndian.Uint32(pixelFormat[8:])
+		bitCount  = binary.LittleEndian.Uint32(pixelFormat[12:])
+		redMask   = binary.LittleEndian.Uint32(pixelFormat[16:])
+		greenMask = binary.LittleEndian.Uint32(pixelFormat[20:])
+		blueMask  = binary.LittleEndian.Uint32(pixelFormat[24:])
+	)
+	if flags&ddsPixelFlagFourCC != 0 {
+		switch fourCC {
+		case ddsFourCCDXT1:
+			return TextureFormatBC1, false, nil
+		case ddsFourCCDXT5:
+			return TextureFormatBC3, false, nil
+		case ddsFourCCATI1, ddsFourCCBC4U:
+			return TextureFormatBC4, false, nil
+		case ddsFourCCATI2, ddsFourCCBC5U:
+			return TextureFormatBC5, false, nil
+		case ddsFourCCRGBA16F:
+			return TextureFormatRGBA16F, false, nil
+		case ddsFourCCRGBA32F:
+			return TextureFormatRGBA32F, false, nil
+		default:
+			return 0, false, fmt.Errorf("unsupported FourCC code %#x", fourCC)
+		}
+	}
+	if flags&ddsPixelFlagRGB != 0 && bitCount == ddsRGBBitCount && greenMask == ddsGreenMask {
+		switch {
+		case redMask == ddsRedMask && blueMask == ddsBlueMask:
+			return TextureFormatRGBA8, false, nil
+		case redMask == ddsBlueMask && blueMask == ddsRedMask:
+			return TextureFormatRGBA8, true, nil
+		}
+	}
+	return 0, false, fmt.Errorf("unsupported pixel format (flags: %#x, bits: %d)", flags, bitCount)
+}
@@ -0,0 +1,356 @@
+package mdl_test
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+	"github.com/mokiat/lacking/util/bcn"
+)
+
+var _ = Describe("DDS textures", func() {
+	const (
+		ddsFlagMipmapCount = 0x20000
+		ddsPixelFlagFourCC = 0x4
+		ddsPixelFlagRGB    = 0x40
+		ddsCaps2Cubemap    = 0x200
+		ddsCaps2CubemapAll = 0xFC00
+		ddsMiscTextureCube = 0x4
+		ddsDimension2D     = 3
+
+		dxgiFormatRGBA8SRGB = 29
+		dxgiFormatBC7Unorm  = 98
+	)
+
+	type ddsPixelFormat struct {
+		flags     uint32
+		fourCC    string
+		bitCount  uint32
+		redMask   uint32
+		greenMask uint32
+		blueMask  uint32
+		alphaMask uint32
+	}
+
+	type ddsHeaderDX10 struct {
+		dxgiFormat uint32
+		dimension  uint32
+		miscFlag   uint32
+		arraySize  uint32
+	}
+
+	type ddsFixture struct {
+		width       uint32
+		height      uint32
+		mipmapCount uint32
+		pixelFormat ddsPixelFormat
+		caps2       uint32
+		dx10        *ddsHeaderDX10
+		data        []byte
+	}
+
+	rgbaPixelFormat := ddsPixelFormat{
+		flags:     ddsPixelFlagRGB,
+		bitCount:  32,
+		redMask:   0x000000FF,
+		greenMask: 0x0000FF00,
+		blueMask:  0x00FF0000,
+		alphaMask: 0xFF000000,
+	}
+
+	bgraPixelFormat := ddsPixelFormat{
+		flags:     ddsPixelFlagRGB,
+		bitCount:  32,
+		redMask:   0x00FF0000,
+		greenMask: 0x0000FF00,
+		blueMask:  0x000000FF,
+		alphaMask: 0xFF000000,
+	}
+
+	fourCC := func(code string) uint32 {
+		if code == "" {
+			return 0
+		}
+		return binary.LittleEndian.Uint32([]byte(code))
+	}
+
+	encodeFixture := func(fixture ddsFixture) []byte {
+		flags := uint32(0x1 | 0x2 | 0x4 | 0x1000) // caps, height, width, pixel format
+		if fixture.mipmapCount > 0 {
+			flags |= ddsFlagMipmapCount
+		}
+		pixelFormat := fixture.pixelFormat
+		if fixture.dx10 != nil {
+			pixelFormat = ddsPixelFormat{
+				flags:  ddsPixelFlagFourCC,
+				fourCC: "DX10",
+			}
+		}
+
+		result := []byte("DDS ")
+		result = binary.LittleEndian.AppendUint32(result, 124)
+		result = binary.LittleEndian.AppendUint32(result, flags)
+		result = binary.LittleEndian.AppendUint32(result, fixture.height)
+		result = binary.LittleEndian.AppendUint32(result, fixture.width)
+		result = binary.LittleEndian.AppendUint32(result, 0) // pitch
+		result = binary.LittleEndian.AppendUint32(result, 0) // depth
+		result = binary.LittleEndian.AppendUint32(result, fixture.mipmapCount)
+		result = append(result, make([]byte, 11*4)...)
+		for _, value := range []uint32{
+			32, pixelFormat.flags, fourCC(pixelFormat.fourCC), pixelFormat.bitCount,
+			pixelFormat.redMask, pixelFormat.greenMask, pixelFormat.blueMask, pixelFormat.alphaMask,
+		} {
+			result = binary.LittleEndian.AppendUint32(result, value)
+		}
+		result = binary.LittleEndian.AppendUint32(result, 0x1000) // caps
+		result = binary.LittleEndian.AppendUint32(result, fixture.caps2)
+		result = append(result, make([]byte, 3*4)...)
+		if dx10 := fixture.dx10; dx10 != nil {
+			for _, value := range []uint32{dx10.dxgiFormat, dx10.dimension, dx10.miscFlag, dx10.arraySize, 0} {
+				result = binary.LittleEndian.AppendUint32(result, value)
+			}
+		}
+		return append(result, fixture.data...)
+	}
+
+	parse := func(fixture ddsFixture) (*mdl.Texture, error) {
+		return mdl.ParseDDSTexture(bytes.NewReader(encodeFixture(fixture)))
+	}
+
+	mustParse := func(fixture ddsFixture) *mdl.Texture {
+		texture, err := parse(fixture)
+		Expect(err).ToNot(HaveOccurred())
+		return texture
+	}
+
+	layerData := func(texture *mdl.Texture, mipmap, layer int) []byte {
+		mipmapLayers := texture.MipmapLayers()
+		layers := mipmapLayers[mipmap].Layers()
+		return layers[layer].Data()
+	}
+
+	// The rows of the 2x2 images are stored from the top to the bottom.
+	image2x2 := []byte{
+		1, 2, 3, 4, 5, 6, 7, 8,
+		9, 10, 11, 12, 13, 14, 15, 16,
+	}
+	flipped2x2 := []byte{
+		9, 10, 11, 12, 13, 14, 15, 16,
+		1, 2, 3, 4, 5, 6, 7, 8,
+	}
+
+	It("parses legacy 2D textures with mipmaps", func() {
+		texture := mustParse(ddsFixture{
+			width:       2,
+			height:      2,
+			mipmapCount: 2,
+			pixelFormat: rgbaPixelFormat,
+			data:        append(append([]byte{}, image2x2...), 20, 21, 22, 23),
+		})
+		Expect(texture.Kind()).To(Equal(mdl.TextureKind2D))
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatRGBA8))
+		Expect(texture.Linear()).To(BeFalse())
+		Expect(texture.MipmapLayers()).To(HaveLen(2))
+		Expect(layerData(texture, 0, 0)).To(Equal(flipped2x2))
+		Expect(layerData(texture, 1, 0)).To(Equal([]byte{20, 21, 22, 23}))
+	})
+
+	It("ignores the mipmap count when the flag is not set", func() {
+		fixture := ddsFixture{
+			width:       2,
+			height:      2,
+			pixelFormat: rgbaPixelFormat,
+			data:        image2x2,
+		}
+		data := encodeFixture(fixture)
+		binary.LittleEndian.PutUint32(data[28:], 5)
+		texture, err := mdl.ParseDDSTexture(bytes.NewReader(data))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(texture.MipmapLayers()).To(HaveLen(1))
+	})
+
+	It("converts legacy BGRA textures", func() {
+		texture := mustParse(ddsFixture{
+			width:       1,
+			height:      1,
+			pixelFormat: bgraPixelFormat,
+			data:        []byte{1, 2, 3, 4},
+		})
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatRGBA8))
+		Expect(layerData(texture, 0, 0)).To(Equal([]byte{3, 2, 1, 4}))
+	})
+
+	It("parses legacy block compressed textures", func() {
+		rgba := make([]byte, 4*4*4)
+		for i := range rgba {
+			rgba[i] = byte(i * 4)
+		}
+		block := bcn.Encode(bcn.FormatBC1, rgba, 4, 4)
+		texture := mustParse(ddsFixture{
+			width:  4,
+			height: 4,
+			pixelFormat: ddsPixelFormat{
+				flags:  ddsPixelFlagFourCC,
+				fourCC: "DXT1",
+			},
+			data: block,
+		})
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatBC1))
+		Expect(layerData(texture, 0, 0)).To(Equal(bcn.FlipVertical(bcn.FormatBC1, block, 4, 4)))
+	})
+
+	It("parses legacy cube textures", func() {
+		var faces []byte
+		for face := range 6 {
+			faces = append(faces, byte(face), byte(face), byte(face), 255)
+		}
+		texture := mustParse(ddsFixture{
+			width:       1,
+			height:      1,
+			pixelFormat: rgbaPixelFormat,
+			caps2:       ddsCaps2Cubemap | ddsCaps2CubemapAll,
+			data:        faces,
+		})
+		Expect(texture.Kind()).To(Equal(mdl.TextureKindCube))
+
+		// Faces are stored in the +X, -X, +Y, -Y, +Z, -Z order.
+		for face, side := range []mdl.CubeSide{
+			mdl.CubeSideRight,
+			mdl.CubeSideLeft,
+			mdl.CubeSideTop,
+			mdl.CubeSideBottom,
+			mdl.CubeSideFront,
+			mdl.CubeSideRear,
+		} {
+			Expect(layerData(texture, 0, int(side))).To(Equal([]byte{byte(face), byte(face), byte(face), 255}))
+		}
+	})
+
+	It("parses DX10 array textures", func() {
+		// Images are ordered by layer and then by mipmap.
+		texture := mustParse(ddsFixture{
+			width:       2,
+			height:      2,
+			mipmapCount: 2,
+			dx10: &ddsHeaderDX10{
+				dxgiFormat: dxgiFormatRGBA8SRGB,
+				dimension:  ddsDimension2D,
+				arraySize:  2,
+			},
+			data: bytes.Join([][]byte{
+				image2x2, {20, 21, 22, 23},
+				image2x2, {30, 31, 32, 33},
+			}, nil),
+		})
+		Expect(texture.Kind()).To(Equal(mdl.TextureKind2DArray))
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatRGBA8))
+		Expect(texture.Linear()).To(BeFalse())
+		Expect(layerData(texture, 0, 0)).To(Equal(flipped2x2))
+		Expect(layerData(texture, 0, 1)).To(Equal(flipped2x2))
+		Expect(layerData(texture, 1, 0)).To(Equal([]byte{20, 21, 22, 23}))
+		Expect(layerData(texture, 1, 1)).To(Equal([]byte{30, 31, 32, 33}))
+	})
+
+	It("parses DX10 cube textures", func() {
+		texture := mustParse(ddsFixture{
+			width:  4,
+			height: 4,
+			dx10: &ddsHeaderDX10{
+				dxgiFormat: dxgiFormatBC7Unorm,
+				dimension:  ddsDimension2D,
+				miscFlag:   ddsMiscTextureCube,
+				arraySize:  1,
+			},
+			data: make([]byte, 6*16),
+		})
+		Expect(texture.Kind()).To(Equal(mdl.TextureKindCube))
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatBC7))
+		Expect(texture.Linear()).To(BeTrue())
+		Expect(texture.MipmapLayers()[0].Layers()).To(HaveLen(6))
+	})
+
+	DescribeTable("invalid files",
+		func(data func() []byte) {
+			_, err := mdl.ParseDDSTexture(bytes.NewReader(data()))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("wrong magic", func() []byte {
+			data := encodeFixture(ddsFixture{width: 1, height: 1, pixelFormat: rgbaPixelFormat, data: []byte{1, 2, 3, 4}})
+			data[0] = 'X'
+			return data
+		}),
+		Entry("truncated header", func() []byte {
+			return encodeFixture(ddsFixture{width: 1, height: 1, pixelFormat: rgbaPixelFormat})[:100]
+		}),
+		Entry("truncated DX10 header", func() []byte {
+			data := encodeFixture(ddsFixture{
+				width:  1,
+				height: 1,
+				dx10:   &ddsHeaderDX10{dxgiFormat: dxgiFormatRGBA8SRGB, dimension: ddsDimension2D, arraySize: 1},
+			})
+			return data[:130]
+		}),
+		Entry("truncated data", func() []byte {
+			return encodeFixture(ddsFixture{width: 2, height: 2, pixelFormat: rgbaPixelFormat, data: image2x2[:12]})
+		}),
+		Entry("truncated mipmap", func() []byte {
+			return encodeFixture(ddsFixture{width: 2, height: 2, mipmapCount: 2, pixelFormat: rgbaPixelFormat, data: image2x2})
+		}),
+		Entry("partial cube texture", func() []byte {
+			return encodeFixture(ddsFixture{
+				width:       1,
+				height:      1,
+				pixelFormat: rgbaPixelFormat,
+				caps2:       ddsCaps2Cubemap | 0x400,
+				data:        make([]byte, 24),
+			})
+		}),
+		Entry("unsupported FourCC", func() []byte {
+			return encodeFixture(ddsFixture{
+				width:       4,
+				height:      4,
+				pixelFormat: ddsPixelFormat{flags: ddsPixelFlagFourCC, fourCC: "DXT3"},
+				data:        make([]byte, 16),
+			})
+		}),
+		Entry("unsupported DXGI format", func() []byte {
+			return encodeFixture(ddsFixture{
+				width:  1,
+				height: 1,
+				dx10:   &ddsHeaderDX10{dxgiFormat: 1, dimension: ddsDimension2D, arraySize: 1},
+				data:   []byte{1, 2, 3, 4},
+			})
+		}),
+		Entry("dimensions that are too large", func() []byte {
+			return encodeFixture(ddsFixture{width: 1 << 30, height: 1 << 30, pixelFormat: rgbaPixelFormat, data: image2x2})
+		}),
+		Entry("dimensions that exceed the data", func() []byte {
+			return encodeFixture(ddsFixture{width: 1024, height: 1024, pixelFormat: rgbaPixelFormat, data: image2x2})
+		}),
+		Entry("zero height", func() []byte {
+			return encodeFixture(ddsFixture{width: 2, height: 0, pixelFormat: rgbaPixelFormat, data: image2x2})
+		}),
+		Entry("too many mipmaps", func() []byte {
+			return encodeFixture(ddsFixture{width: 2, height: 2, mipmapCount: 30, pixelFormat: rgbaPixelFormat, data: image2x2})
+		}),
+		Entry("too many array layers", func() []byte {
+			return encodeFixture(ddsFixture{
+				width:  1,
+				height: 1,
+				dx10:   &ddsHeaderDX10{dxgiFormat: dxgiFormatRGBA8SRGB, dimension: ddsDimension2D, arraySize: 1 << 30},
+				data:   []byte{1, 2, 3, 4},
+			})
+		}),
+		Entry("unsupported DX10 dimension", func() []byte {
+			return encodeFixture(ddsFixture{
+				width:  1,
+				height: 1,
+				dx10:   &ddsHeaderDX10{dxgiFormat: dxgiFormatRGBA8SRGB, dimension: 4, arraySize: 1},
+				data:   []byte{1, 2, 3, 4},
+			})
+		}),
+	)
+})
@@ -0,0 +1,222 @@
+package mdl
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	ktx2HeaderSize     = 80
+	ktx2LevelIndexSize = 24
+
+	ktx2SupercompressionNone = 0
+	ktx2SupercompressionZLIB = 3
+
+	// ktx2MaxZLIBRatio is the largest factor by which zlib can expand data.
+	ktx2MaxZLIBRatio = 1032
+)
+
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// ParseKTX2Texture parses a texture from a KTX2 file.
+//
+// 2D textures, 2D array textures and cube textures are supported, with
+// either no supercompression or with zlib supercompression. The mipmaps that
+// are stored in the file are preserved. When the file requests that mipmaps
+// be generated, the texture is configured to do so.
+func ParseKTX2Texture(in io.Reader) (*Texture, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data: %w", err)
+	}
+	if len(data) < ktx2HeaderSize || !bytes.Equal(data[:len(ktx2Identifier)], ktx2Identifier) {
+		return nil, fmt.Errorf("data is not in KTX2 format")
+	}
+
+	var (
+		vkFormat         = binary.LittleEndian.Uint32(data[12:])
+		pixelWidth       = binary.LittleEndian.Uint32(data[20:])
+		pixelHeight      = binary.LittleEndian.Uint32(data[24:])
+		pixelDepth       = binary.LittleEndian.Uint32(data[28:])
+		layerCount       = binary.LittleEndian.Uint32(data[32:])
+		faceCount        = binary.LittleEndian.Uint32(data[36:])
+		levelCount       = binary.LittleEndian.Uint32(data[40:])
+		supercompression = binary.LittleEndian.Uint32(data[44:])
+		kvdByteOffset    = binary.LittleEndian.Uint32(data[56:])
+		kvdByteLength    = binary.LittleEndian.Uint32(data[60:])
+	)
+
+	format, linear, swapRedBlue, err := resolveKTX2Format(vkFormat)
+	if err != nil {
+		return nil, err
+	}
+	layout := containerLayout{
+		width:       int(pixelWidth),
+		height:      int(pixelHeight),
+		layers:      1,
+		mipmaps:     max(1, int(levelCount)),
+		format:      format,
+		linear:      linear,
+		swapRedBlue: swapRedBlue,
+		topDown:     true,
+	}
+	switch {
+	case pixelHeight == 0:
+		return nil, fmt.Errorf("1D textures are not supported")
+	case pixelDepth > 0:
+		return nil, fmt.Errorf("3D textures are not supported")
+	case faceCount == 6 && layerCount > 0:
+		return nil, fmt.Errorf("cube array textures are not supported")
+	case faceCount == 6:
+		layout.kind = TextureKindCube
+		layout.layers = 6
+	case faceCount != 1:
+		return nil, fmt.Errorf("unsupported face count %d", faceCount)
+	case layerCount > 0:
+		layout.kind = TextureKind2DArray
+		layout.layers = int(layerCount)
+	default:
+		layout.kind = TextureKind2D
+	}
+	switch supercompression {
+	case ktx2SupercompressionNone, ktx2SupercompressionZLIB:
+	default:
+		return nil, fmt.Errorf("unsupported supercompression scheme %d", supercompression)
+	}
+
+	if kvdByteLength > 0 {
+		if dataSize := uint64(len(data)); uint64(kvdByteOffset) > dataSize || uint64(kvdByteLength) > dataSize-uint64(kvdByteOffset) {
+			return nil, fmt.Errorf("key/value data is out of bounds")
+		}
+		keyValues := parseKTX2KeyValues(data[kvdByteOffset : kvdByteOffset+kvdByteLength])
+		// The orientation specifies the direction of increasing S and T
+		// coordinates, where the default is right and down.
+		if orientation, ok := keyValues["KTXorientation"]; ok && len(orientation) > 1 {
+			layout.topDown = orientation[1] != 'u'
+		}
+	}
+
+	maxDataSize := uint64(len(data))
+	if supercompression == ktx2SupercompressionZLIB {
+		maxDataSize *= ktx2MaxZLIBRatio
+	}
+	if err := layout.validate(maxDataSize); err != nil {
+		return nil, err
+	}
+	texture, err := layout.createTexture()
+	if err != nil {
+		return nil, err
+	}
+	texture.SetGenerateMipmaps(levelCount == 0 && !IsBlockCompressed(format))
+
+	if ktx2HeaderSize+layout.mipmaps*ktx2LevelIndexSize > len(data) {
+		return nil, fmt.Errorf("level index is out of bounds")
+	}
+	for mipmap := range layout.mipmaps {
+		levelIndex := data[ktx2HeaderSize+mipmap*ktx2LevelIndexSize:]
+		byteOffset := binary.LittleEndian.Uint64(levelIndex[0:])
+		byteLength := binary.LittleEndian.Uint64(levelIndex[8:])
+		// The offset and length are checked separately, since their sum
+		// could overflow.
+		if dataSize := uint64(len(data)); byteOffset > dataSize || byteLength > dataSize-byteOffset {
+			return nil, fmt.Errorf("mipmap %d data is out of bounds", mipmap)
+		}
+		levelData := data[byteOffset : byteOffset+byteLength]
+		if supercompression == ktx2SupercompressionZLIB {
+			levelData, err = inflateZLIB(levelData, layout.layers*layout.layerDataSize(mipmap))
+			if err != nil {
+				return nil, fmt.Errorf("error decompressing mipmap %d: %w", mipmap, err)
+			}
+		}
+
+		// Images within a level are ordered by array layer and then by face.
+		layerSize := layout.layerDataSize(mipmap)
+		for i := range layout.layers {
+			offset := i * layerSize
+			if offset+layerSize > len(levelData) {
+				return nil, fmt.Errorf("mipmap %d is missing data for layer %d", mipmap, i)
+			}
+			layer := i
+			if layout.kind == TextureKindCube {
+				layer = int(containerCubeSides[i])
+			}
+			if err := layout.setLayerData(texture, mipmap, layer, levelData[offset:offset+layerSize]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return texture, nil
+}
+
+func resolveKTX2Format(vkFormat uint32) (format TextureFormat, linear, swapRedBlue bool, err error) {
+	switch vkFormat {
+	case 37: // VK_FORMAT_R8G8B8A8_UNORM
+		return TextureFormatRGBA8, true, false, nil
+	case 43: // VK_FORMAT_R8G8B8A8_SRGB
+		return TextureFormatRGBA8, false, false, nil
+	case 44: // VK_FORMAT_B8G8R8A8_UNORM
+		return TextureFormatRGBA8, true, true, nil
+	case 50: // VK_FORMAT_B8G8R8A8_SRGB
+		return TextureFormatRGBA8, false, true, nil
+	case 97: // VK_FORMAT_R16G16B16A16_SFLOAT
+		return TextureFormatRGBA16F, true, false, nil
+	case 109: // VK_FORMAT_R32G32B32A32_SFLOAT
+		return TextureFormatRGBA32F, true, false, nil
+	case 131, 133: // VK_FORMAT_BC1_RGB_UNORM_BLOCK, VK_FORMAT_BC1_RGBA_UNORM_BLOCK
+		return TextureFormatBC1, true, false, nil
+	case 132, 134: // VK_FORMAT_BC1_RGB_SRGB_BLOCK, VK_FORMAT_BC1_RGBA_SRGB_BLOCK
+		return TextureFormatBC1, false, false, nil
+	case 137: // VK_FORMAT_BC3_UNORM_BLOCK
+		return TextureFormatBC3, true, false, nil
+	case 138: // VK_FORMAT_BC3_SRGB_BLOCK
+		return TextureFormatBC3, false, false, nil
+	case 139: // VK_FORMAT_BC4_UNORM_BLOCK
+		return TextureFormatBC4, true, false, nil
+	case 141: // VK_FORMAT_BC5_UNORM_BLOCK
+		return TextureFormatBC5, true, false, nil
+	case 145: // VK_FORMAT_BC7_UNORM_BLOCK
+		return TextureFormatBC7, true, false, nil
+	case 146: // VK_FORMAT_BC7_SRGB_BLOCK
+		return TextureFormatBC7, false, false, nil
+	default:
+		return 0, false, false, fmt.Errorf("unsupported Vulkan format %d", vkFormat)
+	}
+}
+
+// parseKTX2KeyValues parses the key/value data of a KTX2 file, where each
+// entry is a length followed by a NUL-terminated key and a value, padded to
+// four bytes.
+func parseKTX2KeyValues(data []byte) map[string]string {
+	result := make(map[string]string)
+	for len(data) >= 4 {
+		length := int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		if length > len(data) {
+			break
+		}
+		entry := data[:length]
+		if separator := bytes.IndexByte(entry, 0); separator >= 0 {
+			key := string(entry[:separator])
+			value := bytes.TrimRight(entry[separator+1:], "\x00")
+			result[key] = string(value)
+		}
+		padded := (length + 3) &^ 3
+		data = data[min(padded, len(data)):]
+	}
+	return result
+}
+
+// inflateZLIB decompresses the specified data, which is expected to hold
+// the specified number of bytes once decompressed. Any data past that is
+// not read.
+func inflateZLIB(data []byte, size int) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, int64(size)))
+}
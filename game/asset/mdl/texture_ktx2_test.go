@@ -0,0 +1,298 @@
+package mdl_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/asset/mdl"
+)
+
+var _ = Describe("KTX2 textures", func() {
+	const (
+		vkFormatRGBA8Unorm = 37
+		vkFormatBGRA8SRGB  = 50
+		vkFormatBC7Unorm   = 145
+	)
+
+	type ktx2Fixture struct {
+		vkFormat         uint32
+		width            uint32
+		height           uint32
+		depth            uint32
+		layers           uint32
+		faces            uint32
+		levelCount       uint32
+		supercompression uint32
+		keyValues        map[string]string
+		levels           [][]byte
+	}
+
+	newFixture := func(width, height uint32, levels ...[]byte) ktx2Fixture {
+		return ktx2Fixture{
+			vkFormat:   vkFormatRGBA8Unorm,
+			width:      width,
+			height:     height,
+			faces:      1,
+			levelCount: uint32(len(levels)),
+			levels:     levels,
+		}
+	}
+
+	encodeFixture := func(fixture ktx2Fixture) []byte {
+		var keyValueData []byte
+		for key, value := range fixture.keyValues {
+			entry := append(append([]byte(key), 0), append([]byte(value), 0)...)
+			keyValueData = binary.LittleEndian.AppendUint32(keyValueData, uint32(len(entry)))
+			keyValueData = append(keyValueData, entry...)
+			for len(keyValueData)%4 != 0 {
+				keyValueData = append(keyValueData, 0)
+			}
+		}
+
+		levelIndexSize := 24 * max(1, len(fixture.levels))
+		keyValueOffset := 80 + levelIndexSize
+		levelOffset := keyValueOffset + len(keyValueData)
+
+		header := []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+		for _, value := range []uint32{
+			fixture.vkFormat, 1,
+			fixture.width, fixture.height, fixture.depth,
+			fixture.layers, fixture.faces, fixture.levelCount,
+			fixture.supercompression,
+			0, 0, // data format descriptor
+			uint32(keyValueOffset), uint32(len(keyValueData)),
+		} {
+			header = binary.LittleEndian.AppendUint32(header, value)
+		}
+		header = binary.LittleEndian.AppendUint64(header, 0) // supercompression global data
+		header = binary.LittleEndian.AppendUint64(header, 0)
+
+		var levelData []byte
+		for _, level := range fixture.levels {
+			stored := level
+			if fixture.supercompression == 3 {
+				var buffer bytes.Buffer
+				writer := zlib.NewWriter(&buffer)
+				writer.Write(level)
+				writer.Close()
+				stored = buffer.Bytes()
+			}
+			header = binary.LittleEndian.AppendUint64(header, uint64(levelOffset+len(levelData)))
+			header = binary.LittleEndian.AppendUint64(header, uint64(len(stored)))
+			header = binary.LittleEndian.AppendUint64(header, uint64(len(level)))
+			levelData = append(levelData, stored...)
+		}
+		for len(header) < keyValueOffset {
+			header = append(header, 0)
+		}
+		return append(append(header, keyValueData...), levelData...)
+	}
+
+	parse := func(fixture ktx2Fixture) (*mdl.Texture, error) {
+		return mdl.ParseKTX2Texture(bytes.NewReader(encodeFixture(fixture)))
+	}
+
+	mustParse := func(fixture ktx2Fixture) *mdl.Texture {
+		texture, err := parse(fixture)
+		Expect(err).ToNot(HaveOccurred())
+		return texture
+	}
+
+	layerData := func(texture *mdl.Texture, mipmap, layer int) []byte {
+		mipmapLayers := texture.MipmapLayers()
+		layers := mipmapLayers[mipmap].Layers()
+		return layers[layer].Data()
+	}
+
+	// The rows of the 2x2 images are stored from the top to the bottom.
+	image2x2 := []byte{
+		1, 2, 3, 4, 5, 6, 7, 8,
+		9, 10, 11, 12, 13, 14, 15, 16,
+	}
+	flipped2x2 := []byte{
+		9, 10, 11, 12, 13, 14, 15, 16,
+		1, 2, 3, 4, 5, 6, 7, 8,
+	}
+
+	It("parses 2D textures with mipmaps", func() {
+		texture := mustParse(newFixture(2, 2, image2x2, []byte{20, 21, 22, 23}))
+		Expect(texture.Kind()).To(Equal(mdl.TextureKind2D))
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatRGBA8))
+		Expect(texture.Linear()).To(BeTrue())
+		Expect(texture.GenerateMipmaps()).To(BeFalse())
+
+		mipmapLayers := texture.MipmapLayers()
+		Expect(mipmapLayers).To(HaveLen(2))
+		Expect(mipmapLayers[1].Width()).To(Equal(1))
+		Expect(mipmapLayers[1].Height()).To(Equal(1))
+		Expect(layerData(texture, 0, 0)).To(Equal(flipped2x2))
+		Expect(layerData(texture, 1, 0)).To(Equal([]byte{20, 21, 22, 23}))
+	})
+
+	It("converts BGRA sRGB textures", func() {
+		fixture := newFixture(1, 1, []byte{1, 2, 3, 4})
+		fixture.vkFormat = vkFormatBGRA8SRGB
+		texture := mustParse(fixture)
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatRGBA8))
+		Expect(texture.Linear()).To(BeFalse())
+		Expect(layerData(texture, 0, 0)).To(Equal([]byte{3, 2, 1, 4}))
+	})
+
+	It("generates mipmaps when the file requests it", func() {
+		fixture := newFixture(2, 2, image2x2)
+		fixture.levelCount = 0
+		texture := mustParse(fixture)
+		Expect(texture.GenerateMipmaps()).To(BeTrue())
+		Expect(texture.MipmapLayers()).To(HaveLen(1))
+	})
+
+	It("keeps the rows of bottom-up textures", func() {
+		fixture := newFixture(2, 2, image2x2)
+		fixture.keyValues = map[string]string{"KTXorientation": "ru"}
+		texture := mustParse(fixture)
+		Expect(layerData(texture, 0, 0)).To(Equal(image2x2))
+	})
+
+	It("decompresses zlib supercompressed textures", func() {
+		fixture := newFixture(2, 2, image2x2)
+		fixture.supercompression = 3
+		texture := mustParse(fixture)
+		Expect(layerData(texture, 0, 0)).To(Equal(flipped2x2))
+	})
+
+	It("parses block compressed textures", func() {
+		block := make([]byte, 16)
+		block[0] = 0x40 // mode 6
+		fixture := newFixture(4, 4, block)
+		fixture.vkFormat = vkFormatBC7Unorm
+		texture := mustParse(fixture)
+		Expect(texture.Format()).To(Equal(mdl.TextureFormatBC7))
+		Expect(layerData(texture, 0, 0)).To(HaveLen(16))
+	})
+
+	It("parses cube textures", func() {
+		var faces []byte
+		for face := range 6 {
+			faces = append(faces, byte(face), byte(face), byte(face), 255)
+		}
+		fixture := newFixture(1, 1, faces)
+		fixture.faces = 6
+		texture := mustParse(fixture)
+		Expect(texture.Kind()).To(Equal(mdl.TextureKindCube))
+
+		// Faces are stored in the +X, -X, +Y, -Y, +Z, -Z order.
+		for face, side := range []mdl.CubeSide{
+			mdl.CubeSideRight,
+			mdl.CubeSideLeft,
+			mdl.CubeSideTop,
+			mdl.CubeSideBottom,
+			mdl.CubeSideFront,
+			mdl.CubeSideRear,
+		} {
+			Expect(layerData(texture, 0, int(side))).To(Equal([]byte{byte(face), byte(face), byte(face), 255}))
+		}
+	})
+
+	It("parses array textures", func() {
+		fixture := newFixture(1, 1, []byte{1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3})
+		fixture.layers = 3
+		texture := mustParse(fixture)
+		Expect(texture.Kind()).To(Equal(mdl.TextureKind2DArray))
+		Expect(texture.MipmapLayers()[0].Layers()).To(HaveLen(3))
+		for layer := range 3 {
+			value := byte(layer + 1)
+			Expect(layerData(texture, 0, layer)).To(Equal([]byte{value, value, value, value}))
+		}
+	})
+
+	DescribeTable("invalid files",
+		func(data func() []byte) {
+			_, err := mdl.ParseKTX2Texture(bytes.NewReader(data()))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("wrong identifier", func() []byte {
+			data := encodeFixture(newFixture(2, 2, image2x2))
+			data[1] = 'X'
+			return data
+		}),
+		Entry("truncated header", func() []byte {
+			return encodeFixture(newFixture(2, 2, image2x2))[:60]
+		}),
+		Entry("truncated level index", func() []byte {
+			fixture := newFixture(4, 4, make([]byte, 64))
+			fixture.levelCount = 3
+			return encodeFixture(fixture)[:100]
+		}),
+		Entry("truncated level data", func() []byte {
+			data := encodeFixture(newFixture(2, 2, image2x2))
+			return data[:len(data)-1]
+		}),
+		Entry("level data offset that overflows", func() []byte {
+			data := encodeFixture(newFixture(2, 2, image2x2))
+			binary.LittleEndian.PutUint64(data[80:], math.MaxUint64-7)
+			binary.LittleEndian.PutUint64(data[88:], 16)
+			return data
+		}),
+		Entry("level data length that overflows", func() []byte {
+			data := encodeFixture(newFixture(2, 2, image2x2))
+			binary.LittleEndian.PutUint64(data[88:], math.MaxUint64)
+			return data
+		}),
+		Entry("level that is too small", func() []byte {
+			return encodeFixture(newFixture(2, 2, image2x2[:8]))
+		}),
+		Entry("unsupported format", func() []byte {
+			fixture := newFixture(2, 2, image2x2)
+			fixture.vkFormat = 1
+			return encodeFixture(fixture)
+		}),
+		Entry("3D texture", func() []byte {
+			fixture := newFixture(2, 2, image2x2)
+			fixture.depth = 2
+			return encodeFixture(fixture)
+		}),
+		Entry("cube array texture", func() []byte {
+			fixture := newFixture(1, 1, make([]byte, 48))
+			fixture.faces = 6
+			fixture.layers = 2
+			return encodeFixture(fixture)
+		}),
+		Entry("dimensions that are too large", func() []byte {
+			fixture := newFixture(1<<30, 1<<30, image2x2)
+			return encodeFixture(fixture)
+		}),
+		Entry("dimensions that exceed the data", func() []byte {
+			fixture := newFixture(1024, 1024, image2x2)
+			return encodeFixture(fixture)
+		}),
+		Entry("zlib dimensions that exceed the data", func() []byte {
+			fixture := newFixture(1<<16, 1<<16, image2x2)
+			fixture.supercompression = 3
+			return encodeFixture(fixture)
+		}),
+		Entry("zero width", func() []byte {
+			fixture := newFixture(0, 2, image2x2)
+			return encodeFixture(fixture)
+		}),
+		Entry("too many mipmaps", func() []byte {
+			fixture := newFixture(2, 2, image2x2)
+			fixture.levelCount = 3
+			return encodeFixture(fixture)
+		}),
+		Entry("too many layers", func() []byte {
+			fixture := newFixture(1, 1, make([]byte, 4))
+			fixture.layers = 1 << 20
+			return encodeFixture(fixture)
+		}),
+		Entry("non-square cube texture", func() []byte {
+			fixture := newFixture(2, 1, make([]byte, 48))
+			fixture.faces = 6
+			return encodeFixture(fixture)
+		}),
+	)
+})
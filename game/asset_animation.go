@@ -1,6 +1,8 @@
 package game
 
 import (
+	"fmt"
+
 	"github.com/mokiat/gomath/dprec"
 	"github.com/mokiat/lacking/game/animation"
 	"github.com/mokiat/lacking/game/asset/dto"
@@ -19,28 +21,48 @@ func LoadAnimationRecording(loader *AssetLoader, assetAnimation dto.Animation) (
 		translationKeyframes := make([]animation.Keyframe[dprec.Vec3], len(assetBinding.TranslationKeyframes))
 		for k, keyframe := range assetBinding.TranslationKeyframes {
 			translationKeyframes[k] = animation.Keyframe[dprec.Vec3]{
-				Timestamp: keyframe.Timestamp,
-				Value:     keyframe.Value,
+				Timestamp:     keyframe.Timestamp,
+				Value:         keyframe.Value,
+				Interpolation: resolveInterpolation(keyframe.Interpolation),
+				InTangent:     keyframe.InTangent,
+				OutTangent:    keyframe.OutTangent,
 			}
 		}
 		rotationKeyframes := make([]animation.Keyframe[dprec.Quat], len(assetBinding.RotationKeyframes))
 		for k, keyframe := range assetBinding.RotationKeyframes {
 			rotationKeyframes[k] = animation.Keyframe[dprec.Quat]{
-				Timestamp: keyframe.Timestamp,
-				Value:     keyframe.Value,
+				Timestamp:     keyframe.Timestamp,
+				Value:         keyframe.Value,
+				Interpolation: resolveInterpolation(keyframe.Interpolation),
+				InTangent:     keyframe.InTangent,
+				OutTangent:    keyframe.OutTangent,
 			}
 		}
 		scaleKeyframes := make([]animation.Keyframe[dprec.Vec3], len(assetBinding.ScaleKeyframes))
 		for k, keyframe := range assetBinding.ScaleKeyframes {
 			scaleKeyframes[k] = animation.Keyframe[dprec.Vec3]{
-				Timestamp: keyframe.Timestamp,
-				Value:     keyframe.Value,
+				Timestamp:     keyframe.Timestamp,
+				Value:         keyframe.Value,
+				Interpolation: resolveInterpolation(keyframe.Interpolation),
+				InTangent:     keyframe.InTangent,
+				OutTangent:    keyframe.OutTangent,
+			}
+		}
+		morphWeightKeyframes := make([]animation.Keyframe[[]float64], len(assetBinding.MorphWeightKeyframes))
+		for k, keyframe := range assetBinding.MorphWeightKeyframes {
+			morphWeightKeyframes[k] = animation.Keyframe[[]float64]{
+				Timestamp:     keyframe.Timestamp,
+				Value:         keyframe.Value,
+				Interpolation: resolveInterpolation(keyframe.Interpolation),
+				InTangent:     keyframe.InTangent,
+				OutTangent:    keyframe.OutTangent,
 			}
 		}
 		recording.SetBinding(assetBinding.NodeName, animation.KeyframeSet{
 			TranslationKeyframes: translationKeyframes,
 			RotationKeyframes:    rotationKeyframes,
 			ScaleKeyframes:       scaleKeyframes,
+			MorphWeightKeyframes: morphWeightKeyframes,
 		})
 	}
 	return Identifiable[*animation.Recording]{
@@ -84,3 +106,16 @@ func UnloadRecordings(loader *AssetLoader, idRecordings IdentifiableList[*animat
 	}
 	return nil
 }
+
+func resolveInterpolation(interpolation dto.AnimationInterpolation) animation.Interpolation {
+	switch interpolation {
+	case dto.AnimationInterpolationLinear:
+		return animation.InterpolationLinear
+	case dto.AnimationInterpolationStep:
+		return animation.InterpolationStep
+	case dto.AnimationInterpolationCubicSpline:
+		return animation.InterpolationCubicSpline
+	default:
+		panic(fmt.Errorf("unknown animation interpolation: %v", interpolation))
+	}
+}
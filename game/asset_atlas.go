@@ -0,0 +1,113 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/render"
+)
+
+// Atlas represents a set of named images that have been packed into a
+// number of textures.
+type Atlas struct {
+	Textures IdentifiableList[render.Texture]
+
+	regions  []AtlasRegion
+	byteSize int64
+}
+
+// ByteSize returns the approximate amount of memory in bytes that the
+// atlas occupies.
+func (a *Atlas) ByteSize() int64 {
+	return a.byteSize
+}
+
+// Regions returns the locations of all images in the atlas.
+func (a *Atlas) Regions() []AtlasRegion {
+	return a.regions
+}
+
+// FindRegion returns the location of the image with the specified name.
+func (a *Atlas) FindRegion(name string) (AtlasRegion, bool) {
+	for _, region := range a.regions {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return AtlasRegion{}, false
+}
+
+// AtlasRegion represents the location of an image within an atlas.
+type AtlasRegion struct {
+
+	// Name is the name of the image.
+	Name string
+
+	// Texture is the texture that holds the image. It is nil when the
+	// engine is headless.
+	Texture render.Texture
+
+	// Width and Height are the dimensions of the image in texels.
+	Width  int
+	Height int
+
+	// MinUV and MaxUV are the tex coords of the bottom-left and top-right
+	// corners of the image.
+	MinUV sprec.Vec2
+	MaxUV sprec.Vec2
+}
+
+// LoadAtlas resolves an atlas from the given asset data.
+//
+// When the engine is headless, the textures of the atlas are not loaded.
+//
+// This is a blocking operation and should be called from a worker thread.
+func LoadAtlas(loader *AssetLoader, assetAtlas dto.Atlas) (*Atlas, error) {
+	if assetAtlas.ShadingChunk == nil || assetAtlas.AtlasChunk == nil {
+		return nil, fmt.Errorf("asset is not an atlas")
+	}
+	if loader.Engine().IsHeadless() {
+		assetAtlas.ShadingChunk = &dto.ShadingChunk{}
+	}
+
+	textures, err := LoadTextures(loader, assetAtlas.ShadingChunk.Textures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve textures: %w", err)
+	}
+
+	regions := make([]AtlasRegion, len(assetAtlas.AtlasChunk.Regions))
+	for i, assetRegion := range assetAtlas.AtlasChunk.Regions {
+		texture, _ := textures.FindByID(assetRegion.TextureID)
+		regions[i] = AtlasRegion{
+			Name:    assetRegion.Name,
+			Texture: texture,
+			Width:   int(assetRegion.Width),
+			Height:  int(assetRegion.Height),
+			MinUV:   assetRegion.MinUV,
+			MaxUV:   assetRegion.MaxUV,
+		}
+	}
+
+	var byteSize int64
+	for _, texture := range assetAtlas.ShadingChunk.Textures {
+		for _, mipmap := range texture.MipmapLayers {
+			for _, layer := range mipmap.Layers {
+				byteSize += int64(len(layer.Data))
+			}
+		}
+	}
+
+	return &Atlas{
+		Textures: textures,
+		regions:  regions,
+		byteSize: byteSize,
+	}, nil
+}
+
+// UnloadAtlas unloads the given atlas from the asset loader.
+//
+// This is a blocking operation and should be called from a worker thread.
+func UnloadAtlas(loader *AssetLoader, atlas *Atlas) error {
+	return UnloadTextures(loader, atlas.Textures)
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// CameraTemplate represents a template for a camera in the scene.
+type CameraTemplate struct {
+	NodeID             uint32
+	Name               string
+	FoVMode            graphics.FoVMode
+	FoV                dprec.Angle
+	AspectRatio        float64
+	OrthographicHeight float64
+	Near               float64
+	Far                float64
+}
+
+// LoadCameraTemplate loads a camera template from the given asset data.
+//
+// This is a blocking operation and should be called from a worker thread.
+func LoadCameraTemplate(loader *AssetLoader, assetCamera dto.Camera) (Identifiable[CameraTemplate], error) {
+	fovMode, err := resolveFoVMode(assetCamera.Projection)
+	if err != nil {
+		return Identifiable[CameraTemplate]{}, fmt.Errorf("error resolving camera %q: %w", assetCamera.Name, err)
+	}
+	return Identifiable[CameraTemplate]{
+		ID: assetCamera.ID,
+		Value: CameraTemplate{
+			NodeID:             assetCamera.NodeID,
+			Name:               assetCamera.Name,
+			FoVMode:            fovMode,
+			FoV:                assetCamera.FoVAngle,
+			AspectRatio:        assetCamera.AspectRatio,
+			OrthographicHeight: assetCamera.OrthographicHeight,
+			Near:               assetCamera.Near,
+			Far:                assetCamera.Far,
+		},
+	}, nil
+}
+
+// LoadCameraTemplates loads a list of camera templates from the given
+// asset cameras.
+//
+// This is a blocking operation and should be called from a worker thread.
+func LoadCameraTemplates(loader *AssetLoader, assetCameras []dto.Camera) (IdentifiableList[CameraTemplate], error) {
+	templates := make(IdentifiableList[CameraTemplate], len(assetCameras))
+	for i, assetCamera := range assetCameras {
+		template, err := LoadCameraTemplate(loader, assetCamera)
+		if err != nil {
+			return nil, err
+		}
+		templates[i] = template
+	}
+	return templates, nil
+}
+
+// UnloadCameraTemplate unloads a camera template from the asset loader.
+//
+// This is a blocking operation and should be called from a worker thread.
+func UnloadCameraTemplate(loader *AssetLoader, idCamera Identifiable[CameraTemplate]) error {
+	// At the time being this is a no-op.
+	return nil
+}
+
+// UnloadCameraTemplates unloads a list of camera templates from the asset
+// loader.
+//
+// This is a blocking operation and should be called from a worker thread.
+func UnloadCameraTemplates(loader *AssetLoader, idCameras IdentifiableList[CameraTemplate]) error {
+	for _, idCamera := range idCameras {
+		if err := UnloadCameraTemplate(loader, idCamera); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstantiateCameraTemplate creates a camera in the scene based on the
+// provided template.
+//
+// The camera does not become the active camera of the scene.
+//
+// This operation needs to be called from the main thread.
+func InstantiateCameraTemplate(scene *Scene, template CameraTemplate, nodes IdentifiableList[hierarchy.NodeID]) *graphics.Camera {
+	node := nodes.GetByID(template.NodeID)
+	info := CameraInfo{
+		FoVMode:            opt.V(template.FoVMode),
+		FoV:                opt.V(sprec.Radians(float32(template.FoV.Radians()))),
+		OrthographicHeight: opt.V(float32(template.OrthographicHeight)),
+		Near:               opt.V(float32(template.Near)),
+		Far:                opt.V(float32(template.Far)),
+	}
+	if template.AspectRatio > 0.0 {
+		info.AspectRatio = opt.V(float32(template.AspectRatio))
+	}
+	return scene.PlaceCamera(node, info)
+}
+
+func resolveFoVMode(projection dto.CameraProjection) (graphics.FoVMode, error) {
+	switch projection {
+	case dto.CameraProjectionPerspective:
+		return graphics.FoVModeHorizontalPlus, nil
+	case dto.CameraProjectionOrthographic:
+		return graphics.FoVModeOrthographic, nil
+	default:
+		return "", fmt.Errorf("unsupported camera projection: %d", projection)
+	}
+}
@@ -1,6 +1,8 @@
 package game
 
 import (
+	"fmt"
+
 	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/gomath/dprec"
 	"github.com/mokiat/lacking/game/asset/dto"
@@ -14,6 +16,7 @@ type AmbientLightTemplate struct {
 	NodeID              uint32
 	ReflectionTextureID uint32
 	RefractionTextureID uint32
+	IrradianceSH9       opt.T[[9]dprec.Vec3]
 	CastShadow          bool
 }
 
@@ -22,12 +25,21 @@ type AmbientLightTemplate struct {
 //
 // This is a blocking operation and should be called from a worker thread.
 func LoadAmbientLightTemplate(loader *AssetLoader, assetLight dto.AmbientLight) (Identifiable[AmbientLightTemplate], error) {
+	var irradianceSH9 opt.T[[9]dprec.Vec3]
+	switch assetLight.Mode {
+	case dto.AmbientLightModeTexture:
+	case dto.AmbientLightModeSH9:
+		irradianceSH9 = opt.V(assetLight.IrradianceSH9)
+	default:
+		return Identifiable[AmbientLightTemplate]{}, fmt.Errorf("unsupported ambient light mode: %d", assetLight.Mode)
+	}
 	return Identifiable[AmbientLightTemplate]{
 		ID: assetLight.ID,
 		Value: AmbientLightTemplate{
 			NodeID:              assetLight.NodeID,
 			ReflectionTextureID: assetLight.ReflectionTextureID,
 			RefractionTextureID: assetLight.RefractionTextureID,
+			IrradianceSH9:       irradianceSH9,
 			CastShadow:          assetLight.CastShadow,
 		},
 	}, nil
@@ -82,6 +94,7 @@ func InstantiateAmbientLightTemplate(scene *Scene, template AmbientLightTemplate
 	info := AmbientLightInfo{
 		ReflectionTexture: reflectionTexture,
 		RefractionTexture: refractionTexture,
+		IrradianceSH9:     template.IrradianceSH9,
 		OuterRadius:       opt.Unspecified[float64](),
 		InnerRadius:       opt.Unspecified[float64](),
 		CastShadow:        opt.V(template.CastShadow),
@@ -6,6 +6,7 @@ import "github.com/mokiat/lacking/util/async"
 type AssetLoader struct {
 	engine      *Engine
 	resourceSet *ResourceSet
+	priority    async.Priority
 }
 
 // AsyncEngine returns the async engine associated with this asset loader.
@@ -18,12 +19,19 @@ func (l *AssetLoader) ResourceSet() *ResourceSet {
 	return l.resourceSet
 }
 
-// ScheduleIO schedules an operation to be executed on the IO worker.
+// Priority returns the priority with which the resource was requested.
+func (l *AssetLoader) Priority() async.Priority {
+	return l.priority
+}
+
+// ScheduleIO schedules an operation to be executed on the IO worker, using
+// the priority of the loading process.
 func (l *AssetLoader) ScheduleIO(cb func() error) async.Operation {
-	return l.engine.ScheduleIO(cb)
+	return l.engine.ScheduleIOPriority(l.priority, cb)
 }
 
-// ScheduleMain schedules an operation to be executed on the main thread.
+// ScheduleMain schedules an operation to be executed on the main thread,
+// using the priority of the loading process.
 func (l *AssetLoader) ScheduleMain(cb func() error) async.Operation {
-	return l.engine.ScheduleMain(cb)
+	return l.engine.ScheduleMainPriority(l.priority, cb)
 }
@@ -110,10 +110,11 @@ func InstantiateArmatureTemplate(scene *Scene, template ArmatureTemplate, nodes
 	return armature
 }
 
-// LoadMeshGeomety resolves a mesh geometry from the given asset data.
+// LoadMeshGeomety resolves a mesh geometry from the given asset data. The
+// morph set is expected to belong to the geometry and can be empty.
 //
 // This is a blocking operation and should be called from a worker thread.
-func LoadMeshGeomety(loader *AssetLoader, assetGeometry dto.Geometry) (Identifiable[*graphics.MeshGeometry], error) {
+func LoadMeshGeomety(loader *AssetLoader, assetGeometry dto.Geometry, assetMorphSet dto.MorphSet) (Identifiable[*graphics.MeshGeometry], error) {
 	meshFragmentsInfo := make([]graphics.MeshGeometryFragmentInfo, len(assetGeometry.Fragments))
 	for j, assetFragment := range assetGeometry.Fragments {
 		meshFragmentsInfo[j] = graphics.MeshGeometryFragmentInfo{
@@ -124,19 +125,33 @@ func LoadMeshGeomety(loader *AssetLoader, assetGeometry dto.Geometry) (Identifia
 		}
 	}
 
+	vertexBuffers := gog.Map(assetGeometry.VertexBuffers, func(buffer dto.VertexBuffer) graphics.MeshGeometryVertexBuffer {
+		return graphics.MeshGeometryVertexBuffer{
+			ByteStride: buffer.Stride,
+			Data:       buffer.Data,
+		}
+	})
+	morphTargetsInfo := gog.Map(assetMorphSet.Targets, func(target dto.MorphTarget) graphics.MeshGeometryMorphTargetInfo {
+		return graphics.MeshGeometryMorphTargetInfo{
+			Name:          target.Name,
+			DefaultWeight: target.DefaultWeight,
+		}
+	})
+	morphDeltasInfo := graphics.MeshGeometryMorphDeltas{
+		HasNormals: assetMorphSet.HasNormalDeltas,
+		Data:       assetMorphSet.Deltas,
+	}
+
 	meshGeometryInfo := graphics.MeshGeometryInfo{
-		VertexBuffers: gog.Map(assetGeometry.VertexBuffers, func(buffer dto.VertexBuffer) graphics.MeshGeometryVertexBuffer {
-			return graphics.MeshGeometryVertexBuffer{
-				ByteStride: buffer.Stride,
-				Data:       buffer.Data,
-			}
-		}),
-		VertexFormat: resolveVertexFormat(assetGeometry.VertexLayout),
+		VertexBuffers: vertexBuffers,
+		VertexFormat:  resolveVertexFormat(assetGeometry.VertexLayout),
 		IndexBuffer: graphics.MeshGeometryIndexBuffer{
 			Data:   assetGeometry.IndexBuffer.Data,
 			Format: resolveIndexFormat(assetGeometry.IndexBuffer.IndexLayout),
 		},
 		Fragments:            meshFragmentsInfo,
+		MorphTargets:         morphTargetsInfo,
+		MorphDeltas:          morphDeltasInfo,
 		BoundingSphereRadius: assetGeometry.BoundingSphereRadius,
 		MinDistance:          opt.V(assetGeometry.MinDistance),
 		MaxDistance:          opt.V(assetGeometry.MaxDistance),
@@ -160,15 +175,19 @@ func LoadMeshGeomety(loader *AssetLoader, assetGeometry dto.Geometry) (Identifia
 }
 
 // LoadMeshGeometries resolves a list of mesh geometries from the given asset
-// geometries.
+// geometries. The morph sets are matched to the geometries by ID.
 //
 // This is a blocking operation and should be called from a worker thread.
-func LoadMeshGeometries(loader *AssetLoader, assetGeometries []dto.Geometry) (IdentifiableList[*graphics.MeshGeometry], error) {
+func LoadMeshGeometries(loader *AssetLoader, assetGeometries []dto.Geometry, assetMorphSets []dto.MorphSet) (IdentifiableList[*graphics.MeshGeometry], error) {
+	morphSets := make(map[uint32]dto.MorphSet, len(assetMorphSets))
+	for _, assetMorphSet := range assetMorphSets {
+		morphSets[assetMorphSet.GeometryID] = assetMorphSet
+	}
 	geometries := make(IdentifiableList[*graphics.MeshGeometry], len(assetGeometries))
 	var group errgroup.Group
 	for i, assetGeometry := range assetGeometries {
 		group.Go(func() error {
-			geometry, err := LoadMeshGeomety(loader, assetGeometry)
+			geometry, err := LoadMeshGeomety(loader, assetGeometry, morphSets[assetGeometry.ID])
 			geometries[i] = geometry
 			return err
 		})
@@ -201,27 +220,42 @@ func UnloadMeshGeometries(loader *AssetLoader, idGeometries IdentifiableList[*gr
 }
 
 // LoadMeshDefinition resolves a mesh definition from the given asset data,
-// using the provided geometries and materials.
+// using the provided geometries and materials. The asset levels of detail
+// are optional and are used when the mesh is small on the screen.
 //
 // This is a blocking operation and should be called from a worker thread.
-func LoadMeshDefinition(loader *AssetLoader, assetDefinition dto.MeshDefinition, geometries IdentifiableList[*graphics.MeshGeometry], materials IdentifiableList[*graphics.Material]) (Identifiable[*graphics.MeshDefinition], error) {
+func LoadMeshDefinition(loader *AssetLoader, assetDefinition dto.MeshDefinition, assetLODs []dto.LODLevel, geometries IdentifiableList[*graphics.MeshGeometry], materials IdentifiableList[*graphics.Material]) (Identifiable[*graphics.MeshDefinition], error) {
 	geometry, ok := geometries.FindByID(assetDefinition.GeometryID)
 	if !ok {
 		return Identifiable[*graphics.MeshDefinition]{}, fmt.Errorf("mesh geometry with ID %d not found", assetDefinition.GeometryID)
 	}
 
-	bindingMaterials := make([]*graphics.Material, geometry.FragmentCount())
-	for _, assetBinding := range assetDefinition.MaterialBindings {
-		material, ok := materials.FindByID(assetBinding.MaterialID)
+	bindingMaterials, err := resolveBindingMaterials(geometry, assetDefinition.MaterialBindings, materials)
+	if err != nil {
+		return Identifiable[*graphics.MeshDefinition]{}, err
+	}
+
+	lodInfos := make([]graphics.MeshLODInfo, len(assetLODs))
+	for i, assetLOD := range assetLODs {
+		lodGeometry, ok := geometries.FindByID(assetLOD.GeometryID)
 		if !ok {
-			return Identifiable[*graphics.MeshDefinition]{}, fmt.Errorf("material with ID %d not found", assetBinding.MaterialID)
+			return Identifiable[*graphics.MeshDefinition]{}, fmt.Errorf("lod mesh geometry with ID %d not found", assetLOD.GeometryID)
+		}
+		lodMaterials, err := resolveBindingMaterials(lodGeometry, assetLOD.MaterialBindings, materials)
+		if err != nil {
+			return Identifiable[*graphics.MeshDefinition]{}, err
+		}
+		lodInfos[i] = graphics.MeshLODInfo{
+			Geometry:   lodGeometry,
+			Materials:  lodMaterials,
+			ScreenSize: assetLOD.ScreenSize,
 		}
-		bindingMaterials[assetBinding.FragmentIndex] = material
 	}
 
 	meshDefinitionInfo := graphics.MeshDefinitionInfo{
 		Geometry:  geometry,
 		Materials: bindingMaterials,
+		LODs:      lodInfos,
 	}
 
 	var meshDefinition *graphics.MeshDefinition
@@ -241,15 +275,20 @@ func LoadMeshDefinition(loader *AssetLoader, assetDefinition dto.MeshDefinition,
 }
 
 // LoadMeshDefinitions resolves a list of mesh definitions from the given
-// asset definitions, using the provided geometries and materials.
+// asset definitions, using the provided geometries and materials. The LOD
+// sets are matched to the definitions by ID.
 //
 // This is a blocking operation and should be called from a worker thread.
-func LoadMeshDefinitions(loader *AssetLoader, assetDefinitions []dto.MeshDefinition, geometries IdentifiableList[*graphics.MeshGeometry], materials IdentifiableList[*graphics.Material]) (IdentifiableList[*graphics.MeshDefinition], error) {
+func LoadMeshDefinitions(loader *AssetLoader, assetDefinitions []dto.MeshDefinition, assetLODSets []dto.LODSet, geometries IdentifiableList[*graphics.MeshGeometry], materials IdentifiableList[*graphics.Material]) (IdentifiableList[*graphics.MeshDefinition], error) {
+	lodLevels := make(map[uint32][]dto.LODLevel, len(assetLODSets))
+	for _, assetLODSet := range assetLODSets {
+		lodLevels[assetLODSet.MeshDefinitionID] = assetLODSet.Levels
+	}
 	definitions := make(IdentifiableList[*graphics.MeshDefinition], len(assetDefinitions))
 	var group errgroup.Group
 	for i, assetDefinition := range assetDefinitions {
 		group.Go(func() error {
-			definition, err := LoadMeshDefinition(loader, assetDefinition, geometries, materials)
+			definition, err := LoadMeshDefinition(loader, assetDefinition, lodLevels[assetDefinition.ID], geometries, materials)
 			definitions[i] = definition
 			return err
 		})
@@ -257,6 +296,20 @@ func LoadMeshDefinitions(loader *AssetLoader, assetDefinitions []dto.MeshDefinit
 	return definitions, group.Wait()
 }
 
+// resolveBindingMaterials returns the materials that are bound to the
+// fragments of the specified geometry, in fragment order.
+func resolveBindingMaterials(geometry *graphics.MeshGeometry, assetBindings []dto.MaterialBinding, materials IdentifiableList[*graphics.Material]) ([]*graphics.Material, error) {
+	bindingMaterials := make([]*graphics.Material, geometry.FragmentCount())
+	for _, assetBinding := range assetBindings {
+		material, ok := materials.FindByID(assetBinding.MaterialID)
+		if !ok {
+			return nil, fmt.Errorf("material with ID %d not found", assetBinding.MaterialID)
+		}
+		bindingMaterials[assetBinding.FragmentIndex] = material
+	}
+	return bindingMaterials, nil
+}
+
 // UnloadMeshDefinition unloads a mesh definition from the asset loader.
 //
 // This is a blocking operation and should be called from a worker thread.
@@ -427,6 +480,13 @@ func resolveVertexFormat(layout dto.VertexLayout) graphics.MeshGeometryVertexFor
 			Format:      resolveVertexAttributeFormat(attrib.Format),
 		})
 	}
+	if attrib := layout.TexCoord1; attrib.BufferIndex != dto.UnspecifiedBufferIndex {
+		result.TexCoord1 = opt.V(graphics.MeshGeometryVertexAttribute{
+			BufferIndex: uint32(attrib.BufferIndex),
+			ByteOffset:  attrib.ByteOffset,
+			Format:      resolveVertexAttributeFormat(attrib.Format),
+		})
+	}
 	if attrib := layout.Color; attrib.BufferIndex != dto.UnspecifiedBufferIndex {
 		result.Color = opt.V(graphics.MeshGeometryVertexAttribute{
 			BufferIndex: uint32(attrib.BufferIndex),
@@ -35,7 +35,16 @@ type ModelTemplate struct {
 	PointLights       IdentifiableList[PointLightTemplate]
 	SpotLights        IdentifiableList[SpotLightTemplate]
 	DirectionalLights IdentifiableList[DirectionalLightTemplate]
+	Cameras           IdentifiableList[CameraTemplate]
 	SkyTemplates      IdentifiableList[SkyTemplate]
+
+	byteSize int64
+}
+
+// ByteSize returns the approximate amount of memory in bytes that the
+// template occupies, mostly in the form of texture and geometry data.
+func (t *ModelTemplate) ByteSize() int64 {
+	return t.byteSize
 }
 
 func (t *ModelTemplate) FindRecording(name string) *animation.Recording {
@@ -49,8 +58,32 @@ func (t *ModelTemplate) FindRecording(name string) *animation.Recording {
 
 // LoadModelTemplate resolves a model template from the given asset data.
 //
+// When the engine is headless, only the hierarchy, physics, animation, light
+// and camera data of the model is loaded and all graphics resources are
+// skipped.
+//
 // This is a blocking operation and should be called from a worker thread.
 func LoadModelTemplate(loader *AssetLoader, assetModel dto.Model) (*ModelTemplate, error) {
+	if loader.Engine().IsHeadless() {
+		assetModel.ShadingChunk = &dto.ShadingChunk{}
+		assetModel.MeshChunk = &dto.MeshChunk{}
+		assetModel.BackgroundChunk = &dto.BackgroundChunk{}
+		assetModel.MorphChunk = &dto.MorphChunk{}
+		assetModel.LODChunk = &dto.LODChunk{}
+	}
+	if assetModel.MorphChunk == nil {
+		// Models that were built prior to morph target support lack this chunk.
+		assetModel.MorphChunk = &dto.MorphChunk{}
+	}
+	if assetModel.LODChunk == nil {
+		// Models that were built prior to LOD support lack this chunk.
+		assetModel.LODChunk = &dto.LODChunk{}
+	}
+	if assetModel.CameraChunk == nil {
+		// Models that were built prior to camera support lack this chunk.
+		assetModel.CameraChunk = &dto.CameraChunk{}
+	}
+
 	recordings, err := LoadAnimationRecordings(loader, assetModel.AnimationChunk.Animations)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve animation recordings: %w", err)
@@ -81,12 +114,12 @@ func LoadModelTemplate(loader *AssetLoader, assetModel dto.Model) (*ModelTemplat
 		return nil, fmt.Errorf("failed to resolve body definitions: %w", err)
 	}
 
-	meshGeometries, err := LoadMeshGeometries(loader, assetModel.MeshChunk.Geometries)
+	meshGeometries, err := LoadMeshGeometries(loader, assetModel.MeshChunk.Geometries, assetModel.MorphChunk.MorphSets)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve mesh geometries: %w", err)
 	}
 
-	meshDefinitions, err := LoadMeshDefinitions(loader, assetModel.MeshChunk.MeshDefinitions, meshGeometries, materials)
+	meshDefinitions, err := LoadMeshDefinitions(loader, assetModel.MeshChunk.MeshDefinitions, assetModel.LODChunk.LODSets, meshGeometries, materials)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve mesh definitions: %w", err)
 	}
@@ -131,7 +164,10 @@ func LoadModelTemplate(loader *AssetLoader, assetModel dto.Model) (*ModelTemplat
 		return nil, fmt.Errorf("failed to resolve directional light templates: %w", err)
 	}
 
-	// TODO: Convert cameras
+	cameras, err := LoadCameraTemplates(loader, assetModel.CameraChunk.Cameras)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve camera templates: %w", err)
+	}
 
 	skyTemplates, err := LoadSkyTemplates(loader, assetModel.BackgroundChunk.Skies, materials)
 	if err != nil {
@@ -156,10 +192,34 @@ func LoadModelTemplate(loader *AssetLoader, assetModel dto.Model) (*ModelTemplat
 		PointLights:       pointLights,
 		SpotLights:        spotLights,
 		DirectionalLights: directionalLights,
+		Cameras:           cameras,
 		SkyTemplates:      skyTemplates,
+
+		byteSize: estimateModelByteSize(assetModel),
 	}, nil
 }
 
+func estimateModelByteSize(assetModel dto.Model) int64 {
+	var result int64
+	for _, texture := range assetModel.ShadingChunk.Textures {
+		for _, mipmap := range texture.MipmapLayers {
+			for _, layer := range mipmap.Layers {
+				result += int64(len(layer.Data))
+			}
+		}
+	}
+	for _, geometry := range assetModel.MeshChunk.Geometries {
+		for _, buffer := range geometry.VertexBuffers {
+			result += int64(len(buffer.Data))
+		}
+		result += int64(len(geometry.IndexBuffer.Data))
+	}
+	for _, morphSet := range assetModel.MorphChunk.MorphSets {
+		result += int64(len(morphSet.Deltas))
+	}
+	return result
+}
+
 // UnloadModelTemplate unloads the given model template from the asset loader.
 //
 // This is a blocking operation and should be called from a worker thread.
@@ -182,6 +242,7 @@ func UnloadModelTemplate(loader *AssetLoader, template *ModelTemplate) error {
 		UnloadPointLightTemplates(loader, template.PointLights),
 		UnloadSpotLightTemplates(loader, template.SpotLights),
 		UnloadDirectionalLightTemplates(loader, template.DirectionalLights),
+		UnloadCameraTemplates(loader, template.Cameras),
 		UnloadSkyTemplates(loader, template.SkyTemplates),
 	)
 }
@@ -231,6 +292,12 @@ type Model struct {
 	root       hierarchy.NodeID
 	nodes      IdentifiableList[hierarchy.NodeID]
 	recordings []*animation.Recording
+	cameras    []modelCamera
+}
+
+type modelCamera struct {
+	name   string
+	camera *graphics.Camera
 }
 
 // Root returns the root node of the model hierarchy.
@@ -248,6 +315,26 @@ func (m *Model) FindNode(name string) hierarchy.NodeID {
 	return m.scene.Hierarchy().FindSubtreeNode(m.root, name)
 }
 
+// Cameras returns all cameras that were instantiated as part of the model.
+func (m *Model) Cameras() []*graphics.Camera {
+	result := make([]*graphics.Camera, len(m.cameras))
+	for i, entry := range m.cameras {
+		result[i] = entry.camera
+	}
+	return result
+}
+
+// FindCamera returns the camera with the specified name or nil if there is
+// no such camera in the model.
+func (m *Model) FindCamera(name string) *graphics.Camera {
+	for _, entry := range m.cameras {
+		if entry.name == name {
+			return entry.camera
+		}
+	}
+	return nil
+}
+
 func (m *Model) Recordings() []*animation.Recording {
 	return m.recordings
 }
@@ -312,9 +399,7 @@ func InstantiateModel(scene *Scene, info ModelInfo) *Model {
 	nodes := hierarchyInstance.Nodes
 
 	definition := info.Template
-	textures := definition.Textures
 	recordings := definition.Recordings
-	meshDefinitions := definition.MeshDefinitions
 
 	for template := range definition.Bodies.Values() {
 		if nodes.HasID(template.NodeID) {
@@ -326,6 +411,41 @@ func InstantiateModel(scene *Scene, info ModelInfo) *Model {
 		}
 	}
 
+	var cameras []modelCamera
+	if scene.Graphics() != nil {
+		cameras = instantiateModelGraphics(scene, definition, nodes, info.IsDynamic)
+	}
+
+	scene.Hierarchy().ResetNodeDelta(modelNode, true)
+	scene.Hierarchy().ApplySourceToTarget(modelNode, true)
+	scene.Hierarchy().ApplyNodeToTarget(modelNode, true)
+	scene.Hierarchy().ApplyNodeToInterpolation(modelNode, 1.0, true)
+
+	if !info.IsDynamic && info.DiscardHierarchy {
+		for node := range nodes.Values() {
+			scene.AmbientLightBindingSet().Unbind(node, false)
+			scene.PointLightBindingSet().Unbind(node, false)
+			scene.SpotLightBindingSet().Unbind(node, false)
+			scene.DirectionalLightBindingSet().Unbind(node, false)
+			scene.CameraBindingSet().Unbind(node, false)
+			scene.SkyBindingSet().Unbind(node, false)
+		}
+		scene.Hierarchy().DeleteNode(modelNode)
+	}
+
+	return &Model{
+		scene:      scene,
+		root:       modelNode,
+		nodes:      nodes,
+		recordings: recordings.ValuesList(),
+		cameras:    cameras,
+	}
+}
+
+func instantiateModelGraphics(scene *Scene, definition *ModelTemplate, nodes IdentifiableList[hierarchy.NodeID], isDynamic bool) []modelCamera {
+	textures := definition.Textures
+	meshDefinitions := definition.MeshDefinitions
+
 	armatures := make(IdentifiableList[*graphics.Armature], 0, len(definition.Armatures))
 	for id, template := range definition.Armatures.Iter() {
 		armature := InstantiateArmatureTemplate(scene, template, nodes)
@@ -337,7 +457,7 @@ func InstantiateModel(scene *Scene, info ModelInfo) *Model {
 
 	for template := range definition.Meshes.Values() {
 		if nodes.HasID(template.NodeID) {
-			if info.IsDynamic {
+			if isDynamic {
 				InstantiateMeshTemplateDynamic(scene, template, nodes, meshDefinitions, armatures)
 			} else {
 				InstantiateMeshTemplateStatic(scene, template, nodes, meshDefinitions, armatures)
@@ -371,28 +491,16 @@ func InstantiateModel(scene *Scene, info ModelInfo) *Model {
 		}
 	}
 
-	scene.Hierarchy().ResetNodeDelta(modelNode, true)
-	scene.Hierarchy().ApplySourceToTarget(modelNode, true)
-	scene.Hierarchy().ApplyNodeToTarget(modelNode, true)
-	scene.Hierarchy().ApplyNodeToInterpolation(modelNode, 1.0, true)
-
-	if !info.IsDynamic && info.DiscardHierarchy {
-		for node := range nodes.Values() {
-			scene.AmbientLightBindingSet().Unbind(node, false)
-			scene.PointLightBindingSet().Unbind(node, false)
-			scene.SpotLightBindingSet().Unbind(node, false)
-			scene.DirectionalLightBindingSet().Unbind(node, false)
-			scene.SkyBindingSet().Unbind(node, false)
+	var cameras []modelCamera
+	for template := range definition.Cameras.Values() {
+		if nodes.HasID(template.NodeID) {
+			cameras = append(cameras, modelCamera{
+				name:   template.Name,
+				camera: InstantiateCameraTemplate(scene, template, nodes),
+			})
 		}
-		scene.Hierarchy().DeleteNode(modelNode)
-	}
-
-	return &Model{
-		scene:      scene,
-		root:       modelNode,
-		nodes:      nodes,
-		recordings: recordings.ValuesList(),
 	}
+	return cameras
 }
 
 func (s *Scene) InstantiateModel(info ModelInfo) *Model {
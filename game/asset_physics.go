@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/dprec"
 	"github.com/mokiat/lacking/core/spatial/shape3d"
 	"github.com/mokiat/lacking/game/asset/dto"
 	"github.com/mokiat/lacking/game/hierarchy"
@@ -93,6 +94,7 @@ func LoadPhysicsBodyDefinition(loader *AssetLoader, assetBodyDefinition dto.Body
 		AerodynamicShapes:      nil, // TODO
 		CollisionSpheres:       resolveCollisionSpheres(assetBodyDefinition),
 		CollisionBoxes:         resolveCollisionBoxes(assetBodyDefinition),
+		CollisionConvexHulls:   resolveCollisionConvexHulls(assetBodyDefinition),
 		CollisionMeshes:        resolveCollisionMeshes(assetBodyDefinition),
 	}
 
@@ -175,6 +177,68 @@ func resolveCollisionBoxes(bodyDef dto.BodyDefinition) []shape3d.Box {
 	return result
 }
 
+func resolveCollisionConvexHulls(bodyDef dto.BodyDefinition) []shape3d.ConvexHull {
+	result := make([]shape3d.ConvexHull, len(bodyDef.CollisionConvexHulls))
+	for i, collisionHullAsset := range bodyDef.CollisionConvexHulls {
+		points, faces := resolveConvexHullShape(collisionHullAsset.Triangles)
+		result[i] = shape3d.ConvexHull{
+			Center:   collisionHullAsset.Translation,
+			Rotation: shape3d.RotationFromQuat(collisionHullAsset.Rotation),
+			Points:   points,
+			Faces:    faces,
+		}
+	}
+	return result
+}
+
+// resolveConvexHullShape derives the unique vertices and the bounding planes
+// of a convex hull from the triangles of its boundary. Coplanar triangles
+// contribute a single plane.
+func resolveConvexHullShape(triangles []dto.CollisionTriangle) ([]dprec.Vec3, []shape3d.Surface) {
+	const planeEpsilon = 1e-6
+
+	var (
+		points []dprec.Vec3
+		faces  []shape3d.Surface
+	)
+	seenPoints := make(map[dprec.Vec3]struct{})
+	addPoint := func(point dprec.Vec3) {
+		if _, ok := seenPoints[point]; !ok {
+			seenPoints[point] = struct{}{}
+			points = append(points, point)
+		}
+	}
+	addFace := func(face shape3d.Surface) {
+		for _, existing := range faces {
+			if dprec.Vec3Dot(existing.Normal, face.Normal) > 1.0-planeEpsilon &&
+				dprec.Abs(existing.Distance-face.Distance) < planeEpsilon {
+				return
+			}
+		}
+		faces = append(faces, face)
+	}
+
+	for _, triangle := range triangles {
+		addPoint(triangle.A)
+		addPoint(triangle.B)
+		addPoint(triangle.C)
+
+		normal := dprec.Vec3Cross(
+			dprec.Vec3Diff(triangle.B, triangle.A),
+			dprec.Vec3Diff(triangle.C, triangle.A),
+		)
+		if normal.Length() < planeEpsilon {
+			continue // degenerate triangle
+		}
+		normal = dprec.UnitVec3(normal)
+		addFace(shape3d.Surface{
+			Normal:   normal,
+			Distance: dprec.Vec3Dot(normal, triangle.A),
+		})
+	}
+	return points, faces
+}
+
 func resolveCollisionMeshes(bodyDef dto.BodyDefinition) []shape3d.Mesh {
 	result := make([]shape3d.Mesh, len(bodyDef.CollisionMeshes))
 	for i, collisionMeshAsset := range bodyDef.CollisionMeshes {
@@ -269,12 +333,13 @@ func InstantiatePhysicsBodyTemplateStatic(scene *Scene, template BodyTemplate, n
 	absMatrix := scene.Hierarchy().NodeAbsoluteMatrix(node)
 	nodeName := scene.Hierarchy().NodeName(node)
 	scene.physicsScene.CreateProp(physics.PropInfo{
-		Name:             nodeName,
-		Position:         opt.V(absMatrix.Translation()),
-		Rotation:         opt.V(absMatrix.Rotation()),
-		CollisionSpheres: template.Definition.CollisionSpheres(),
-		CollisionBoxes:   template.Definition.CollisionBoxes(),
-		CollisionMeshes:  template.Definition.CollisionMeshes(),
+		Name:                 nodeName,
+		Position:             opt.V(absMatrix.Translation()),
+		Rotation:             opt.V(absMatrix.Rotation()),
+		CollisionSpheres:     template.Definition.CollisionSpheres(),
+		CollisionBoxes:       template.Definition.CollisionBoxes(),
+		CollisionConvexHulls: template.Definition.CollisionConvexHulls(),
+		CollisionMeshes:      template.Definition.CollisionMeshes(),
 	})
 }
 
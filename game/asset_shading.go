@@ -2,12 +2,14 @@ package game
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/mokiat/gog"
 	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/lacking/game/asset/dto"
 	"github.com/mokiat/lacking/game/graphics"
 	"github.com/mokiat/lacking/render"
+	"github.com/mokiat/lacking/util/bcn"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -91,13 +93,15 @@ func LoadTexture(loader *AssetLoader, assetTexture dto.Texture) (Identifiable[re
 func LoadTexture2D(loader *AssetLoader, assetTexture dto.Texture) (Identifiable[render.Texture], error) {
 	var texture render.Texture
 
+	format, mipmapLayers := resolveTextureData(loader, assetTexture)
+
 	allocateTexture := func() error {
 		renderAPI := loader.Engine().Graphics().API()
 		texture = renderAPI.CreateColorTexture2D(render.ColorTexture2DInfo{
 			GenerateMipmaps: assetTexture.Flags.Has(dto.TextureFlagMipmapping),
 			GammaCorrection: !assetTexture.Flags.Has(dto.TextureFlagLinearSpace),
-			Format:          resolveDataFormat(assetTexture.Format),
-			MipmapLayers: gog.Map(assetTexture.MipmapLayers, func(layer dto.MipmapLayer) render.Mipmap2DLayer {
+			Format:          format,
+			MipmapLayers: gog.Map(mipmapLayers, func(layer dto.MipmapLayer) render.Mipmap2DLayer {
 				return render.Mipmap2DLayer{
 					Width:  layer.Width,
 					Height: layer.Height,
@@ -123,13 +127,15 @@ func LoadTexture2D(loader *AssetLoader, assetTexture dto.Texture) (Identifiable[
 func LoadTextureCube(loader *AssetLoader, assetTexture dto.Texture) (Identifiable[render.Texture], error) {
 	var texture render.Texture
 
+	format, mipmapLayers := resolveTextureData(loader, assetTexture)
+
 	allocateTexture := func() error {
 		renderAPI := loader.Engine().Graphics().API()
 		texture = renderAPI.CreateColorTextureCube(render.ColorTextureCubeInfo{
 			GenerateMipmaps: assetTexture.Flags.Has(dto.TextureFlagMipmapping),
 			GammaCorrection: !assetTexture.Flags.Has(dto.TextureFlagLinearSpace),
-			Format:          resolveDataFormat(assetTexture.Format),
-			MipmapLayers: gog.Map(assetTexture.MipmapLayers, func(layer dto.MipmapLayer) render.MipmapCubeLayer {
+			Format:          format,
+			MipmapLayers: gog.Map(mipmapLayers, func(layer dto.MipmapLayer) render.MipmapCubeLayer {
 				return render.MipmapCubeLayer{
 					Dimension:      layer.Width,
 					FrontSideData:  layer.Layers[0].Data,
@@ -270,14 +276,15 @@ func LoadMaterialPass(loader *AssetLoader, assetPass dto.MaterialPass, shaders I
 		return graphics.MaterialPassInfo{}, fmt.Errorf("shader with ID %d not found", assetPass.ShaderID)
 	}
 	return graphics.MaterialPassInfo{
-		Layer:           assetPass.Layer,
-		Culling:         opt.V(resolveCullMode(assetPass.Culling)),
-		FrontFace:       opt.V(resolveFaceOrientation(assetPass.FrontFace)),
-		DepthTest:       opt.V(assetPass.DepthTest),
-		DepthWrite:      opt.V(assetPass.DepthWrite),
-		DepthComparison: opt.V(resolveComparison(assetPass.DepthComparison)),
-		Blending:        opt.V(assetPass.Blending),
-		Shader:          shader,
+		Layer:            assetPass.Layer,
+		Culling:          opt.V(resolveCullMode(assetPass.Culling)),
+		FrontFace:        opt.V(resolveFaceOrientation(assetPass.FrontFace)),
+		DepthTest:        opt.V(assetPass.DepthTest),
+		DepthWrite:       opt.V(assetPass.DepthWrite),
+		DepthComparison:  opt.V(resolveComparison(assetPass.DepthComparison)),
+		Blending:         opt.V(assetPass.Blending),
+		AdditiveBlending: opt.V(assetPass.AdditiveBlending),
+		Shader:           shader,
 	}, nil
 }
 
@@ -359,11 +366,68 @@ func resolveDataFormat(format dto.TexelFormat) render.DataFormat {
 		return render.DataFormatRGBA16F
 	case dto.TexelFormatRGBA32F:
 		return render.DataFormatRGBA32F
+	case dto.TexelFormatBC1:
+		return render.DataFormatBC1
+	case dto.TexelFormatBC3:
+		return render.DataFormatBC3
+	case dto.TexelFormatBC4:
+		return render.DataFormatBC4
+	case dto.TexelFormatBC5:
+		return render.DataFormatBC5
+	case dto.TexelFormatBC7:
+		return render.DataFormatBC7
 	default:
 		panic(fmt.Errorf("unknown format: %v", format))
 	}
 }
 
+// resolveTextureData returns the data format and the mipmap layers with
+// which the texture should be created. Block-compressed data is decompressed
+// to RGBA8 when the render backend does not support its format.
+func resolveTextureData(loader *AssetLoader, assetTexture dto.Texture) (render.DataFormat, []dto.MipmapLayer) {
+	format := resolveDataFormat(assetTexture.Format)
+	if !format.IsBlockCompressed() {
+		return format, assetTexture.MipmapLayers
+	}
+	limits := loader.Engine().Graphics().API().Limits()
+	if limits.SupportsDataFormat(format) {
+		return format, assetTexture.MipmapLayers
+	}
+
+	blockFormat := resolveBlockFormat(format)
+	logger.Warn("Decompressing texture with unsupported format", slog.String("format", format.String()))
+	return render.DataFormatRGBA8, gog.Map(assetTexture.MipmapLayers, func(mipLayer dto.MipmapLayer) dto.MipmapLayer {
+		width, height := int(mipLayer.Width), int(mipLayer.Height)
+		return dto.MipmapLayer{
+			Width:  mipLayer.Width,
+			Height: mipLayer.Height,
+			Depth:  mipLayer.Depth,
+			Layers: gog.Map(mipLayer.Layers, func(layer dto.TextureLayer) dto.TextureLayer {
+				return dto.TextureLayer{
+					Data: bcn.Decode(blockFormat, layer.Data, width, height),
+				}
+			}),
+		}
+	})
+}
+
+func resolveBlockFormat(format render.DataFormat) bcn.Format {
+	switch format {
+	case render.DataFormatBC1:
+		return bcn.FormatBC1
+	case render.DataFormatBC3:
+		return bcn.FormatBC3
+	case render.DataFormatBC4:
+		return bcn.FormatBC4
+	case render.DataFormatBC5:
+		return bcn.FormatBC5
+	case render.DataFormatBC7:
+		return bcn.FormatBC7
+	default:
+		panic(fmt.Errorf("unknown block format: %v", format))
+	}
+}
+
 func resolveWrapMode(wrap dto.WrapMode) render.WrapMode {
 	switch wrap {
 	case dto.WrapModeClamp:
@@ -19,12 +19,17 @@ type GenericBindingSet interface {
 	DeleteStale()
 }
 
-// NewAnimationBinding creates a new binding for animations.
-func NewAnimationBinding() hierarchy.SourceBinding[*animation.Player] {
-	return &animationBinding{}
+// NewAnimationBinding creates a new binding for animations. Morph target
+// weights are applied to the meshes in the specified mesh binding set.
+func NewAnimationBinding(meshBindingSet *hierarchy.InterpolationBindingSet[*graphics.Mesh]) hierarchy.SourceBinding[*animation.Player] {
+	return &animationBinding{
+		meshBindingSet: meshBindingSet,
+	}
 }
 
-type animationBinding struct{}
+type animationBinding struct {
+	meshBindingSet *hierarchy.InterpolationBindingSet[*graphics.Mesh]
+}
 
 func (b *animationBinding) OnSourceToNode(scene *hierarchy.Scene, player *animation.Player, id hierarchy.NodeID) {
 	name := scene.NodeName(id)
@@ -38,6 +43,13 @@ func (b *animationBinding) OnSourceToNode(scene *hierarchy.Scene, player *animat
 	if transform.Scale.Specified {
 		scene.SetNodeScale(id, transform.Scale.Value)
 	}
+	if transform.MorphWeights.Specified {
+		if mesh := b.meshBindingSet.Get(id); mesh != nil {
+			for i, weight := range transform.MorphWeights.Value {
+				mesh.SetMorphWeight(i, float32(weight))
+			}
+		}
+	}
 }
 
 func (b *animationBinding) OnStaleBinding(scene *hierarchy.Scene, player *animation.Player) {
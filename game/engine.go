@@ -35,6 +35,24 @@ func WithGraphics(gfxEngine *graphics.Engine) EngineOption {
 	}
 }
 
+// WithResourceRetention configures how resources that are no longer
+// referenced are kept around in case they are needed again.
+//
+// By default, unreferenced resources are unloaded immediately.
+func WithResourceRetention(policy ResourceRetentionPolicy) EngineOption {
+	return func(e *Engine) {
+		e.retentionPolicy = policy
+	}
+}
+
+// NewEngine creates a new game engine.
+//
+// If no graphics engine is specified through WithGraphics, the engine runs
+// in headless mode. In that mode models are loaded without any graphics
+// resources, scenes are created without a Graphics sub-scene and the engine
+// can be advanced manually through Advance. This makes it possible to run
+// game logic on dedicated servers and in simulation tests. A GFX worker is
+// still required, as it represents the main thread of the game.
 func NewEngine(opts ...EngineOption) *Engine {
 	result := &Engine{
 		lastTick: time.Now(),
@@ -42,8 +60,9 @@ func NewEngine(opts ...EngineOption) *Engine {
 	for _, opt := range opts {
 		opt(result)
 	}
-	result.registry = newResourceRegistry(result, result.store)
+	result.registry = newResourceRegistry(result, result.store, result.retentionPolicy)
 	result.registry.RegisterResourceLoader(newModelResourceLoader())
+	result.registry.RegisterResourceLoader(newAtlasResourceLoader())
 	return result
 }
 
@@ -53,22 +72,32 @@ type Engine struct {
 	gfxWorker Worker
 	gfxEngine *graphics.Engine
 
-	registry *resourceRegistry
+	retentionPolicy ResourceRetentionPolicy
+	registry        *resourceRegistry
 
 	activeScene *Scene
 	lastTick    time.Time
 }
 
 func (e *Engine) Create() {
-	e.gfxEngine.Create()
+	if e.gfxEngine != nil {
+		e.gfxEngine.Create()
+	}
 	e.ResetDeltaTime()
 }
 
 func (e *Engine) Destroy() {
-	e.gfxEngine.Destroy()
+	if e.gfxEngine != nil {
+		e.gfxEngine.Destroy()
+	}
 	// TODO: Release all scenes and all resource sets
 }
 
+// IsHeadless returns whether the engine is running without graphics.
+func (e *Engine) IsHeadless() bool {
+	return e.gfxEngine == nil
+}
+
 func (e *Engine) Storage() resource.Store {
 	return e.store
 }
@@ -107,6 +136,86 @@ func (e *Engine) UnregisterResourceLoader(resourceLoader ResourceLoader[any]) {
 	e.registry.UnregisterResourceLoader(resourceLoader)
 }
 
+// ResourceRetentionPolicy returns the policy that determines how unreferenced
+// resources are retained.
+func (e *Engine) ResourceRetentionPolicy() ResourceRetentionPolicy {
+	return e.registry.RetentionPolicy()
+}
+
+// SetResourceRetentionPolicy changes the policy that determines how
+// unreferenced resources are retained. Any retained resources that exceed
+// the new policy are unloaded.
+//
+// This method can be called from any thread.
+func (e *Engine) SetResourceRetentionPolicy(policy ResourceRetentionPolicy) {
+	e.registry.SetRetentionPolicy(policy)
+}
+
+// PinResource marks the resource at the specified path so that it is not
+// unloaded even once no ResourceSet references it anymore.
+//
+// Only resources that are currently loaded can be pinned.
+//
+// This method can be called from any thread.
+func (e *Engine) PinResource(path string) error {
+	return e.registry.PinResource(path)
+}
+
+// UnpinResource reverts a previous PinResource call. If the resource is no
+// longer referenced, it becomes subject to the retention policy.
+//
+// This method can be called from any thread.
+func (e *Engine) UnpinResource(path string) {
+	e.registry.UnpinResource(path)
+}
+
+// PurgeResourceCache unloads all resources that are not referenced and are
+// only kept around by the retention policy.
+//
+// This method can be called from any thread.
+func (e *Engine) PurgeResourceCache() {
+	e.registry.PurgeRetained()
+}
+
+// ResourceCacheStats returns summary information on the resources that are
+// currently tracked by the engine.
+//
+// This method can be called from any thread.
+func (e *Engine) ResourceCacheStats() ResourceCacheStats {
+	return e.registry.CacheStats()
+}
+
+// ResourceCacheEntries returns information on each resource that is
+// currently tracked by the engine, sorted by path.
+//
+// This method can be called from any thread.
+func (e *Engine) ResourceCacheEntries() []ResourceCacheEntry {
+	return e.registry.CacheEntries()
+}
+
+// ReloadResource reloads the resource at the specified path in place.
+//
+// The resource is loaded anew from the storage and once ready it replaces
+// the previous value on the main thread, after which the previous value is
+// unloaded. Subsequent fetches of the resource will receive the new value.
+// Subscribers registered through SubscribeResourceReload are notified so
+// that they can recreate any objects that depend on the resource.
+//
+// Only resources that are currently loaded can be reloaded.
+//
+// This method can be called from any thread.
+func (e *Engine) ReloadResource(path string) async.Operation {
+	return e.registry.ReloadResource(path)
+}
+
+// SubscribeResourceReload registers a callback that will be invoked on the
+// main thread whenever a resource has been reloaded.
+//
+// This method should be called from the main thread.
+func (e *Engine) SubscribeResourceReload(callback ResourceReloadCallback) *ResourceReloadSubscription {
+	return e.registry.SubscribeReload(callback)
+}
+
 func (e *Engine) CreateScene(info SceneInfo) *Scene {
 	result := newScene(e, info)
 	if e.activeScene == nil {
@@ -119,9 +228,19 @@ func (e *Engine) ResetDeltaTime() {
 	e.lastTick = time.Now()
 }
 
+// ScheduleIO schedules the specified function to be executed on the IO
+// worker with normal priority.
 func (e *Engine) ScheduleIO(cb func() error) async.Operation {
+	return e.ScheduleIOPriority(async.PriorityNormal, cb)
+}
+
+// ScheduleIOPriority schedules the specified function to be executed on the
+// IO worker with the specified priority.
+//
+// The priority is only honoured if the IO worker is a PriorityWorker.
+func (e *Engine) ScheduleIOPriority(priority async.Priority, cb func() error) async.Operation {
 	result := async.NewOperation()
-	e.ioWorker.Schedule(func() {
+	schedulePriority(e.ioWorker, priority, func() {
 		if err := cb(); err == nil {
 			result.Pass()
 		} else {
@@ -131,9 +250,19 @@ func (e *Engine) ScheduleIO(cb func() error) async.Operation {
 	return result
 }
 
+// ScheduleMain schedules the specified function to be executed on the main
+// thread with normal priority.
 func (e *Engine) ScheduleMain(cb func() error) async.Operation {
+	return e.ScheduleMainPriority(async.PriorityNormal, cb)
+}
+
+// ScheduleMainPriority schedules the specified function to be executed on
+// the main thread with the specified priority.
+//
+// The priority is only honoured if the GFX worker is a PriorityWorker.
+func (e *Engine) ScheduleMainPriority(priority async.Priority, cb func() error) async.Operation {
 	result := async.NewOperation()
-	e.gfxWorker.Schedule(func() {
+	schedulePriority(e.gfxWorker, priority, func() {
 		if err := cb(); err == nil {
 			result.Pass()
 		} else {
@@ -143,13 +272,26 @@ func (e *Engine) ScheduleMain(cb func() error) async.Operation {
 	return result
 }
 
+// Update advances the active scene by the amount of real time that has
+// passed since the last update.
 func (e *Engine) Update() {
-	e.gfxEngine.Debug().Reset()
-
 	currentTime := time.Now()
 	elapsedTime := currentTime.Sub(e.lastTick)
 	e.lastTick = currentTime
 
+	e.Advance(elapsedTime)
+}
+
+// Advance advances the active scene by the specified amount of time,
+// regardless of how much real time has passed.
+//
+// This is useful for headless simulations where the game needs to be driven
+// with fixed timesteps. Using the scene's FixedTimestep as the elapsed time
+// results in exactly one fixed update per call.
+func (e *Engine) Advance(elapsedTime time.Duration) {
+	if e.gfxEngine != nil {
+		e.gfxEngine.Debug().Reset()
+	}
 	if e.activeScene != nil {
 		e.activeScene.Update(elapsedTime)
 	}
@@ -24,6 +24,12 @@ const (
 	// FoVModePixelBased will use an orthogonal projection that
 	// will match in side the screen pixel size.
 	FoVModePixelBased FoVMode = "pixel-based"
+
+	// FoVModeOrthographic will use an orthogonal projection where the
+	// OrthographicHeight setting determines the visible vertical extent
+	// and the horizontal extent is adjusted to preserve the screen's
+	// aspect ratio.
+	FoVModeOrthographic FoVMode = "orthographic"
 )
 
 func newCamera(scene *Scene) *Camera {
@@ -32,6 +38,7 @@ func newCamera(scene *Scene) *Camera {
 		scene:             scene,
 		fov:               sprec.Degrees(120),
 		fovMode:           FoVModeHorizontalPlus,
+		orthoHeight:       10.0,
 		near:              0.1,
 		far:               4000.0,
 		maxExposure:       10000.0,
@@ -50,6 +57,7 @@ type Camera struct {
 	fov                 sprec.Angle
 	fovMode             FoVMode
 	aspectRatio         float32
+	orthoHeight         float32
 	near                float32
 	far                 float32
 	autoFocusEnabled    bool
@@ -97,6 +105,18 @@ func (c *Camera) SetAspectRatio(ratio float32) {
 	c.aspectRatio = ratio
 }
 
+// OrthographicHeight returns the visible vertical extent of this camera
+// when the FoVModeOrthographic mode is used.
+func (c *Camera) OrthographicHeight() float32 {
+	return c.orthoHeight
+}
+
+// SetOrthographicHeight changes the visible vertical extent of this camera
+// when the FoVModeOrthographic mode is used.
+func (c *Camera) SetOrthographicHeight(height float32) {
+	c.orthoHeight = height
+}
+
 // Near returns the distance to the near clipping plane.
 func (c *Camera) Near() float32 {
 	return c.near
@@ -166,31 +166,33 @@ func (e *Engine) CreateMaterial(info MaterialInfo) *Material {
 
 	forwardPasses := gog.Map(info.ForwardPasses, func(passInfo MaterialPassInfo) internal.MaterialRenderPass {
 		return internal.MaterialRenderPass{
-			Layer:           passInfo.Layer,
-			Culling:         passInfo.Culling.ValueOrDefault(render.CullModeNone),
-			FrontFace:       passInfo.FrontFace.ValueOrDefault(render.FaceOrientationCCW),
-			DepthTest:       passInfo.DepthTest.ValueOrDefault(true),
-			DepthWrite:      passInfo.DepthWrite.ValueOrDefault(true),
-			DepthComparison: passInfo.DepthComparison.ValueOrDefault(render.ComparisonLessOrEqual),
-			Blending:        passInfo.Blending.ValueOrDefault(false),
-			TextureSet:      internal.NewShaderTextureSet(passInfo.Shader.ast),
-			UniformSet:      internal.NewShaderUniformSet(passInfo.Shader.ast),
-			Shader:          passInfo.Shader.ast,
+			Layer:            passInfo.Layer,
+			Culling:          passInfo.Culling.ValueOrDefault(render.CullModeNone),
+			FrontFace:        passInfo.FrontFace.ValueOrDefault(render.FaceOrientationCCW),
+			DepthTest:        passInfo.DepthTest.ValueOrDefault(true),
+			DepthWrite:       passInfo.DepthWrite.ValueOrDefault(true),
+			DepthComparison:  passInfo.DepthComparison.ValueOrDefault(render.ComparisonLessOrEqual),
+			Blending:         passInfo.Blending.ValueOrDefault(false),
+			AdditiveBlending: passInfo.AdditiveBlending.ValueOrDefault(false),
+			TextureSet:       internal.NewShaderTextureSet(passInfo.Shader.ast),
+			UniformSet:       internal.NewShaderUniformSet(passInfo.Shader.ast),
+			Shader:           passInfo.Shader.ast,
 		}
 	})
 
 	skyPasses := gog.Map(info.SkyPasses, func(passInfo MaterialPassInfo) internal.MaterialRenderPass {
 		return internal.MaterialRenderPass{
-			Layer:           passInfo.Layer,
-			Culling:         passInfo.Culling.ValueOrDefault(render.CullModeBack),
-			FrontFace:       passInfo.FrontFace.ValueOrDefault(render.FaceOrientationCW),
-			DepthTest:       passInfo.DepthTest.ValueOrDefault(true),
-			DepthWrite:      passInfo.DepthWrite.ValueOrDefault(false),
-			DepthComparison: passInfo.DepthComparison.ValueOrDefault(render.ComparisonLessOrEqual),
-			Blending:        passInfo.Blending.ValueOrDefault(false),
-			TextureSet:      internal.NewShaderTextureSet(passInfo.Shader.ast),
-			UniformSet:      internal.NewShaderUniformSet(passInfo.Shader.ast),
-			Shader:          passInfo.Shader.ast,
+			Layer:            passInfo.Layer,
+			Culling:          passInfo.Culling.ValueOrDefault(render.CullModeBack),
+			FrontFace:        passInfo.FrontFace.ValueOrDefault(render.FaceOrientationCW),
+			DepthTest:        passInfo.DepthTest.ValueOrDefault(true),
+			DepthWrite:       passInfo.DepthWrite.ValueOrDefault(false),
+			DepthComparison:  passInfo.DepthComparison.ValueOrDefault(render.ComparisonLessOrEqual),
+			Blending:         passInfo.Blending.ValueOrDefault(false),
+			AdditiveBlending: passInfo.AdditiveBlending.ValueOrDefault(false),
+			TextureSet:       internal.NewShaderTextureSet(passInfo.Shader.ast),
+			UniformSet:       internal.NewShaderUniformSet(passInfo.Shader.ast),
+			Shader:           passInfo.Shader.ast,
 		}
 	})
 
@@ -260,6 +262,14 @@ func (e *Engine) CreateMeshGeometry(info MeshGeometryInfo) *MeshGeometry {
 			Offset:   attribInfo.Value.ByteOffset,
 		})
 	}
+	if attribInfo := info.VertexFormat.TexCoord1; attribInfo.Specified {
+		attributes = append(attributes, render.VertexArrayAttribute{
+			Binding:  uint(attribInfo.Value.BufferIndex),
+			Location: internal.TexCoord1AttributeIndex,
+			Format:   attribInfo.Value.Format,
+			Offset:   attribInfo.Value.ByteOffset,
+		})
+	}
 	if attribInfo := info.VertexFormat.Color; attribInfo.Specified {
 		attributes = append(attributes, render.VertexArrayAttribute{
 			Binding:  uint(attribInfo.Value.BufferIndex),
@@ -297,7 +307,7 @@ func (e *Engine) CreateMeshGeometry(info MeshGeometryInfo) *MeshGeometry {
 		IndexFormat: info.IndexBuffer.Format,
 	})
 
-	return &MeshGeometry{
+	geometry := &MeshGeometry{
 		vertexBuffers: vertexBuffers,
 		indexBuffer:   indexBuffer,
 		vertexArray:   vertexArray,
@@ -315,6 +325,64 @@ func (e *Engine) CreateMeshGeometry(info MeshGeometryInfo) *MeshGeometry {
 		maxDistance:          info.MaxDistance.ValueOrDefault(32000),
 		maxCascade:           info.MaxCascade.ValueOrDefault(255),
 	}
+	e.createMeshGeometryMorphTargets(geometry, info.MorphTargets, info.MorphDeltas)
+	return geometry
+}
+
+func (e *Engine) createMeshGeometryMorphTargets(geometry *MeshGeometry, targetInfos []MeshGeometryMorphTargetInfo, deltas MeshGeometryMorphDeltas) {
+	if len(targetInfos) == 0 {
+		return
+	}
+
+	const texelSize = 4 * 4 // RGBA32F
+	texelsPerVertex := 1
+	if deltas.HasNormals {
+		texelsPerVertex = 2
+	}
+	targetSize := len(deltas.Data) / len(targetInfos)
+	vertexSize := texelsPerVertex * texelSize
+	if targetSize == 0 || len(deltas.Data)%len(targetInfos) != 0 || targetSize%vertexSize != 0 {
+		logger.Warn("Mesh geometry has inconsistent morph target deltas - ignoring morph targets",
+			slog.Int("targets", len(targetInfos)),
+			slog.Int("bytes", len(deltas.Data)),
+		)
+		return
+	}
+
+	// The texture is padded to full rows, since each row needs to be
+	// specified in its entirety.
+	texelCount := len(deltas.Data) / texelSize
+	height := (texelCount + morphDeltaTextureWidth - 1) / morphDeltaTextureWidth
+	data := make([]byte, height*morphDeltaTextureWidth*texelSize)
+	copy(data, deltas.Data)
+
+	geometry.morphTargets = gog.Map(targetInfos, func(targetInfo MeshGeometryMorphTargetInfo) MeshGeometryMorphTarget {
+		return MeshGeometryMorphTarget{
+			name:          targetInfo.Name,
+			defaultWeight: targetInfo.DefaultWeight,
+		}
+	})
+	geometry.morphDeltaTexture = e.api.CreateColorTexture2D(render.ColorTexture2DInfo{
+		Label:           "Morph Deltas",
+		GenerateMipmaps: false,
+		GammaCorrection: false,
+		Format:          render.DataFormatRGBA32F,
+		MipmapLayers: []render.Mipmap2DLayer{
+			{
+				Width:  morphDeltaTextureWidth,
+				Height: uint32(height),
+				Data:   data,
+			},
+		},
+	})
+	geometry.morphSampler = e.api.CreateSampler(render.SamplerInfo{
+		Label:      "Morph Deltas Sampler",
+		Wrapping:   render.WrapModeClamp,
+		Filtering:  render.FilterModeNearest,
+		Mipmapping: false,
+	})
+	geometry.morphVertexCount = uint32(targetSize / vertexSize)
+	geometry.hasMorphNormals = deltas.HasNormals
 }
 
 // CreateMeshDefinition creates a new MeshDefinition using the specified
@@ -338,6 +406,15 @@ func (e *Engine) CreateMeshDefinition(info MeshDefinitionInfo) *MeshDefinition {
 	for i := range min(len(info.Materials), len(geometry.fragments)) {
 		result.SetMaterial(i, info.Materials[i])
 	}
+	for _, lodInfo := range info.LODs {
+		result.lods = append(result.lods, meshLOD{
+			definition: e.CreateMeshDefinition(MeshDefinitionInfo{
+				Geometry:  lodInfo.Geometry,
+				Materials: lodInfo.Materials,
+			}),
+			screenSize: lodInfo.ScreenSize,
+		})
+	}
 	return result
 }
 
@@ -359,6 +436,7 @@ func (e *Engine) createGeometryPassProgram(programCode render.ProgramCode) rende
 			render.NewTextureBinding("uTexture5", 5),
 			render.NewTextureBinding("uTexture6", 6),
 			render.NewTextureBinding("uTexture7", 7),
+			render.NewTextureBinding("uMorphDeltas", internal.TextureBindingMeshMorphDeltas),
 		},
 		UniformBindings: []render.UniformBinding{
 			render.NewUniformBinding("Camera", internal.UniformBufferBindingCamera),
@@ -366,6 +444,7 @@ func (e *Engine) createGeometryPassProgram(programCode render.ProgramCode) rende
 			render.NewUniformBinding("Timing", internal.UniformBufferBindingTiming),
 			render.NewUniformBinding("Material", internal.UniformBufferBindingMaterial),
 			render.NewUniformBinding("Armature", internal.UniformBufferBindingArmature),
+			render.NewUniformBinding("Morph", internal.UniformBufferBindingMorph),
 		},
 	})
 }
@@ -413,6 +492,7 @@ func (e *Engine) createShadowPassProgram(programCode render.ProgramCode) render.
 			render.NewTextureBinding("uTexture5", 5),
 			render.NewTextureBinding("uTexture6", 6),
 			render.NewTextureBinding("uTexture7", 7),
+			render.NewTextureBinding("uMorphDeltas", internal.TextureBindingMeshMorphDeltas),
 		},
 		UniformBindings: []render.UniformBinding{
 			render.NewUniformBinding("Camera", internal.UniformBufferBindingCamera),
@@ -420,6 +500,7 @@ func (e *Engine) createShadowPassProgram(programCode render.ProgramCode) render.
 			render.NewUniformBinding("Timing", internal.UniformBufferBindingTiming),
 			render.NewUniformBinding("Material", internal.UniformBufferBindingMaterial),
 			render.NewUniformBinding("Armature", internal.UniformBufferBindingArmature),
+			render.NewUniformBinding("Morph", internal.UniformBufferBindingMorph),
 		},
 	})
 }
@@ -467,6 +548,7 @@ func (e *Engine) createForwardPassProgram(programCode render.ProgramCode) render
 			render.NewTextureBinding("uTexture5", 5),
 			render.NewTextureBinding("uTexture6", 6),
 			render.NewTextureBinding("uTexture7", 7),
+			render.NewTextureBinding("uMorphDeltas", internal.TextureBindingMeshMorphDeltas),
 		},
 		UniformBindings: []render.UniformBinding{
 			render.NewUniformBinding("Camera", internal.UniformBufferBindingCamera),
@@ -474,11 +556,16 @@ func (e *Engine) createForwardPassProgram(programCode render.ProgramCode) render
 			render.NewUniformBinding("Timing", internal.UniformBufferBindingTiming),
 			render.NewUniformBinding("Material", internal.UniformBufferBindingMaterial),
 			render.NewUniformBinding("Armature", internal.UniformBufferBindingArmature),
+			render.NewUniformBinding("Morph", internal.UniformBufferBindingMorph),
 		},
 	})
 }
 
 func (e *Engine) createForwardPassPipeline(info internal.RenderPassPipelineInfo) render.Pipeline {
+	destinationColorFactor := render.BlendFactorOneMinusSourceAlpha
+	if info.PassDefinition.AdditiveBlending {
+		destinationColorFactor = render.BlendFactorOne
+	}
 	return e.api.CreatePipeline(render.PipelineInfo{
 		Program: info.Program,
 
@@ -498,12 +585,11 @@ func (e *Engine) createForwardPassPipeline(info internal.RenderPassPipelineInfo)
 
 		ColorWrite: render.ColorMaskTrue,
 
-		BlendEnabled:           info.PassDefinition.Blending, // default: false
-		BlendColor:             [4]float32{0.0, 0.0, 0.0, 0.0},
-		BlendSourceColorFactor: render.BlendFactorSourceAlpha,
-		BlendSourceAlphaFactor: render.BlendFactorOne,
-		// BlendDestinationColorFactor: render.BlendFactorOne,
-		BlendDestinationColorFactor: render.BlendFactorOneMinusSourceAlpha, // TODO: Make configurable, so that additive blending (e.g. like with the lighting) is possible as well.
+		BlendEnabled:                info.PassDefinition.Blending, // default: false
+		BlendColor:                  [4]float32{0.0, 0.0, 0.0, 0.0},
+		BlendSourceColorFactor:      render.BlendFactorSourceAlpha,
+		BlendSourceAlphaFactor:      render.BlendFactorOne,
+		BlendDestinationColorFactor: destinationColorFactor,
 		BlendDestinationAlphaFactor: render.BlendFactorZero,
 		BlendOpColor:                render.BlendOperationAdd,
 		BlendOpAlpha:                render.BlendOperationAdd,
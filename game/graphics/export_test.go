@@ -0,0 +1,20 @@
+package graphics
+
+// NewLODMeshDefinition creates a MeshDefinition that only has levels of
+// detail with the specified screen sizes, which is sufficient for testing
+// the selection of levels of detail.
+func NewLODMeshDefinition(screenSizes ...float64) *MeshDefinition {
+	definition := &MeshDefinition{}
+	for _, screenSize := range screenSizes {
+		definition.lods = append(definition.lods, meshLOD{
+			definition: &MeshDefinition{},
+			screenSize: screenSize,
+		})
+	}
+	return definition
+}
+
+// SelectLOD exposes selectLOD to the external test package.
+func (d *MeshDefinition) SelectLOD(current int, screenSize float64) int {
+	return d.selectLOD(current, screenSize)
+}
@@ -0,0 +1,50 @@
+/* template "version.glsl" . */
+/* template "precision.glsl" . */
+/* template "outputs.glsl" . */
+/* template "camera.glsl" . */
+/* template "public.glsl" . */
+
+layout (std140) uniform AmbientLight
+{
+  mat3 irradianceSH012In;
+  mat3 irradianceSH345In;
+  mat3 irradianceSH678In;
+};
+
+uniform sampler2D fbColor0TextureIn;
+uniform sampler2D fbColor1TextureIn;
+uniform sampler2D fbDepthTextureIn;
+uniform samplerCube reflectionTextureIn;
+
+smooth in vec2 screenCoordInOut;
+
+void main()
+{
+  vec4 albedoMetallic = texture(fbColor0TextureIn, screenCoordInOut);
+  vec4 normalRoughness = texture(fbColor1TextureIn, screenCoordInOut);
+  float depth = texture(fbDepthTextureIn, screenCoordInOut).x;
+
+  vec3 ndcPosition = vec3(screenCoordInOut, depth) * 2.0 - 1.0;
+  vec4 viewPosition = inverse(projectionMatrixIn) * vec4(ndcPosition, 1.0);
+  vec3 worldPosition = (cameraMatrixIn * vec4(viewPosition.xyz / viewPosition.w, 1.0)).xyz;
+  vec3 cameraPosition = cameraMatrixIn[3].xyz;
+
+  vec3 baseColor = albedoMetallic.xyz;
+  float metallic = albedoMetallic.w;
+  vec3 normal = normalize(normalRoughness.xyz);
+  float roughness = normalRoughness.w;
+
+  vec3 viewDirection = normalize(cameraPosition - worldPosition);
+  vec3 reflectedDirection = reflect(-viewDirection, normal);
+  float maxLOD = log2(float(textureSize(reflectionTextureIn, 0).x));
+  vec3 reflectedColor = textureLod(reflectionTextureIn, reflectedDirection, roughness * maxLOD).xyz;
+  vec3 refractedColor = max(irradianceSH9(irradianceSH012In, irradianceSH345In, irradianceSH678In, normal), vec3(0.0));
+
+  vec3 reflectance = mix(vec3(0.04), baseColor, metallic);
+  float cosTheta = max(dot(normal, viewDirection), 0.0);
+  vec3 fresnel = reflectance + (max(vec3(1.0 - roughness), reflectance) - reflectance) * pow(1.0 - cosTheta, 5.0);
+
+  vec3 diffuse = (1.0 - fresnel) * (1.0 - metallic) * baseColor * refractedColor;
+  vec3 specular = fresnel * reflectedColor;
+  fbColor0Out = vec4(diffuse + specular, 1.0);
+}
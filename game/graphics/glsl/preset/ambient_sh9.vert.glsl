@@ -0,0 +1,11 @@
+/* template "version.glsl" . */
+
+layout(location = 0) in vec2 coordIn;
+
+smooth out vec2 screenCoordInOut;
+
+void main()
+{
+  screenCoordInOut = (coordIn + 1.0) / 2.0;
+  gl_Position = vec4(coordIn, 0.0, 1.0);
+}
@@ -15,7 +15,9 @@ smooth in float custom1InOut;
 smooth in float custom2InOut;
 smooth in vec3 normalInOut;
 smooth in vec3 tangentInOut;
+smooth in float tangentSignInOut;
 smooth in vec2 texCoordInOut;
+smooth in vec2 texCoord1InOut;
 smooth in vec4 colorInOut;
 
 void main()
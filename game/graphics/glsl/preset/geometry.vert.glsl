@@ -4,6 +4,7 @@
 /* template "model.glsl" . */
 /* template "timing.glsl" . */
 /* template "armature.glsl" . */
+/* template "morph.glsl" . */
 /* template "textures.glsl" . */
 /* template "uniforms.glsl" . */
 /* template "varyings.glsl" . */
@@ -16,7 +17,9 @@ smooth out float custom1InOut;
 smooth out float custom2InOut;
 smooth out vec3 normalInOut;
 smooth out vec3 tangentInOut;
+smooth out float tangentSignInOut;
 smooth out vec2 texCoordInOut;
+smooth out vec2 texCoord1InOut;
 smooth out vec4 colorInOut;
 
 void main()
@@ -37,15 +40,41 @@ void main()
   vec3 normal_ls = vec3(0.0, 0.0, 1.0);
   /*- end */
   /*- if .HasAttributeTangent */
-  vec3 tangent_ls = attrTangent;
+  vec3 tangent_ls = attrTangent.xyz;
+  float tangent_sign = attrTangent.w;
   /*- else */
   vec3 tangent_ls = vec3(1.0, 0.0, 0.0);
+  float tangent_sign = 1.0;
+  /*- end */
+  /*- if .HasMorphTargets */
+  /*- if .HasMorphNormals */
+  uint morph_stride = 2u;
+  /*- else */
+  uint morph_stride = 1u;
+  /*- end */
+  for (int i = 0; i < 8; i++) {
+    float morph_weight = morphWeightsIn[gl_InstanceID * 2 + i / 4][i % 4];
+    uint morph_target = (morphTargetsIn[gl_InstanceID][i / 2] >> (16u * uint(i % 2))) & 0xFFFFu;
+    if (morph_weight == 0.0 || morph_target >= morphInfoIn.y) {
+      continue;
+    }
+    uint morph_index = (morph_target * morphInfoIn.x + uint(gl_VertexID)) * morph_stride;
+    coord_ls.xyz += morphDelta(morph_index) * morph_weight;
+    /*- if .HasMorphNormals */
+    normal_ls += morphDelta(morph_index + 1u) * morph_weight;
+    /*- end */
+  }
   /*- end */
   /*- if .HasAttributeTexCoord */
   vec2 tex_coord = attrTexCoord;
   /*- else */
   vec2 tex_coord = vec2(0.0, 0.0);
   /*- end */
+  /*- if .HasAttributeTexCoord1 */
+  vec2 tex_coord1 = attrTexCoord1;
+  /*- else */
+  vec2 tex_coord1 = vec2(0.0, 0.0);
+  /*- end */
   /*- if .HasAttributeColor */
   vec4 color = attrColor;
   /*- else */
@@ -62,10 +91,12 @@ void main()
   /*- end */
   vec4 position = vec4(0.0, 0.0, 0.0, 1.0);
   texCoordInOut = tex_coord;
+  texCoord1InOut = tex_coord1;
   colorInOut = color;
   /*- if .MainStatements */
   normalInOut = normal_ls;
   tangentInOut = tangent_ls;
+  tangentSignInOut = tangent_sign;
   /*- range $statement := .MainStatements */
     /* $statement */
   /*- end */
@@ -74,6 +105,7 @@ void main()
   position = projectionMatrixIn * (viewMatrixIn * (model_matrix * coord_ls));
   normalInOut = model_rot_matrix * normal_ls;
   tangentInOut = model_rot_matrix * tangent_ls;
+  tangentSignInOut = tangent_sign;
   /*- end */
   gl_Position = position;
 }
@@ -6,12 +6,16 @@ type VersionProperties struct {
 }
 
 type AttributeProperties struct {
-	HasAttributeCoord    bool
-	HasAttributeNormal   bool
-	HasAttributeTangent  bool
-	HasAttributeTexCoord bool
-	HasAttributeColor    bool
-	HasAttributeArmature bool
+	HasAttributeCoord     bool
+	HasAttributeNormal    bool
+	HasAttributeTangent   bool
+	HasAttributeTexCoord  bool
+	HasAttributeTexCoord1 bool
+	HasAttributeColor     bool
+	HasAttributeArmature  bool
+
+	HasMorphTargets bool
+	HasMorphNormals bool
 }
 
 type OutputProperties struct {
@@ -81,3 +85,8 @@ type ForwardProperties struct {
 	BaseProperties
 	MainProperties
 }
+
+type LightingProperties struct {
+	VersionProperties
+	OutputProperties
+}
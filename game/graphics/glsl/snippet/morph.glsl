@@ -0,0 +1,16 @@
+/*- if .HasMorphTargets */
+layout (std140) uniform Morph
+{
+  uvec4 morphInfoIn; // x - vertex count, y - target count
+  vec4 morphWeightsIn[512];
+  uvec4 morphTargetsIn[256];
+};
+
+uniform highp sampler2D uMorphDeltas;
+
+vec3 morphDelta(uint index)
+{
+  uint width = uint(textureSize(uMorphDeltas, 0).x);
+  return texelFetch(uMorphDeltas, ivec2(index % width, index / width), 0).xyz;
+}
+/*- end */
\ No newline at end of file
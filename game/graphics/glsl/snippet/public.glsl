@@ -6,7 +6,15 @@ mat3 extractRotation(mat4 matrix)
 
 vec3 normalFromTexel(vec3 texel, float scale)
 {
-  return normalize((texel * 2.0 - vec3(1.0)) * vec3(scale, scale, 1.0));
+  if (texel.z > 0.0) {
+    return normalize((texel * 2.0 - vec3(1.0)) * vec3(scale, scale, 1.0));
+  }
+  // Two-channel formats (e.g. BC5) sample a Z of zero, which a stored normal
+  // never has, since it points away from the surface. The Z component is
+  // reconstructed for them instead.
+  vec2 xy = texel.xy * 2.0 - vec2(1.0);
+  float z = sqrt(max(0.0, 1.0 - dot(xy, xy)));
+  return normalize(vec3(xy * scale, z));
 }
 
 vec3 vectorToSurface(vec3 vector, vec3 normal, vec3 tangent)
@@ -15,3 +23,26 @@ vec3 vectorToSurface(vec3 vector, vec3 normal, vec3 tangent)
   mat3 tbn = mat3(tangent, bitangent, normal);
   return tbn * vector;
 }
+
+vec3 vectorToSurface(vec3 vector, vec3 normal, vec3 tangent, float tangentSign)
+{
+  // The sign is interpolated across triangles, so only its sign is used.
+  vec3 bitangent = cross(normal, tangent) * (tangentSign < 0.0 ? -1.0 : 1.0);
+  mat3 tbn = mat3(tangent, bitangent, normal);
+  return tbn * vector;
+}
+
+vec3 irradianceSH9(mat3 sh012, mat3 sh345, mat3 sh678, vec3 normal)
+{
+  // The columns of the matrices hold the nine irradiance coefficients,
+  // ordered by band, as produced by the asset pipeline.
+  return sh012[0] * 0.282095 +
+    sh012[1] * (0.488603 * normal.y) +
+    sh012[2] * (0.488603 * normal.z) +
+    sh345[0] * (0.488603 * normal.x) +
+    sh345[1] * (1.092548 * normal.x * normal.y) +
+    sh345[2] * (1.092548 * normal.y * normal.z) +
+    sh678[0] * (0.315392 * (3.0 * normal.z * normal.z - 1.0)) +
+    sh678[1] * (1.092548 * normal.x * normal.z) +
+    sh678[2] * (0.546274 * (normal.x * normal.x - normal.y * normal.y));
+}
@@ -71,12 +71,16 @@ func (t *Translator) buildVersionProperties() VersionProperties {
 
 func (t *Translator) buildAttributeProperties(settings graphics.ShaderConstraints) AttributeProperties {
 	return AttributeProperties{
-		HasAttributeCoord:    settings.HasCoords,
-		HasAttributeNormal:   settings.HasNormals,
-		HasAttributeTangent:  settings.HasTangents,
-		HasAttributeTexCoord: settings.HasTexCoords,
-		HasAttributeColor:    settings.HasVertexColors,
-		HasAttributeArmature: settings.HasArmature,
+		HasAttributeCoord:     settings.HasCoords,
+		HasAttributeNormal:    settings.HasNormals,
+		HasAttributeTangent:   settings.HasTangents,
+		HasAttributeTexCoord:  settings.HasTexCoords,
+		HasAttributeTexCoord1: settings.HasTexCoords1,
+		HasAttributeColor:     settings.HasVertexColors,
+		HasAttributeArmature:  settings.HasArmature,
+
+		HasMorphTargets: settings.HasMorphTargets,
+		HasMorphNormals: settings.HasMorphNormals,
 	}
 }
 
@@ -357,6 +361,8 @@ func (t *Translator) translateFunctionCall(ctx *translationContext, call *lsl.Fu
 		return t.translateFunctionCallAsIs(ctx, identifier.Name, call.Arguments)
 	case "vectorToSurface":
 		return t.translateFunctionCallAsIs(ctx, identifier.Name, call.Arguments)
+	case "irradianceSH9":
+		return t.translateFunctionCallAsIs(ctx, identifier.Name, call.Arguments)
 	case "billboard":
 		return t.translateFunctionCallAsIs(ctx, identifier.Name, call.Arguments)
 	case "billboardX":
@@ -22,7 +22,9 @@ func (t *Translator) translateForwardVertexCode(shader *lsl.Shader, constraints
 		ctx.RegisterIdentifier("#vertexCoord", "coord_ls")
 		ctx.RegisterIdentifier("#vertexNormal", "normal_ls")
 		ctx.RegisterIdentifier("#vertexTangent", "tangent_ls")
+		ctx.RegisterIdentifier("#vertexTangentSign", "tangent_sign")
 		ctx.RegisterIdentifier("#vertexUV", "tex_coord")
+		ctx.RegisterIdentifier("#vertexUV1", "tex_coord1")
 		ctx.RegisterIdentifier("#vertexColor", "color")
 		// model
 		ctx.RegisterIdentifier("#modelMatrix", "model_matrix")
@@ -23,7 +23,9 @@ func (t *Translator) translateGeometryVertexCode(shader *lsl.Shader, constraints
 		ctx.RegisterIdentifier("#vertexCoord", "coord_ls")
 		ctx.RegisterIdentifier("#vertexNormal", "normal_ls")
 		ctx.RegisterIdentifier("#vertexTangent", "tangent_ls")
+		ctx.RegisterIdentifier("#vertexTangentSign", "tangent_sign")
 		ctx.RegisterIdentifier("#vertexUV", "tex_coord")
+		ctx.RegisterIdentifier("#vertexUV1", "tex_coord1")
 		ctx.RegisterIdentifier("#vertexColor", "color")
 		// model
 		ctx.RegisterIdentifier("#modelMatrix", "model_matrix")
@@ -35,7 +37,9 @@ func (t *Translator) translateGeometryVertexCode(shader *lsl.Shader, constraints
 		// output
 		ctx.RegisterIdentifier("#varyingNormal", "normalInOut")
 		ctx.RegisterIdentifier("#varyingTangent", "tangentInOut")
+		ctx.RegisterIdentifier("#varyingTangentSign", "tangentSignInOut")
 		ctx.RegisterIdentifier("#varyingUV", "texCoordInOut")
+		ctx.RegisterIdentifier("#varyingUV1", "texCoord1InOut")
 		ctx.RegisterIdentifier("#varyingColor", "colorInOut")
 		ctx.RegisterIdentifier("#position", "position")
 
@@ -66,7 +70,9 @@ func (t *Translator) translateGeometryFragmentCode(shader *lsl.Shader, constrain
 		// input
 		ctx.RegisterIdentifier("#varyingNormal", "normalInOut")
 		ctx.RegisterIdentifier("#varyingTangent", "tangentInOut")
+		ctx.RegisterIdentifier("#varyingTangentSign", "tangentSignInOut")
 		ctx.RegisterIdentifier("#varyingUV", "texCoordInOut")
+		ctx.RegisterIdentifier("#varyingUV1", "texCoord1InOut")
 		ctx.RegisterIdentifier("#varyingColor", "colorInOut")
 		// output
 		ctx.RegisterIdentifier("#normal", "normal_ws")
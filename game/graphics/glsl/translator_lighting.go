@@ -0,0 +1,17 @@
+package glsl
+
+// AmbientLightSH9Code returns the program code for ambient lights that use
+// spherical harmonics coefficients for diffuse lighting. It can be used to
+// fill the AmbientLightSH9Set field of graphics.ShaderCollection.
+func (t *Translator) AmbientLightSH9Code() ProgramCode {
+	properties := LightingProperties{
+		VersionProperties: t.buildVersionProperties(),
+		OutputProperties: OutputProperties{
+			HasFramebufferOutput0: true,
+		},
+	}
+	return ProgramCode{
+		VertexCode:   construct("ambient_sh9.vert.glsl", properties),
+		FragmentCode: construct("ambient_sh9.frag.glsl", properties),
+	}
+}
@@ -6,15 +6,16 @@ import (
 )
 
 type MaterialRenderPass struct {
-	Layer           int32
-	Culling         render.CullMode
-	FrontFace       render.FaceOrientation
-	DepthTest       bool
-	DepthWrite      bool
-	DepthComparison render.Comparison
-	Blending        bool
-	TextureSet      TextureSet
-	UniformSet      UniformSet
+	Layer            int32
+	Culling          render.CullMode
+	FrontFace        render.FaceOrientation
+	DepthTest        bool
+	DepthWrite       bool
+	DepthComparison  render.Comparison
+	Blending         bool
+	AdditiveBlending bool
+	TextureSet       TextureSet
+	UniformSet       UniformSet
 	// TODO: Add blending
 	Shader *lsl.Shader
 }
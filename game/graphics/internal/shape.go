@@ -10,6 +10,8 @@ const (
 	ColorAttributeIndex    = 4
 	WeightsAttributeIndex  = 5
 	JointsAttributeIndex   = 6
+
+	TexCoord1AttributeIndex = 15
 )
 
 // Shape defines a simple 3D mesh that does not have any materials.
@@ -12,11 +12,16 @@ const (
 	UniformBufferBindingPostprocess = 6
 	UniformBufferBindingBloom       = 7
 	UniformBufferBindingTiming      = 8
+	UniformBufferBindingMorph       = 9
 )
 
 const (
 	TextureBindingGeometryAlbedoTexture = 0
 
+	// TextureBindingMeshMorphDeltas follows the texture units that are
+	// reserved for material textures.
+	TextureBindingMeshMorphDeltas = 8
+
 	TextureBindingLightingFramebufferColor0 = 0
 	TextureBindingLightingFramebufferColor1 = 1
 	TextureBindingLightingFramebufferDepth  = 3
@@ -47,3 +47,19 @@ func (u LightUniform) Std140Plot(plotter *blob.Plotter) {
 func (u LightUniform) Std140Size() uint32 {
 	return 4*64 + 64 + 4*16 + 16 + 16
 }
+
+type AmbientLightUniform struct {
+	IrradianceSH9 [9]sprec.Vec3
+}
+
+func (u AmbientLightUniform) Std140Plot(plotter *blob.Plotter) {
+	// 9 x vec4, which has the same layout as 3 x mat3
+	for _, coefficient := range u.IrradianceSH9 {
+		plotter.PlotSPVec3(coefficient)
+		plotter.Skip(4)
+	}
+}
+
+func (u AmbientLightUniform) Std140Size() uint32 {
+	return 9 * 16
+}
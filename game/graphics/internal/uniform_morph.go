@@ -0,0 +1,25 @@
+package internal
+
+import "github.com/mokiat/lacking/util/blob"
+
+type MorphUniform struct {
+	VertexCount   uint32
+	TargetCount   uint32
+	TargetWeights []byte
+	TargetIndices []byte
+}
+
+func (u MorphUniform) Std140Plot(plotter *blob.Plotter) {
+	// info
+	plotter.PlotUint32(u.VertexCount)
+	plotter.PlotUint32(u.TargetCount)
+	plotter.PlotUint32(0)
+	plotter.PlotUint32(0)
+
+	plotter.PlotBytes(u.TargetWeights)
+	plotter.PlotBytes(u.TargetIndices)
+}
+
+func (u MorphUniform) Std140Size() uint32 {
+	return 16 + 512*16 + 256*16
+}
@@ -10,13 +10,30 @@ import (
 // - Width, Height, Length
 // Overflow (for linear falloff into neighboring lights)
 
+const (
+	// AmbientLightModeTexture indicates that the ambient light samples the
+	// refraction texture for diffuse lighting.
+	AmbientLightModeTexture AmbientLightMode = iota
+
+	// AmbientLightModeSH9 indicates that the ambient light evaluates
+	// spherical harmonics coefficients for diffuse lighting, which avoids a
+	// texture lookup.
+	AmbientLightModeSH9
+)
+
+// AmbientLightMode determines how an ambient light calculates its diffuse
+// lighting.
+type AmbientLightMode uint8
+
 type AmbientLightInfo struct {
 	Position          dprec.Vec3
 	InnerRadius       float64
 	OuterRadius       float64
 	ReflectionTexture render.Texture
 	RefractionTexture render.Texture
-	CastShadow        bool // TODO: Implement SSAO
+	Mode              AmbientLightMode
+	IrradianceSH9     [9]dprec.Vec3 // used with AmbientLightModeSH9
+	CastShadow        bool          // TODO: Implement SSAO
 }
 
 func newAmbientLight(scene *Scene, info AmbientLightInfo) *AmbientLight {
@@ -29,6 +46,8 @@ func newAmbientLight(scene *Scene, info AmbientLightInfo) *AmbientLight {
 	light.outerRadius = info.OuterRadius
 	light.reflectionTexture = info.ReflectionTexture
 	light.refractionTexture = info.RefractionTexture
+	light.mode = info.Mode
+	light.irradianceSH9 = info.IrradianceSH9
 	light.active = true
 	return light
 }
@@ -42,6 +61,8 @@ type AmbientLight struct {
 	outerRadius       float64
 	reflectionTexture render.Texture
 	refractionTexture render.Texture
+	mode              AmbientLightMode
+	irradianceSH9     [9]dprec.Vec3
 
 	active bool
 }
@@ -54,6 +75,25 @@ func (l *AmbientLight) SetActive(active bool) {
 	l.active = active
 }
 
+// Mode returns the way the light calculates its diffuse lighting.
+func (l *AmbientLight) Mode() AmbientLightMode {
+	return l.mode
+}
+
+// IrradianceSH9 returns the spherical harmonics coefficients that are used
+// in AmbientLightModeSH9.
+func (l *AmbientLight) IrradianceSH9() [9]dprec.Vec3 {
+	return l.irradianceSH9
+}
+
+// SetIrradianceSH9 changes the spherical harmonics coefficients of the light
+// and switches it to AmbientLightModeSH9. This makes it possible to update
+// the diffuse lighting at runtime, for example when the sky changes.
+func (l *AmbientLight) SetIrradianceSH9(coefficients [9]dprec.Vec3) {
+	l.irradianceSH9 = coefficients
+	l.mode = AmbientLightModeSH9
+}
+
 func (l *AmbientLight) SetPosition(position dprec.Vec3) {
 	l.position = position
 	l.scene.ambientLightSet.Update(l.itemID, position, l.outerRadius)
@@ -23,13 +23,16 @@ func newMesh(scene *Scene, info MeshInfo) *Mesh {
 	mesh.scene = scene
 	mesh.itemID = scene.dynamicMeshSet.Insert(dprec.ZeroVec3(), definition.geometry.boundingSphereRadius, mesh)
 	mesh.definition = definition
+	mesh.position = dprec.ZeroVec3()
 	mesh.maxCascade = definition.geometry.maxCascade
 	mesh.armature = info.Armature
+	mesh.lodIndex = 0
 	mesh.active = true
 	mesh.SetCustom0Value(0.0)
 	mesh.SetCustom1Value(0.0)
 	mesh.SetCustom2Value(0.0)
 	mesh.setSpawnTime(scene.gameTime)
+	mesh.morph.reset(definition.geometry)
 	return mesh
 }
 
@@ -39,10 +42,13 @@ type Mesh struct {
 
 	scene        *Scene
 	itemID       spatial.DynamicSetItemID
+	position     dprec.Vec3
 	definition   *MeshDefinition
 	armature     *Armature
 	maxCascade   uint8
 	instanceData [4 * 4]byte // 1x vec4
+	morph        meshMorph
+	lodIndex     int
 	active       bool
 }
 
@@ -57,10 +63,17 @@ func (m *Mesh) SetActive(active bool) {
 	}
 }
 
+// LOD returns the index of the level of detail that was last selected for
+// this mesh, where zero is the main geometry of the definition.
+func (m *Mesh) LOD() int {
+	return m.lodIndex
+}
+
 func (m *Mesh) SetMatrix(matrix dprec.Mat4) {
 	m.Node.SetMatrix(matrix)
 	position := matrix.Translation()
 	radius := m.definition.geometry.boundingSphereRadius
+	m.position = position
 	m.scene.dynamicMeshSet.Update(m.itemID, position, radius)
 }
 
@@ -79,6 +92,27 @@ func (m *Mesh) SetCustom2Value(value float32) {
 	block.SetFloat32(3*4, value)
 }
 
+// MorphWeight returns the weight of the morph target at the specified index.
+func (m *Mesh) MorphWeight(index int) float32 {
+	return m.morph.weight(index)
+}
+
+// SetMorphWeight changes the weight of the morph target at the specified
+// index. Indices that do not correspond to a morph target of the geometry
+// are ignored.
+func (m *Mesh) SetMorphWeight(index int, weight float32) {
+	m.morph.setWeight(index, weight)
+}
+
+// SetMorphWeights changes the weights of the morph targets, starting from the
+// first one. Weights that do not correspond to a morph target of the geometry
+// are ignored.
+func (m *Mesh) SetMorphWeights(weights []float32) {
+	for i, weight := range weights {
+		m.SetMorphWeight(i, weight)
+	}
+}
+
 func (m *Mesh) setSpawnTime(spawnTime time.Duration) {
 	block := gblob.LittleEndianBlock(m.instanceData[:])
 	block.SetFloat32(0*4, float32(spawnTime.Seconds()))
@@ -113,6 +147,7 @@ func createStaticMesh(scene *Scene, info StaticMeshInfo) {
 	staticMesh := &scene.staticMeshes[meshIndex]
 	staticMesh.scene = scene
 	staticMesh.position = position
+	staticMesh.radius = radius
 	staticMesh.minDistance = info.Definition.geometry.minDistance
 	staticMesh.maxDistance = info.Definition.geometry.maxDistance
 	staticMesh.maxCascade = info.Definition.geometry.maxCascade
@@ -124,6 +159,7 @@ func createStaticMesh(scene *Scene, info StaticMeshInfo) {
 	staticMesh.SetCustom1Value(0.0)
 	staticMesh.SetCustom2Value(0.0)
 	staticMesh.setSpawnTime(scene.gameTime)
+	staticMesh.morph.reset(info.Definition.geometry)
 
 	matrix := dtos.Mat4(info.Matrix)
 	plotter := blob.NewPlotter(staticMesh.matrixData)
@@ -133,6 +169,7 @@ func createStaticMesh(scene *Scene, info StaticMeshInfo) {
 type StaticMesh struct {
 	scene        *Scene
 	position     dprec.Vec3
+	radius       float64
 	minDistance  float64
 	maxDistance  float64
 	maxCascade   uint8
@@ -140,6 +177,8 @@ type StaticMesh struct {
 	definition   *MeshDefinition
 	armature     *Armature
 	instanceData [4 * 4]byte // 1x vec4
+	morph        meshMorph
+	lodIndex     int
 	active       bool
 }
 
@@ -154,6 +193,12 @@ func (m *StaticMesh) SetActive(active bool) {
 	}
 }
 
+// LOD returns the index of the level of detail that was last selected for
+// this mesh, where zero is the main geometry of the definition.
+func (m *StaticMesh) LOD() int {
+	return m.lodIndex
+}
+
 func (m *StaticMesh) SetCustom0Value(value float32) {
 	block := gblob.LittleEndianBlock(m.instanceData[:])
 	block.SetFloat32(1*4, value)
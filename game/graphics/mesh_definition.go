@@ -4,10 +4,31 @@ import (
 	"github.com/mokiat/lacking/game/graphics/internal"
 )
 
+// lodHysteresis is the relative margin by which the projected screen size
+// of a mesh needs to cross a level of detail threshold before the mesh
+// switches away from its current level.
+const lodHysteresis = 0.1
+
 // MeshDefinitionInfo contains everything needed to create a new MeshDefinition.
 type MeshDefinitionInfo struct {
 	Geometry  *MeshGeometry
 	Materials []*Material
+
+	// LODs specifies simplified levels of detail that are used instead of
+	// the main geometry when meshes are small on the screen. The levels
+	// should be ordered from the most to the least detailed one.
+	LODs []MeshLODInfo
+}
+
+// MeshLODInfo contains everything needed to define a level of detail of
+// a MeshDefinition.
+type MeshLODInfo struct {
+	Geometry  *MeshGeometry
+	Materials []*Material
+
+	// ScreenSize is the portion of the viewport height that the bounding
+	// sphere of a mesh needs to drop below for this level to be used.
+	ScreenSize float64
 }
 
 // MeshDefinition represents the definition of a mesh.
@@ -20,6 +41,15 @@ type MeshDefinition struct {
 	materials      []*Material
 	materialPasses [][internal.MeshRenderPassTypeCount][]internal.MeshRenderPass
 	passesByType   [internal.MeshRenderPassTypeCount][]internal.MeshRenderPass
+
+	lods []meshLOD
+}
+
+// meshLOD represents a level of detail of a MeshDefinition. Each level is
+// backed by a separate definition that holds the passes of its geometry.
+type meshLOD struct {
+	definition *MeshDefinition
+	screenSize float64
 }
 
 // MaterialCount returns the number of materials defined for this
@@ -33,26 +63,76 @@ func (d *MeshDefinition) Material(index int) *Material {
 	return d.materials[index]
 }
 
-// SetMaterial sets the material at the specified index.
+// SetMaterial sets the material at the specified index. The material is
+// also applied to the fragment at the same index of each level of detail.
 func (d *MeshDefinition) SetMaterial(index int, material *Material) {
-	d.materials[index] = material
-	for i := range internal.MeshRenderPassTypeCount {
-		d.deleteMaterialPasses(index, internal.MeshRenderPassType(i))
-		if material != nil {
-			d.createMaterialPasses(index, internal.MeshRenderPassType(i))
+	d.setMaterial(index, material)
+	for _, lod := range d.lods {
+		if index < len(lod.definition.materials) {
+			lod.definition.setMaterial(index, material)
 		}
-		d.updateGlobalPasses(internal.MeshRenderPassType(i))
 	}
 }
 
+// LODCount returns the number of levels of detail of this MeshDefinition,
+// including the main geometry.
+func (d *MeshDefinition) LODCount() int {
+	return len(d.lods) + 1
+}
+
 // Delete releases any resources owned by this MeshDefinition.
 func (d *MeshDefinition) Delete() {
+	for _, lod := range d.lods {
+		lod.definition.Delete()
+	}
+	d.lods = nil
 	for i := range len(d.materials) {
-		d.SetMaterial(i, nil)
+		d.setMaterial(i, nil)
 	}
 	d.engine = nil
 }
 
+// lodDefinition returns the definition that holds the passes of the
+// level of detail at the specified index, where zero is the main geometry.
+func (d *MeshDefinition) lodDefinition(index int) *MeshDefinition {
+	if index <= 0 || len(d.lods) == 0 {
+		return d
+	}
+	return d.lods[min(index, len(d.lods))-1].definition
+}
+
+// selectLOD returns the index of the level of detail that should be used
+// for a mesh with the specified projected screen size, given the index
+// that the mesh currently uses. Switching away from the current level
+// requires the screen size to cross the threshold by a margin, which
+// prevents meshes from flickering between levels near the threshold.
+func (d *MeshDefinition) selectLOD(current int, screenSize float64) int {
+	result := 0
+	for i, lod := range d.lods {
+		threshold := lod.screenSize
+		if i < current {
+			threshold *= 1.0 + lodHysteresis
+		} else {
+			threshold *= 1.0 - lodHysteresis
+		}
+		if screenSize < threshold {
+			result = i + 1
+		}
+	}
+	return result
+}
+
+func (d *MeshDefinition) setMaterial(index int, material *Material) {
+	d.materials[index] = material
+	for i := range internal.MeshRenderPassTypeCount {
+		d.deleteMaterialPasses(index, internal.MeshRenderPassType(i))
+		if material != nil {
+			d.createMaterialPasses(index, internal.MeshRenderPassType(i))
+		}
+		d.updateGlobalPasses(internal.MeshRenderPassType(i))
+	}
+}
+
 func (d *MeshDefinition) deleteMaterialPasses(index int, passType internal.MeshRenderPassType) {
 	for _, pass := range d.materialPasses[index][passType] {
 		pass.Pipeline.Release()
@@ -68,8 +148,11 @@ func (d *MeshDefinition) createMaterialPasses(index int, passType internal.MeshR
 		HasNormals:      d.geometry.vertexFormat.Normal.Specified,
 		HasTangents:     d.geometry.vertexFormat.Tangent.Specified,
 		HasTexCoords:    d.geometry.vertexFormat.TexCoord.Specified,
+		HasTexCoords1:   d.geometry.vertexFormat.TexCoord1.Specified,
 		HasVertexColors: d.geometry.vertexFormat.Color.Specified,
 		HasArmature:     d.geometry.vertexFormat.Weights.Specified && d.geometry.vertexFormat.Joints.Specified,
+		HasMorphTargets: len(d.geometry.morphTargets) > 0,
+		HasMorphNormals: d.geometry.hasMorphNormals,
 	}
 
 	fragment := d.geometry.fragments[index]
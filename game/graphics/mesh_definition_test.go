@@ -0,0 +1,76 @@
+package graphics_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/game/graphics"
+)
+
+var _ = Describe("MeshDefinition", func() {
+	Describe("SelectLOD", func() {
+		var definition *graphics.MeshDefinition
+
+		BeforeEach(func() {
+			definition = graphics.NewLODMeshDefinition(0.5, 0.2)
+		})
+
+		It("uses the main geometry when there are no levels of detail", func() {
+			definition = graphics.NewLODMeshDefinition()
+			Expect(definition.SelectLOD(0, 0.01)).To(Equal(0))
+		})
+
+		DescribeTable("selection away from the thresholds",
+			func(screenSize float64, expected int) {
+				for current := range definition.LODCount() {
+					Expect(definition.SelectLOD(current, screenSize)).To(Equal(expected))
+				}
+			},
+			Entry("large mesh", 1.0, 0),
+			Entry("medium mesh", 0.35, 1),
+			Entry("small mesh", 0.1, 2),
+		)
+
+		It("switches to a lower level only past the threshold margin", func() {
+			Expect(definition.SelectLOD(0, 0.48)).To(Equal(0))
+			Expect(definition.SelectLOD(0, 0.46)).To(Equal(0))
+			Expect(definition.SelectLOD(0, 0.44)).To(Equal(1))
+		})
+
+		It("switches to a higher level only past the threshold margin", func() {
+			Expect(definition.SelectLOD(1, 0.52)).To(Equal(1))
+			Expect(definition.SelectLOD(1, 0.54)).To(Equal(1))
+			Expect(definition.SelectLOD(1, 0.56)).To(Equal(0))
+		})
+
+		It("keeps the current level while oscillating around a threshold", func() {
+			current := 0
+			for _, screenSize := range []float64{0.52, 0.49, 0.51, 0.48, 0.53} {
+				current = definition.SelectLOD(current, screenSize)
+				Expect(current).To(Equal(0))
+			}
+			current = definition.SelectLOD(current, 0.4)
+			Expect(current).To(Equal(1))
+			for _, screenSize := range []float64{0.48, 0.51, 0.49, 0.53, 0.52} {
+				current = definition.SelectLOD(current, screenSize)
+				Expect(current).To(Equal(1))
+			}
+			current = definition.SelectLOD(current, 0.6)
+			Expect(current).To(Equal(0))
+		})
+
+		It("applies the margin to each threshold relative to the current level", func() {
+			// Dropping from the main geometry straight to the last level
+			// requires crossing both thresholds by the margin.
+			Expect(definition.SelectLOD(0, 0.19)).To(Equal(1))
+			Expect(definition.SelectLOD(0, 0.17)).To(Equal(2))
+
+			// Returning from the last level to the main geometry requires
+			// crossing both thresholds by the margin.
+			Expect(definition.SelectLOD(2, 0.21)).To(Equal(2))
+			Expect(definition.SelectLOD(2, 0.23)).To(Equal(1))
+			Expect(definition.SelectLOD(2, 0.54)).To(Equal(1))
+			Expect(definition.SelectLOD(2, 0.56)).To(Equal(0))
+		})
+	})
+})
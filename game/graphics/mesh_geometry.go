@@ -5,12 +5,24 @@ import (
 	"github.com/mokiat/lacking/render"
 )
 
+// MaxActiveMorphTargets is the maximum number of morph targets that can
+// affect a single mesh at a time. When more morph targets have a non-zero
+// weight, only the ones with the largest weights are applied.
+const MaxActiveMorphTargets = 8
+
+// morphDeltaTextureWidth is the width of the texture that holds the morph
+// target deltas of a mesh geometry. The deltas wrap around to subsequent
+// rows of the texture.
+const morphDeltaTextureWidth = 2048
+
 // MeshGeometryInfo contains everything needed to create a new MeshGeometry.
 type MeshGeometryInfo struct {
 	VertexBuffers        []MeshGeometryVertexBuffer
 	VertexFormat         MeshGeometryVertexFormat
 	IndexBuffer          MeshGeometryIndexBuffer
 	Fragments            []MeshGeometryFragmentInfo
+	MorphTargets         []MeshGeometryMorphTargetInfo
+	MorphDeltas          MeshGeometryMorphDeltas
 	BoundingSphereRadius float64
 	MinDistance          opt.T[float64]
 	MaxDistance          opt.T[float64]
@@ -26,6 +38,27 @@ type MeshGeometryFragmentInfo struct {
 	IndexCount      uint32
 }
 
+// MeshGeometryMorphTargetInfo contains the information needed to represent a
+// morph target (blend shape) of a mesh. The deltas of the morph target are
+// part of the MorphDeltas of the MeshGeometryInfo.
+type MeshGeometryMorphTargetInfo struct {
+	Name          string
+	DefaultWeight float32
+}
+
+// MeshGeometryMorphDeltas contains the per-vertex deltas of all morph targets
+// of a mesh. The deltas are added to the base vertex attributes, scaled by
+// the weight of the respective morph target.
+//
+// The data is a sequence of RGBA32F values, ordered by morph target and then
+// by vertex. Each vertex has one value with the coordinate delta, followed by
+// one value with the normal delta when HasNormals is set. The alpha
+// components are unused.
+type MeshGeometryMorphDeltas struct {
+	HasNormals bool
+	Data       []byte
+}
+
 // MeshGeometry represents the raw geometry of a mesh, without any materials
 // or shading.
 type MeshGeometry struct {
@@ -34,6 +67,11 @@ type MeshGeometry struct {
 	vertexArray          render.VertexArray
 	vertexFormat         MeshGeometryVertexFormat
 	fragments            []MeshGeometryFragment
+	morphTargets         []MeshGeometryMorphTarget
+	morphDeltaTexture    render.Texture
+	morphSampler         render.Sampler
+	morphVertexCount     uint32
+	hasMorphNormals      bool
 	boundingSphereRadius float64
 	minDistance          float64
 	maxDistance          float64
@@ -53,6 +91,16 @@ func (g *MeshGeometry) Fragment(index int) *MeshGeometryFragment {
 	return &g.fragments[index]
 }
 
+// MorphTargetCount returns the number of morph targets of this mesh.
+func (g *MeshGeometry) MorphTargetCount() int {
+	return len(g.morphTargets)
+}
+
+// MorphTarget returns the morph target at the specified index.
+func (g *MeshGeometry) MorphTarget(index int) *MeshGeometryMorphTarget {
+	return &g.morphTargets[index]
+}
+
 // Delete releases the resources that are associated with this mesh geometry.
 func (g *MeshGeometry) Delete() {
 	for _, buffer := range g.vertexBuffers {
@@ -60,6 +108,12 @@ func (g *MeshGeometry) Delete() {
 	}
 	defer g.indexBuffer.Release()
 	defer g.vertexArray.Release()
+	if g.morphDeltaTexture != nil {
+		defer g.morphDeltaTexture.Release()
+	}
+	if g.morphSampler != nil {
+		defer g.morphSampler.Release()
+	}
 }
 
 // MeshGeometryFragment represents a portion of a mesh that is drawn with a
@@ -81,6 +135,23 @@ func (g *MeshGeometryFragment) Topology() render.Topology {
 	return g.topology
 }
 
+// MeshGeometryMorphTarget represents a morph target (blend shape) of a mesh.
+type MeshGeometryMorphTarget struct {
+	name          string
+	defaultWeight float32
+}
+
+// Name returns the name of the morph target.
+func (t *MeshGeometryMorphTarget) Name() string {
+	return t.name
+}
+
+// DefaultWeight returns the weight that meshes initially use for this
+// morph target.
+func (t *MeshGeometryMorphTarget) DefaultWeight() float32 {
+	return t.defaultWeight
+}
+
 // MeshGeometryVertexBuffer represents a buffer that contains vertex data.
 type MeshGeometryVertexBuffer struct {
 	ByteStride uint32
@@ -96,6 +167,9 @@ type MeshGeometryVertexFormat struct {
 	Color    opt.T[MeshGeometryVertexAttribute]
 	Weights  opt.T[MeshGeometryVertexAttribute]
 	Joints   opt.T[MeshGeometryVertexAttribute]
+
+	// TexCoord1 is the secondary texture coordinate set of the vertex.
+	TexCoord1 opt.T[MeshGeometryVertexAttribute]
 }
 
 // MeshGeometryVertexAttribute describes a single attribute of a vertex.
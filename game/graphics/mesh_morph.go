@@ -0,0 +1,88 @@
+package graphics
+
+import (
+	"math"
+
+	"github.com/mokiat/gblob"
+)
+
+// meshMorph tracks the morph target weights of a mesh and the uniform data
+// that selects the morph targets that are applied when the mesh is drawn.
+type meshMorph struct {
+	weights     []float32
+	weightData  [MaxActiveMorphTargets * 4]byte // 2x vec4
+	targetData  [MaxActiveMorphTargets * 2]byte // 1x uvec4
+	needsUpdate bool
+}
+
+// reset initializes the weights to the default weights of the morph targets
+// of the specified geometry.
+func (m *meshMorph) reset(geometry *MeshGeometry) {
+	m.weights = m.weights[:0]
+	for _, target := range geometry.morphTargets {
+		m.weights = append(m.weights, target.defaultWeight)
+	}
+	m.needsUpdate = true
+}
+
+func (m *meshMorph) weight(index int) float32 {
+	if index < 0 || index >= len(m.weights) {
+		return 0.0
+	}
+	return m.weights[index]
+}
+
+func (m *meshMorph) setWeight(index int, weight float32) {
+	if index < 0 || index >= len(m.weights) {
+		return
+	}
+	if m.weights[index] != weight {
+		m.weights[index] = weight
+		m.needsUpdate = true
+	}
+}
+
+// uniformData returns the weights and the indices of the morph targets with
+// the largest absolute weights, in the layout expected by the Morph uniform.
+// Slots that are not used have a zero weight.
+func (m *meshMorph) uniformData() ([MaxActiveMorphTargets * 4]byte, [MaxActiveMorphTargets * 2]byte) {
+	if m.needsUpdate {
+		m.update()
+		m.needsUpdate = false
+	}
+	return m.weightData, m.targetData
+}
+
+func (m *meshMorph) update() {
+	var (
+		slotTargets [MaxActiveMorphTargets]int
+		slotWeights [MaxActiveMorphTargets]float32
+		slotCount   int
+	)
+	for target, weight := range m.weights {
+		if weight == 0.0 {
+			continue
+		}
+		magnitude := float32(math.Abs(float64(weight)))
+		position := slotCount
+		for position > 0 && float32(math.Abs(float64(slotWeights[position-1]))) < magnitude {
+			position--
+		}
+		if position >= MaxActiveMorphTargets {
+			continue
+		}
+		last := min(slotCount, MaxActiveMorphTargets-1)
+		copy(slotTargets[position+1:last+1], slotTargets[position:last])
+		copy(slotWeights[position+1:last+1], slotWeights[position:last])
+		slotTargets[position] = target
+		slotWeights[position] = weight
+		slotCount = min(slotCount+1, MaxActiveMorphTargets)
+	}
+
+	weightBlock := gblob.LittleEndianBlock(m.weightData[:])
+	targetBlock := gblob.LittleEndianBlock(m.targetData[:])
+	for i := range MaxActiveMorphTargets {
+		weightBlock.SetFloat32(i*4, slotWeights[i])
+		targetBlock.SetUint16(i*2, uint16(slotTargets[i]))
+	}
+}
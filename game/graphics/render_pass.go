@@ -32,6 +32,11 @@ type MaterialPassInfo struct {
 	// background. Useful for unlit/emissive special effects.
 	Blending opt.T[bool]
 
+	// AdditiveBlending specifies whether the output will be added to the
+	// background instead of being mixed based on alpha. Only relevant when
+	// Blending is enabled.
+	AdditiveBlending opt.T[bool]
+
 	// Shader is the forward shader that will be used to render the material.
 	Shader *Shader
 }
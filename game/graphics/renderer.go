@@ -3,6 +3,7 @@ package graphics
 import (
 	"fmt"
 	"log/slog"
+	"math"
 	"time"
 
 	"github.com/mokiat/gog/opt"
@@ -172,6 +173,8 @@ func (r *sceneRenderer) Render(framebuffer render.Framebuffer, viewport Viewport
 	r.visibleStaticMeshes.Reset()
 	scene.staticMeshOctree.VisitHexahedronRegion(&frustum, r.visibleStaticMeshes)
 
+	r.updateMeshLODs(scene, projectionMatrix, stod.Vec3(cameraMatrix.Translation()))
+
 	stageCtx := StageContext{
 		Scene:                    scene,
 		Camera:                   camera,
@@ -210,6 +213,40 @@ func (r *sceneRenderer) Render(framebuffer render.Framebuffer, viewport Viewport
 	uniformBuffer.Swap()
 }
 
+// updateMeshLODs selects the level of detail of the visible meshes based
+// on the portion of the viewport height that their bounding spheres cover.
+func (r *sceneRenderer) updateMeshLODs(scene *Scene, projectionMatrix sprec.Mat4, cameraPosition dprec.Vec3) {
+	// The projected size is based on the distance to the camera instead of
+	// the view depth so that rotating the camera does not change the level.
+	screenSize := func(position dprec.Vec3, radius float64) float64 {
+		distance := dprec.Vec3Diff(position, cameraPosition).Length()
+		w := float64(projectionMatrix.M44) - float64(projectionMatrix.M43)*distance
+		if w < 0.0001 {
+			return math.Inf(1)
+		}
+		return radius * dprec.Abs(float64(projectionMatrix.M22)) / w
+	}
+
+	for _, mesh := range r.visibleMeshes.Items() {
+		definition := mesh.definition
+		if definition.LODCount() == 1 {
+			continue
+		}
+		size := screenSize(mesh.position, definition.geometry.boundingSphereRadius)
+		mesh.lodIndex = definition.selectLOD(mesh.lodIndex, size)
+	}
+
+	for _, meshIndex := range r.visibleStaticMeshes.Items() {
+		mesh := &scene.staticMeshes[meshIndex]
+		definition := mesh.definition
+		if definition.LODCount() == 1 {
+			continue
+		}
+		size := screenSize(mesh.position, mesh.radius)
+		mesh.lodIndex = definition.selectLOD(mesh.lodIndex, size)
+	}
+}
+
 func evaluateProjectionMatrix(camera *Camera, width, height uint32) sprec.Mat4 {
 	var (
 		near    = camera.Near()
@@ -240,6 +277,13 @@ func evaluateProjectionMatrix(camera *Camera, width, height uint32) sprec.Mat4 {
 			-halfWidth, halfWidth, halfHeight, -halfHeight, near, far,
 		)
 
+	case FoVModeOrthographic:
+		halfHeight := camera.orthoHeight / 2.0
+		halfWidth := halfHeight * (fWidth / fHeight)
+		return sprec.OrthoMat4(
+			-halfWidth, halfWidth, halfHeight, -halfHeight, near, far,
+		)
+
 	default:
 		panic(fmt.Errorf("unsupported fov mode: %s", camera.fovMode))
 	}
@@ -260,6 +304,13 @@ type renderItem struct {
 	ArmatureData []byte
 	InstanceData [4 * 4]byte
 
+	MorphTexture     render.Texture
+	MorphSampler     render.Sampler
+	MorphVertexCount uint32
+	MorphTargetCount uint32
+	MorphWeightData  [MaxActiveMorphTargets * 4]byte
+	MorphTargetData  [MaxActiveMorphTargets * 2]byte
+
 	IndexByteOffset uint32
 	IndexCount      uint32
 }
@@ -20,11 +20,22 @@ type ShaderMeshConstraints struct {
 	// HasTexCoords specifies whether the mesh has texture coordinates.
 	HasTexCoords bool
 
+	// HasTexCoords1 specifies whether the mesh has a secondary set of texture
+	// coordinates.
+	HasTexCoords1 bool
+
 	// HasVertexColors specifies whether the mesh has vertex colors.
 	HasVertexColors bool
 
 	// HasArmature specifies whether the mesh has an armature.
 	HasArmature bool
+
+	// HasMorphTargets specifies whether the mesh has morph targets.
+	HasMorphTargets bool
+
+	// HasMorphNormals specifies whether the morph targets of the mesh
+	// have normal deltas.
+	HasMorphNormals bool
 }
 
 // ShaderOutputConstraints contains the constraints imposed by the designated
@@ -123,6 +134,16 @@ type ShaderCollection struct {
 	SpotLightSet        func() render.ProgramCode
 	DirectionalLightSet func() render.ProgramCode
 
+	// AmbientLightSH9Set is used for ambient lights in AmbientLightModeSH9.
+	// Instead of a refraction texture, the program receives an
+	// "AmbientLight" uniform block that holds the nine irradiance
+	// coefficients as the columns of three mat3 values.
+	//
+	// GLSL backends can use glsl.Translator.AmbientLightSH9Code. When this
+	// field is nil, such lights are rendered with AmbientLightSet and their
+	// refraction texture instead.
+	AmbientLightSH9Set func() render.ProgramCode
+
 	DebugSet func() render.ProgramCode
 
 	ExposureSet func() render.ProgramCode
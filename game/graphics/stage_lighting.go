@@ -44,6 +44,9 @@ type LightingStage struct {
 	ambientLightProgram  render.Program
 	ambientLightPipeline render.Pipeline
 
+	ambientLightSH9Program  render.Program
+	ambientLightSH9Pipeline render.Pipeline
+
 	pointLightProgram  render.Program
 	pointLightPipeline render.Pipeline
 
@@ -96,7 +99,14 @@ func (s *LightingStage) Render(ctx StageContext) {
 	})
 
 	for _, ambientLight := range ctx.VisibleAmbientLights {
-		if ambientLight.active {
+		if !ambientLight.active {
+			continue
+		}
+		// Backends that do not provide an SH9 program fall back to the
+		// refraction texture of the light.
+		if ambientLight.mode == AmbientLightModeSH9 && s.ambientLightSH9Pipeline != nil {
+			s.renderAmbientLightSH9(ctx, ambientLight)
+		} else {
 			s.renderAmbientLight(ctx, ambientLight)
 		}
 	}
@@ -187,6 +197,44 @@ func (s *LightingStage) allocatePipelines() {
 		BlendOpAlpha:                render.BlendOperationAdd,
 	})
 
+	if s.shaders.AmbientLightSH9Set != nil {
+		s.ambientLightSH9Program = s.api.CreateProgram(render.ProgramInfo{
+			Label:      "Ambient Light SH9 Program",
+			SourceCode: s.shaders.AmbientLightSH9Set(),
+			TextureBindings: []render.TextureBinding{
+				render.NewTextureBinding("fbColor0TextureIn", internal.TextureBindingLightingFramebufferColor0),
+				render.NewTextureBinding("fbColor1TextureIn", internal.TextureBindingLightingFramebufferColor1),
+				render.NewTextureBinding("fbDepthTextureIn", internal.TextureBindingLightingFramebufferDepth),
+				render.NewTextureBinding("reflectionTextureIn", internal.TextureBindingLightingReflectionTexture),
+			},
+			UniformBindings: []render.UniformBinding{
+				render.NewUniformBinding("Camera", internal.UniformBufferBindingCamera),
+				render.NewUniformBinding("AmbientLight", internal.UniformBufferBindingLight),
+			},
+		})
+		s.ambientLightSH9Pipeline = s.api.CreatePipeline(render.PipelineInfo{
+			Label:                       "Ambient Light SH9 Pipeline",
+			Program:                     s.ambientLightSH9Program,
+			VertexArray:                 quadShape.VertexArray(),
+			Topology:                    quadShape.Topology(),
+			Culling:                     render.CullModeBack,
+			FrontFace:                   render.FaceOrientationCCW,
+			DepthTest:                   false,
+			DepthWrite:                  false,
+			DepthComparison:             render.ComparisonAlways,
+			StencilTest:                 false,
+			ColorWrite:                  render.ColorMaskTrue,
+			BlendEnabled:                true,
+			BlendColor:                  [4]float32{0.0, 0.0, 0.0, 0.0},
+			BlendSourceColorFactor:      render.BlendFactorOne,
+			BlendSourceAlphaFactor:      render.BlendFactorOne,
+			BlendDestinationColorFactor: render.BlendFactorOne,
+			BlendDestinationAlphaFactor: render.BlendFactorZero,
+			BlendOpColor:                render.BlendOperationAdd,
+			BlendOpAlpha:                render.BlendOperationAdd,
+		})
+	}
+
 	s.pointLightProgram = s.api.CreateProgram(render.ProgramInfo{
 		Label:      "Point Light Program",
 		SourceCode: s.shaders.PointLightSet(),
@@ -298,6 +346,11 @@ func (s *LightingStage) releasePipelines() {
 	defer s.ambientLightProgram.Release()
 	defer s.ambientLightPipeline.Release()
 
+	if s.ambientLightSH9Program != nil {
+		defer s.ambientLightSH9Program.Release()
+		defer s.ambientLightSH9Pipeline.Release()
+	}
+
 	defer s.pointLightProgram.Release()
 	defer s.pointLightPipeline.Release()
 
@@ -341,6 +394,51 @@ func (s *LightingStage) renderAmbientLight(ctx StageContext, light *AmbientLight
 	commandBuffer.DrawIndexed(0, quadShape.IndexCount(), 1)
 }
 
+func (s *LightingStage) renderAmbientLightSH9(ctx StageContext, light *AmbientLight) {
+	quadShape := s.data.QuadShape()
+
+	nearestSampler := s.data.NearestSampler()
+	mipmapSampler := s.data.MipmapSampler()
+
+	commandBuffer := ctx.CommandBuffer
+	uniformBuffer := ctx.UniformBuffer
+
+	albedoMetallicTexture := s.input.AlbedoMetallicTexture()
+	normalRoughnessTexture := s.input.NormalRoughnessTexture()
+	depthTexture := s.input.DepthTexture()
+
+	var coefficients [9]sprec.Vec3
+	for i, coefficient := range light.irradianceSH9 {
+		coefficients[i] = dtos.Vec3(coefficient)
+	}
+	lightPlacement := ubo.WriteUniform(uniformBuffer, internal.AmbientLightUniform{
+		IrradianceSH9: coefficients,
+	})
+
+	commandBuffer.BindPipeline(s.ambientLightSH9Pipeline)
+	commandBuffer.TextureUnit(internal.TextureBindingLightingFramebufferColor0, albedoMetallicTexture)
+	commandBuffer.SamplerUnit(internal.TextureBindingLightingFramebufferColor0, nearestSampler)
+	commandBuffer.TextureUnit(internal.TextureBindingLightingFramebufferColor1, normalRoughnessTexture)
+	commandBuffer.SamplerUnit(internal.TextureBindingLightingFramebufferColor1, nearestSampler)
+	commandBuffer.TextureUnit(internal.TextureBindingLightingFramebufferDepth, depthTexture)
+	commandBuffer.SamplerUnit(internal.TextureBindingLightingFramebufferDepth, nearestSampler)
+	commandBuffer.TextureUnit(internal.TextureBindingLightingReflectionTexture, light.reflectionTexture)
+	commandBuffer.SamplerUnit(internal.TextureBindingLightingReflectionTexture, mipmapSampler)
+	commandBuffer.UniformBufferUnit(
+		internal.UniformBufferBindingCamera,
+		ctx.CameraPlacement.Buffer,
+		ctx.CameraPlacement.Offset,
+		ctx.CameraPlacement.Size,
+	)
+	commandBuffer.UniformBufferUnit(
+		internal.UniformBufferBindingLight,
+		lightPlacement.Buffer,
+		lightPlacement.Offset,
+		lightPlacement.Size,
+	)
+	commandBuffer.DrawIndexed(0, quadShape.IndexCount(), 1)
+}
+
 func (s *LightingStage) renderPointLight(ctx StageContext, light *PointLight) {
 	sphereShape := s.data.SphereShape()
 	nearestSampler := s.data.NearestSampler()
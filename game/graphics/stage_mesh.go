@@ -22,6 +22,14 @@ const (
 	instanceUniformBufferItemSize  = 16 // 1x vec4
 	instanceUniformBufferItemCount = 256
 	instanceUniformBufferSize      = instanceUniformBufferItemSize * instanceUniformBufferItemCount
+
+	morphWeightsUniformBufferItemSize  = 32 // 2x vec4
+	morphWeightsUniformBufferItemCount = 256
+	morphWeightsUniformBufferSize      = morphWeightsUniformBufferItemSize * morphWeightsUniformBufferItemCount
+
+	morphTargetsUniformBufferItemSize  = 16 // 1x uvec4
+	morphTargetsUniformBufferItemCount = 256
+	morphTargetsUniformBufferSize      = morphTargetsUniformBufferItemSize * morphTargetsUniformBufferItemCount
 )
 
 func newMeshRenderer() *meshRenderer {
@@ -29,6 +37,8 @@ func newMeshRenderer() *meshRenderer {
 		renderItems:               make([]renderItem, 0, initialRenderItemCount),
 		modelUniformBufferData:    make(gblob.LittleEndianBlock, modelUniformBufferSize),
 		instanceUniformBufferData: make(gblob.LittleEndianBlock, instanceUniformBufferSize),
+		morphWeightsBufferData:    make(gblob.LittleEndianBlock, morphWeightsUniformBufferSize),
+		morphTargetsBufferData:    make(gblob.LittleEndianBlock, morphTargetsUniformBufferSize),
 	}
 }
 
@@ -36,6 +46,8 @@ type meshRenderer struct {
 	renderItems               []renderItem
 	modelUniformBufferData    gblob.LittleEndianBlock
 	instanceUniformBufferData gblob.LittleEndianBlock
+	morphWeightsBufferData    gblob.LittleEndianBlock
+	morphTargetsBufferData    gblob.LittleEndianBlock
 }
 
 func (s *meshRenderer) DiscardRenderItems() {
@@ -49,8 +61,9 @@ func (s *meshRenderer) QueueMeshRenderItems(ctx StageContext, mesh *Mesh, passTy
 	if ctx.Cascade > mesh.maxCascade {
 		return
 	}
-	definition := mesh.definition
+	definition := mesh.definition.lodDefinition(mesh.lodIndex)
 	passes := definition.passesByType[passType]
+	morphWeightData, morphTargetData := mesh.morph.uniformData()
 	for _, pass := range passes {
 		s.renderItems = append(s.renderItems, renderItem{
 			Layer:       pass.Layer,
@@ -65,6 +78,13 @@ func (s *meshRenderer) QueueMeshRenderItems(ctx StageContext, mesh *Mesh, passTy
 			ArmatureData: mesh.armature.uniformData(),
 			InstanceData: mesh.instanceData,
 
+			MorphTexture:     definition.geometry.morphDeltaTexture,
+			MorphSampler:     definition.geometry.morphSampler,
+			MorphVertexCount: definition.geometry.morphVertexCount,
+			MorphTargetCount: uint32(definition.geometry.MorphTargetCount()),
+			MorphWeightData:  morphWeightData,
+			MorphTargetData:  morphTargetData,
+
 			IndexByteOffset: pass.IndexByteOffset,
 			IndexCount:      pass.IndexCount,
 		})
@@ -85,8 +105,9 @@ func (s *meshRenderer) QueueStaticMeshRenderItems(ctx StageContext, mesh *Static
 
 	// TODO: Extract common stuff between mesh and static mesh into a type
 	// that is passed ot this function instead so that it can be reused.
-	definition := mesh.definition
+	definition := mesh.definition.lodDefinition(mesh.lodIndex)
 	passes := definition.passesByType[passType]
+	morphWeightData, morphTargetData := mesh.morph.uniformData()
 	for _, pass := range passes {
 		s.renderItems = append(s.renderItems, renderItem{
 			Layer:       pass.Layer,
@@ -100,6 +121,13 @@ func (s *meshRenderer) QueueStaticMeshRenderItems(ctx StageContext, mesh *Static
 			ArmatureData: mesh.armature.uniformData(),
 			InstanceData: mesh.instanceData,
 
+			MorphTexture:     definition.geometry.morphDeltaTexture,
+			MorphSampler:     definition.geometry.morphSampler,
+			MorphVertexCount: definition.geometry.morphVertexCount,
+			MorphTargetCount: uint32(definition.geometry.MorphTargetCount()),
+			MorphWeightData:  morphWeightData,
+			MorphTargetData:  morphTargetData,
+
 			IndexByteOffset: pass.IndexByteOffset,
 			IndexCount:      pass.IndexCount,
 		})
@@ -181,6 +209,16 @@ func (s *meshRenderer) renderMeshRenderItemBatch(ctx StageContext, items []rende
 		instanceStart := i * instanceUniformBufferItemSize
 		instanceEnd := instanceStart + instanceUniformBufferItemSize
 		copy(s.instanceUniformBufferData[instanceStart:instanceEnd], item.InstanceData[:])
+
+		if template.MorphTexture != nil {
+			weightsStart := i * morphWeightsUniformBufferItemSize
+			weightsEnd := weightsStart + morphWeightsUniformBufferItemSize
+			copy(s.morphWeightsBufferData[weightsStart:weightsEnd], item.MorphWeightData[:])
+
+			targetsStart := i * morphTargetsUniformBufferItemSize
+			targetsEnd := targetsStart + morphTargetsUniformBufferItemSize
+			copy(s.morphTargetsBufferData[targetsStart:targetsEnd], item.MorphTargetData[:])
+		}
 	}
 	modelPlacement := ubo.WriteUniform(uniformBuffer, internal.ModelUniform{
 		ModelMatrices: s.modelUniformBufferData,
@@ -201,6 +239,25 @@ func (s *meshRenderer) renderMeshRenderItemBatch(ctx StageContext, items []rende
 		instancePlacement.Size,
 	)
 
+	// Morph deltas are shared between all items, since they belong to the
+	// geometry.
+	if template.MorphTexture != nil {
+		commandBuffer.TextureUnit(internal.TextureBindingMeshMorphDeltas, template.MorphTexture)
+		commandBuffer.SamplerUnit(internal.TextureBindingMeshMorphDeltas, template.MorphSampler)
+		morphPlacement := ubo.WriteUniform(uniformBuffer, internal.MorphUniform{
+			VertexCount:   template.MorphVertexCount,
+			TargetCount:   template.MorphTargetCount,
+			TargetWeights: s.morphWeightsBufferData,
+			TargetIndices: s.morphTargetsBufferData,
+		})
+		commandBuffer.UniformBufferUnit(
+			internal.UniformBufferBindingMorph,
+			morphPlacement.Buffer,
+			morphPlacement.Offset,
+			morphPlacement.Size,
+		)
+	}
+
 	// Armature data is shared between all items.
 	if template.ArmatureData != nil {
 		armaturePlacement := ubo.WriteUniform(uniformBuffer, internal.ArmatureUniform{
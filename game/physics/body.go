@@ -20,6 +20,7 @@ type BodyDefinitionInfo struct {
 	CollisionRejectGroup   uint32
 	CollisionSpheres       []shape3d.Sphere
 	CollisionBoxes         []shape3d.Box
+	CollisionConvexHulls   []shape3d.ConvexHull
 	CollisionMeshes        []shape3d.Mesh
 	AerodynamicShapes      []AerodynamicShape
 }
@@ -34,6 +35,7 @@ type BodyDefinition struct {
 	collisionRejectGroup   uint32
 	collisionSpheres       []shape3d.Sphere
 	collisionBoxes         []shape3d.Box
+	collisionConvexHulls   []shape3d.ConvexHull
 	collisionMeshes        []shape3d.Mesh
 	aerodynamicShapes      []AerodynamicShape
 }
@@ -51,6 +53,7 @@ func NewBodyDefinition(info BodyDefinitionInfo) *BodyDefinition {
 		collisionRejectGroup:   info.CollisionRejectGroup,
 		collisionSpheres:       info.CollisionSpheres,
 		collisionBoxes:         info.CollisionBoxes,
+		collisionConvexHulls:   info.CollisionConvexHulls,
 		collisionMeshes:        info.CollisionMeshes,
 		aerodynamicShapes:      info.AerodynamicShapes,
 	}
@@ -64,6 +67,10 @@ func (d *BodyDefinition) CollisionBoxes() []shape3d.Box {
 	return d.collisionBoxes
 }
 
+func (d *BodyDefinition) CollisionConvexHulls() []shape3d.ConvexHull {
+	return d.collisionConvexHulls
+}
+
 func (d *BodyDefinition) CollisionMeshes() []shape3d.Mesh {
 	return d.collisionMeshes
 }
@@ -405,6 +412,14 @@ func createBody(scene *Scene, info BodyInfo) Body {
 			Box: box,
 		})
 	}
+	for _, hull := range info.Definition.collisionConvexHulls {
+		scene.shapeScene.AttachConvexHull(objectID, placement3d.ConvexHullInfo[struct{}]{
+			Filtering: placement3d.FilterInfo{
+				RejectGroup: info.Definition.collisionRejectGroup,
+			},
+			ConvexHull: hull,
+		})
+	}
 	// for _, mesh := range info.Definition.collisionMeshes {
 	// 	scene.shapeScene.CreateMesh(placement3d.MeshInfo[struct{}]{
 	// 		ShapeInfo: placement3d.ShapeInfo[struct{}]{
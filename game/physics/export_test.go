@@ -0,0 +1,4 @@
+package physics
+
+// ConvexHullMesh exposes convexHullMesh to the external test package.
+var ConvexHullMesh = convexHullMesh
@@ -1,6 +1,10 @@
 package physics
 
 import (
+	"cmp"
+	"math"
+	"slices"
+
 	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/gomath/dprec"
 	"github.com/mokiat/lacking/core/spatial/placement3d"
@@ -8,12 +12,13 @@ import (
 )
 
 type PropInfo struct {
-	Name             string
-	Position         opt.T[dprec.Vec3]
-	Rotation         opt.T[dprec.Quat]
-	CollisionSpheres []shape3d.Sphere
-	CollisionBoxes   []shape3d.Box
-	CollisionMeshes  []shape3d.Mesh
+	Name                 string
+	Position             opt.T[dprec.Vec3]
+	Rotation             opt.T[dprec.Quat]
+	CollisionSpheres     []shape3d.Sphere
+	CollisionBoxes       []shape3d.Box
+	CollisionConvexHulls []shape3d.ConvexHull
+	CollisionMeshes      []shape3d.Mesh
 }
 
 type Prop struct {
@@ -29,3 +34,50 @@ type propState struct {
 	meshID    placement3d.MeshID
 	name      string
 }
+
+// convexHullMesh returns a mesh of the boundary of the specified hull, with
+// triangles that face outward.
+//
+// Each face is triangulated as a fan of the hull points that lie on it,
+// ordered counter-clockwise around the face normal.
+func convexHullMesh(hull shape3d.ConvexHull) shape3d.Mesh {
+	var extent float64
+	for _, point := range hull.Points {
+		extent = max(extent, point.Length())
+	}
+	epsilon := 1e-6 * max(extent, 1.0)
+
+	var triangles []shape3d.Triangle
+	for _, face := range hull.Faces {
+		var facePoints []dprec.Vec3
+		for _, point := range hull.Points {
+			if dprec.Abs(face.SignedDistance(point)) <= epsilon {
+				facePoints = append(facePoints, point)
+			}
+		}
+		if len(facePoints) < 3 {
+			continue
+		}
+
+		var center dprec.Vec3
+		for _, point := range facePoints {
+			center = dprec.Vec3Sum(center, point)
+		}
+		center = dprec.Vec3Quot(center, float64(len(facePoints)))
+
+		axisU := dprec.UnitVec3(dprec.Vec3Diff(facePoints[0], center))
+		axisV := dprec.Vec3Cross(face.Normal, axisU)
+		angle := func(point dprec.Vec3) float64 {
+			offset := dprec.Vec3Diff(point, center)
+			return math.Atan2(dprec.Vec3Dot(offset, axisV), dprec.Vec3Dot(offset, axisU))
+		}
+		slices.SortFunc(facePoints, func(a, b dprec.Vec3) int {
+			return cmp.Compare(angle(a), angle(b))
+		})
+
+		for i := 1; i+1 < len(facePoints); i++ {
+			triangles = append(triangles, shape3d.NewTriangle(facePoints[0], facePoints[i], facePoints[i+1]))
+		}
+	}
+	return shape3d.TransformedMesh(shape3d.NewMesh(triangles), shape3d.TRTransform(hull.Center, hull.Rotation))
+}
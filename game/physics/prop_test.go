@@ -0,0 +1,57 @@
+package physics_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/core/spatial/shape3d"
+	"github.com/mokiat/lacking/game/physics"
+)
+
+var _ = Describe("Prop", func() {
+
+	Describe("ConvexHullMesh", func() {
+		var hull shape3d.ConvexHull
+
+		BeforeEach(func() {
+			var points []dprec.Vec3
+			for _, x := range []float64{-1.0, 1.0} {
+				for _, y := range []float64{-1.0, 1.0} {
+					for _, z := range []float64{-1.0, 1.0} {
+						points = append(points, dprec.NewVec3(x, y, z))
+					}
+				}
+			}
+			faces := []shape3d.Surface{
+				{Normal: dprec.BasisXVec3(), Distance: 1.0},
+				{Normal: dprec.InverseVec3(dprec.BasisXVec3()), Distance: 1.0},
+				{Normal: dprec.BasisYVec3(), Distance: 1.0},
+				{Normal: dprec.InverseVec3(dprec.BasisYVec3()), Distance: 1.0},
+				{Normal: dprec.BasisZVec3(), Distance: 1.0},
+				{Normal: dprec.InverseVec3(dprec.BasisZVec3()), Distance: 1.0},
+			}
+			hull = shape3d.NewConvexHull(dprec.NewVec3(5.0, 0.0, 0.0), shape3d.IdentityRotation(), points, faces)
+		})
+
+		It("covers the boundary of the hull", func() {
+			mesh := physics.ConvexHullMesh(hull)
+			Expect(mesh.Triangles).To(HaveLen(12))
+
+			var area float64
+			for _, triangle := range mesh.Triangles {
+				area += triangle.Area()
+			}
+			Expect(area).To(BeNumerically("~", 24.0, 1e-9))
+		})
+
+		It("places the triangles at the hull and faces them outward", func() {
+			mesh := physics.ConvexHullMesh(hull)
+			for _, triangle := range mesh.Triangles {
+				offset := dprec.Vec3Diff(triangle.Centroid(), hull.Center)
+				Expect(offset.Length()).To(BeNumerically("<=", dprec.Sqrt(3.0)))
+				Expect(triangle.FacesTowards(offset)).To(BeTrue())
+			}
+		})
+	})
+})
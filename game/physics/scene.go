@@ -2,6 +2,7 @@ package physics
 
 import (
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/mokiat/gog/ds"
@@ -276,7 +277,13 @@ func (s *Scene) CreateProp(info PropInfo) {
 	// 		Box:       box,
 	// 	})
 	// }
-	for _, mesh := range info.CollisionMeshes {
+	// Static props are only represented by meshes, hence convex hulls are
+	// converted to the meshes of their boundaries.
+	meshes := slices.Clone(info.CollisionMeshes)
+	for _, hull := range info.CollisionConvexHulls {
+		meshes = append(meshes, convexHullMesh(hull))
+	}
+	for _, mesh := range meshes {
 		propIndex := uint32(len(s.props))
 
 		meshID := s.shapeScene.CreateMesh(placement3d.MeshInfo[propRef]{
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mokiat/gog"
+	"github.com/mokiat/lacking/game/asset/dto"
+	"github.com/mokiat/lacking/storage/chunked"
+)
+
+func newAtlasResourceLoader() ResourceLoader[any] {
+	return GenericResourceLoader(&atlasResourceLoader{
+		resourceType: reflect.TypeOf(gog.Zero[*Atlas]()),
+	})
+}
+
+type atlasResourceLoader struct {
+	resourceType reflect.Type
+}
+
+func (l *atlasResourceLoader) ApplicableType() reflect.Type {
+	return l.resourceType
+}
+
+func (l *atlasResourceLoader) LoadResource(loader *AssetLoader, asset *chunked.Asset) (*Atlas, error) {
+	var dtoAtlas dto.Atlas
+	if err := asset.Read(&dtoAtlas); err != nil {
+		return nil, fmt.Errorf("failed to read asset: %w", err)
+	}
+	atlas, err := LoadAtlas(loader, dtoAtlas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load atlas: %w", err)
+	}
+	return atlas, nil
+}
+
+func (l *atlasResourceLoader) ResourceSize(resource *Atlas) int64 {
+	return resource.ByteSize()
+}
+
+func (l *atlasResourceLoader) UnloadResource(loader *AssetLoader, resource *Atlas) error {
+	if err := UnloadAtlas(loader, resource); err != nil {
+		return fmt.Errorf("failed to unload atlas: %w", err)
+	}
+	return nil
+}
@@ -1,11 +1,18 @@
 package game
 
 import (
+	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/lacking/util/async"
 )
 
 type resourceHandle struct {
+	path           string
 	resourceLoader ResourceLoader[any]
+	dependencies   *ResourceSet
 	promise        async.Promise[any]
 	refCount       int
+	size           int64
+	pinned         bool
+	retained       bool
+	reload         opt.T[async.Operation]
 }
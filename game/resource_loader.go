@@ -45,3 +45,15 @@ func (l *genericResourceLoader[T]) LoadResource(loader *AssetLoader, asset *chun
 func (l *genericResourceLoader[T]) UnloadResource(loader *AssetLoader, resource any) error {
 	return l.delegate.UnloadResource(loader, resource.(T))
 }
+
+func (l *genericResourceLoader[T]) ResourceSize(resource any) int64 {
+	if sizer, ok := l.delegate.(ResourceSizer[T]); ok {
+		return sizer.ResourceSize(resource.(T))
+	}
+	return 0
+}
+
+func (l *genericResourceLoader[T]) isSized() bool {
+	_, ok := l.delegate.(ResourceSizer[T])
+	return ok
+}
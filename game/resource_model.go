@@ -35,6 +35,10 @@ func (l *modelResourceLoader) LoadResource(loader *AssetLoader, asset *chunked.A
 	return model, nil
 }
 
+func (l *modelResourceLoader) ResourceSize(resource *ModelTemplate) int64 {
+	return resource.ByteSize()
+}
+
 func (l *modelResourceLoader) UnloadResource(loader *AssetLoader, resource *ModelTemplate) error {
 	if err := UnloadModelTemplate(loader, resource); err != nil {
 		return fmt.Errorf("failed to unload model template: %w", err)
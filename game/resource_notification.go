@@ -0,0 +1,24 @@
+package game
+
+import "github.com/mokiat/lacking/util/observer"
+
+// ResourceReloadCallback is a function type for callbacks that are invoked
+// when a resource has been reloaded in place.
+//
+// The previous resource is still valid during the callback but will be
+// unloaded shortly after, so any objects that were created from it (e.g.
+// model instances) should be recreated from the new resource.
+type ResourceReloadCallback func(path string, oldResource, newResource any)
+
+// ResourceReloadSubscription represents a notification subscription for
+// resource reloads.
+type ResourceReloadSubscription = observer.Subscription[ResourceReloadCallback]
+
+// ResourceReloadSubscriptionSet represents a set of resource reload
+// subscriptions.
+type ResourceReloadSubscriptionSet = observer.SubscriptionSet[ResourceReloadCallback]
+
+// NewResourceReloadSubscriptionSet creates a new ResourceReloadSubscriptionSet.
+func NewResourceReloadSubscriptionSet() *ResourceReloadSubscriptionSet {
+	return observer.NewSubscriptionSet[ResourceReloadCallback]()
+}
@@ -4,20 +4,26 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 
+	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/lacking/core/resource"
 	"github.com/mokiat/lacking/storage/chunked"
 	"github.com/mokiat/lacking/util/async"
 )
 
-func newResourceRegistry(engine *Engine, store resource.Store) *resourceRegistry {
+func newResourceRegistry(engine *Engine, store resource.Store, policy ResourceRetentionPolicy) *resourceRegistry {
 	return &resourceRegistry{
 		engine: engine,
 		store:  store,
+		policy: policy,
 
 		resourceLoaders: make(map[reflect.Type]ResourceLoader[any]),
 		resources:       make(map[string]*resourceHandle),
+
+		reloadSubscriptions: NewResourceReloadSubscriptionSet(),
 	}
 }
 
@@ -28,6 +34,12 @@ type resourceRegistry struct {
 	mu              sync.Mutex
 	resourceLoaders map[reflect.Type]ResourceLoader[any]
 	resources       map[string]*resourceHandle
+
+	policy       ResourceRetentionPolicy
+	retained     []*resourceHandle // least recently used first
+	retainedSize int64
+
+	reloadSubscriptions *ResourceReloadSubscriptionSet
 }
 
 func (r *resourceRegistry) RegisterResourceLoader(resourceLoader ResourceLoader[any]) {
@@ -40,7 +52,7 @@ func (r *resourceRegistry) UnregisterResourceLoader(resourceLoader ResourceLoade
 	delete(r.resourceLoaders, resourceType)
 }
 
-func (r *resourceRegistry) LoadResource(resourceSet *ResourceSet, path string, target any) async.Operation {
+func (r *resourceRegistry) LoadResource(path string, priority async.Priority, target any) async.Operation {
 	reflValue := reflect.ValueOf(target)
 	if reflValue.Kind() != reflect.Pointer || reflValue.IsNil() {
 		return async.NewFailedOperation(fmt.Errorf("target must be a non-nil pointer, got %T", target))
@@ -50,48 +62,92 @@ func (r *resourceRegistry) LoadResource(resourceSet *ResourceSet, path string, t
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	var promise async.Promise[any]
-	if handle, ok := r.resources[path]; ok {
+	handle, ok := r.resources[path]
+	if ok {
 		handle.refCount++
-		promise = handle.promise
+		if handle.retained {
+			r.removeRetained(handle)
+		}
 	} else {
 		resourceType := reflValue.Type()
 		resourceLoader, ok := r.resourceLoaders[resourceType]
 		if !ok {
 			return async.NewFailedOperation(fmt.Errorf("no resource loader registered for type: %s", resourceType.String()))
 		}
-		promise = async.NewPromise[any]()
-		r.resources[path] = &resourceHandle{
+		// Resources that are fetched while loading are tracked by a set that
+		// is owned by the registry, since the requesting set may be deleted
+		// while the resource is still in use by others.
+		dependencies := newResourceSet(r.engine, r)
+		promise := async.NewPromise[any]()
+		handle = &resourceHandle{
+			path:           path,
 			resourceLoader: resourceLoader,
+			dependencies:   dependencies,
 			promise:        promise,
 			refCount:       1,
 		}
+		r.resources[path] = handle
 		asset := chunked.NewAsset(r.store, path)
 		go func() {
 			assetLoader := &AssetLoader{
 				engine:      r.engine,
-				resourceSet: resourceSet,
+				resourceSet: dependencies,
+				priority:    priority,
 			}
 			resource, err := resourceLoader.LoadResource(assetLoader, asset)
 			if err != nil {
 				promise.Fail(err)
-			} else {
-				promise.Deliver(resource)
+				return
 			}
+			// The size is recorded before the value is delivered, since a
+			// reload can only replace the value once it has been delivered.
+			r.mu.Lock()
+			if handle.promise == promise {
+				r.updateSize(handle, resourceSize(resourceLoader, resource))
+			}
+			r.mu.Unlock()
+			promise.Deliver(resource)
 		}()
 	}
 
 	return async.NewFuncOperation(func() error {
+		return r.awaitResource(handle, func(resource any) {
+			reflValue.Set(reflect.ValueOf(resource))
+		})
+	})
+}
+
+// awaitResource waits for the resource of the handle and passes it to the
+// assign function.
+//
+// If a reload replaces the resource while waiting, the new value is awaited
+// instead. The assignment happens under the registry lock, so a waiter
+// either receives the old value before the swap, in which case reload
+// subscribers are notified afterwards, or it receives the new value. The
+// old value is never handed out once it is scheduled for unloading.
+func (r *resourceRegistry) awaitResource(handle *resourceHandle, assign func(resource any)) error {
+	r.mu.Lock()
+	promise := handle.promise
+	r.mu.Unlock()
+
+	for {
 		resource, err := promise.Wait()
-		if err != nil {
-			return err
+
+		r.mu.Lock()
+		if current := handle.promise; current != promise {
+			r.mu.Unlock()
+			promise = current
+			continue
 		}
-		reflValue.Set(reflect.ValueOf(resource))
-		return nil
-	})
+		if err == nil {
+			assign(resource)
+		}
+		r.mu.Unlock()
+		return err
+	}
 }
 
-func (r *resourceRegistry) UnloadResource(resourceSet *ResourceSet, path string, count int) {
+func (r *resourceRegistry) UnloadResource(path string, count int) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -104,21 +160,319 @@ func (r *resourceRegistry) UnloadResource(resourceSet *ResourceSet, path string,
 	if handle.refCount -= count; handle.refCount > 0 {
 		return // still in use
 	}
-	delete(r.resources, path)
+	r.releaseHandle(handle)
+}
+
+func (r *resourceRegistry) PinResource(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handle, ok := r.resources[path]
+	if !ok {
+		return fmt.Errorf("resource %q is not loaded", path)
+	}
+	handle.pinned = true
+	if handle.retained {
+		r.removeRetained(handle)
+	}
+	return nil
+}
+
+func (r *resourceRegistry) UnpinResource(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handle, ok := r.resources[path]
+	if !ok || !handle.pinned {
+		logger.Error("Trying to unpin resource that is not pinned", slog.String("path", path))
+		return
+	}
+	handle.pinned = false
+	if handle.refCount <= 0 {
+		r.releaseHandle(handle)
+	}
+}
+
+func (r *resourceRegistry) RetentionPolicy() ResourceRetentionPolicy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.policy
+}
+
+func (r *resourceRegistry) SetRetentionPolicy(policy ResourceRetentionPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy = policy
+	r.evictRetained()
+}
+
+func (r *resourceRegistry) PurgeRetained() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(r.retained) > 0 {
+		r.evictHandle(r.retained[0])
+	}
+}
+
+func (r *resourceRegistry) CacheStats() ResourceCacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := ResourceCacheStats{
+		Policy:        r.policy,
+		TotalCount:    len(r.resources),
+		RetainedCount: len(r.retained),
+		RetainedSize:  r.retainedSize,
+	}
+	for _, handle := range r.resources {
+		result.TotalSize += handle.size
+		if handle.pinned {
+			result.PinnedCount++
+		}
+	}
+	return result
+}
+
+func (r *resourceRegistry) CacheEntries() []ResourceCacheEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]ResourceCacheEntry, 0, len(r.resources))
+	for path, handle := range r.resources {
+		result = append(result, ResourceCacheEntry{
+			Path:     path,
+			Size:     handle.size,
+			RefCount: handle.refCount,
+			Pinned:   handle.pinned,
+			Retained: handle.retained,
+		})
+	}
+	slices.SortFunc(result, func(a, b ResourceCacheEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return result
+}
+
+// releaseHandle is called when a resource is no longer referenced. Depending
+// on the retention policy, the resource is either unloaded or retained.
+//
+// The caller must hold the registry lock.
+func (r *resourceRegistry) releaseHandle(handle *resourceHandle) {
+	if handle.pinned || handle.retained {
+		return
+	}
+	if !r.policy.canRetain(isResourceSized(handle.resourceLoader)) {
+		r.evictHandle(handle)
+		return
+	}
+	if handle.promise.Ready() {
+		if _, err := handle.promise.Wait(); err != nil {
+			r.evictHandle(handle) // no point in retaining a failed resource
+			return
+		}
+	}
+	handle.retained = true
+	r.retained = append(r.retained, handle)
+	r.retainedSize += handle.size
+	r.evictRetained()
+}
+
+// updateSize records the size of a resource once it has been loaded.
+//
+// The caller must hold the registry lock.
+func (r *resourceRegistry) updateSize(handle *resourceHandle, size int64) {
+	if current, ok := r.resources[handle.path]; !ok || current != handle {
+		return // evicted in the meantime
+	}
+	if handle.retained {
+		r.retainedSize += size - handle.size
+	}
+	handle.size = size
+	if handle.retained {
+		r.evictRetained()
+	}
+}
+
+// removeRetained removes the handle from the list of retained resources.
+//
+// The caller must hold the registry lock.
+func (r *resourceRegistry) removeRetained(handle *resourceHandle) {
+	if index := slices.Index(r.retained, handle); index >= 0 {
+		r.retained = slices.Delete(r.retained, index, index+1)
+	}
+	r.retainedSize -= handle.size
+	handle.retained = false
+}
+
+// evictRetained unloads the least recently used retained resources until
+// the retention policy is satisfied. Resources that the policy does not
+// allow to be retained at all are unloaded first.
+//
+// The caller must hold the registry lock.
+func (r *resourceRegistry) evictRetained() {
+	for _, handle := range slices.Clone(r.retained) {
+		if !r.policy.canRetain(isResourceSized(handle.resourceLoader)) {
+			r.evictHandle(handle)
+		}
+	}
+	for len(r.retained) > 0 {
+		if !r.policy.isExceeded(len(r.retained), r.retainedSize) {
+			return
+		}
+		r.evictHandle(r.retained[0])
+	}
+}
+
+// evictHandle removes the handle from the registry and unloads its resource.
+//
+// The caller must hold the registry lock.
+func (r *resourceRegistry) evictHandle(handle *resourceHandle) {
+	if handle.retained {
+		r.removeRetained(handle)
+	}
+	delete(r.resources, handle.path)
 
+	path := handle.path
 	resourceLoader := handle.resourceLoader
+	dependencies := handle.dependencies
 	promise := handle.promise
 	go func() {
+		// The dependencies are released only once the resource itself has
+		// been unloaded, since it may still reference them until then.
+		defer dependencies.Delete()
+
 		resource, err := promise.Wait()
 		if err != nil {
 			return // cannot unload resource that failed to load
 		}
 		assetLoader := &AssetLoader{
 			engine:      r.engine,
-			resourceSet: resourceSet,
+			resourceSet: dependencies,
+			priority:    async.PriorityBackground,
 		}
 		if err := resourceLoader.UnloadResource(assetLoader, resource); err != nil {
 			logger.Error("Failed to unload resource", slog.String("path", path), slog.String("error", err.Error()))
 		}
 	}()
 }
+
+func (r *resourceRegistry) SubscribeReload(callback ResourceReloadCallback) *ResourceReloadSubscription {
+	return r.reloadSubscriptions.Subscribe(callback)
+}
+
+func (r *resourceRegistry) ReloadResource(path string) async.Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handle, ok := r.resources[path]
+	if !ok {
+		return async.NewFailedOperation(fmt.Errorf("resource %q is not loaded", path))
+	}
+	if handle.reload.Specified {
+		return handle.reload.Value // already in progress
+	}
+
+	operation := async.NewFuncOperation(func() error {
+		return r.reloadResource(path, handle)
+	})
+	handle.reload = opt.V(operation)
+	return operation
+}
+
+func (r *resourceRegistry) reloadResource(path string, handle *resourceHandle) error {
+	defer func() {
+		r.mu.Lock()
+		handle.reload = opt.Unspecified[async.Operation]()
+		r.mu.Unlock()
+	}()
+
+	r.mu.Lock()
+	resourceLoader := handle.resourceLoader
+	oldPromise := handle.promise
+	oldDependencies := handle.dependencies
+	r.mu.Unlock()
+
+	// Make sure that an initial load is not still in progress, otherwise
+	// the old value would not be unloaded.
+	oldResource, oldErr := oldPromise.Wait()
+
+	// The new value gets its own set of dependencies, so that they can be
+	// released independently of the ones of the old value.
+	newDependencies := newResourceSet(r.engine, r)
+	newAssetLoader := &AssetLoader{
+		engine:      r.engine,
+		resourceSet: newDependencies,
+	}
+	asset := chunked.NewAsset(r.store, path)
+	newResource, err := resourceLoader.LoadResource(newAssetLoader, asset)
+	if err != nil {
+		newDependencies.Delete()
+		return fmt.Errorf("failed to reload resource %q: %w", path, err)
+	}
+
+	var stale bool
+	swapResource := func() error {
+		r.mu.Lock()
+		if current, ok := r.resources[path]; !ok || current != handle {
+			stale = true // unloaded while we were reloading
+		} else {
+			handle.promise = async.NewDeliveredPromise(newResource)
+			handle.dependencies = newDependencies
+			// The size needs to change together with the value, otherwise
+			// an eviction in between would account for the wrong one.
+			r.updateSize(handle, resourceSize(resourceLoader, newResource))
+		}
+		r.mu.Unlock()
+
+		if !stale {
+			for callback := range r.reloadSubscriptions.CallbacksIter() {
+				callback(path, oldResource, newResource)
+			}
+		}
+		return nil
+	}
+	if err := r.engine.ScheduleMain(swapResource).Wait(); err != nil {
+		return err
+	}
+
+	if stale {
+		if err := resourceLoader.UnloadResource(newAssetLoader, newResource); err != nil {
+			logger.Error("Failed to unload stale resource", slog.String("path", path), slog.String("error", err.Error()))
+		}
+		newDependencies.Delete()
+		return fmt.Errorf("resource %q was unloaded during reload", path)
+	}
+
+	// Waiters that received the old value did so before the swap and have
+	// been notified through the reload subscriptions, while all others
+	// receive the new value (see awaitResource), so it is safe to unload.
+	defer oldDependencies.Delete()
+	if oldErr != nil {
+		return nil // cannot unload resource that failed to load
+	}
+	oldAssetLoader := &AssetLoader{
+		engine:      r.engine,
+		resourceSet: oldDependencies,
+	}
+	if err := resourceLoader.UnloadResource(oldAssetLoader, oldResource); err != nil {
+		logger.Error("Failed to unload replaced resource", slog.String("path", path), slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// isResourceSized returns whether the specified loader reports the sizes of
+// the resources that it loads.
+func isResourceSized(resourceLoader ResourceLoader[any]) bool {
+	if generic, ok := resourceLoader.(interface{ isSized() bool }); ok {
+		return generic.isSized()
+	}
+	_, ok := resourceLoader.(ResourceSizer[any])
+	return ok
+}
+
+func resourceSize(resourceLoader ResourceLoader[any], resource any) int64 {
+	if sizer, ok := resourceLoader.(ResourceSizer[any]); ok {
+		return sizer.ResourceSize(resource)
+	}
+	return 0
+}
@@ -0,0 +1,297 @@
+package game_test
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/core/resource"
+	"github.com/mokiat/lacking/game"
+	"github.com/mokiat/lacking/storage/chunked"
+)
+
+type SizedResource struct {
+	Path    string
+	Version int
+}
+
+type PlainResource struct {
+	Path    string
+	Version int
+}
+
+// TestLoader creates resources without reading the asset, counting how many
+// times each path has been loaded.
+type TestLoader[T any] struct {
+	create func(path string, version int) T
+
+	mu       sync.Mutex
+	versions map[string]int
+	unloaded []T
+}
+
+func (l *TestLoader[T]) ApplicableType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (l *TestLoader[T]) LoadResource(loader *game.AssetLoader, asset *chunked.Asset) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.versions == nil {
+		l.versions = make(map[string]int)
+	}
+	l.versions[asset.Path()]++
+	return l.create(asset.Path(), l.versions[asset.Path()]), nil
+}
+
+func (l *TestLoader[T]) UnloadResource(loader *game.AssetLoader, resource T) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unloaded = append(l.unloaded, resource)
+	return nil
+}
+
+func (l *TestLoader[T]) Unloaded() []T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.unloaded)
+}
+
+// TestSizedLoader is a TestLoader that reports the same size for all
+// resources.
+type TestSizedLoader struct {
+	TestLoader[*SizedResource]
+}
+
+func (l *TestSizedLoader) ResourceSize(resource *SizedResource) int64 {
+	return 10
+}
+
+var _ = Describe("Resource Registry", func() {
+	var (
+		engine      *game.Engine
+		sizedLoader *TestSizedLoader
+		plainLoader *TestLoader[*PlainResource]
+	)
+
+	createEngine := func(policy game.ResourceRetentionPolicy) {
+		immediate := game.WorkerFunc(func(fn func()) {
+			fn()
+		})
+		engine = game.NewEngine(
+			game.WithStore(resource.NewMemStore()),
+			game.WithIOWorker(immediate),
+			game.WithGFXWorker(immediate),
+			game.WithResourceRetention(policy),
+		)
+		engine.RegisterResourceLoader(game.GenericResourceLoader[*SizedResource](sizedLoader))
+		engine.RegisterResourceLoader(game.GenericResourceLoader(plainLoader))
+	}
+
+	fetchSized := func(set *game.ResourceSet, path string) *SizedResource {
+		var result *SizedResource
+		Expect(set.FetchResource(path, &result).Wait()).To(Succeed())
+		return result
+	}
+
+	fetchPlain := func(set *game.ResourceSet, path string) *PlainResource {
+		var result *PlainResource
+		Expect(set.FetchResource(path, &result).Wait()).To(Succeed())
+		return result
+	}
+
+	retainedPaths := func() []string {
+		var result []string
+		for _, entry := range engine.ResourceCacheEntries() {
+			if entry.Retained {
+				result = append(result, entry.Path)
+			}
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		sizedLoader = &TestSizedLoader{
+			TestLoader: TestLoader[*SizedResource]{
+				create: func(path string, version int) *SizedResource {
+					return &SizedResource{Path: path, Version: version}
+				},
+			},
+		}
+		plainLoader = &TestLoader[*PlainResource]{
+			create: func(path string, version int) *PlainResource {
+				return &PlainResource{Path: path, Version: version}
+			},
+		}
+	})
+
+	When("no retention is configured", func() {
+		BeforeEach(func() {
+			createEngine(game.ResourceRetentionPolicy{})
+		})
+
+		It("shares a resource between sets", func() {
+			first := engine.CreateResourceSet()
+			second := engine.CreateResourceSet()
+			Expect(fetchSized(first, "a")).To(BeIdenticalTo(fetchSized(second, "a")))
+			Expect(engine.ResourceCacheStats().TotalSize).To(Equal(int64(10)))
+
+			first.Delete()
+			Consistently(sizedLoader.Unloaded).Should(BeEmpty())
+			second.Delete()
+			Eventually(sizedLoader.Unloaded).Should(HaveLen(1))
+			Expect(engine.ResourceCacheEntries()).To(BeEmpty())
+		})
+
+		It("replaces the value on reload", func() {
+			set := engine.CreateResourceSet()
+			oldResource := fetchSized(set, "a")
+			Expect(oldResource.Version).To(Equal(1))
+
+			var notified []any
+			subscription := engine.SubscribeResourceReload(func(path string, oldValue, newValue any) {
+				notified = append(notified, oldValue, newValue)
+			})
+			defer subscription.Delete()
+
+			Expect(engine.ReloadResource("a").Wait()).To(Succeed())
+
+			newResource := fetchSized(engine.CreateResourceSet(), "a")
+			Expect(newResource.Version).To(Equal(2))
+			Expect(notified).To(Equal([]any{oldResource, newResource}))
+			Expect(sizedLoader.Unloaded()).To(Equal([]*SizedResource{oldResource}))
+			Expect(engine.ResourceCacheStats().TotalSize).To(Equal(int64(10)))
+		})
+
+		It("fails to reload a resource that is not loaded", func() {
+			Expect(engine.ReloadResource("a").Wait()).ToNot(Succeed())
+		})
+
+		It("keeps pinned resources until unpinned", func() {
+			set := engine.CreateResourceSet()
+			fetchSized(set, "a")
+			Expect(engine.PinResource("a")).To(Succeed())
+
+			set.Delete()
+			Consistently(sizedLoader.Unloaded).Should(BeEmpty())
+			Expect(engine.ResourceCacheStats().PinnedCount).To(Equal(1))
+
+			engine.UnpinResource("a")
+			Eventually(sizedLoader.Unloaded).Should(HaveLen(1))
+			Expect(engine.ResourceCacheEntries()).To(BeEmpty())
+		})
+
+		It("fails to pin a resource that is not loaded", func() {
+			Expect(engine.PinResource("a")).ToNot(Succeed())
+		})
+	})
+
+	When("retention is limited by count", func() {
+		BeforeEach(func() {
+			createEngine(game.ResourceRetentionPolicy{
+				MaxCount: 2,
+			})
+		})
+
+		It("evicts the least recently used resources", func() {
+			for _, path := range []string{"a", "b", "c"} {
+				set := engine.CreateResourceSet()
+				fetchSized(set, path)
+				set.Delete()
+			}
+			Expect(retainedPaths()).To(Equal([]string{"b", "c"}))
+			Eventually(sizedLoader.Unloaded).Should(HaveLen(1))
+			Expect(sizedLoader.Unloaded()[0].Path).To(Equal("a"))
+
+			// Using a retained resource again makes it the most recently
+			// used one.
+			set := engine.CreateResourceSet()
+			Expect(fetchSized(set, "b").Version).To(Equal(1))
+			set.Delete()
+
+			set = engine.CreateResourceSet()
+			fetchSized(set, "d")
+			set.Delete()
+			Expect(retainedPaths()).To(Equal([]string{"b", "d"}))
+			Eventually(sizedLoader.Unloaded).Should(HaveLen(2))
+			Expect(sizedLoader.Unloaded()[1].Path).To(Equal("c"))
+		})
+
+		It("does not retain pinned resources", func() {
+			set := engine.CreateResourceSet()
+			fetchSized(set, "a")
+			Expect(engine.PinResource("a")).To(Succeed())
+			set.Delete()
+			Expect(retainedPaths()).To(BeEmpty())
+
+			engine.UnpinResource("a")
+			Expect(retainedPaths()).To(Equal([]string{"a"}))
+		})
+
+		It("retains resources without a size", func() {
+			set := engine.CreateResourceSet()
+			fetchPlain(set, "a")
+			set.Delete()
+			Expect(retainedPaths()).To(Equal([]string{"a"}))
+		})
+
+		It("unloads retained resources when purged", func() {
+			set := engine.CreateResourceSet()
+			fetchSized(set, "a")
+			set.Delete()
+
+			engine.PurgeResourceCache()
+			Expect(retainedPaths()).To(BeEmpty())
+			Eventually(sizedLoader.Unloaded).Should(HaveLen(1))
+		})
+	})
+
+	When("retention is limited by memory", func() {
+		BeforeEach(func() {
+			createEngine(game.ResourceRetentionPolicy{
+				MemoryBudget: 25,
+			})
+		})
+
+		It("evicts the least recently used resources", func() {
+			for _, path := range []string{"a", "b", "c"} {
+				set := engine.CreateResourceSet()
+				fetchSized(set, path)
+				set.Delete()
+			}
+			Expect(retainedPaths()).To(Equal([]string{"b", "c"}))
+			Expect(engine.ResourceCacheStats().RetainedSize).To(Equal(int64(20)))
+			Eventually(sizedLoader.Unloaded).Should(HaveLen(1))
+			Expect(sizedLoader.Unloaded()[0].Path).To(Equal("a"))
+		})
+
+		It("does not retain resources without a size", func() {
+			set := engine.CreateResourceSet()
+			fetchPlain(set, "a")
+			set.Delete()
+			Expect(retainedPaths()).To(BeEmpty())
+			Eventually(plainLoader.Unloaded).Should(HaveLen(1))
+		})
+
+		It("evicts resources without a size once the count limit is removed", func() {
+			engine.SetResourceRetentionPolicy(game.ResourceRetentionPolicy{
+				MemoryBudget: 25,
+				MaxCount:     5,
+			})
+			set := engine.CreateResourceSet()
+			fetchPlain(set, "a")
+			fetchSized(set, "b")
+			set.Delete()
+			Expect(retainedPaths()).To(ConsistOf("a", "b"))
+
+			engine.SetResourceRetentionPolicy(game.ResourceRetentionPolicy{
+				MemoryBudget: 25,
+			})
+			Expect(retainedPaths()).To(Equal([]string{"b"}))
+			Eventually(plainLoader.Unloaded).Should(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package game
+
+// ResourceRetentionPolicy specifies how resources that are no longer
+// referenced by any ResourceSet are kept around in case they are needed
+// again.
+//
+// Unreferenced resources are kept in a least-recently-used order and the
+// oldest ones are unloaded once any of the limits is exceeded.
+type ResourceRetentionPolicy struct {
+
+	// MemoryBudget is the total approximate size in bytes that unreferenced
+	// resources may occupy. The size of a resource is reported by its loader
+	// through the ResourceSizer interface.
+	//
+	// Resources whose loader does not implement ResourceSizer cannot be
+	// accounted for, so they are only retained when MaxCount is specified
+	// and they count against it only.
+	//
+	// A value of zero means that unreferenced resources are unloaded
+	// immediately, unless MaxCount allows them to be retained.
+	MemoryBudget int64
+
+	// MaxCount is the maximum number of unreferenced resources that may be
+	// retained. A value of zero means that there is no limit on the count
+	// and only the MemoryBudget applies.
+	MaxCount int
+}
+
+// canRetain returns whether an unreferenced resource may be retained at all,
+// depending on whether its size is known.
+func (p ResourceRetentionPolicy) canRetain(sized bool) bool {
+	return p.MaxCount > 0 || (sized && p.MemoryBudget > 0)
+}
+
+func (p ResourceRetentionPolicy) isExceeded(count int, size int64) bool {
+	if p.MaxCount > 0 && count > p.MaxCount {
+		return true
+	}
+	return p.MemoryBudget > 0 && size > p.MemoryBudget
+}
+
+// ResourceSizer can optionally be implemented by a ResourceLoader in order
+// to report the approximate memory that a loaded resource occupies. This is
+// used by the retention policy to track the resource cache budget.
+//
+// Loaders that don't implement this interface report a size of zero and
+// their resources are retained only if the policy specifies a MaxCount.
+type ResourceSizer[T any] interface {
+
+	// ResourceSize returns the approximate size in bytes of the specified
+	// resource.
+	ResourceSize(resource T) int64
+}
+
+// ResourceCacheEntry holds information on a single resource that is tracked
+// by the engine.
+type ResourceCacheEntry struct {
+
+	// Path is the storage path of the resource.
+	Path string
+
+	// Size is the approximate size in bytes of the resource, as reported by
+	// its loader. This is zero while the resource is still loading.
+	Size int64
+
+	// RefCount is the number of active references to the resource.
+	RefCount int
+
+	// Pinned indicates whether the resource is pinned and will not be
+	// unloaded even if unreferenced.
+	Pinned bool
+
+	// Retained indicates whether the resource is unreferenced and is only
+	// kept around by the retention policy.
+	Retained bool
+}
+
+// ResourceCacheStats holds summary information about the resources that are
+// tracked by the engine.
+type ResourceCacheStats struct {
+
+	// Policy is the retention policy that is in effect.
+	Policy ResourceRetentionPolicy
+
+	// TotalCount is the number of resources that are currently loaded or
+	// loading.
+	TotalCount int
+
+	// TotalSize is the approximate size in bytes of all loaded resources.
+	TotalSize int64
+
+	// PinnedCount is the number of resources that are pinned.
+	PinnedCount int
+
+	// RetainedCount is the number of unreferenced resources that are kept
+	// by the retention policy.
+	RetainedCount int
+
+	// RetainedSize is the approximate size in bytes of all unreferenced
+	// resources that are kept by the retention policy.
+	RetainedSize int64
+}
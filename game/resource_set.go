@@ -39,8 +39,22 @@ func (s *ResourceSet) Engine() *Engine {
 // be tracked by this ResourceSet. Once the ResourceSet is deleted, all
 // tracked resources will be scheduled for deletion.
 //
+// The resource is loaded with normal priority.
+//
 // This method can be called from any thread.
 func (s *ResourceSet) FetchResource(path string, target any) async.Operation {
+	return s.FetchResourcePriority(path, async.PriorityNormal, target)
+}
+
+// FetchResourcePriority is like FetchResource but allows the priority of
+// the loading work to be specified. Critical resources will have their
+// work processed before that of background ones.
+//
+// If the resource is already being loaded, the priority of the pending
+// work is not changed.
+//
+// This method can be called from any thread.
+func (s *ResourceSet) FetchResourcePriority(path string, priority async.Priority, target any) async.Operation {
 	s.trackedResourcesMU.Lock()
 	defer s.trackedResourcesMU.Unlock()
 
@@ -49,7 +63,7 @@ func (s *ResourceSet) FetchResource(path string, target any) async.Operation {
 	}
 
 	s.trackedResources[path]++
-	return s.registry.LoadResource(s, path, target)
+	return s.registry.LoadResource(path, priority, target)
 }
 
 // Delete schedules all resources managed by this ResourceSet for deletion.
@@ -60,7 +74,7 @@ func (s *ResourceSet) Delete() {
 	defer s.trackedResourcesMU.Unlock()
 
 	for path, count := range s.trackedResources {
-		s.registry.UnloadResource(s, path, count)
+		s.registry.UnloadResource(path, count)
 	}
 	s.trackedResources = nil
 }
@@ -75,8 +75,10 @@ func newScene(engine *Engine, info SceneInfo) *Scene {
 
 	fixedTimestep := info.FixedTimestep.ValueOrDefault(16 * time.Millisecond)
 
+	// mesh binding set is needed by the animation binding for morph weights
+	meshBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewMeshBinding())
 	// source binding sets
-	armatureBindingSet := hierarchy.NewSourceBindingSet(hierarchyScene, NewAnimationBinding())
+	armatureBindingSet := hierarchy.NewSourceBindingSet(hierarchyScene, NewAnimationBinding(meshBindingSet))
 	bodyBindingSet := hierarchy.NewSourceBindingSet(hierarchyScene, NewBodyBinding())
 	// target binding sets
 	skyBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewSkyBinding())
@@ -84,7 +86,6 @@ func newScene(engine *Engine, info SceneInfo) *Scene {
 	pointLightBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewPointLightBinding())
 	spotLightBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewSpotLightBinding())
 	directionalLightBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewDirectionalLightBinding())
-	meshBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewMeshBinding())
 	boneBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewBoneBinding())
 	cameraBindingSet := hierarchy.NewInterpolationBindingSet(hierarchyScene, NewCameraBinding())
 
@@ -11,7 +11,12 @@ type SkyInfo struct {
 }
 
 // PlaceSky places a sky on the provided node using the provided definition.
+//
+// Returns nil if this scene does not have Graphics enabled.
 func (s *Scene) PlaceSky(node hierarchy.NodeID, info SkyInfo) *graphics.Sky {
+	if s.gfxScene == nil {
+		return nil
+	}
 	sky := s.gfxScene.CreateSky(graphics.SkyInfo{
 		Definition: info.Definition,
 	})
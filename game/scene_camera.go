@@ -0,0 +1,54 @@
+package game
+
+import (
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// CameraInfo contains the information required to create a camera.
+type CameraInfo struct {
+	FoVMode            opt.T[graphics.FoVMode]
+	FoV                opt.T[sprec.Angle]
+	AspectRatio        opt.T[float32]
+	OrthographicHeight opt.T[float32]
+	Near               opt.T[float32]
+	Far                opt.T[float32]
+}
+
+// PlaceCamera places a camera on the provided node.
+//
+// Unlike graphics.Scene.CreateCamera, the new camera does not become the
+// active camera of the scene, even if there is no active camera yet.
+//
+// Returns nil if this scene does not have Graphics enabled.
+func (s *Scene) PlaceCamera(nodeID hierarchy.NodeID, info CameraInfo) *graphics.Camera {
+	if s.gfxScene == nil {
+		return nil
+	}
+	activeCamera := s.gfxScene.ActiveCamera()
+	camera := s.gfxScene.CreateCamera()
+	s.gfxScene.SetActiveCamera(activeCamera)
+
+	if info.FoVMode.Specified {
+		camera.SetFoVMode(info.FoVMode.Value)
+	}
+	if info.FoV.Specified {
+		camera.SetFoV(info.FoV.Value)
+	}
+	if info.AspectRatio.Specified {
+		camera.SetAspectRatio(info.AspectRatio.Value)
+	}
+	if info.OrthographicHeight.Specified {
+		camera.SetOrthographicHeight(info.OrthographicHeight.Value)
+	}
+	if info.Near.Specified {
+		camera.SetNear(info.Near.Value)
+	}
+	if info.Far.Specified {
+		camera.SetFar(info.Far.Value)
+	}
+	s.cameraBindingSet.Bind(nodeID, camera)
+	return camera
+}
@@ -13,6 +13,7 @@ import (
 type AmbientLightInfo struct {
 	ReflectionTexture render.Texture
 	RefractionTexture render.Texture
+	IrradianceSH9     opt.T[[9]dprec.Vec3]
 	OuterRadius       opt.T[float64]
 	InnerRadius       opt.T[float64]
 	CastShadow        opt.T[bool]
@@ -27,13 +28,24 @@ func (s *Scene) CreateAmbientLight(info AmbientLightInfo) hierarchy.NodeID {
 }
 
 // PlaceAmbientLight places an ambient light on the provided node.
+//
+// Returns nil if this scene does not have Graphics enabled.
 func (s *Scene) PlaceAmbientLight(nodeID hierarchy.NodeID, info AmbientLightInfo) *graphics.AmbientLight {
+	if s.gfxScene == nil {
+		return nil
+	}
+	mode := graphics.AmbientLightModeTexture
+	if info.IrradianceSH9.Specified {
+		mode = graphics.AmbientLightModeSH9
+	}
 	light := s.gfxScene.CreateAmbientLight(graphics.AmbientLightInfo{
 		Position:          dprec.ZeroVec3(),
 		InnerRadius:       25000.0,
 		OuterRadius:       25000.0,
 		ReflectionTexture: info.ReflectionTexture,
 		RefractionTexture: info.RefractionTexture,
+		Mode:              mode,
+		IrradianceSH9:     info.IrradianceSH9.Value,
 		CastShadow:        info.CastShadow.ValueOrDefault(false),
 	})
 	s.ambientLightBindingSet.Bind(nodeID, light)
@@ -56,7 +68,12 @@ func (s *Scene) CreatePointLight(info PointLightInfo) hierarchy.NodeID {
 }
 
 // PlacePointLight places a point light on the provided node.
+//
+// Returns nil if this scene does not have Graphics enabled.
 func (s *Scene) PlacePointLight(nodeID hierarchy.NodeID, info PointLightInfo) *graphics.PointLight {
+	if s.gfxScene == nil {
+		return nil
+	}
 	light := s.gfxScene.CreatePointLight(graphics.PointLightInfo{
 		Position:   dprec.ZeroVec3(),
 		EmitColor:  info.EmitColor.ValueOrDefault(dprec.NewVec3(10.0, 0.0, 10.0)),
@@ -85,7 +102,12 @@ func (s *Scene) CreateSpotLight(info SpotLightInfo) hierarchy.NodeID {
 }
 
 // PlaceSpotLight places a spot light on the provided node.
+//
+// Returns nil if this scene does not have Graphics enabled.
 func (s *Scene) PlaceSpotLight(nodeID hierarchy.NodeID, info SpotLightInfo) *graphics.SpotLight {
+	if s.gfxScene == nil {
+		return nil
+	}
 	light := s.gfxScene.CreateSpotLight(graphics.SpotLightInfo{
 		Position:           dprec.ZeroVec3(),
 		Rotation:           dprec.IdentityQuat(),
@@ -115,7 +137,12 @@ func (s *Scene) CreateDirectionalLight(info DirectionalLightInfo) hierarchy.Node
 }
 
 // PlaceDirectionalLight places a directional light on the provided node.
+//
+// Returns nil if this scene does not have Graphics enabled.
 func (s *Scene) PlaceDirectionalLight(nodeID hierarchy.NodeID, info DirectionalLightInfo) *graphics.DirectionalLight {
+	if s.gfxScene == nil {
+		return nil
+	}
 	light := s.gfxScene.CreateDirectionalLight(graphics.DirectionalLightInfo{
 		Position:   dprec.ZeroVec3(),
 		Rotation:   dprec.IdentityQuat(),
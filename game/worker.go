@@ -1,5 +1,7 @@
 package game
 
+import "github.com/mokiat/lacking/util/async"
+
 type Worker interface {
 	Schedule(fn func())
 }
@@ -9,3 +11,25 @@ type WorkerFunc func(fn func())
 func (f WorkerFunc) Schedule(fn func()) {
 	f(fn)
 }
+
+// PriorityWorker is a Worker that is able to order scheduled functions
+// by priority.
+//
+// Workers that don't implement this interface process all functions with
+// the same priority.
+type PriorityWorker interface {
+	Worker
+
+	// SchedulePriority queues a function with the specified priority.
+	SchedulePriority(priority async.Priority, fn func())
+}
+
+var _ PriorityWorker = (*async.Worker)(nil)
+
+func schedulePriority(worker Worker, priority async.Priority, fn func()) {
+	if priorityWorker, ok := worker.(PriorityWorker); ok {
+		priorityWorker.SchedulePriority(priority, fn)
+	} else {
+		worker.Schedule(fn)
+	}
+}
@@ -14,6 +14,13 @@ type Limits interface {
 	// UniformBufferOffsetAlignment returns the alignment requirement
 	// for uniform buffer offsets.
 	UniformBufferOffsetAlignment() int
+
+	// SupportsDataFormat returns whether textures can be created with the
+	// specified data format.
+	//
+	// Uncompressed formats are always supported, whereas block-compressed
+	// formats depend on the capabilities of the underlying hardware.
+	SupportsDataFormat(format DataFormat) bool
 }
 
 // Quality is an enumeration of the supported render quality levels.
@@ -36,6 +36,26 @@ const (
 
 	// DataFormatRGBA32F indicates that the format is RGBA32F.
 	DataFormatRGBA32F
+
+	// DataFormatBC1 indicates that the format is the BC1 block-compressed
+	// RGB format.
+	DataFormatBC1
+
+	// DataFormatBC3 indicates that the format is the BC3 block-compressed
+	// RGBA format.
+	DataFormatBC3
+
+	// DataFormatBC4 indicates that the format is the BC4 block-compressed
+	// single-channel format.
+	DataFormatBC4
+
+	// DataFormatBC5 indicates that the format is the BC5 block-compressed
+	// two-channel format.
+	DataFormatBC5
+
+	// DataFormatBC7 indicates that the format is the BC7 block-compressed
+	// RGBA format.
+	DataFormatBC7
 )
 
 // DataFormat describes the format of the data that is stored in a
@@ -53,11 +73,32 @@ func (f DataFormat) String() string {
 		return "RGBA16F"
 	case DataFormatRGBA32F:
 		return "RGBA32F"
+	case DataFormatBC1:
+		return "BC1"
+	case DataFormatBC3:
+		return "BC3"
+	case DataFormatBC4:
+		return "BC4"
+	case DataFormatBC5:
+		return "BC5"
+	case DataFormatBC7:
+		return "BC7"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// IsBlockCompressed returns whether the DataFormat stores the data in
+// compressed blocks of 4x4 texels.
+func (f DataFormat) IsBlockCompressed() bool {
+	switch f {
+	case DataFormatBC1, DataFormatBC3, DataFormatBC4, DataFormatBC5, DataFormatBC7:
+		return true
+	default:
+		return false
+	}
+}
+
 // ColorTexture2DInfo represents the information needed to create a
 // 2D color Texture.
 type ColorTexture2DInfo struct {
@@ -67,6 +108,9 @@ type ColorTexture2DInfo struct {
 	Label string
 
 	// GenerateMipmaps specifies whether mipmaps should be generated.
+	//
+	// Mipmaps cannot be generated for block-compressed formats, in which
+	// case all layers should be provided through MipmapLayers.
 	GenerateMipmaps bool
 
 	// GammaCorrection specifies whether gamma correction should be performed
@@ -105,6 +149,9 @@ type ColorTextureCubeInfo struct {
 	Label string
 
 	// GenerateMipmaps specifies whether mipmaps should be generated.
+	//
+	// Mipmaps cannot be generated for block-compressed formats, in which
+	// case all layers should be provided through MipmapLayers.
 	GenerateMipmaps bool
 
 	// GammaCorrection specifies whether gamma correction should be performed
@@ -2,6 +2,7 @@ package chunked
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/mokiat/gblob"
 	"github.com/mokiat/lacking/core/resource"
@@ -31,10 +32,7 @@ func (a *Asset) Read(target any) error {
 	}
 	defer in.Close()
 
-	dec := decoder{
-		in: gblob.NewLittleEndianPackedDecoder(in),
-	}
-	if err := dec.Decode(target); err != nil {
+	if err := Decode(in, target); err != nil {
 		return fmt.Errorf("error decoding asset: %w", err)
 	}
 
@@ -57,3 +55,13 @@ func (a *Asset) Write(source any) error {
 
 	return nil
 }
+
+// Decode reads chunked data from the specified reader into the target.
+//
+// This is useful when the data does not come from a resource.Store.
+func Decode(in io.Reader, target any) error {
+	dec := decoder{
+		in: gblob.NewLittleEndianPackedDecoder(in),
+	}
+	return dec.Decode(target)
+}
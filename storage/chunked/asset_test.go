@@ -167,4 +167,21 @@ var _ = Describe("Asset", func() {
 		Expect(output.ID).To(Equal(&IDChunk{Name: "test"}))
 		Expect(output.Location).To(Equal(&LocationChunk{X: 1, Y: 2}))
 	})
+
+	It("is possible to decode an asset from a reader", func() {
+		output := PrimaryModel{
+			ID:       &IDChunk{Name: "test"},
+			Location: &LocationChunk{X: 1, Y: 2},
+		}
+		Expect(asset.Write(output)).To(Succeed())
+
+		in, err := store.Open("example.dat")
+		Expect(err).ToNot(HaveOccurred())
+		defer in.Close()
+
+		var input PrimaryModel
+		Expect(chunked.Decode(in, &input)).To(Succeed())
+		Expect(input.ID).To(Equal(&IDChunk{Name: "test"}))
+		Expect(input.Location).To(Equal(&LocationChunk{X: 1, Y: 2}))
+	})
 })
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/mokiat/gomath/sprec"
+)
+
+// AtlasInfo contains the information needed to create an Atlas.
+type AtlasInfo struct {
+
+	// Pages are the images that hold the packed images. The rows of the
+	// pages are expected to go from the bottom to the top.
+	Pages []image.Image
+
+	// Regions specify the locations of the packed images.
+	Regions []AtlasRegionInfo
+}
+
+// AtlasRegionInfo contains the information needed to locate an image
+// within an Atlas.
+type AtlasRegionInfo struct {
+
+	// Name is the name of the image.
+	Name string
+
+	// Page is the index of the page that holds the image.
+	Page int
+
+	// Size is the size of the image in pixels.
+	Size Size
+
+	// MinUV and MaxUV are the tex coords of the bottom-left and top-right
+	// corners of the image within the page.
+	MinUV sprec.Vec2
+	MaxUV sprec.Vec2
+}
+
+func newAtlas(pages []*Image, regions []AtlasRegion) *Atlas {
+	return &Atlas{
+		pages:   pages,
+		regions: regions,
+	}
+}
+
+// Atlas represents a set of named images that have been packed into a
+// number of pages.
+type Atlas struct {
+	pages   []*Image
+	regions []AtlasRegion
+}
+
+// Regions returns the locations of all images in this Atlas.
+func (a *Atlas) Regions() []AtlasRegion {
+	return a.regions
+}
+
+// Region returns the location of the image with the specified name.
+func (a *Atlas) Region(name string) (AtlasRegion, bool) {
+	for _, region := range a.regions {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return AtlasRegion{}, false
+}
+
+// Destroy releases all resources allocated for this
+// atlas.
+func (a *Atlas) Destroy() {
+	for _, page := range a.pages {
+		page.Destroy()
+	}
+	a.pages = nil
+}
+
+// AtlasRegion represents the location of an image within an Atlas.
+type AtlasRegion struct {
+
+	// Name is the name of the image.
+	Name string
+
+	// Page is the Image that holds the image.
+	Page *Image
+
+	// Size is the size of the image in pixels.
+	Size Size
+
+	// MinUV and MaxUV are the tex coords of the bottom-left and top-right
+	// corners of the image within the page.
+	MinUV sprec.Vec2
+	MaxUV sprec.Vec2
+}
+
+// Fill returns a Fill that stretches the image over the specified bounds
+// and multiplies it by the specified color.
+func (r AtlasRegion) Fill(bounds DrawBounds, color Color) Fill {
+	// The rows of atlas pages go from the bottom to the top, so the top of
+	// the bounds corresponds to the maximum V coordinate.
+	imageSize := sprec.NewVec2(
+		bounds.Size.X/(r.MaxUV.X-r.MinUV.X),
+		-bounds.Size.Y/(r.MaxUV.Y-r.MinUV.Y),
+	)
+	return Fill{
+		Color: color,
+		Image: r.Page,
+		ImageOffset: sprec.NewVec2(
+			bounds.Position.X-r.MinUV.X*imageSize.X,
+			bounds.Position.Y-r.MaxUV.Y*imageSize.Y,
+		),
+		ImageSize: imageSize,
+	}
+}
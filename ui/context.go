@@ -17,6 +17,8 @@ func newContext(parent *Context, window *Window, resMan *resourceManager) *Conte
 		adhocImages: nil,
 		namedImages: make(map[string]*Image),
 
+		adhocAtlases: nil,
+
 		adhocFonts: nil,
 		namedFonts: make(map[string]*Font),
 
@@ -36,6 +38,8 @@ type Context struct {
 	adhocImages []*Image
 	namedImages map[string]*Image
 
+	adhocAtlases []*Atlas
+
 	adhocFonts []*Font
 	namedFonts map[string]*Font
 
@@ -102,6 +106,20 @@ func (c *Context) OpenImage(uri string) (*Image, error) {
 	return result, nil
 }
 
+// CreateAtlas creates a new Atlas resource from the specified pages and
+// regions.
+//
+// The Atlas will be destroyed once this Context is destroyed.
+func (c *Context) CreateAtlas(info AtlasInfo) (*Atlas, error) {
+	logger.Debug("Creating ad-hoc atlas")
+	result, err := c.resMan.CreateAtlas(info)
+	if err != nil {
+		return nil, err
+	}
+	c.adhocAtlases = append(c.adhocAtlases, result)
+	return result, nil
+}
+
 // CreateFont creates a new Font resource.
 //
 // The Font will be destroyed once this Context is destroyed.
@@ -239,6 +257,12 @@ func (c *Context) Destroy() {
 	}
 	c.namedImages = make(map[string]*Image)
 
+	for _, atlas := range c.adhocAtlases {
+		logger.Debug("Destroying ad-hoc atlas")
+		atlas.Destroy()
+	}
+	c.adhocAtlases = nil
+
 	for _, font := range c.adhocFonts {
 		logger.Debug("Destroying ad-hoc font")
 		font.Destroy()
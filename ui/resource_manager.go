@@ -48,6 +48,29 @@ func (m *resourceManager) OpenImage(uri string) (*Image, error) {
 	return m.CreateImage(img), nil
 }
 
+func (m *resourceManager) CreateAtlas(info AtlasInfo) (*Atlas, error) {
+	for _, region := range info.Regions {
+		if region.Page < 0 || region.Page >= len(info.Pages) {
+			return nil, fmt.Errorf("atlas region %q references missing page %d", region.Name, region.Page)
+		}
+	}
+	pages := make([]*Image, len(info.Pages))
+	for i, page := range info.Pages {
+		pages[i] = m.imgFact.CreateImage(page)
+	}
+	regions := make([]AtlasRegion, len(info.Regions))
+	for i, region := range info.Regions {
+		regions[i] = AtlasRegion{
+			Name:  region.Name,
+			Page:  pages[region.Page],
+			Size:  region.Size,
+			MinUV: region.MinUV,
+			MaxUV: region.MaxUV,
+		}
+	}
+	return newAtlas(pages, regions), nil
+}
+
 func (m *resourceManager) CreateFont(otFont *opentype.Font) (*Font, error) {
 	return m.fntFact.CreateFont(otFont)
 }
@@ -1,36 +1,131 @@
 package async
 
 import (
+	"container/heap"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
 type Func = func()
 
+const (
+	// PriorityBackground is intended for work that is not immediately needed,
+	// like prefetching of content.
+	PriorityBackground Priority = -1
+
+	// PriorityNormal is the default priority of scheduled work.
+	PriorityNormal Priority = 0
+
+	// PriorityCritical is intended for work that is needed as soon as
+	// possible.
+	PriorityCritical Priority = 1
+)
+
+// Priority determines the order in which scheduled work is processed. Work
+// with higher priority is processed first.
+type Priority int
+
+// DefaultAgingInterval is the default amount of time that a scheduled task
+// needs to wait in order for its effective priority to be raised by one
+// level.
+//
+// The interval is intentionally long, so that aging only prevents tasks
+// from starving under sustained load and does not let a prefetch overtake
+// work that was just requested with a higher priority.
+const DefaultAgingInterval = 30 * time.Second
+
 func NewWorker(capacity int) *Worker {
-	return &Worker{
-		tasks:   make(chan Func, capacity),
-		running: 1,
+	result := &Worker{
+		capacity:      max(capacity, 1),
+		agingInterval: DefaultAgingInterval,
+		epoch:         time.Now(),
+		running:       true,
 	}
+	result.hasTasks = sync.NewCond(&result.mu)
+	result.hasSpace = sync.NewCond(&result.mu)
+	return result
 }
 
+// Worker is a queue of tasks that are processed by whoever calls one of the
+// Process methods.
+//
+// Tasks are processed in order of priority and tasks with equal priority are
+// processed in the order in which they were scheduled. To prevent starvation
+// of low priority tasks, the effective priority of a task increases the
+// longer it waits (see SetAgingInterval).
 type Worker struct {
-	tasks     chan Func
-	running   int32
-	pipelines sync.WaitGroup
+	mu       sync.Mutex
+	hasTasks *sync.Cond
+	hasSpace *sync.Cond
+
+	capacity      int
+	agingInterval time.Duration
+	epoch         time.Time
+	sequence      uint64
+	tasks         workerTaskHeap
+	running       bool
+	pipelines     sync.WaitGroup
+}
+
+// AgingInterval returns the amount of time that a task needs to wait in order
+// for its effective priority to be raised by one level.
+func (w *Worker) AgingInterval() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.agingInterval
+}
+
+// SetAgingInterval changes the amount of time that a task needs to wait in
+// order for its effective priority to be raised by one level. A value of
+// zero disables aging.
+func (w *Worker) SetAgingInterval(interval time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.agingInterval = interval
+	for i := range w.tasks {
+		w.tasks[i].rank = taskRank(w.tasks[i].priority, w.tasks[i].scheduled, interval)
+	}
+	heap.Init(&w.tasks)
 }
 
+// Schedule queues the specified function with normal priority.
+//
+// If the worker is at capacity, this call blocks until there is space.
 func (w *Worker) Schedule(fn Func) {
-	w.tasks <- fn
+	w.SchedulePriority(PriorityNormal, fn)
+}
+
+// SchedulePriority queues the specified function with the specified
+// priority.
+//
+// If the worker is at capacity, this call blocks until there is space.
+func (w *Worker) SchedulePriority(priority Priority, fn Func) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for w.running && len(w.tasks) >= w.capacity {
+		w.hasSpace.Wait()
+	}
+	if !w.running {
+		panic("async: scheduling on a worker that has been shut down")
+	}
+	scheduled := time.Since(w.epoch)
+	heap.Push(&w.tasks, workerTask{
+		fn:        fn,
+		priority:  priority,
+		scheduled: scheduled,
+		sequence:  w.sequence,
+		rank:      taskRank(priority, scheduled, w.agingInterval),
+	})
+	w.sequence++
+	w.hasTasks.Signal()
 }
 
 func (w *Worker) ProcessCount(count int) bool {
-	w.pipelines.Add(1)
-	defer w.pipelines.Done()
-	if atomic.LoadInt32(&w.running) == 0 {
+	if !w.beginPipeline() {
 		return false
 	}
+	defer w.pipelines.Done()
 
 	for count > 0 {
 		if !w.processNextTask() {
@@ -42,11 +137,10 @@ func (w *Worker) ProcessCount(count int) bool {
 }
 
 func (w *Worker) ProcessDuration(targetDuration time.Duration) bool {
-	w.pipelines.Add(1)
-	defer w.pipelines.Done()
-	if atomic.LoadInt32(&w.running) == 0 {
+	if !w.beginPipeline() {
 		return false
 	}
+	defer w.pipelines.Done()
 
 	startTime := time.Now()
 	for time.Since(startTime) < targetDuration {
@@ -57,36 +151,136 @@ func (w *Worker) ProcessDuration(targetDuration time.Duration) bool {
 	return true
 }
 
+// ProcessAll processes tasks as they are scheduled until the worker is shut
+// down. It is safe to call this method from multiple goroutines, in which
+// case tasks are processed concurrently.
 func (w *Worker) ProcessAll() {
-	w.pipelines.Add(1)
-	defer w.pipelines.Done()
-	if atomic.LoadInt32(&w.running) == 0 {
+	if !w.beginPipeline() {
 		return
 	}
+	defer w.pipelines.Done()
 
-	for task := range w.tasks {
+	for {
+		task, ok := w.waitNextTask()
+		if !ok {
+			return
+		}
 		task()
 	}
 }
 
 func (w *Worker) Shutdown() {
-	atomic.StoreInt32(&w.running, 0)
-	close(w.tasks)
+	w.mu.Lock()
+	w.running = false
+	w.hasTasks.Broadcast()
+	w.hasSpace.Broadcast()
+	w.mu.Unlock()
+
 	w.pipelines.Wait()
-	for task := range w.tasks {
+	for {
+		task, ok := w.popNextTask()
+		if !ok {
+			return
+		}
 		task()
 	}
 }
 
-func (w *Worker) processNextTask() bool {
-	select {
-	case task, ok := <-w.tasks:
-		if ok {
-			task()
-			return true
-		}
-		return false
-	default:
+func (w *Worker) beginPipeline() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.running {
 		return false
 	}
+	w.pipelines.Add(1)
+	return true
+}
+
+func (w *Worker) processNextTask() bool {
+	task, ok := w.popNextTask()
+	if ok {
+		task()
+	}
+	return ok
+}
+
+func (w *Worker) popNextTask() (Func, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.takeTask()
+}
+
+func (w *Worker) waitNextTask() (Func, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.running && len(w.tasks) == 0 {
+		w.hasTasks.Wait()
+	}
+	return w.takeTask()
+}
+
+// takeTask removes the task with the highest effective priority from the
+// queue. The caller must hold the lock.
+func (w *Worker) takeTask() (Func, bool) {
+	if len(w.tasks) == 0 {
+		return nil, false
+	}
+	task := heap.Pop(&w.tasks).(workerTask)
+	w.hasSpace.Signal()
+	return task.fn, true
+}
+
+type workerTask struct {
+	fn        Func
+	priority  Priority
+	scheduled time.Duration // since the worker epoch
+	sequence  uint64
+	rank      int64
+}
+
+// taskRank returns a value that orders tasks by their effective priority.
+//
+// The effective priority of a task is its priority plus the time it has
+// waited divided by the aging interval. Since all tasks age at the same
+// rate, scaling by the interval and subtracting the time at which the task
+// was scheduled, instead of adding the time it has waited, produces the same
+// order without depending on the current time.
+func taskRank(priority Priority, scheduled, agingInterval time.Duration) int64 {
+	if agingInterval <= 0 {
+		return int64(priority)
+	}
+	return int64(priority)*int64(agingInterval) - int64(scheduled)
+}
+
+// workerTaskHeap implements heap.Interface, keeping the task with the
+// highest rank at the top. Tasks of equal rank are kept in the order in
+// which they were scheduled.
+type workerTaskHeap []workerTask
+
+func (h workerTaskHeap) Len() int {
+	return len(h)
+}
+
+func (h workerTaskHeap) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank > h[j].rank
+	}
+	return h[i].sequence < h[j].sequence
+}
+
+func (h workerTaskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *workerTaskHeap) Push(x any) {
+	*h = append(*h, x.(workerTask))
+}
+
+func (h *workerTaskHeap) Pop() any {
+	old := *h
+	last := len(old) - 1
+	task := old[last]
+	old[last] = workerTask{} // release the function for garbage collection
+	*h = old[:last]
+	return task
 }
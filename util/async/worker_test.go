@@ -0,0 +1,148 @@
+package async_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/util/async"
+)
+
+var _ = Describe("Worker", func() {
+	var (
+		worker *async.Worker
+		order  []string
+	)
+
+	record := func(name string) async.Func {
+		return func() {
+			order = append(order, name)
+		}
+	}
+
+	BeforeEach(func() {
+		worker = async.NewWorker(16)
+		order = nil
+	})
+
+	AfterEach(func() {
+		worker.Shutdown()
+	})
+
+	Describe("ordering", func() {
+		It("processes tasks of equal priority in scheduling order", func() {
+			worker.Schedule(record("first"))
+			worker.Schedule(record("second"))
+			worker.Schedule(record("third"))
+
+			Expect(worker.ProcessCount(3)).To(BeTrue())
+			Expect(order).To(Equal([]string{"first", "second", "third"}))
+		})
+
+		It("processes tasks with higher priority first", func() {
+			worker.SchedulePriority(async.PriorityBackground, record("background"))
+			worker.SchedulePriority(async.PriorityNormal, record("normal"))
+			worker.SchedulePriority(async.PriorityCritical, record("critical"))
+			worker.SchedulePriority(async.PriorityNormal, record("normal-late"))
+
+			Expect(worker.ProcessCount(4)).To(BeTrue())
+			Expect(order).To(Equal([]string{"critical", "normal", "normal-late", "background"}))
+		})
+
+		It("reports when there are no more tasks", func() {
+			worker.Schedule(record("only"))
+
+			Expect(worker.ProcessCount(2)).To(BeFalse())
+			Expect(order).To(Equal([]string{"only"}))
+		})
+
+		It("processes the remaining tasks on shutdown", func() {
+			worker.SchedulePriority(async.PriorityBackground, record("background"))
+			worker.SchedulePriority(async.PriorityCritical, record("critical"))
+
+			worker.Shutdown()
+			Expect(order).To(Equal([]string{"critical", "background"}))
+		})
+	})
+
+	Describe("aging", func() {
+		It("does not let a recent background task overtake a critical one", func() {
+			Expect(worker.AgingInterval()).To(Equal(async.DefaultAgingInterval))
+
+			worker.SchedulePriority(async.PriorityBackground, record("background"))
+			time.Sleep(20 * time.Millisecond)
+			worker.SchedulePriority(async.PriorityCritical, record("critical"))
+
+			Expect(worker.ProcessCount(2)).To(BeTrue())
+			Expect(order).To(Equal([]string{"critical", "background"}))
+		})
+
+		It("raises the priority of tasks that have waited long enough", func() {
+			worker.SetAgingInterval(10 * time.Millisecond)
+
+			worker.SchedulePriority(async.PriorityBackground, record("background"))
+			time.Sleep(50 * time.Millisecond)
+			worker.SchedulePriority(async.PriorityCritical, record("critical"))
+
+			Expect(worker.ProcessCount(2)).To(BeTrue())
+			Expect(order).To(Equal([]string{"background", "critical"}))
+		})
+
+		It("applies a changed interval to tasks that are already queued", func() {
+			worker.SetAgingInterval(time.Hour)
+
+			worker.SchedulePriority(async.PriorityBackground, record("background"))
+			time.Sleep(50 * time.Millisecond)
+			worker.SchedulePriority(async.PriorityNormal, record("normal"))
+
+			worker.SetAgingInterval(10 * time.Millisecond)
+			Expect(worker.ProcessCount(2)).To(BeTrue())
+			Expect(order).To(Equal([]string{"background", "normal"}))
+		})
+
+		It("can be disabled", func() {
+			worker.SetAgingInterval(0)
+
+			worker.SchedulePriority(async.PriorityBackground, record("background"))
+			time.Sleep(20 * time.Millisecond)
+			worker.SchedulePriority(async.PriorityNormal, record("normal"))
+
+			Expect(worker.ProcessCount(2)).To(BeTrue())
+			Expect(order).To(Equal([]string{"normal", "background"}))
+		})
+	})
+
+	Describe("ProcessDuration", func() {
+		slowTask := func(name string) async.Func {
+			return func() {
+				time.Sleep(20 * time.Millisecond)
+				order = append(order, name)
+			}
+		}
+
+		It("stops processing once the duration has elapsed", func() {
+			for _, name := range []string{"a", "b", "c", "d", "e"} {
+				worker.Schedule(slowTask(name))
+			}
+
+			Expect(worker.ProcessDuration(30 * time.Millisecond)).To(BeTrue())
+			Expect(order).To(Equal([]string{"a", "b"}))
+
+			Expect(worker.ProcessCount(3)).To(BeTrue())
+			Expect(order).To(Equal([]string{"a", "b", "c", "d", "e"}))
+		})
+
+		It("reports when it runs out of tasks", func() {
+			worker.Schedule(record("only"))
+
+			Expect(worker.ProcessDuration(time.Second)).To(BeFalse())
+			Expect(order).To(Equal([]string{"only"}))
+		})
+
+		It("does nothing after shutdown", func() {
+			worker.Shutdown()
+			Expect(worker.ProcessDuration(time.Second)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,130 @@
+package bcn
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// bc1Factors holds the interpolation factor between the two endpoints of a
+// color block for each index in four-color mode.
+var bc1Factors = [4]float64{0.0, 1.0, 1.0 / 3.0, 2.0 / 3.0}
+
+type bc1Encoding struct {
+	color0  uint16
+	color1  uint16
+	indices [16]uint8
+	cost    int
+}
+
+func encodeBC1Block(dst []byte, block *pixelBlock) {
+	low, high := principalEndpoints(block, 3)
+	best := encodeBC1Endpoints(block, high, low)
+
+	var factors [16]float64
+	for i, index := range best.indices {
+		factors[i] = bc1Factors[index]
+	}
+	if first, second, ok := leastSquaresEndpoints(block, 3, &factors); ok {
+		if candidate := encodeBC1Endpoints(block, first, second); candidate.cost < best.cost {
+			best = candidate
+		}
+	}
+
+	var indices uint32
+	for i, index := range best.indices {
+		indices |= uint32(index) << (2 * i)
+	}
+	binary.LittleEndian.PutUint16(dst[0:2], best.color0)
+	binary.LittleEndian.PutUint16(dst[2:4], best.color1)
+	binary.LittleEndian.PutUint32(dst[4:8], indices)
+}
+
+// encodeBC1Endpoints quantizes the endpoints and picks the closest palette
+// entry for each pixel. The endpoints are ordered so that the block is
+// decoded in four-color mode.
+func encodeBC1Endpoints(block *pixelBlock, first, second [4]float64) bc1Encoding {
+	color0 := packRGB565(first)
+	color1 := packRGB565(second)
+	if color0 < color1 {
+		color0, color1 = color1, color0
+	}
+	result := bc1Encoding{
+		color0: color0,
+		color1: color1,
+	}
+	palette := bc1Palette(color0, color1, true)
+	paletteSize := len(palette)
+	if color0 == color1 {
+		paletteSize = 1
+	}
+	for i, pixel := range block {
+		bestIndex, bestCost := 0, math.MaxInt
+		for index := range paletteSize {
+			entry := palette[index]
+			cost := sqrDiff(pixel[0], entry[0]) + sqrDiff(pixel[1], entry[1]) + sqrDiff(pixel[2], entry[2])
+			if cost < bestCost {
+				bestIndex, bestCost = index, cost
+			}
+		}
+		result.indices[i] = uint8(bestIndex)
+		result.cost += bestCost
+	}
+	return result
+}
+
+func decodeBC1Block(block *pixelBlock, src []byte) {
+	decodeColorBlock(block, src, false)
+}
+
+// decodeColorBlock decodes a BC1 color block. When fourColor is false, the
+// mode is determined by the order of the endpoints, as is the case for BC1.
+func decodeColorBlock(block *pixelBlock, src []byte, fourColor bool) {
+	color0 := binary.LittleEndian.Uint16(src[0:2])
+	color1 := binary.LittleEndian.Uint16(src[2:4])
+	indices := binary.LittleEndian.Uint32(src[4:8])
+	palette := bc1Palette(color0, color1, fourColor)
+	for i := range block {
+		block[i] = palette[(indices>>(2*i))&0x03]
+	}
+}
+
+func bc1Palette(color0, color1 uint16, fourColor bool) [4][4]uint8 {
+	var palette [4][4]uint8
+	palette[0] = unpackRGB565(color0)
+	palette[1] = unpackRGB565(color1)
+	if fourColor || color0 > color1 {
+		for c := range 3 {
+			first, second := int(palette[0][c]), int(palette[1][c])
+			palette[2][c] = uint8((2*first + second + 1) / 3)
+			palette[3][c] = uint8((first + 2*second + 1) / 3)
+		}
+		palette[2][3] = 255
+		palette[3][3] = 255
+	} else {
+		for c := range 3 {
+			palette[2][c] = uint8((int(palette[0][c]) + int(palette[1][c]) + 1) / 2)
+		}
+		palette[2][3] = 255
+		palette[3] = [4]uint8{0, 0, 0, 0}
+	}
+	return palette
+}
+
+func packRGB565(color [4]float64) uint16 {
+	r := roundClamp(color[0]*31.0/255.0, 31)
+	g := roundClamp(color[1]*63.0/255.0, 63)
+	b := roundClamp(color[2]*31.0/255.0, 31)
+	return uint16(r<<11 | g<<5 | b)
+}
+
+func unpackRGB565(color uint16) [4]uint8 {
+	r := uint8(color>>11) & 0x1F
+	g := uint8(color>>5) & 0x3F
+	b := uint8(color) & 0x1F
+	return [4]uint8{
+		r<<3 | r>>2,
+		g<<2 | g>>4,
+		b<<3 | b>>2,
+		255,
+	}
+}
@@ -0,0 +1,96 @@
+package bcn
+
+import "math"
+
+func encodeBC3Block(dst []byte, block *pixelBlock) {
+	encodeChannelBlock(dst[0:8], block, 3)
+	encodeBC1Block(dst[8:16], block)
+}
+
+func decodeBC3Block(block *pixelBlock, src []byte) {
+	decodeColorBlock(block, src[8:16], true)
+	decodeChannelBlock(block, src[0:8], 3)
+}
+
+func encodeBC4Block(dst []byte, block *pixelBlock) {
+	encodeChannelBlock(dst, block, 0)
+}
+
+func decodeBC4Block(block *pixelBlock, src []byte) {
+	for i := range block {
+		block[i] = [4]uint8{0, 0, 0, 255}
+	}
+	decodeChannelBlock(block, src, 0)
+}
+
+func encodeBC5Block(dst []byte, block *pixelBlock) {
+	encodeChannelBlock(dst[0:8], block, 0)
+	encodeChannelBlock(dst[8:16], block, 1)
+}
+
+func decodeBC5Block(block *pixelBlock, src []byte) {
+	for i := range block {
+		block[i] = [4]uint8{0, 0, 0, 255}
+	}
+	decodeChannelBlock(block, src[0:8], 0)
+	decodeChannelBlock(block, src[8:16], 1)
+}
+
+// encodeChannelBlock encodes a single channel of the pixels into a BC4 block.
+// The endpoints are ordered so that the block is decoded with eight
+// interpolated values.
+func encodeChannelBlock(dst []byte, block *pixelBlock, channel int) {
+	low, high := uint8(255), uint8(0)
+	for _, pixel := range block {
+		low = min(low, pixel[channel])
+		high = max(high, pixel[channel])
+	}
+
+	var indices uint64
+	if high > low {
+		palette := channelPalette(high, low)
+		for i, pixel := range block {
+			bestIndex, bestCost := 0, math.MaxInt
+			for index, entry := range palette {
+				if cost := sqrDiff(pixel[channel], entry); cost < bestCost {
+					bestIndex, bestCost = index, cost
+				}
+			}
+			indices |= uint64(bestIndex) << (3 * i)
+		}
+	}
+
+	dst[0] = high
+	dst[1] = low
+	for i := range 6 {
+		dst[2+i] = uint8(indices >> (8 * i))
+	}
+}
+
+func decodeChannelBlock(block *pixelBlock, src []byte, channel int) {
+	palette := channelPalette(src[0], src[1])
+	var indices uint64
+	for i := range 6 {
+		indices |= uint64(src[2+i]) << (8 * i)
+	}
+	for i := range block {
+		block[i][channel] = palette[(indices>>(3*i))&0x07]
+	}
+}
+
+func channelPalette(value0, value1 uint8) [8]uint8 {
+	first, second := int(value0), int(value1)
+	palette := [8]uint8{value0, value1}
+	if value0 > value1 {
+		for i := 1; i < 7; i++ {
+			palette[i+1] = uint8(((7-i)*first + i*second + 3) / 7)
+		}
+	} else {
+		for i := 1; i < 5; i++ {
+			palette[i+1] = uint8(((5-i)*first + i*second + 2) / 5)
+		}
+		palette[6] = 0
+		palette[7] = 255
+	}
+	return palette
+}
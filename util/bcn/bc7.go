@@ -0,0 +1,391 @@
+package bcn
+
+import "math"
+
+// bc7Mode describes the bit layout of a BC7 block mode.
+type bc7Mode struct {
+	subsets        int
+	partitionBits  int
+	rotationBits   int
+	selectionBits  int
+	colorBits      int
+	alphaBits      int
+	endpointPBits  bool
+	sharedPBits    bool
+	indexBits      int
+	secondaryIndex int
+}
+
+var bc7Modes = [8]bc7Mode{
+	{subsets: 3, partitionBits: 4, colorBits: 4, endpointPBits: true, indexBits: 3},
+	{subsets: 2, partitionBits: 6, colorBits: 6, sharedPBits: true, indexBits: 3},
+	{subsets: 3, partitionBits: 6, colorBits: 5, indexBits: 2},
+	{subsets: 2, partitionBits: 6, colorBits: 7, endpointPBits: true, indexBits: 2},
+	{subsets: 1, rotationBits: 2, selectionBits: 1, colorBits: 5, alphaBits: 6, indexBits: 2, secondaryIndex: 3},
+	{subsets: 1, rotationBits: 2, colorBits: 7, alphaBits: 8, indexBits: 2, secondaryIndex: 2},
+	{subsets: 1, colorBits: 7, alphaBits: 7, endpointPBits: true, indexBits: 4},
+	{subsets: 2, partitionBits: 6, colorBits: 5, alphaBits: 5, endpointPBits: true, indexBits: 2},
+}
+
+// bc7Weights holds the interpolation weights, out of 64, for each index
+// precision.
+var bc7Weights = [5][]int{
+	2: {0, 21, 43, 64},
+	3: {0, 9, 18, 27, 37, 46, 55, 64},
+	4: {0, 4, 9, 13, 17, 21, 26, 30, 34, 38, 43, 47, 51, 55, 60, 64},
+}
+
+// bc7Partitions2 holds the two-subset partitions, with one bit per pixel
+// that is set when the pixel belongs to the second subset.
+var bc7Partitions2 = [64]uint16{
+	0xCCCC, 0x8888, 0xEEEE, 0xECC8, 0xC880, 0xFEEC, 0xFEC8, 0xEC80,
+	0xC800, 0xFFEC, 0xFE80, 0xE800, 0xFFE8, 0xFF00, 0xFFF0, 0xF000,
+	0xF710, 0x008E, 0x7100, 0x08CE, 0x008C, 0x7310, 0x3100, 0x8CCE,
+	0x088C, 0x3110, 0x6666, 0x366C, 0x17E8, 0x0FF0, 0x718E, 0x399C,
+	0xAAAA, 0xF0F0, 0x5A5A, 0x33CC, 0x3C3C, 0x55AA, 0x9696, 0xA55A,
+	0x73CE, 0x13C8, 0x324C, 0x3BDC, 0x6996, 0xC33C, 0x9966, 0x0660,
+	0x0272, 0x04E4, 0x4E40, 0x2720, 0xC936, 0x936C, 0x39C6, 0x639C,
+	0x9336, 0x9CC6, 0x817E, 0xE718, 0xCCF0, 0x0FCC, 0x7744, 0xEE22,
+}
+
+// bc7Partitions3 holds the three-subset partitions, with two bits per pixel
+// that hold the subset of the pixel.
+var bc7Partitions3 = [64]uint32{
+	0xAA685050, 0x6A5A5040, 0x5A5A4200, 0x5450A0A8, 0xA5A50000, 0xA0A05050, 0x5555A0A0, 0x5A5A5050,
+	0xAA550000, 0xAA555500, 0xAAAA5500, 0x90909090, 0x94949494, 0xA4A4A4A4, 0xA9A59450, 0x2A0A4250,
+	0xA5945040, 0x0A425054, 0xA5A5A500, 0x55A0A0A0, 0xA8A85454, 0x6A6A4040, 0xA4A45000, 0x1A1A0500,
+	0x0050A4A4, 0xAAA59090, 0x14696914, 0x69691400, 0xA08585A0, 0xAA821414, 0x50A4A450, 0x6A5A0200,
+	0xA9A58000, 0x5090A0A8, 0xA8A09050, 0x24242424, 0x00AA5500, 0x24924924, 0x24499224, 0x50A50A50,
+	0x500AA550, 0xAAAA4444, 0x66660000, 0xA5A0A5A0, 0x50A050A0, 0x69286928, 0x44AAAA44, 0x66666600,
+	0xAA444444, 0x54A854A8, 0x95809580, 0x96969600, 0xA85454A8, 0x80959580, 0xAA141414, 0x96960000,
+	0xAAAA1414, 0xA05050A0, 0xA0A5A5A0, 0x96000000, 0x40804080, 0xA9A8A9A8, 0xAAAAAA44, 0x2A4A5254,
+}
+
+// bc7Anchors2 holds the anchor pixel of the second subset of the two-subset
+// partitions.
+var bc7Anchors2 = [64]uint8{
+	15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15,
+	15, 2, 8, 2, 2, 8, 8, 15, 2, 8, 2, 2, 8, 8, 2, 2,
+	15, 15, 6, 8, 2, 8, 15, 15, 2, 8, 2, 2, 2, 15, 15, 6,
+	6, 2, 6, 8, 15, 15, 2, 2, 15, 15, 15, 15, 15, 2, 2, 15,
+}
+
+// bc7Anchors3 holds the anchor pixels of the second and third subsets of the
+// three-subset partitions.
+var bc7Anchors3 = [2][64]uint8{
+	{
+		3, 3, 15, 15, 8, 3, 15, 15, 8, 8, 6, 6, 6, 5, 3, 3,
+		3, 3, 8, 15, 3, 3, 6, 10, 5, 8, 8, 6, 8, 5, 15, 15,
+		8, 15, 3, 5, 6, 10, 8, 15, 15, 3, 15, 5, 15, 15, 15, 15,
+		3, 15, 5, 5, 5, 8, 5, 10, 5, 10, 8, 13, 15, 12, 3, 3,
+	},
+	{
+		15, 8, 8, 3, 15, 15, 3, 8, 15, 15, 15, 15, 15, 15, 15, 8,
+		15, 8, 15, 3, 15, 8, 15, 8, 3, 15, 6, 10, 15, 15, 10, 8,
+		15, 3, 15, 10, 10, 8, 9, 10, 6, 15, 8, 15, 3, 6, 6, 8,
+		15, 3, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 3, 15, 15, 8,
+	},
+}
+
+type bc7Encoding struct {
+	endpoints [2][4]uint8
+	pBits     [2]uint8
+	indices   [16]uint8
+	cost      int
+}
+
+// encodeBC7Block encodes the pixels with mode 6, which uses a single subset
+// with full RGBA endpoints and 4-bit indices. It is the mode that suits
+// smooth color and alpha gradients best.
+func encodeBC7Block(dst []byte, block *pixelBlock) {
+	low, high := principalEndpoints(block, 4)
+	best := encodeBC7Endpoints(block, low, high)
+
+	var factors [16]float64
+	for i, index := range best.indices {
+		factors[i] = float64(bc7Weights[4][index]) / 64.0
+	}
+	if first, second, ok := leastSquaresEndpoints(block, 4, &factors); ok {
+		if candidate := encodeBC7Endpoints(block, first, second); candidate.cost < best.cost {
+			best = candidate
+		}
+	}
+
+	// The most significant bit of the anchor index is implicitly zero, which
+	// is achieved by swapping the endpoints.
+	if best.indices[0] >= 8 {
+		best.endpoints[0], best.endpoints[1] = best.endpoints[1], best.endpoints[0]
+		best.pBits[0], best.pBits[1] = best.pBits[1], best.pBits[0]
+		for i := range best.indices {
+			best.indices[i] = 15 - best.indices[i]
+		}
+	}
+
+	var writer bitWriter
+	writer.write(1<<6, 7)
+	for c := range 4 {
+		writer.write(uint32(best.endpoints[0][c]>>1), 7)
+		writer.write(uint32(best.endpoints[1][c]>>1), 7)
+	}
+	writer.write(uint32(best.pBits[0]), 1)
+	writer.write(uint32(best.pBits[1]), 1)
+	writer.write(uint32(best.indices[0]), 3)
+	for _, index := range best.indices[1:] {
+		writer.write(uint32(index), 4)
+	}
+	writer.store(dst)
+}
+
+// encodeBC7Endpoints quantizes the endpoints for mode 6 and picks the closest
+// palette entry for each pixel.
+func encodeBC7Endpoints(block *pixelBlock, first, second [4]float64) bc7Encoding {
+	var result bc7Encoding
+	for i, endpoint := range [2][4]float64{first, second} {
+		bestCost := math.Inf(1)
+		for pBit := range 2 {
+			var quantized [4]uint8
+			var cost float64
+			for c := range 4 {
+				value := roundClamp((endpoint[c]-float64(pBit))/2.0, 127)
+				quantized[c] = uint8(value<<1 | pBit)
+				diff := float64(quantized[c]) - endpoint[c]
+				cost += diff * diff
+			}
+			if cost < bestCost {
+				bestCost = cost
+				result.endpoints[i] = quantized
+				result.pBits[i] = uint8(pBit)
+			}
+		}
+	}
+
+	var palette [16][4]uint8
+	for index, weight := range bc7Weights[4] {
+		for c := range 4 {
+			palette[index][c] = bc7Interpolate(result.endpoints[0][c], result.endpoints[1][c], weight)
+		}
+	}
+	for i, pixel := range block {
+		bestIndex, bestCost := 0, math.MaxInt
+		for index, entry := range palette {
+			cost := sqrDiff(pixel[0], entry[0]) + sqrDiff(pixel[1], entry[1]) +
+				sqrDiff(pixel[2], entry[2]) + sqrDiff(pixel[3], entry[3])
+			if cost < bestCost {
+				bestIndex, bestCost = index, cost
+			}
+		}
+		result.indices[i] = uint8(bestIndex)
+		result.cost += bestCost
+	}
+	return result
+}
+
+// decodeBC7Block decodes a block of any of the eight BC7 modes.
+func decodeBC7Block(block *pixelBlock, src []byte) {
+	reader := newBitReader(src)
+
+	modeIndex := 0
+	for modeIndex < 8 && reader.read(1) == 0 {
+		modeIndex++
+	}
+	if modeIndex == 8 {
+		// Reserved modes decode to transparent black.
+		*block = pixelBlock{}
+		return
+	}
+	mode := bc7Modes[modeIndex]
+
+	partition := int(reader.read(mode.partitionBits))
+	rotation := reader.read(mode.rotationBits)
+	selection := reader.read(mode.selectionBits)
+
+	endpointCount := mode.subsets * 2
+	var endpoints [6][4]int
+	for c := range 3 {
+		for i := range endpointCount {
+			endpoints[i][c] = int(reader.read(mode.colorBits))
+		}
+	}
+	if mode.alphaBits > 0 {
+		for i := range endpointCount {
+			endpoints[i][3] = int(reader.read(mode.alphaBits))
+		}
+	}
+
+	colorBits, alphaBits := mode.colorBits, mode.alphaBits
+	if mode.endpointPBits || mode.sharedPBits {
+		var pBits [6]int
+		if mode.endpointPBits {
+			for i := range endpointCount {
+				pBits[i] = int(reader.read(1))
+			}
+		} else {
+			for subset := range mode.subsets {
+				pBit := int(reader.read(1))
+				pBits[subset*2] = pBit
+				pBits[subset*2+1] = pBit
+			}
+		}
+		for i := range endpointCount {
+			for c := range 4 {
+				endpoints[i][c] = endpoints[i][c]<<1 | pBits[i]
+			}
+		}
+		colorBits++
+		if alphaBits > 0 {
+			alphaBits++
+		}
+	}
+	for i := range endpointCount {
+		for c := range 3 {
+			endpoints[i][c] = bc7Expand(endpoints[i][c], colorBits)
+		}
+		if alphaBits > 0 {
+			endpoints[i][3] = bc7Expand(endpoints[i][3], alphaBits)
+		} else {
+			endpoints[i][3] = 255
+		}
+	}
+
+	var subsets [16]int
+	for i := range subsets {
+		subsets[i] = bc7Subset(mode.subsets, partition, i)
+	}
+
+	var indices [16]int
+	for i := range indices {
+		bits := mode.indexBits
+		if bc7IsAnchor(mode.subsets, partition, i) {
+			bits--
+		}
+		indices[i] = int(reader.read(bits))
+	}
+	var secondaryIndices [16]int
+	if mode.secondaryIndex > 0 {
+		for i := range secondaryIndices {
+			bits := mode.secondaryIndex
+			if i == 0 {
+				bits--
+			}
+			secondaryIndices[i] = int(reader.read(bits))
+		}
+	}
+
+	for i := range block {
+		first := endpoints[subsets[i]*2]
+		second := endpoints[subsets[i]*2+1]
+		colorWeight := bc7Weights[mode.indexBits][indices[i]]
+		alphaWeight := colorWeight
+		if mode.secondaryIndex > 0 {
+			alphaWeight = bc7Weights[mode.secondaryIndex][secondaryIndices[i]]
+			if selection == 1 {
+				colorWeight, alphaWeight = alphaWeight, colorWeight
+			}
+		}
+
+		var pixel [4]uint8
+		for c := range 3 {
+			pixel[c] = bc7Interpolate(uint8(first[c]), uint8(second[c]), colorWeight)
+		}
+		pixel[3] = bc7Interpolate(uint8(first[3]), uint8(second[3]), alphaWeight)
+		if rotation > 0 {
+			pixel[rotation-1], pixel[3] = pixel[3], pixel[rotation-1]
+		}
+		block[i] = pixel
+	}
+}
+
+func bc7Subset(subsets, partition, pixel int) int {
+	switch subsets {
+	case 2:
+		return int(bc7Partitions2[partition]>>pixel) & 0x01
+	case 3:
+		return int(bc7Partitions3[partition]>>(2*pixel)) & 0x03
+	default:
+		return 0
+	}
+}
+
+func bc7IsAnchor(subsets, partition, pixel int) bool {
+	if pixel == 0 {
+		return true
+	}
+	switch subsets {
+	case 2:
+		return pixel == int(bc7Anchors2[partition])
+	case 3:
+		return pixel == int(bc7Anchors3[0][partition]) || pixel == int(bc7Anchors3[1][partition])
+	default:
+		return false
+	}
+}
+
+func bc7Expand(value, bits int) int {
+	value <<= 8 - bits
+	return value | value>>bits
+}
+
+func bc7Interpolate(first, second uint8, weight int) uint8 {
+	return uint8(((64-weight)*int(first) + weight*int(second) + 32) >> 6)
+}
+
+func newBitReader(data []byte) bitReader {
+	var reader bitReader
+	for i := range 8 {
+		reader.low |= uint64(data[i]) << (8 * i)
+		reader.high |= uint64(data[8+i]) << (8 * i)
+	}
+	return reader
+}
+
+// bitReader reads the bits of a 128-bit block, starting from the least
+// significant one.
+type bitReader struct {
+	low    uint64
+	high   uint64
+	offset int
+}
+
+func (r *bitReader) read(count int) uint32 {
+	var value uint32
+	for i := range count {
+		position := r.offset + i
+		var bit uint64
+		if position < 64 {
+			bit = (r.low >> position) & 0x01
+		} else {
+			bit = (r.high >> (position - 64)) & 0x01
+		}
+		value |= uint32(bit) << i
+	}
+	r.offset += count
+	return value
+}
+
+// bitWriter writes the bits of a 128-bit block, starting from the least
+// significant one.
+type bitWriter struct {
+	low    uint64
+	high   uint64
+	offset int
+}
+
+func (w *bitWriter) write(value uint32, count int) {
+	for i := range count {
+		position := w.offset + i
+		bit := uint64(value>>i) & 0x01
+		if position < 64 {
+			w.low |= bit << position
+		} else {
+			w.high |= bit << (position - 64)
+		}
+	}
+	w.offset += count
+}
+
+func (w *bitWriter) store(dst []byte) {
+	for i := range 8 {
+		dst[i] = uint8(w.low >> (8 * i))
+		dst[8+i] = uint8(w.high >> (8 * i))
+	}
+}
@@ -0,0 +1,8 @@
+// Package bcn provides encoders and decoders for the BC1, BC3, BC4, BC5 and
+// BC7 block-compressed texture formats.
+//
+// All functions operate on tightly packed RGBA8 pixel data and on block data
+// that is stored row by row, with each block covering 4x4 pixels. Images
+// whose dimensions are not a multiple of four are padded by repeating the
+// edge pixels.
+package bcn
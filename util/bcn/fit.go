@@ -0,0 +1,110 @@
+package bcn
+
+import "math"
+
+// principalEndpoints returns the two points at the extremes of the
+// projection of the pixels onto their principal axis, considering only the
+// first channels of each pixel.
+func principalEndpoints(block *pixelBlock, channels int) (low, high [4]float64) {
+	var mean [4]float64
+	for _, pixel := range block {
+		for c := range channels {
+			mean[c] += float64(pixel[c])
+		}
+	}
+	for c := range channels {
+		mean[c] /= float64(len(block))
+	}
+
+	var covariance [4][4]float64
+	for _, pixel := range block {
+		for i := range channels {
+			for j := range channels {
+				covariance[i][j] += (float64(pixel[i]) - mean[i]) * (float64(pixel[j]) - mean[j])
+			}
+		}
+	}
+
+	// The principal axis is found with power iteration, starting from the
+	// diagonal of the block, which is rarely orthogonal to it.
+	var axis [4]float64
+	for c := range channels {
+		axis[c] = 1.0
+	}
+	for range 8 {
+		var next [4]float64
+		for i := range channels {
+			for j := range channels {
+				next[i] += covariance[i][j] * axis[j]
+			}
+		}
+		var length float64
+		for c := range channels {
+			length += next[c] * next[c]
+		}
+		if length < 1e-12 {
+			break
+		}
+		length = math.Sqrt(length)
+		for c := range channels {
+			axis[c] = next[c] / length
+		}
+	}
+
+	minProjection, maxProjection := math.Inf(1), math.Inf(-1)
+	for _, pixel := range block {
+		var projection float64
+		for c := range channels {
+			projection += (float64(pixel[c]) - mean[c]) * axis[c]
+		}
+		minProjection = min(minProjection, projection)
+		maxProjection = max(maxProjection, projection)
+	}
+	for c := range channels {
+		low[c] = mean[c] + axis[c]*minProjection
+		high[c] = mean[c] + axis[c]*maxProjection
+	}
+	return low, high
+}
+
+// leastSquaresEndpoints returns the two endpoints that best reproduce the
+// pixels, given the interpolation factor between the endpoints that each
+// pixel uses. The returned bool is false when the factors do not constrain
+// both endpoints.
+func leastSquaresEndpoints(block *pixelBlock, channels int, factors *[16]float64) (first, second [4]float64, ok bool) {
+	var aa, ab, bb float64
+	var ax, bx [4]float64
+	for i, pixel := range block {
+		b := factors[i]
+		a := 1.0 - b
+		aa += a * a
+		ab += a * b
+		bb += b * b
+		for c := range channels {
+			ax[c] += a * float64(pixel[c])
+			bx[c] += b * float64(pixel[c])
+		}
+	}
+	determinant := aa*bb - ab*ab
+	if math.Abs(determinant) < 1e-6 {
+		return first, second, false
+	}
+	for c := range channels {
+		first[c] = (bb*ax[c] - ab*bx[c]) / determinant
+		second[c] = (aa*bx[c] - ab*ax[c]) / determinant
+	}
+	return first, second, true
+}
+
+func clampInt(value, low, high int) int {
+	return min(max(value, low), high)
+}
+
+func roundClamp(value float64, high int) int {
+	return clampInt(int(math.Round(value)), 0, high)
+}
+
+func sqrDiff(a, b uint8) int {
+	diff := int(a) - int(b)
+	return diff * diff
+}
@@ -0,0 +1,105 @@
+package bcn
+
+import "slices"
+
+// FlipVertical returns the block data of the image flipped vertically.
+//
+// The flip is performed by reordering the rows of the blocks, which is
+// lossless. This is not possible for FormatBC7, whose partitions are not
+// symmetric, or when the height is not a multiple of four and spans more than
+// one block, in which case the data is decoded and encoded again.
+func FlipVertical(format Format, data []byte, width, height int) []byte {
+	if height <= 1 {
+		return slices.Clone(data)
+	}
+
+	permuteRows := blockRowPermuter(format)
+	switch {
+	case permuteRows != nil && height%4 == 0:
+		blockSize := format.BlockSize()
+		rowSize := blockCount(width) * blockSize
+		rows := blockCount(height)
+		result := make([]byte, len(data))
+		for row := range rows {
+			srcRow := data[(rows-row-1)*rowSize : (rows-row)*rowSize]
+			dstRow := result[row*rowSize : (row+1)*rowSize]
+			for offset := 0; offset < rowSize; offset += blockSize {
+				permuteRows(dstRow[offset:offset+blockSize], srcRow[offset:offset+blockSize], [4]int{3, 2, 1, 0})
+			}
+		}
+		return result
+
+	case permuteRows != nil && height < 4:
+		order := [4]int{0, 1, 2, 3}
+		for row := range height {
+			order[row] = height - row - 1
+		}
+		blockSize := format.BlockSize()
+		result := make([]byte, len(data))
+		for offset := 0; offset < len(data); offset += blockSize {
+			permuteRows(result[offset:offset+blockSize], data[offset:offset+blockSize], order)
+		}
+		return result
+
+	default:
+		rgba := Decode(format, data, width, height)
+		rowSize := width * 4
+		flipped := make([]byte, len(rgba))
+		for row := range height {
+			copy(flipped[row*rowSize:(row+1)*rowSize], rgba[(height-row-1)*rowSize:(height-row)*rowSize])
+		}
+		return Encode(format, flipped, width, height)
+	}
+}
+
+// blockRowPermuter returns a function that copies a block, such that each
+// row of the destination is taken from the row of the source that is
+// specified by the order. It returns nil for formats that do not support
+// this.
+func blockRowPermuter(format Format) func(dst, src []byte, order [4]int) {
+	switch format {
+	case FormatBC1:
+		return permuteColorBlockRows
+	case FormatBC3:
+		return func(dst, src []byte, order [4]int) {
+			permuteChannelBlockRows(dst[0:8], src[0:8], order)
+			permuteColorBlockRows(dst[8:16], src[8:16], order)
+		}
+	case FormatBC4:
+		return permuteChannelBlockRows
+	case FormatBC5:
+		return func(dst, src []byte, order [4]int) {
+			permuteChannelBlockRows(dst[0:8], src[0:8], order)
+			permuteChannelBlockRows(dst[8:16], src[8:16], order)
+		}
+	default:
+		return nil
+	}
+}
+
+// permuteColorBlockRows reorders the rows of a BC1 color block, where each
+// row of indices occupies a single byte.
+func permuteColorBlockRows(dst, src []byte, order [4]int) {
+	copy(dst[0:4], src[0:4])
+	for row, srcRow := range order {
+		dst[4+row] = src[4+srcRow]
+	}
+}
+
+// permuteChannelBlockRows reorders the rows of a BC4 channel block, where
+// each row of indices occupies twelve bits.
+func permuteChannelBlockRows(dst, src []byte, order [4]int) {
+	var srcIndices uint64
+	for i := range 6 {
+		srcIndices |= uint64(src[2+i]) << (8 * i)
+	}
+	var dstIndices uint64
+	for row, srcRow := range order {
+		dstIndices |= ((srcIndices >> (12 * srcRow)) & 0xFFF) << (12 * row)
+	}
+	dst[0] = src[0]
+	dst[1] = src[1]
+	for i := range 6 {
+		dst[2+i] = uint8(dstIndices >> (8 * i))
+	}
+}
@@ -0,0 +1,160 @@
+package bcn
+
+import "fmt"
+
+const (
+	// FormatBC1 stores RGB data in 8 bytes per block. Alpha is discarded.
+	FormatBC1 Format = iota
+
+	// FormatBC3 stores RGBA data in 16 bytes per block, where alpha is
+	// encoded separately from the color.
+	FormatBC3
+
+	// FormatBC4 stores a single channel in 8 bytes per block.
+	FormatBC4
+
+	// FormatBC5 stores two channels in 16 bytes per block.
+	FormatBC5
+
+	// FormatBC7 stores RGBA data in 16 bytes per block with a higher quality
+	// than FormatBC3.
+	FormatBC7
+)
+
+// Format represents a block-compressed format.
+type Format uint8
+
+// String returns a string representation of the Format.
+func (f Format) String() string {
+	switch f {
+	case FormatBC1:
+		return "BC1"
+	case FormatBC3:
+		return "BC3"
+	case FormatBC4:
+		return "BC4"
+	case FormatBC5:
+		return "BC5"
+	case FormatBC7:
+		return "BC7"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// BlockSize returns the number of bytes that a single 4x4 block occupies.
+func (f Format) BlockSize() int {
+	switch f {
+	case FormatBC1, FormatBC4:
+		return 8
+	case FormatBC3, FormatBC5, FormatBC7:
+		return 16
+	default:
+		panic(fmt.Errorf("unknown format: %d", f))
+	}
+}
+
+// DataSize returns the number of bytes that an image with the specified
+// dimensions occupies in the format.
+func (f Format) DataSize(width, height int) int {
+	return blockCount(width) * blockCount(height) * f.BlockSize()
+}
+
+// Encode compresses the specified RGBA8 pixel data into the format.
+//
+// For FormatBC4 only the red channel is stored and for FormatBC5 only the red
+// and green channels are stored.
+func Encode(format Format, rgba []byte, width, height int) []byte {
+	var encodeBlock func(dst []byte, block *pixelBlock)
+	switch format {
+	case FormatBC1:
+		encodeBlock = encodeBC1Block
+	case FormatBC3:
+		encodeBlock = encodeBC3Block
+	case FormatBC4:
+		encodeBlock = encodeBC4Block
+	case FormatBC5:
+		encodeBlock = encodeBC5Block
+	case FormatBC7:
+		encodeBlock = encodeBC7Block
+	default:
+		panic(fmt.Errorf("unknown format: %d", format))
+	}
+
+	blockSize := format.BlockSize()
+	result := make([]byte, format.DataSize(width, height))
+	offset := 0
+	var block pixelBlock
+	for blockY := range blockCount(height) {
+		for blockX := range blockCount(width) {
+			block.load(rgba, width, height, blockX*4, blockY*4)
+			encodeBlock(result[offset:offset+blockSize], &block)
+			offset += blockSize
+		}
+	}
+	return result
+}
+
+// Decode decompresses the specified block data of the format into RGBA8
+// pixel data.
+//
+// Channels that the format does not store are set to zero, except for alpha,
+// which is set to one, matching the behavior of graphics hardware.
+func Decode(format Format, data []byte, width, height int) []byte {
+	var decodeBlock func(block *pixelBlock, src []byte)
+	switch format {
+	case FormatBC1:
+		decodeBlock = decodeBC1Block
+	case FormatBC3:
+		decodeBlock = decodeBC3Block
+	case FormatBC4:
+		decodeBlock = decodeBC4Block
+	case FormatBC5:
+		decodeBlock = decodeBC5Block
+	case FormatBC7:
+		decodeBlock = decodeBC7Block
+	default:
+		panic(fmt.Errorf("unknown format: %d", format))
+	}
+
+	blockSize := format.BlockSize()
+	result := make([]byte, width*height*4)
+	offset := 0
+	var block pixelBlock
+	for blockY := range blockCount(height) {
+		for blockX := range blockCount(width) {
+			decodeBlock(&block, data[offset:offset+blockSize])
+			block.store(result, width, height, blockX*4, blockY*4)
+			offset += blockSize
+		}
+	}
+	return result
+}
+
+func blockCount(size int) int {
+	return max(1, (size+3)/4)
+}
+
+// pixelBlock holds the RGBA8 pixels of a 4x4 block in row order.
+type pixelBlock [16][4]uint8
+
+func (b *pixelBlock) load(rgba []byte, width, height, x, y int) {
+	for i := range b {
+		pixelX := min(x+i%4, width-1)
+		pixelY := min(y+i/4, height-1)
+		offset := (pixelY*width + pixelX) * 4
+		copy(b[i][:], rgba[offset:offset+4])
+	}
+}
+
+func (b *pixelBlock) store(rgba []byte, width, height, x, y int) {
+	for i := range b {
+		pixelX := x + i%4
+		pixelY := y + i/4
+		if pixelX >= width || pixelY >= height {
+			continue
+		}
+		offset := (pixelY*width + pixelX) * 4
+		copy(rgba[offset:offset+4], b[i][:])
+	}
+}
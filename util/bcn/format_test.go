@@ -0,0 +1,191 @@
+package bcn_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mokiat/lacking/util/bcn"
+)
+
+var _ = Describe("Format", func() {
+	// rampImage creates an image whose colors vary smoothly along a line
+	// in RGBA space, which all formats are able to represent closely.
+	rampImage := func(width, height int) []byte {
+		result := make([]byte, width*height*4)
+		for y := range height {
+			for x := range width {
+				offset := (y*width + x) * 4
+				t := 16 + x*6 + y*4
+				result[offset+0] = byte(t)
+				result[offset+1] = byte(255 - t)
+				result[offset+2] = byte(64 + t/2)
+				result[offset+3] = byte(t)
+			}
+		}
+		return result
+	}
+
+	// noiseImage creates an image with pseudo-random pixels.
+	noiseImage := func(width, height int) []byte {
+		result := make([]byte, width*height*4)
+		seed := uint32(1)
+		for i := range result {
+			seed = seed*1664525 + 1013904223
+			result[i] = byte(seed >> 24)
+		}
+		return result
+	}
+
+	// maxChannelError returns the largest difference of the specified
+	// channel between the two images.
+	maxChannelError := func(expected, actual []byte, channel int) int {
+		var result int
+		for i := channel; i < len(expected); i += 4 {
+			result = max(result, abs(int(expected[i])-int(actual[i])))
+		}
+		return result
+	}
+
+	// channelValues returns the distinct values of the specified channel.
+	channelValues := func(rgba []byte, channel int) []byte {
+		seen := make(map[byte]struct{})
+		var result []byte
+		for i := channel; i < len(rgba); i += 4 {
+			if _, ok := seen[rgba[i]]; !ok {
+				seen[rgba[i]] = struct{}{}
+				result = append(result, rgba[i])
+			}
+		}
+		return result
+	}
+
+	roundTrip := func(format bcn.Format, rgba []byte, width, height int) []byte {
+		data := bcn.Encode(format, rgba, width, height)
+		Expect(data).To(HaveLen(format.DataSize(width, height)))
+		result := bcn.Decode(format, data, width, height)
+		Expect(result).To(HaveLen(width * height * 4))
+		return result
+	}
+
+	DescribeTable("DataSize",
+		func(format bcn.Format, width, height, expected int) {
+			Expect(format.DataSize(width, height)).To(Equal(expected))
+		},
+		Entry("BC1 aligned", bcn.FormatBC1, 8, 4, 16),
+		Entry("BC1 unaligned", bcn.FormatBC1, 5, 3, 16),
+		Entry("BC1 single pixel", bcn.FormatBC1, 1, 1, 8),
+		Entry("BC4 unaligned", bcn.FormatBC4, 6, 7, 32),
+		Entry("BC3 unaligned", bcn.FormatBC3, 13, 2, 64),
+		Entry("BC5 unaligned", bcn.FormatBC5, 2, 9, 48),
+		Entry("BC7 unaligned", bcn.FormatBC7, 9, 9, 144),
+	)
+
+	sizes := [][2]int{{16, 16}, {1, 1}, {5, 3}, {6, 7}, {13, 2}, {2, 9}}
+
+	DescribeTable("round trip of a smooth ramp",
+		func(format bcn.Format, channelErrors [4]int) {
+			for _, size := range sizes {
+				width, height := size[0], size[1]
+				source := rampImage(width, height)
+				result := roundTrip(format, source, width, height)
+				for channel, maxError := range channelErrors {
+					if maxError < 0 {
+						continue
+					}
+					Expect(maxChannelError(source, result, channel)).To(
+						BeNumerically("<=", maxError),
+						"format %s, size %dx%d, channel %d", format, width, height, channel,
+					)
+				}
+			}
+		},
+		Entry("BC1", bcn.FormatBC1, [4]int{8, 8, 8, -1}),
+		Entry("BC3", bcn.FormatBC3, [4]int{8, 8, 8, 3}),
+		Entry("BC4", bcn.FormatBC4, [4]int{3, -1, -1, -1}),
+		Entry("BC5", bcn.FormatBC5, [4]int{3, 3, -1, -1}),
+		Entry("BC7", bcn.FormatBC7, [4]int{2, 2, 2, 2}),
+	)
+
+	DescribeTable("round trip of single channel noise",
+		func(format bcn.Format, channels []int) {
+			for _, size := range sizes {
+				width, height := size[0], size[1]
+				source := noiseImage(width, height)
+				result := roundTrip(format, source, width, height)
+				for _, channel := range channels {
+					Expect(maxChannelError(source, result, channel)).To(
+						BeNumerically("<=", 24),
+						"format %s, size %dx%d, channel %d", format, width, height, channel,
+					)
+				}
+			}
+		},
+		Entry("BC3 alpha", bcn.FormatBC3, []int{3}),
+		Entry("BC4", bcn.FormatBC4, []int{0}),
+		Entry("BC5", bcn.FormatBC5, []int{0, 1}),
+	)
+
+	DescribeTable("round trip of a solid color",
+		func(format bcn.Format, expected [4]byte, maxError int) {
+			for _, size := range sizes {
+				width, height := size[0], size[1]
+				source := make([]byte, width*height*4)
+				for i := 0; i < len(source); i += 4 {
+					copy(source[i:i+4], []byte{0x84, 0x41, 0xC6, 0x80})
+				}
+				result := roundTrip(format, source, width, height)
+				for channel := range 4 {
+					values := channelValues(result, channel)
+					Expect(values).To(HaveLen(1), "format %s, size %dx%d, channel %d", format, width, height, channel)
+					Expect(abs(int(values[0])-int(expected[channel]))).To(
+						BeNumerically("<=", maxError),
+						"format %s, size %dx%d, channel %d", format, width, height, channel,
+					)
+				}
+			}
+		},
+		// The color is exactly representable in RGB565.
+		Entry("BC1", bcn.FormatBC1, [4]byte{0x84, 0x41, 0xC6, 0xFF}, 0),
+		Entry("BC3", bcn.FormatBC3, [4]byte{0x84, 0x41, 0xC6, 0x80}, 0),
+		Entry("BC4", bcn.FormatBC4, [4]byte{0x84, 0x00, 0x00, 0xFF}, 0),
+		Entry("BC5", bcn.FormatBC5, [4]byte{0x84, 0x41, 0x00, 0xFF}, 0),
+		// Mode 6 shares the lowest bit of all channels of an endpoint.
+		Entry("BC7", bcn.FormatBC7, [4]byte{0x84, 0x41, 0xC6, 0x80}, 1),
+	)
+
+	DescribeTable("FlipVertical",
+		func(format bcn.Format, width, height int) {
+			source := rampImage(width, height)
+			data := bcn.Encode(format, source, width, height)
+			flipped := bcn.Decode(format, bcn.FlipVertical(format, data, width, height), width, height)
+			decoded := bcn.Decode(format, data, width, height)
+
+			maxError := 0
+			if format == bcn.FormatBC7 || (height%4 != 0 && height > 4) {
+				maxError = 8 // the image is encoded again
+			}
+			rowSize := width * 4
+			for y := range height {
+				expected := decoded[(height-y-1)*rowSize : (height-y)*rowSize]
+				actual := flipped[y*rowSize : (y+1)*rowSize]
+				for channel := range 4 {
+					Expect(maxChannelError(expected, actual, channel)).To(BeNumerically("<=", maxError))
+				}
+			}
+		},
+		Entry("BC1 aligned", bcn.FormatBC1, 8, 8),
+		Entry("BC1 short", bcn.FormatBC1, 5, 3),
+		Entry("BC3 aligned", bcn.FormatBC3, 4, 12),
+		Entry("BC4 short", bcn.FormatBC4, 6, 2),
+		Entry("BC5 aligned", bcn.FormatBC5, 8, 4),
+		Entry("BC1 unaligned", bcn.FormatBC1, 4, 6),
+		Entry("BC7 aligned", bcn.FormatBC7, 8, 8),
+	)
+})
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,13 @@
+package bcn_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBCN(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BCn Suite")
+}
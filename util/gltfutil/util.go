@@ -1,15 +1,19 @@
 package gltfutil
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 
 	"github.com/mokiat/gblob"
+	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/gomath/dprec"
 	"github.com/mokiat/gomath/sprec"
 	"github.com/mokiat/gomath/stod"
 	"github.com/mokiat/lacking/util/blob"
 	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/ext/texturetransform"
 )
 
 func RootNodeIndices(doc *gltf.Document) []int {
@@ -116,7 +120,9 @@ func Normals(doc *gltf.Document, primitive *gltf.Primitive) ([]sprec.Vec3, error
 	return result, nil
 }
 
-func Tangents(doc *gltf.Document, primitive *gltf.Primitive) ([]sprec.Vec3, error) {
+// Tangents returns the tangents of the specified primitive, where the W
+// component holds the handedness of the tangent space.
+func Tangents(doc *gltf.Document, primitive *gltf.Primitive) ([]sprec.Vec4, error) {
 	if !HasAttribute(primitive, gltf.TANGENT) {
 		return nil, nil
 	}
@@ -130,12 +136,11 @@ func Tangents(doc *gltf.Document, primitive *gltf.Primitive) ([]sprec.Vec3, erro
 	buffer := BufferViewData(doc, *accessor.BufferView)
 	scanner := blob.NewScanner(buffer)
 
-	result := make([]sprec.Vec3, accessor.Count)
+	result := make([]sprec.Vec4, accessor.Count)
 	switch accessor.ComponentType {
 	case gltf.ComponentFloat:
 		for i := range result {
-			item := scanner.ScanSPVec4()
-			result[i] = sprec.Vec3Prod(sprec.NewVec3(item.X, item.Y, item.Z), item.W)
+			result[i] = scanner.ScanSPVec4()
 		}
 	default:
 		return nil, fmt.Errorf("unsupported accessor component type %d", accessor.ComponentType)
@@ -144,10 +149,18 @@ func Tangents(doc *gltf.Document, primitive *gltf.Primitive) ([]sprec.Vec3, erro
 }
 
 func TexCoord0s(doc *gltf.Document, primitive *gltf.Primitive) ([]sprec.Vec2, error) {
-	if !HasAttribute(primitive, gltf.TEXCOORD_0) {
+	return texCoords(doc, primitive, gltf.TEXCOORD_0)
+}
+
+func TexCoord1s(doc *gltf.Document, primitive *gltf.Primitive) ([]sprec.Vec2, error) {
+	return texCoords(doc, primitive, gltf.TEXCOORD_1)
+}
+
+func texCoords(doc *gltf.Document, primitive *gltf.Primitive, name string) ([]sprec.Vec2, error) {
+	if !HasAttribute(primitive, name) {
 		return nil, nil
 	}
-	accessor := doc.Accessors[primitive.Attributes[gltf.TEXCOORD_0]]
+	accessor := doc.Accessors[primitive.Attributes[name]]
 	if accessor.BufferView == nil {
 		return nil, fmt.Errorf("accessor lacks a buffer view")
 	}
@@ -349,6 +362,81 @@ func Joint0s(doc *gltf.Document, primitive *gltf.Primitive) ([][4]uint8, error)
 	return result, nil
 }
 
+// MorphTargetCount returns the number of morph targets of the specified mesh.
+func MorphTargetCount(mesh *gltf.Mesh) int {
+	var result int
+	for _, primitive := range mesh.Primitives {
+		result = max(result, len(primitive.Targets))
+	}
+	return result
+}
+
+// MorphTargetNames returns the names of the morph targets of the specified
+// mesh, as stored in the targetNames extras property by most exporters. An
+// empty name is returned for morph targets that are not named.
+func MorphTargetNames(mesh *gltf.Mesh) []string {
+	result := make([]string, MorphTargetCount(mesh))
+	extras, ok := mesh.Extras.(map[string]any)
+	if !ok {
+		return result
+	}
+	names, ok := extras["targetNames"].([]any)
+	if !ok {
+		return result
+	}
+	for i, name := range names {
+		if i >= len(result) {
+			break
+		}
+		if name, ok := name.(string); ok {
+			result[i] = name
+		}
+	}
+	return result
+}
+
+// MorphTargetCoords returns the coordinate deltas of the specified morph
+// target of the primitive.
+func MorphTargetCoords(doc *gltf.Document, primitive *gltf.Primitive, target int) ([]sprec.Vec3, error) {
+	return morphTargetVec3s(doc, primitive, target, gltf.POSITION)
+}
+
+// MorphTargetNormals returns the normal deltas of the specified morph target
+// of the primitive.
+func MorphTargetNormals(doc *gltf.Document, primitive *gltf.Primitive, target int) ([]sprec.Vec3, error) {
+	return morphTargetVec3s(doc, primitive, target, gltf.NORMAL)
+}
+
+func morphTargetVec3s(doc *gltf.Document, primitive *gltf.Primitive, target int, name string) ([]sprec.Vec3, error) {
+	if target >= len(primitive.Targets) {
+		return nil, nil
+	}
+	accessorIndex, ok := primitive.Targets[target][name]
+	if !ok {
+		return nil, nil
+	}
+	accessor := doc.Accessors[accessorIndex]
+	if accessor.BufferView == nil {
+		return nil, fmt.Errorf("accessor lacks a buffer view")
+	}
+	if accessor.Type != gltf.AccessorVec3 {
+		return nil, fmt.Errorf("unsupported accessor type %d", accessor.Type)
+	}
+	buffer := BufferViewData(doc, *accessor.BufferView)
+	scanner := blob.NewScanner(buffer)
+
+	result := make([]sprec.Vec3, accessor.Count)
+	switch accessor.ComponentType {
+	case gltf.ComponentFloat:
+		for i := range result {
+			result[i] = scanner.ScanSPVec3()
+		}
+	default:
+		return nil, fmt.Errorf("unsupported accessor component type %d", accessor.ComponentType)
+	}
+	return result, nil
+}
+
 func PrimitiveMaterial(doc *gltf.Document, primitive *gltf.Primitive) *gltf.Material {
 	if primitive.Material == nil {
 		return nil
@@ -361,40 +449,146 @@ func BaseColor(pbr *gltf.PBRMetallicRoughness) sprec.Vec4 {
 	return sprec.NewVec4(float32(factor[0]), float32(factor[1]), float32(factor[2]), float32(factor[3]))
 }
 
-func ColorTextureIndex(doc *gltf.Document, pbr *gltf.PBRMetallicRoughness) *int {
+// TextureReference describes how a material samples a texture.
+type TextureReference struct {
+
+	// Index is the index of the texture within the document.
+	Index int
+
+	// TexCoord is the index of the TEXCOORD attribute that is used to sample
+	// the texture. Only 0 and 1 are supported.
+	TexCoord int
+
+	// Transform, if specified, is the KHR_texture_transform of the reference.
+	Transform opt.T[TextureTransform]
+}
+
+// TextureTransform is an affine transformation of texture coordinates,
+// expressed in the flipped texture coordinate space that is produced by
+// TexCoord0s and TexCoord1s.
+//
+// The transformed coordinates are calculated as follows:
+//
+//	u' = dot(U, vec3(u, v, 1.0))
+//	v' = dot(V, vec3(u, v, 1.0))
+type TextureTransform struct {
+	U sprec.Vec3
+	V sprec.Vec3
+}
+
+func ColorTexture(doc *gltf.Document, pbr *gltf.PBRMetallicRoughness) *TextureReference {
 	colorTexture := pbr.BaseColorTexture
 	if colorTexture == nil {
 		return nil
 	}
-	if colorTexture.TexCoord != 0 {
-		logger.Warn("Unsupported color texture: tex coord layer unsupported")
-		return nil
-	}
-	return &colorTexture.Index
+	return textureReference("color", colorTexture.Index, colorTexture.TexCoord, colorTexture.Extensions)
 }
 
-func MetallicRoughnessTextureIndex(doc *gltf.Document, pbr *gltf.PBRMetallicRoughness) *int {
+func MetallicRoughnessTexture(doc *gltf.Document, pbr *gltf.PBRMetallicRoughness) *TextureReference {
 	mrTexture := pbr.MetallicRoughnessTexture
 	if mrTexture == nil {
 		return nil
 	}
-	if mrTexture.TexCoord != 0 {
-		logger.Warn("Unsupported metallic-roughness texture: tex coord layer unsupported")
-		return nil
-	}
-	return &mrTexture.Index
+	return textureReference("metallic-roughness", mrTexture.Index, mrTexture.TexCoord, mrTexture.Extensions)
 }
 
-func NormalTextureIndexScale(doc *gltf.Document, material *gltf.Material) (*int, float32) {
+func NormalTextureScale(doc *gltf.Document, material *gltf.Material) (*TextureReference, float32) {
 	normalTexture := material.NormalTexture
-	if normalTexture == nil {
+	if normalTexture == nil || normalTexture.Index == nil {
 		return nil, 1.0
 	}
-	if normalTexture.TexCoord > 0 {
-		logger.Warn("Unsupported normal texture: tex coord layer unsupported")
+	ref := textureReference("normal", *normalTexture.Index, normalTexture.TexCoord, normalTexture.Extensions)
+	if ref == nil {
 		return nil, 1.0
 	}
-	return normalTexture.Index, float32(normalTexture.ScaleOrDefault())
+	return ref, float32(normalTexture.ScaleOrDefault())
+}
+
+func OcclusionTextureStrength(doc *gltf.Document, material *gltf.Material) (*TextureReference, float32) {
+	occlusionTexture := material.OcclusionTexture
+	if occlusionTexture == nil || occlusionTexture.Index == nil {
+		return nil, 1.0
+	}
+	ref := textureReference("occlusion", *occlusionTexture.Index, occlusionTexture.TexCoord, occlusionTexture.Extensions)
+	if ref == nil {
+		return nil, 1.0
+	}
+	return ref, float32(occlusionTexture.StrengthOrDefault())
+}
+
+func EmissiveTexture(doc *gltf.Document, material *gltf.Material) *TextureReference {
+	emissiveTexture := material.EmissiveTexture
+	if emissiveTexture == nil {
+		return nil
+	}
+	return textureReference("emissive", emissiveTexture.Index, emissiveTexture.TexCoord, emissiveTexture.Extensions)
+}
+
+// EmissiveColor returns the emissive factor of the material, multiplied by
+// the KHR_materials_emissive_strength extension, if present.
+func EmissiveColor(material *gltf.Material) sprec.Vec3 {
+	factor := material.EmissiveFactor
+	strength := float32(EmissiveStrength(material))
+	return sprec.Vec3Prod(sprec.NewVec3(float32(factor[0]), float32(factor[1]), float32(factor[2])), strength)
+}
+
+// EmissiveStrength returns the emissive strength of the material as specified
+// by the KHR_materials_emissive_strength extension. If the extension is not
+// present, 1.0 is returned.
+func EmissiveStrength(material *gltf.Material) float64 {
+	raw, ok := material.Extensions[emissiveStrengthExtensionName].(json.RawMessage)
+	if !ok {
+		return 1.0
+	}
+	var ext struct {
+		EmissiveStrength *float64 `json:"emissiveStrength"`
+	}
+	if err := json.Unmarshal(raw, &ext); err != nil {
+		logger.Warn("Invalid emissive strength extension", slog.String("error", err.Error()))
+		return 1.0
+	}
+	if ext.EmissiveStrength == nil {
+		return 1.0
+	}
+	return *ext.EmissiveStrength
+}
+
+const emissiveStrengthExtensionName = "KHR_materials_emissive_strength"
+
+func textureReference(kind string, index, texCoord int, extensions gltf.Extensions) *TextureReference {
+	result := &TextureReference{
+		Index:    index,
+		TexCoord: texCoord,
+	}
+	if ext, ok := extensions[texturetransform.ExtensionName].(*texturetransform.TextureTranform); ok {
+		if ext.TexCoord != nil {
+			result.TexCoord = *ext.TexCoord
+		}
+		result.Transform = opt.V(textureTransform(ext))
+	}
+	if result.TexCoord > 1 {
+		logger.Warn("Unsupported texture: tex coord layer unsupported",
+			slog.String("texture", kind),
+			slog.Int("tex_coord", result.TexCoord),
+		)
+		return nil
+	}
+	return result
+}
+
+func textureTransform(ext *texturetransform.TextureTranform) TextureTransform {
+	// The extension defines the transform as T * R * S in the original
+	// texture coordinate space. Since TexCoord0s and TexCoord1s flip the V
+	// coordinate (F), the effective transform is F * T * R * S * F.
+	scale := ext.ScaleOrDefault()
+	cos := math.Cos(ext.Rotation)
+	sin := math.Sin(ext.Rotation)
+	a, b, c := cos*scale[0], sin*scale[1], ext.Offset[0]
+	d, e, f := -sin*scale[0], cos*scale[1], ext.Offset[1]
+	return TextureTransform{
+		U: sprec.NewVec3(float32(a), float32(-b), float32(b+c)),
+		V: sprec.NewVec3(float32(-d), float32(e), float32(1.0-e-f)),
+	}
 }
 
 func InverseBindMatrix(doc *gltf.Document, skin *gltf.Skin, index int) sprec.Mat4 {
@@ -506,6 +700,17 @@ func AnimationTranslations(doc *gltf.Document, sampler *gltf.AnimationSampler) [
 }
 
 func AnimationRotations(doc *gltf.Document, sampler *gltf.AnimationSampler) []dprec.Quat {
+	result := AnimationRawRotations(doc, sampler)
+	for i, rotation := range result {
+		result[i] = dprec.UnitQuat(rotation)
+	}
+	return result
+}
+
+// AnimationRawRotations returns the rotation outputs of the specified
+// sampler without normalizing them. This is needed for the tangents of
+// cubic spline samplers, which are not unit quaternions.
+func AnimationRawRotations(doc *gltf.Document, sampler *gltf.AnimationSampler) []dprec.Quat {
 	accessor := doc.Accessors[sampler.Output]
 	if accessor.BufferView == nil {
 		logger.Warn("Accessor lacks a buffer view")
@@ -522,12 +727,12 @@ func AnimationRotations(doc *gltf.Document, sampler *gltf.AnimationSampler) []dp
 	case gltf.ComponentFloat:
 		result := make([]dprec.Quat, accessor.Count)
 		for i := 0; i < int(accessor.Count); i++ {
-			result[i] = stod.Quat(sprec.UnitQuat(sprec.NewQuat(
+			result[i] = stod.Quat(sprec.NewQuat(
 				buffer.Float32(i*16+3*4),
 				buffer.Float32(i*16+0*4),
 				buffer.Float32(i*16+1*4),
 				buffer.Float32(i*16+2*4),
-			)))
+			))
 		}
 		return result
 	default:
@@ -570,6 +775,37 @@ func AnimationScales(doc *gltf.Document, sampler *gltf.AnimationSampler) []dprec
 	}
 }
 
+// AnimationWeights returns the morph target weight outputs of the specified
+// sampler. The weights of all morph targets are returned in a flat list, one
+// group per output.
+func AnimationWeights(doc *gltf.Document, sampler *gltf.AnimationSampler) []float64 {
+	accessor := doc.Accessors[sampler.Output]
+	if accessor.BufferView == nil {
+		logger.Warn("Accessor lacks a buffer view")
+		return nil
+	}
+	buffer := BufferViewData(doc, *accessor.BufferView)
+	if accessor.Type != gltf.AccessorScalar {
+		logger.Error("Unsupported sampler output accessor type",
+			slog.String("type", accessor.Type.String()),
+		)
+		return nil
+	}
+	switch accessor.ComponentType {
+	case gltf.ComponentFloat:
+		result := make([]float64, accessor.Count)
+		for i := 0; i < int(accessor.Count); i++ {
+			result[i] = float64(buffer.Float32(i * 4))
+		}
+		return result
+	default:
+		logger.Error("Unsupported sampler output accessor component type",
+			slog.String("type", accessor.ComponentType.String()),
+		)
+		return nil
+	}
+}
+
 func BufferViewData(doc *gltf.Document, index int) gblob.LittleEndianBlock {
 	bufferView := doc.BufferViews[index]
 	offset := bufferView.ByteOffset